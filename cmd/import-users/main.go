@@ -0,0 +1,338 @@
+// Command import-users imports users from a CSV or JSON Lines file through the
+// ImportUsers RPC and writes the outcome of every row as CSV.
+//
+// CSV files start with a header naming their columns, among email, first_name,
+// last_name, nickname, password, country, type and attributes; attributes hold
+// a JSON object. JSON Lines files hold an object per line with the same keys,
+// attributes being a nested object. type is human or service_account.
+//
+// The whole file is read and checked before anything is sent, so that a
+// malformed line does not leave the import half done.
+//
+//	import-users -api-key <key> -dry-run users.csv
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	// 3rd party
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/logger"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+// rowsPerMessage is the number of rows sent in each request of the stream.
+const rowsPerMessage = 500
+
+var userTypes = map[string]pb.UserType{
+	"":                pb.UserType_USER_TYPE_UNSPECIFIED,
+	"human":           pb.UserType_USER_TYPE_HUMAN,
+	"service_account": pb.UserType_USER_TYPE_SERVICE_ACCOUNT,
+}
+
+type options struct {
+	addr   string
+	apiKey string
+	format string
+	report string
+	dryRun bool
+	upsert bool
+}
+
+func main() {
+	log, err := logger.New("import-users")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = log.Sync()
+	}()
+
+	var opts options
+	flag.StringVar(&opts.addr, "addr", "localhost:50000", "address of the gRPC server")
+	flag.StringVar(&opts.apiKey, "api-key", os.Getenv("API_KEY"), "API key with the users:write scope, API_KEY by default")
+	flag.StringVar(&opts.format, "format", "", "csv or jsonl, by default from the file extension")
+	flag.StringVar(&opts.report, "report", "-", "file the report is written to, - for stdout")
+	flag.BoolVar(&opts.dryRun, "dry-run", false, "validate the rows without writing anything")
+	flag.BoolVar(&opts.upsert, "upsert", false, "update the users whose primary email matches a row")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: import-users [flags] <file>")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	if err := run(log, opts, flag.Arg(0)); err != nil {
+		log.Error(err)
+		_ = log.Sync()
+		os.Exit(1)
+	}
+}
+
+func run(log *zap.SugaredLogger, opts options, path string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	format := opts.format
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+
+	users, err := readFile(path, format)
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.DialContext(ctx, opts.addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if opts.apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "ApiKey "+opts.apiKey)
+	}
+
+	resp, err := importUsers(ctx, pb.NewUserClient(conn), opts, users)
+	if err != nil {
+		return err
+	}
+
+	if err := writeReport(opts.report, resp); err != nil {
+		return err
+	}
+
+	log.Infow("users imported",
+		"rows", len(users),
+		"created", resp.GetCreated(),
+		"updated", resp.GetUpdated(),
+		"failed", resp.GetFailed(),
+		"dry_run", resp.GetDryRun())
+
+	return nil
+}
+
+func importUsers(ctx context.Context, client pb.UserClient, opts options, users []*pb.CreateUserRequest) (*pb.ImportUsersResponse, error) {
+	stream, err := client.ImportUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.ImportUsersRequest{DryRun: opts.dryRun, Upsert: opts.upsert}
+	for {
+		n := len(users)
+		if n > rowsPerMessage {
+			n = rowsPerMessage
+		}
+		req.Users, users = users[:n], users[n:]
+
+		if err := stream.Send(req); err != nil {
+			// The reason is returned by CloseAndRecv.
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		if len(users) == 0 {
+			break
+		}
+		req = &pb.ImportUsersRequest{}
+	}
+
+	return stream.CloseAndRecv()
+}
+
+func readFile(path, format string) ([]*pb.CreateUserRequest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	switch format {
+	case "csv":
+		return readCSV(f)
+	case "jsonl":
+		return readJSONL(f)
+	default:
+		return nil, fmt.Errorf("unknown format %q, use csv or jsonl", format)
+	}
+}
+
+// row is a user as read from a file.
+type row struct {
+	Email      string         `json:"email"`
+	FirstName  string         `json:"first_name"`
+	LastName   string         `json:"last_name"`
+	Nickname   string         `json:"nickname"`
+	Password   string         `json:"password"`
+	Country    string         `json:"country"`
+	Type       string         `json:"type"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+func readCSV(r io.Reader) ([]*pb.CreateUserRequest, error) {
+	cr := csv.NewReader(r)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	var users []*pb.CreateUserRequest
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return users, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var rw row
+		for i, column := range header {
+			v := record[i]
+			switch column {
+			case "email":
+				rw.Email = v
+			case "first_name":
+				rw.FirstName = v
+			case "last_name":
+				rw.LastName = v
+			case "nickname":
+				rw.Nickname = v
+			case "password":
+				rw.Password = v
+			case "country":
+				rw.Country = v
+			case "type":
+				rw.Type = v
+			case "attributes":
+				if v == "" {
+					continue
+				}
+				if err := json.Unmarshal([]byte(v), &rw.Attributes); err != nil {
+					return nil, fmt.Errorf("line %d: attributes: %w", line, err)
+				}
+			default:
+				return nil, fmt.Errorf("unknown column %q", column)
+			}
+		}
+
+		u, err := rw.request()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		users = append(users, u)
+	}
+}
+
+func readJSONL(r io.Reader) ([]*pb.CreateUserRequest, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+
+	var users []*pb.CreateUserRequest
+	for line := 1; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+
+		dec := json.NewDecoder(strings.NewReader(sc.Text()))
+		dec.DisallowUnknownFields()
+
+		var rw row
+		if err := dec.Decode(&rw); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		u, err := rw.request()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		users = append(users, u)
+	}
+
+	return users, sc.Err()
+}
+
+func (rw row) request() (*pb.CreateUserRequest, error) {
+	userType, ok := userTypes[rw.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown user type %q", rw.Type)
+	}
+
+	var attributes map[string]*structpb.Value
+	if len(rw.Attributes) > 0 {
+		attributes = make(map[string]*structpb.Value, len(rw.Attributes))
+		for name, a := range rw.Attributes {
+			v, err := structpb.NewValue(a)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %q: %w", name, err)
+			}
+			attributes[name] = v
+		}
+	}
+
+	return &pb.CreateUserRequest{
+		Email:      rw.Email,
+		FirstName:  rw.FirstName,
+		LastName:   rw.LastName,
+		Nickname:   rw.Nickname,
+		Password:   rw.Password,
+		Country:    rw.Country,
+		Type:       userType,
+		Attributes: attributes,
+	}, nil
+}
+
+// writeReport writes a line per row: its number, what was done, the id of the
+// user and why it failed.
+func writeReport(path string, resp *pb.ImportUsersResponse) (err error) {
+	out := os.Stdout
+	if path != "-" {
+		if out, err = os.Create(path); err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := out.Close(); err == nil {
+				err = cerr
+			}
+		}()
+	}
+
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{"row", "action", "user_id", "error"})
+	for _, r := range resp.GetResults() {
+		action := strings.ToLower(strings.TrimPrefix(r.GetAction().String(), "IMPORT_ACTION_"))
+		_ = w.Write([]string{strconv.FormatUint(r.GetRow(), 10), action, r.GetUserId(), r.GetError()})
+	}
+	w.Flush()
+
+	return w.Error()
+}
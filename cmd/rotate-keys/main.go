@@ -0,0 +1,215 @@
+// Command rotate-keys brings stored encryption up to date with the configured
+// keys. Data keys wrapped by a retired master key are wrapped again with the
+// current one, users written before PII was encrypted are encrypted, users
+// written before search was introduced get their search tokens and emails
+// indexed before they were normalized are indexed again. Rows are processed in
+// batches, each in its own transaction, so the service can keep running.
+//
+// To rotate the master key, move the old key to ENCRYPTION_RETIRED_MASTER_KEYS,
+// set the new one as ENCRYPTION_MASTER_KEY, deploy the service and run this
+// command. The retired key can be dropped once it reports nothing left to do.
+//
+// Rotating the master key keeps the data keys of users, only their wrapping
+// changes. To replace the data keys themselves, e.g. after they leaked, pass
+// -replace-keys-before: every user whose data key was created before that time
+// gets a fresh one, and their rows are encrypted again with it in batches of
+// users. The id of the last user of every batch is logged; if the command is
+// interrupted, run it again with the same time to pick up the remaining
+// users, and with -replace-keys-after set to the last id logged to skip the
+// users already scanned. Reads racing the replacement of a key may fail once.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/config"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/datakey"
+	sqlusers "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/user"
+	"github.com/TonyPath/user-mng-grpc-service/logger"
+)
+
+func main() {
+	log, err := logger.New("rotate-keys")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = log.Sync()
+	}()
+
+	batchSize := flag.Uint64("batch-size", 500, "rows processed per transaction")
+	replaceBefore := flag.String("replace-keys-before", "", "replace the data keys created before this RFC 3339 time and encrypt their rows again")
+	replaceAfter := flag.String("replace-keys-after", "", "resume replacing data keys after the user with this id")
+	flag.Parse()
+
+	replace, err := parseReplaceFlags(*replaceBefore, *replaceAfter)
+	if err != nil {
+		log.Error(err)
+		_ = log.Sync()
+		os.Exit(1)
+	}
+
+	if err := run(log, *batchSize, replace); err != nil {
+		log.Error(err)
+		_ = log.Sync()
+		os.Exit(1)
+	}
+}
+
+// replaceKeys selects the users whose data keys are replaced.
+type replaceKeys struct {
+	before time.Time
+	after  uuid.UUID
+}
+
+func parseReplaceFlags(before string, after string) (*replaceKeys, error) {
+	if before == "" {
+		if after != "" {
+			return nil, errors.New("-replace-keys-after requires -replace-keys-before")
+		}
+		return nil, nil
+	}
+
+	var (
+		r   replaceKeys
+		err error
+	)
+	if r.before, err = time.Parse(time.RFC3339, before); err != nil {
+		return nil, fmt.Errorf("parsing -replace-keys-before: %w", err)
+	}
+
+	if after != "" {
+		if r.after, err = uuid.Parse(after); err != nil {
+			return nil, fmt.Errorf("parsing -replace-keys-after: %w", err)
+		}
+	}
+
+	return &r, nil
+}
+
+func run(log *zap.SugaredLogger, batchSize uint64, replace *replaceKeys) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.New()
+	if err != nil {
+		return err
+	}
+
+	db, err := pg.NewDB(pg.Config{
+		User:     cfg.DB.Username,
+		Password: cfg.DB.Password,
+		Host:     cfg.DB.Host,
+		DBName:   cfg.DB.DBName,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Errorw("close db", "error", err)
+		}
+	}()
+
+	keyring, blindIndex, err := cfg.EncryptionKeys()
+	if err != nil {
+		return err
+	}
+	dataKeys := datakey.NewStore(keyring)
+	usersRepo := sqlusers.NewRepository(db, dataKeys, blindIndex, log)
+
+	rewrapped, err := inBatches(ctx, func() (int, error) {
+		var n int
+		err := pg.WithinTx(ctx, db, func(tx *sql.Tx) error {
+			var err error
+			n, err = dataKeys.Rewrap(ctx, tx, batchSize)
+			return err
+		})
+		return n, err
+	})
+	if err != nil {
+		return fmt.Errorf("rewrapping data keys: %w", err)
+	}
+	log.Infow("data keys rewrapped", "count", rewrapped, "master_key_id", keyring.CurrentKeyID())
+
+	encrypted, err := inBatches(ctx, func() (int, error) {
+		return usersRepo.EncryptPlaintextUsers(ctx, batchSize)
+	})
+	if err != nil {
+		return fmt.Errorf("encrypting users: %w", err)
+	}
+	log.Infow("users encrypted", "count", encrypted)
+
+	if replace != nil {
+		after := replace.after
+		replaced, err := inBatches(ctx, func() (int, error) {
+			n, last, err := usersRepo.ReplaceDataKeys(ctx, replace.before, after, batchSize)
+			if err != nil {
+				return 0, err
+			}
+			if n > 0 {
+				log.Infow("batch of data keys replaced", "count", n, "last_user_id", last)
+			}
+			after = last
+			return n, nil
+		})
+		if err != nil {
+			return fmt.Errorf("replacing data keys after user %s: %w", after, err)
+		}
+		log.Infow("data keys replaced", "count", replaced, "created_before", replace.before)
+	}
+
+	indexed, err := inBatches(ctx, func() (int, error) {
+		return usersRepo.IndexSearchTokens(ctx, batchSize)
+	})
+	if err != nil {
+		return fmt.Errorf("indexing users for search: %w", err)
+	}
+	log.Infow("users indexed for search", "count", indexed)
+
+	normalized, err := inBatches(ctx, func() (int, error) {
+		return usersRepo.NormalizeEmailIndexes(ctx, batchSize)
+	})
+	if err != nil {
+		return fmt.Errorf("normalizing email indexes: %w", err)
+	}
+	log.Infow("email indexes normalized", "count", normalized)
+
+	return nil
+}
+
+// inBatches runs batch until it has nothing left to do and returns the total
+// number of rows processed.
+func inBatches(ctx context.Context, batch func() (int, error)) (int, error) {
+	var total int
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		n, err := batch()
+		if err != nil {
+			return total, err
+		}
+
+		if n == 0 {
+			return total, nil
+		}
+		total += n
+	}
+}
@@ -15,13 +15,28 @@ import (
 
 	// internal
 	"github.com/TonyPath/user-mng-grpc-service/internal/config"
+	"github.com/TonyPath/user-mng-grpc-service/internal/oidc"
 	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	sqlapikeys "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/apikey"
+	sqlattributes "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/attribute"
+	sqlaudit "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/audit"
+	sqlconsents "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/consent"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/datakey"
+	sqlgroups "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/group"
+	sqlidempotency "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/idempotency"
+	sqlidentities "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/identity"
+	sqloauth "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/oauth"
+	sqlsettings "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/setting"
 	sqlusers "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/user"
 	"github.com/TonyPath/user-mng-grpc-service/internal/service"
+	"github.com/TonyPath/user-mng-grpc-service/internal/settings"
 	"github.com/TonyPath/user-mng-grpc-service/logger"
 	"github.com/TonyPath/user-mng-grpc-service/stream"
 	"github.com/TonyPath/user-mng-grpc-service/transport/grpc"
+	httpexport "github.com/TonyPath/user-mng-grpc-service/transport/http/export"
 	httpinfra "github.com/TonyPath/user-mng-grpc-service/transport/http/infra"
+	httpoidc "github.com/TonyPath/user-mng-grpc-service/transport/http/oidc"
+	httpscim "github.com/TonyPath/user-mng-grpc-service/transport/http/scim"
 )
 
 func main() {
@@ -67,9 +82,50 @@ func run(log *zap.SugaredLogger) error {
 		}
 	}()
 
+	// Encryption
+	// ----------
+	keyring, blindIndex, err := cfg.EncryptionKeys()
+	if err != nil {
+		return err
+	}
+	dataKeys := datakey.NewStore(keyring)
+
+	signingKey, err := oidc.LoadSigningKey(cfg.OIDC.SigningKeyFile)
+	if err != nil {
+		return err
+	}
+	if cfg.OIDC.SigningKeyFile == "" {
+		log.Warnw("startup", "status", "no OIDC signing key file, tokens will not verify after a restart")
+	}
+
+	pageTokenKey, generated, err := cfg.PageTokenKey()
+	if err != nil {
+		return err
+	}
+	if generated {
+		log.Warnw("startup", "status", "no page token key, page tokens will not verify after a restart or on other replicas")
+	}
+
 	// App Dependencies
 	// ----------------
-	usersRepo := sqlusers.NewRepository(db, log)
+	usersRepo := sqlusers.NewRepository(db, dataKeys, blindIndex, log)
+	groupsRepo := sqlgroups.NewRepository(db, log)
+	apiKeysRepo := sqlapikeys.NewRepository(db, log)
+	auditRepo := sqlaudit.NewRepository(db, dataKeys, log)
+	consentsRepo := sqlconsents.NewRepository(db, log)
+	settingsRepo := sqlsettings.NewRepository(db, log)
+	attributesRepo := sqlattributes.NewRepository(db, log)
+	identitiesRepo := sqlidentities.NewRepository(db, dataKeys, log)
+	oauthRepo := sqloauth.NewRepository(db, log)
+	idempotencyRepo := sqlidempotency.NewRepository(db, log)
+
+	settingsRegistry := settings.NewRegistry()
+	if cfg.Settings.SchemasDir != "" {
+		settingsRegistry, err = settings.LoadDir(cfg.Settings.SchemasDir)
+		if err != nil {
+			return fmt.Errorf("loading settings schemas: %w", err)
+		}
+	}
 
 	streamConfig := stream.Config{
 		Brokers: strings.Split(cfg.Kafka.ProducerBrokers, ","),
@@ -81,7 +137,59 @@ func run(log *zap.SugaredLogger) error {
 	publisher := stream.NewEventPublisher(kafkaClient)
 	defer publisher.Close()
 
-	svc := service.NewUserService(usersRepo, publisher)
+	svc := service.NewUserService(usersRepo, attributesRepo, publisher)
+	groupSvc := service.NewGroupService(groupsRepo, publisher)
+	apiKeySvc := service.NewAPIKeyService(apiKeysRepo, usersRepo)
+	auditSvc := service.NewAuditService(auditRepo)
+	consentSvc := service.NewConsentService(consentsRepo, publisher)
+	attributeSvc := service.NewAttributeService(attributesRepo)
+	contactSvc := service.NewContactService(usersRepo, usersRepo, publisher)
+	identitySvc := service.NewIdentityService(identitiesRepo, svc)
+	oidcCfg := service.OIDCConfig{
+		Issuer:   strings.TrimSuffix(cfg.OIDC.Issuer, "/"),
+		CodeTTL:  cfg.OIDC.CodeTTL,
+		TokenTTL: cfg.OIDC.TokenTTL,
+	}
+	oidcSvc := service.NewOIDCService(oauthRepo, usersRepo, oidc.NewSigner(signingKey), oidcCfg)
+	settingsCfg := service.SettingsConfig{
+		MaxValueBytes: cfg.Settings.MaxValueBytes,
+		MaxKeys:       cfg.Settings.MaxKeys,
+	}
+	settingsSvc := service.NewSettingsService(settingsRepo, usersRepo, settingsRegistry, settingsCfg)
+	exportSvc := service.NewExportService(usersRepo, usersRepo, identitiesRepo, groupsRepo, apiKeysRepo, consentsRepo, settingsRepo, auditRepo)
+
+	purgeCfg := service.PurgeConfig{
+		Retention: cfg.Purge.Retention,
+		Interval:  cfg.Purge.Interval,
+		BatchSize: cfg.Purge.BatchSize,
+	}
+	purger, err := service.NewUserPurger(usersRepo, publisher, purgeCfg, log)
+	if err != nil {
+		return err
+	}
+
+	changeListener := sqlusers.NewChangeListener(pgCfg, log)
+	watchCfg := service.WatchConfig{
+		Retention:          cfg.Watch.Retention,
+		MaxLag:             cfg.Watch.MaxLag,
+		PollInterval:       cfg.Watch.PollInterval,
+		CheckpointInterval: cfg.Watch.CheckpointInterval,
+	}
+	watchSvc := service.NewWatchService(usersRepo, changeListener, svc, watchCfg, log)
+
+	statsCfg := service.StatsConfig{
+		Rollup:         cfg.Stats.Rollup,
+		RollupInterval: cfg.Stats.RollupInterval,
+		CacheTTL:       cfg.Stats.CacheTTL,
+	}
+	statsSvc := service.NewStatsService(usersRepo, statsCfg, log)
+
+	idempotencyCfg := service.IdempotencyConfig{
+		TTL:           cfg.Idempotency.TTL,
+		LockTimeout:   cfg.Idempotency.LockTimeout,
+		PurgeInterval: cfg.Idempotency.PurgeInterval,
+	}
+	idempotencySvc := service.NewIdempotencyService(idempotencyRepo, idempotencyCfg, log)
 
 	//---------------------------
 	//
@@ -110,7 +218,68 @@ func run(log *zap.SugaredLogger) error {
 		return infraServer.Run(gctx)
 	})
 
-	grpcServer := grpc.NewServer(log, fmt.Sprintf(":%d", cfg.GRPCPort), svc)
+	oidcServer := httpoidc.NewServer(log, fmt.Sprintf(":%d", cfg.OIDC.HTTPPort), oidcCfg.Issuer, oidcSvc)
+	g.Go(func() error {
+		return oidcServer.Run(gctx)
+	})
+
+	scimServer := httpscim.NewServer(log, fmt.Sprintf(":%d", cfg.SCIM.HTTPPort), cfg.SCIM.BaseURL, svc, groupSvc, apiKeySvc)
+	g.Go(func() error {
+		return scimServer.Run(gctx)
+	})
+
+	exportServer := httpexport.NewServer(log, fmt.Sprintf(":%d", cfg.Export.HTTPPort), svc, apiKeySvc)
+	g.Go(func() error {
+		return exportServer.Run(gctx)
+	})
+
+	g.Go(func() error {
+		return purger.Run(gctx)
+	})
+
+	g.Go(func() error {
+		return changeListener.Run(gctx)
+	})
+
+	g.Go(func() error {
+		return watchSvc.PurgeChanges(gctx, cfg.Watch.PurgeInterval)
+	})
+
+	g.Go(func() error {
+		return statsSvc.RefreshRollup(gctx)
+	})
+
+	g.Go(func() error {
+		return idempotencySvc.Run(gctx)
+	})
+
+	grpcServices := grpc.Services{
+		User:        svc,
+		Group:       groupSvc,
+		APIKey:      apiKeySvc,
+		Audit:       auditSvc,
+		Export:      exportSvc,
+		Consent:     consentSvc,
+		Settings:    settingsSvc,
+		Attribute:   attributeSvc,
+		Contact:     contactSvc,
+		Identity:    identitySvc,
+		OAuthClient: oidcSvc,
+		Watch:       watchSvc,
+		Stats:       statsSvc,
+		Idempotency: idempotencySvc,
+	}
+	trustedProxies, err := cfg.TrustedProxies()
+	if err != nil {
+		return err
+	}
+	grpcOpts := grpc.Options{
+		AuthRequired:   cfg.Auth.Required,
+		PageTokenKey:   pageTokenKey,
+		MaxPageOffset:  cfg.Pagination.MaxPageOffset,
+		TrustedProxies: trustedProxies,
+	}
+	grpcServer := grpc.NewServer(log, fmt.Sprintf(":%d", cfg.GRPCPort), grpcOpts, grpcServices)
 	g.Go(func() error {
 		return grpcServer.Run(gctx)
 	})
@@ -0,0 +1,41 @@
+package dockertest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+// UserInserter stores users, as the user repository does. Taking it rather
+// than the repository keeps the user package free to use this package.
+type UserInserter interface {
+	InsertUser(ctx context.Context, user models.User) (uuid.UUID, error)
+}
+
+// InsertUser creates an active user with the email through users, so that its
+// row is encrypted and indexed like the rows written by the service.
+func InsertUser(t *testing.T, users UserInserter, email string) uuid.UUID {
+	t.Helper()
+
+	userID, err := users.InsertUser(context.TODO(), models.User{
+		ID:        uuid.New(),
+		Type:      models.UserTypeHuman,
+		Status:    models.UserStatusActive,
+		Email:     email,
+		FirstName: "tony",
+		LastName:  "papath",
+		Nickname:  "TonyPath",
+		Country:   "GR",
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	return userID
+}
@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+// SystemActor is recorded for mutations that are not made on behalf of a
+// caller, such as the scheduled purge.
+const SystemActor = "system"
+
+// Metadata describes who made a request and where it came from.
+type Metadata struct {
+	Actor     string
+	RequestID string
+	ClientIP  string
+}
+
+type ctxKey int
+
+const metadataKey ctxKey = iota
+
+// WithMetadata returns a copy of ctx carrying the request metadata.
+func WithMetadata(ctx context.Context, m Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey, m)
+}
+
+// MetadataFromContext returns the request metadata. Contexts without metadata
+// are attributed to the SystemActor.
+func MetadataFromContext(ctx context.Context) Metadata {
+	m, ok := ctx.Value(metadataKey).(Metadata)
+	if !ok || m.Actor == "" {
+		m.Actor = SystemActor
+	}
+	return m
+}
+
+// NewEntry describes an action on the user taken on behalf of the caller in ctx.
+func NewEntry(ctx context.Context, userID uuid.UUID, action string, before map[string]any, after map[string]any) models.AuditEntry {
+	md := MetadataFromContext(ctx)
+	return models.AuditEntry{
+		UserID:    userID,
+		Actor:     md.Actor,
+		Action:    action,
+		Before:    before,
+		After:     after,
+		RequestID: md.RequestID,
+		ClientIP:  md.ClientIP,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,69 @@
+package audit
+
+import (
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+// Redacted replaces the value of secret fields.
+const Redacted = "[REDACTED]"
+
+// secretFields are never written to the audit log in clear.
+var secretFields = map[string]bool{
+	"password": true,
+}
+
+// Snapshot returns the audited fields of the user with secrets redacted.
+func Snapshot(u models.User) map[string]any {
+	return redact(fields(u))
+}
+
+// Diff returns the fields that differ between before and after, with secrets
+// redacted. Both maps are empty when nothing changed. A custom attribute set
+// on one side only is nil on the other.
+func Diff(before models.User, after models.User) (map[string]any, map[string]any) {
+	b, a := fields(before), fields(after)
+
+	changedBefore := make(map[string]any)
+	changedAfter := make(map[string]any)
+	for k, v := range b {
+		if a[k] != v {
+			changedBefore[k] = v
+			changedAfter[k] = a[k]
+		}
+	}
+	for k, v := range a {
+		if _, ok := b[k]; !ok {
+			changedBefore[k] = nil
+			changedAfter[k] = v
+		}
+	}
+
+	return redact(changedBefore), redact(changedAfter)
+}
+
+func fields(u models.User) map[string]any {
+	f := map[string]any{
+		"type":       string(u.Type),
+		"status":     string(u.Status),
+		"email":      u.Email,
+		"first_name": u.FirstName,
+		"last_name":  u.LastName,
+		"nickname":   u.Nickname,
+		"country":    u.Country,
+		"password":   string(u.Password),
+	}
+	for name, value := range u.Attributes {
+		f["attributes."+name] = value
+	}
+	return f
+}
+
+func redact(m map[string]any) map[string]any {
+	for k := range m {
+		if secretFields[k] {
+			m[k] = Redacted
+		}
+	}
+	return m
+}
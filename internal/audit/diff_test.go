@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestDiff(t *testing.T) {
+	before := models.User{
+		Email:    "old@example.com",
+		Nickname: "robin",
+		Country:  "GR",
+		Password: []byte("hash-1"),
+		Attributes: map[string]any{
+			"team": "blue",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		after      func(u models.User) models.User
+		wantBefore map[string]any
+		wantAfter  map[string]any
+	}{
+		{
+			name: "no changes",
+			after: func(u models.User) models.User {
+				return u
+			},
+			wantBefore: map[string]any{},
+			wantAfter:  map[string]any{},
+		},
+		{
+			name: "email changed",
+			after: func(u models.User) models.User {
+				u.Email = "new@example.com"
+				return u
+			},
+			wantBefore: map[string]any{"email": "old@example.com"},
+			wantAfter:  map[string]any{"email": "new@example.com"},
+		},
+		{
+			name: "password is redacted",
+			after: func(u models.User) models.User {
+				u.Password = []byte("hash-2")
+				return u
+			},
+			wantBefore: map[string]any{"password": Redacted},
+			wantAfter:  map[string]any{"password": Redacted},
+		},
+		{
+			name: "attributes changed",
+			after: func(u models.User) models.User {
+				u.Attributes = map[string]any{"level": int64(3)}
+				return u
+			},
+			wantBefore: map[string]any{"attributes.team": "blue", "attributes.level": nil},
+			wantAfter:  map[string]any{"attributes.team": nil, "attributes.level": int64(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, a := Diff(before, tt.after(before))
+			require.Equal(t, tt.wantBefore, b)
+			require.Equal(t, tt.wantAfter, a)
+		})
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	s := Snapshot(models.User{Email: "bruce@example.com", Password: []byte("hash")})
+	require.Equal(t, "bruce@example.com", s["email"])
+	require.Equal(t, Redacted, s["password"])
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"context"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+type ctxKey int
+
+const principalKey ctxKey = iota
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, p models.Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+// PrincipalFromContext returns the authenticated caller, if any.
+func PrincipalFromContext(ctx context.Context) (models.Principal, bool) {
+	p, ok := ctx.Value(principalKey).(models.Principal)
+	return p, ok
+}
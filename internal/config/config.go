@@ -1,8 +1,18 @@
 package config
 
 import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
 	// 3rd party
 	"github.com/caarlos0/env/v6"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/crypto"
 )
 
 type Config struct {
@@ -20,6 +30,85 @@ type Config struct {
 	Kafka struct {
 		ProducerBrokers string `env:"PRODUCER_BROKERS" envDefault:"localhost:9092"`
 	}
+
+	Purge struct {
+		Retention time.Duration `env:"PURGE_RETENTION" envDefault:"720h"`
+		Interval  time.Duration `env:"PURGE_INTERVAL" envDefault:"1h"`
+		BatchSize uint64        `env:"PURGE_BATCH_SIZE" envDefault:"500"`
+	}
+
+	Watch struct {
+		Retention          time.Duration `env:"WATCH_RETENTION" envDefault:"24h"`
+		MaxLag             int64         `env:"WATCH_MAX_LAG" envDefault:"10000"`
+		PollInterval       time.Duration `env:"WATCH_POLL_INTERVAL" envDefault:"5s"`
+		CheckpointInterval time.Duration `env:"WATCH_CHECKPOINT_INTERVAL" envDefault:"30s"`
+		PurgeInterval      time.Duration `env:"WATCH_PURGE_INTERVAL" envDefault:"1h"`
+	}
+
+	Stats struct {
+		Rollup         bool          `env:"STATS_ROLLUP" envDefault:"false"`
+		RollupInterval time.Duration `env:"STATS_ROLLUP_INTERVAL" envDefault:"1h"`
+		CacheTTL       time.Duration `env:"STATS_CACHE_TTL" envDefault:"1m"`
+	}
+
+	Idempotency struct {
+		TTL           time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+		LockTimeout   time.Duration `env:"IDEMPOTENCY_LOCK_TIMEOUT" envDefault:"1m"`
+		PurgeInterval time.Duration `env:"IDEMPOTENCY_PURGE_INTERVAL" envDefault:"1h"`
+	}
+
+	Settings struct {
+		// SchemasDir holds a <namespace>.json JSON schema for every validated namespace.
+		SchemasDir    string `env:"SETTINGS_SCHEMAS_DIR"`
+		MaxValueBytes int    `env:"SETTINGS_MAX_VALUE_BYTES" envDefault:"16384"`
+		MaxKeys       int    `env:"SETTINGS_MAX_KEYS" envDefault:"100"`
+	}
+
+	Auth struct {
+		Required bool `env:"AUTH_REQUIRED" envDefault:"false"`
+		// TrustedProxies are the addresses or CIDR networks of the proxies
+		// whose x-forwarded-for header is honored.
+		TrustedProxies []string `env:"TRUSTED_PROXIES" envSeparator:","`
+	}
+
+	// OIDC configures the OpenID Connect provider. Without a signing key file,
+	// a key is generated at startup and issued tokens do not survive restarts.
+	OIDC struct {
+		HTTPPort       int           `env:"OIDC_HTTP_PORT" envDefault:"8080"`
+		Issuer         string        `env:"OIDC_ISSUER" envDefault:"http://localhost:8080"`
+		SigningKeyFile string        `env:"OIDC_SIGNING_KEY_FILE"`
+		CodeTTL        time.Duration `env:"OIDC_CODE_TTL" envDefault:"1m"`
+		TokenTTL       time.Duration `env:"OIDC_TOKEN_TTL" envDefault:"1h"`
+	}
+
+	// SCIM configures the SCIM 2.0 provisioning API. The base URL is the public
+	// URL of its /scim/v2 endpoints.
+	SCIM struct {
+		HTTPPort int    `env:"SCIM_HTTP_PORT" envDefault:"8081"`
+		BaseURL  string `env:"SCIM_BASE_URL" envDefault:"http://localhost:8081/scim/v2"`
+	}
+
+	// Export configures the HTTP server of user exports.
+	Export struct {
+		HTTPPort int `env:"EXPORT_HTTP_PORT" envDefault:"8082"`
+	}
+
+	// Pagination configures the paging of QueryUsers. Without a page token key,
+	// a key is generated at startup and issued tokens do not survive restarts.
+	Pagination struct {
+		TokenKey      string `env:"PAGE_TOKEN_KEY"`
+		TokenKeyFile  string `env:"PAGE_TOKEN_KEY_FILE"`
+		MaxPageOffset uint64 `env:"PAGE_NUMBER_MAX_OFFSET" envDefault:"10000"`
+	}
+
+	// Keys are base64 encoded 32 byte keys, given either inline or in a file.
+	Encryption struct {
+		MasterKey         string   `env:"ENCRYPTION_MASTER_KEY"`
+		MasterKeyFile     string   `env:"ENCRYPTION_MASTER_KEY_FILE"`
+		RetiredMasterKeys []string `env:"ENCRYPTION_RETIRED_MASTER_KEYS" envSeparator:","`
+		IndexKey          string   `env:"ENCRYPTION_INDEX_KEY"`
+		IndexKeyFile      string   `env:"ENCRYPTION_INDEX_KEY_FILE"`
+	}
 }
 
 func New() (Config, error) {
@@ -31,3 +120,83 @@ func New() (Config, error) {
 
 	return cfg, nil
 }
+
+// EncryptionKeys loads the keyring and the blind index of the Encryption settings.
+func (c Config) EncryptionKeys() (*crypto.Keyring, *crypto.BlindIndex, error) {
+	master, err := crypto.LoadKey(c.Encryption.MasterKey, c.Encryption.MasterKeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading master key: %w", err)
+	}
+
+	retired := make([][]byte, 0, len(c.Encryption.RetiredMasterKeys))
+	for _, encoded := range c.Encryption.RetiredMasterKeys {
+		key, err := crypto.LoadKey(encoded, "")
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading retired master key: %w", err)
+		}
+		retired = append(retired, key)
+	}
+
+	keyring, err := crypto.NewKeyring(master, retired...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indexKey, err := crypto.LoadKey(c.Encryption.IndexKey, c.Encryption.IndexKeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading index key: %w", err)
+	}
+
+	index, err := crypto.NewBlindIndex(indexKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return keyring, index, nil
+}
+
+// PageTokenKey loads the key signing page tokens, generating one when none is
+// set.
+func (c Config) PageTokenKey() (key []byte, generated bool, err error) {
+	key, err = crypto.LoadKey(c.Pagination.TokenKey, c.Pagination.TokenKeyFile)
+	if errors.Is(err, crypto.ErrMissingKey) {
+		key = make([]byte, crypto.KeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, false, err
+		}
+		return key, true, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("loading page token key: %w", err)
+	}
+
+	return key, false, nil
+}
+
+// TrustedProxies parses the TrustedProxies of the Auth settings. A single
+// address stands for a network of its own.
+func (c Config) TrustedProxies() ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(c.Auth.TrustedProxies))
+	for _, proxy := range c.Auth.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
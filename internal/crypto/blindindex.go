@@ -0,0 +1,32 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// BlindIndex computes keyed hashes of values so that encrypted columns can
+// still be looked up by equality.
+type BlindIndex struct {
+	key []byte
+}
+
+func NewBlindIndex(key []byte) (*BlindIndex, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	return &BlindIndex{
+		key: key,
+	}, nil
+}
+
+// Compute returns the index of the value of the field. The field name is part
+// of the hash, so equal values of different fields do not share an index.
+func (b *BlindIndex) Compute(field string, value string) []byte {
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,163 @@
+// Package crypto implements envelope encryption: data is encrypted with data
+// keys, and data keys are stored wrapped by a master key.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the size in bytes of master and data keys (AES-256).
+const KeySize = 32
+
+var (
+	ErrInvalidKeySize   = errors.New("crypto: invalid key size")
+	ErrDecrypt          = errors.New("crypto: message authentication failed")
+	ErrUnknownMasterKey = errors.New("crypto: unknown master key")
+)
+
+// Keyring issues data keys and wraps them with the current master key.
+// Retired master keys are only used to unwrap data keys until they have been
+// wrapped again with the current one.
+type Keyring struct {
+	currentID string
+	masters   map[string]cipher.AEAD
+}
+
+func NewKeyring(current []byte, retired ...[]byte) (*Keyring, error) {
+	k := &Keyring{
+		currentID: KeyID(current),
+		masters:   make(map[string]cipher.AEAD, len(retired)+1),
+	}
+
+	for _, key := range append([][]byte{current}, retired...) {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.masters[KeyID(key)] = aead
+	}
+
+	return k, nil
+}
+
+// KeyID identifies a master key without revealing it.
+func KeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// CurrentKeyID returns the id of the master key new data keys are wrapped with.
+func (k *Keyring) CurrentKeyID() string {
+	return k.currentID
+}
+
+// NewDataKey returns a fresh data key together with its wrapped form, which
+// is the only one that should be stored, and the id of the master key that
+// wrapped it.
+func (k *Keyring) NewDataKey() ([]byte, []byte, string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, "", fmt.Errorf("generating data key: %w", err)
+	}
+
+	wrapped, err := k.WrapDataKey(key)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return key, wrapped, k.currentID, nil
+}
+
+// WrapDataKey wraps the data key with the current master key.
+func (k *Keyring) WrapDataKey(key []byte) ([]byte, error) {
+	return seal(k.masters[k.currentID], key, nil)
+}
+
+// UnwrapDataKey recovers a data key wrapped by the master key with the given
+// id. Every master key is tried for an empty id.
+func (k *Keyring) UnwrapDataKey(keyID string, wrapped []byte) ([]byte, error) {
+	if keyID == "" {
+		for _, master := range k.masters {
+			if key, err := open(master, wrapped, nil); err == nil {
+				return key, nil
+			}
+		}
+		return nil, ErrUnknownMasterKey
+	}
+
+	master, ok := k.masters[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMasterKey, keyID)
+	}
+	return open(master, wrapped, nil)
+}
+
+// Encrypt seals plaintext with the data key. The additional data is
+// authenticated but not encrypted, and must be given again to Decrypt.
+func Encrypt(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return seal(aead, plaintext, additionalData)
+}
+
+// Decrypt opens a ciphertext produced by Encrypt.
+func Decrypt(key []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, ciphertext, additionalData)
+}
+
+// Reencrypt opens a ciphertext produced by Encrypt with the old data key and
+// seals it again with the new one, under the same additional data.
+func Reencrypt(oldKey []byte, newKey []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	plaintext, err := Decrypt(oldKey, ciphertext, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	return Encrypt(newKey, plaintext, additionalData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal prepends a random nonce to the ciphertext.
+func seal(aead cipher.AEAD, plaintext []byte, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, additionalData)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
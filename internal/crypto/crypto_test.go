@@ -0,0 +1,198 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyring(t *testing.T) *Keyring {
+	master := make([]byte, KeySize)
+	_, err := rand.Read(master)
+	require.NoError(t, err)
+
+	k, err := NewKeyring(master)
+	require.NoError(t, err)
+	return k
+}
+
+func TestKeyring_DataKey(t *testing.T) {
+	k := newTestKeyring(t)
+
+	key, wrapped, keyID, err := k.NewDataKey()
+	require.NoError(t, err)
+	require.Len(t, key, KeySize)
+	require.Equal(t, k.CurrentKeyID(), keyID)
+	require.False(t, bytes.Contains(wrapped, key))
+
+	unwrapped, err := k.UnwrapDataKey(keyID, wrapped)
+	require.NoError(t, err)
+	require.Equal(t, key, unwrapped)
+
+	t.Log("wrapped by an unknown master key")
+	{
+		_, err := newTestKeyring(t).UnwrapDataKey(keyID, wrapped)
+		require.ErrorIs(t, err, ErrUnknownMasterKey)
+	}
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	oldMaster := make([]byte, KeySize)
+	newMaster := make([]byte, KeySize)
+	_, _ = rand.Read(oldMaster)
+	_, _ = rand.Read(newMaster)
+
+	oldKeyring, err := NewKeyring(oldMaster)
+	require.NoError(t, err)
+
+	key, wrapped, oldID, err := oldKeyring.NewDataKey()
+	require.NoError(t, err)
+
+	newKeyring, err := NewKeyring(newMaster, oldMaster)
+	require.NoError(t, err)
+	require.NotEqual(t, oldID, newKeyring.CurrentKeyID())
+
+	unwrapped, err := newKeyring.UnwrapDataKey(oldID, wrapped)
+	require.NoError(t, err)
+	require.Equal(t, key, unwrapped)
+
+	rewrapped, err := newKeyring.WrapDataKey(unwrapped)
+	require.NoError(t, err)
+
+	unwrapped, err = newKeyring.UnwrapDataKey(newKeyring.CurrentKeyID(), rewrapped)
+	require.NoError(t, err)
+	require.Equal(t, key, unwrapped)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key, _, _, err := newTestKeyring(t).NewDataKey()
+	require.NoError(t, err)
+
+	ciphertext, err := Encrypt(key, []byte("bruce@wayne.com"), []byte("user-1"))
+	require.NoError(t, err)
+
+	plaintext, err := Decrypt(key, ciphertext, []byte("user-1"))
+	require.NoError(t, err)
+	require.Equal(t, "bruce@wayne.com", string(plaintext))
+
+	tests := []struct {
+		name           string
+		key            []byte
+		ciphertext     []byte
+		additionalData []byte
+		wantErr        error
+	}{
+		{
+			name:           "other additional data",
+			key:            key,
+			ciphertext:     ciphertext,
+			additionalData: []byte("user-2"),
+			wantErr:        ErrDecrypt,
+		},
+		{
+			name:           "truncated ciphertext",
+			key:            key,
+			ciphertext:     ciphertext[:4],
+			additionalData: []byte("user-1"),
+			wantErr:        ErrDecrypt,
+		},
+		{
+			name:           "invalid key size",
+			key:            key[:16],
+			ciphertext:     ciphertext,
+			additionalData: []byte("user-1"),
+			wantErr:        ErrInvalidKeySize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decrypt(tt.key, tt.ciphertext, tt.additionalData)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReencrypt(t *testing.T) {
+	k := newTestKeyring(t)
+	oldKey, _, _, err := k.NewDataKey()
+	require.NoError(t, err)
+	newKey, _, _, err := k.NewDataKey()
+	require.NoError(t, err)
+
+	ciphertext, err := Encrypt(oldKey, []byte("bruce@wayne.com"), []byte("user-1"))
+	require.NoError(t, err)
+
+	reencrypted, err := Reencrypt(oldKey, newKey, ciphertext, []byte("user-1"))
+	require.NoError(t, err)
+
+	plaintext, err := Decrypt(newKey, reencrypted, []byte("user-1"))
+	require.NoError(t, err)
+	require.Equal(t, "bruce@wayne.com", string(plaintext))
+
+	_, err = Decrypt(oldKey, reencrypted, []byte("user-1"))
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	t.Log("other additional data")
+	{
+		_, err := Reencrypt(oldKey, newKey, ciphertext, []byte("user-2"))
+		require.ErrorIs(t, err, ErrDecrypt)
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	idx, err := NewBlindIndex(key)
+	require.NoError(t, err)
+
+	require.Equal(t, idx.Compute("email", "bruce@wayne.com"), idx.Compute("email", "bruce@wayne.com"))
+	require.NotEqual(t, idx.Compute("email", "bruce@wayne.com"), idx.Compute("email", "alfred@wayne.com"))
+	require.NotEqual(t, idx.Compute("email", "GR"), idx.Compute("country", "GR"))
+
+	_, err = NewBlindIndex(key[:8])
+	require.ErrorIs(t, err, ErrInvalidKeySize)
+}
+
+func TestLoadKey(t *testing.T) {
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	path := filepath.Join(t.TempDir(), "master.key")
+	require.NoError(t, os.WriteFile(path, []byte(encoded+"\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		encoded string
+		path    string
+		wantErr error
+	}{
+		{name: "inline", encoded: encoded},
+		{name: "file", path: path},
+		{name: "file takes precedence", encoded: "ignored", path: path},
+		{name: "missing", wantErr: ErrMissingKey},
+		{name: "too short", encoded: base64.StdEncoding.EncodeToString(key[:16]), wantErr: ErrInvalidKeySize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadKey(tt.encoded, tt.path)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, key, got)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ErrMissingKey = errors.New("crypto: missing key")
+
+// LoadKey decodes a base64 encoded key given either inline or in a file.
+// The file takes precedence when both are set.
+func LoadKey(encoded string, path string) ([]byte, error) {
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading key file: %w", err)
+		}
+		encoded = string(b)
+	}
+
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		return nil, ErrMissingKey
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding key: %w", err)
+	}
+
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	return key, nil
+}
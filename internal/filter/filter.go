@@ -0,0 +1,436 @@
+// Package filter parses AIP-160 filter expressions, such as
+// `country = "GR" AND created_at > "2024-01-01" AND nickname:"tony*"`, into an
+// AST. Checking fields and values is left to whoever compiles the AST.
+//
+// Besides the comparators of AIP-160, restrictions accept IN lists, e.g.
+// `status IN (active, pending)`. As in AIP-160, OR binds tighter than AND and
+// terms separated by whitespace only are ANDed.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid is returned for expressions that cannot be parsed.
+var ErrInvalid = errors.New("invalid filter")
+
+const (
+	// maxLength is the length of the longest expression parsed.
+	maxLength = 2048
+	// maxDepth is the deepest nesting of parentheses and NOTs parsed.
+	maxDepth = 32
+)
+
+// Op is the comparator of a restriction.
+type Op string
+
+const (
+	OpEq Op = "="
+	OpNe Op = "!="
+	OpLt Op = "<"
+	OpLe Op = "<="
+	OpGt Op = ">"
+	OpGe Op = ">="
+	// OpHas matches present fields with `field:*` and prefixes with
+	// `field:"value*"`; otherwise it is an equality.
+	OpHas Op = ":"
+	OpIn  Op = "IN"
+)
+
+// ValueKind tells how a value was written.
+type ValueKind int
+
+const (
+	// String values were quoted.
+	String ValueKind = iota
+	// Text values were not quoted: numbers, enum values, true, false and null.
+	Text
+	// Any is the `*` of `field:*`.
+	Any
+)
+
+type Value struct {
+	Kind ValueKind
+	Text string
+}
+
+// Null reports whether the value is the unquoted null.
+func (v Value) Null() bool {
+	return v.Kind == Text && v.Text == "null"
+}
+
+// Expr is a node of the AST: And, Or, Not or Restriction.
+type Expr interface {
+	expr()
+}
+
+type And struct {
+	Exprs []Expr
+}
+
+type Or struct {
+	Exprs []Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+// Restriction compares a field to its values; IN restrictions have one or more
+// values, others exactly one.
+type Restriction struct {
+	Field  string
+	Op     Op
+	Values []Value
+}
+
+func (And) expr()         {}
+func (Or) expr()          {}
+func (Not) expr()         {}
+func (Restriction) expr() {}
+
+// Parse returns the AST of the expression, nil for a blank one.
+func Parse(s string) (Expr, error) {
+	if len(s) > maxLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalid, maxLength)
+	}
+
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	if tokens[0].kind == tokenEOF {
+		return nil, nil
+	}
+
+	p := &parser{tokens: tokens}
+	e, err := p.expression(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalid, t.text)
+	}
+
+	return e, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) keyword(k string) bool {
+	t := p.peek()
+	if t.kind == tokenText && t.text == k {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expression := sequence { "AND" sequence }
+func (p *parser) expression(depth int) (Expr, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("%w: nested deeper than %d", ErrInvalid, maxDepth)
+	}
+
+	var exprs []Expr
+	for {
+		e, err := p.sequence(depth)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+
+		if !p.keyword("AND") {
+			break
+		}
+	}
+
+	return and(exprs), nil
+}
+
+// sequence := factor { factor }
+func (p *parser) sequence(depth int) (Expr, error) {
+	var exprs []Expr
+	for {
+		e, err := p.factor(depth)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+
+		t := p.peek()
+		if t.kind == tokenEOF || t.kind == tokenRParen || (t.kind == tokenText && t.text == "AND") {
+			break
+		}
+	}
+
+	return and(exprs), nil
+}
+
+// factor := term { "OR" term }
+func (p *parser) factor(depth int) (Expr, error) {
+	var exprs []Expr
+	for {
+		e, err := p.term(depth)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+
+		if !p.keyword("OR") {
+			break
+		}
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return Or{Exprs: exprs}, nil
+}
+
+// term := [ "NOT" | "-" ] simple
+// simple := "(" expression ")" | restriction
+func (p *parser) term(depth int) (Expr, error) {
+	negate := p.keyword("NOT")
+	if !negate && p.peek().kind == tokenMinus {
+		p.next()
+		negate = true
+	}
+
+	if negate {
+		if depth+1 > maxDepth {
+			return nil, fmt.Errorf("%w: nested deeper than %d", ErrInvalid, maxDepth)
+		}
+		e, err := p.term(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: e}, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+		e, err := p.expression(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, fmt.Errorf("%w: missing )", ErrInvalid)
+		}
+		return e, nil
+	}
+
+	return p.restriction()
+}
+
+// restriction := field comparator arg | field "IN" "(" arg { "," arg } ")"
+func (p *parser) restriction() (Expr, error) {
+	field, err := p.field()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.next()
+	switch {
+	case t.kind == tokenComparator:
+		v, err := p.value(Op(t.text) == OpHas)
+		if err != nil {
+			return nil, err
+		}
+		return Restriction{Field: field, Op: Op(t.text), Values: []Value{v}}, nil
+	case t.kind == tokenText && t.text == "IN":
+		values, err := p.list()
+		if err != nil {
+			return nil, err
+		}
+		return Restriction{Field: field, Op: OpIn, Values: values}, nil
+	case t.kind == tokenEOF:
+		return nil, fmt.Errorf("%w: %s has no comparator", ErrInvalid, field)
+	default:
+		return nil, fmt.Errorf("%w: unexpected %q after %s", ErrInvalid, t.text, field)
+	}
+}
+
+// field := name { "." name }
+func (p *parser) field() (string, error) {
+	t := p.next()
+	if t.kind != tokenText || isKeyword(t.text) {
+		return "", fmt.Errorf("%w: expected a field, got %q", ErrInvalid, t.text)
+	}
+	return t.text, nil
+}
+
+func (p *parser) value(allowAny bool) (Value, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenString:
+		return Value{Kind: String, Text: t.text}, nil
+	case t.kind == tokenText && !isKeyword(t.text):
+		return Value{Kind: Text, Text: t.text}, nil
+	case t.kind == tokenStar && allowAny:
+		return Value{Kind: Any, Text: "*"}, nil
+	case t.kind == tokenEOF:
+		return Value{}, fmt.Errorf("%w: missing value", ErrInvalid)
+	default:
+		return Value{}, fmt.Errorf("%w: unexpected %q", ErrInvalid, t.text)
+	}
+}
+
+func (p *parser) list() ([]Value, error) {
+	if t := p.next(); t.kind != tokenLParen {
+		return nil, fmt.Errorf("%w: IN expects a parenthesized list", ErrInvalid)
+	}
+
+	var values []Value
+	for {
+		v, err := p.value(false)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+
+		t := p.next()
+		if t.kind == tokenRParen {
+			return values, nil
+		}
+		if t.kind != tokenComma {
+			return nil, fmt.Errorf("%w: unterminated IN list", ErrInvalid)
+		}
+	}
+}
+
+func and(exprs []Expr) Expr {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	return And{Exprs: exprs}
+}
+
+func isKeyword(s string) bool {
+	switch s {
+	case "AND", "OR", "NOT", "IN":
+		return true
+	}
+	return false
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenText
+	tokenString
+	tokenComparator
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenMinus
+	tokenStar
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lex splits the expression into tokens, ending with a tokenEOF.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ","})
+			i++
+		case c == '*':
+			tokens = append(tokens, token{kind: tokenStar, text: "*"})
+			i++
+		case c == '-' && (i+1 == len(s) || !isDigit(s[i+1])):
+			tokens = append(tokens, token{kind: tokenMinus, text: "-"})
+			i++
+		case c == '=' || c == ':':
+			tokens = append(tokens, token{kind: tokenComparator, text: string(c)})
+			i++
+		case c == '!' || c == '<' || c == '>':
+			if i+1 < len(s) && s[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokenComparator, text: s[i : i+2]})
+				i += 2
+				continue
+			}
+			if c == '!' {
+				return nil, fmt.Errorf("%w: unexpected !", ErrInvalid)
+			}
+			tokens = append(tokens, token{kind: tokenComparator, text: string(c)})
+			i++
+		case c == '"' || c == '\'':
+			text, n, err := lexString(s[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text})
+			i += n
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\n\r(),*=:!<>\"'", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenText, text: s[i:j]})
+			i = j
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+// lexString returns the unquoted string at the start of s and the length of
+// the quoted string. Backslashes escape the next character.
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 == len(s) {
+				return "", 0, fmt.Errorf("%w: unterminated string", ErrInvalid)
+			}
+			i++
+			b.WriteByte(s[i])
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: unterminated string", ErrInvalid)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,100 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+)
+
+func eq(field string, value string) Restriction {
+	return Restriction{Field: field, Op: OpEq, Values: []Value{{Kind: String, Text: value}}}
+}
+
+func TestParse(t *testing.T) {
+	tt := map[string]struct {
+		expr string
+		want Expr
+	}{
+		"restriction": {
+			expr: `country = "GR"`,
+			want: eq("country", "GR"),
+		},
+		"comparators and values": {
+			expr: `created_at >= "2024-01-01" AND updated_at != null AND status IN (active, 'pending')`,
+			want: And{Exprs: []Expr{
+				Restriction{Field: "created_at", Op: OpGe, Values: []Value{{Kind: String, Text: "2024-01-01"}}},
+				Restriction{Field: "updated_at", Op: OpNe, Values: []Value{{Kind: Text, Text: "null"}}},
+				Restriction{Field: "status", Op: OpIn, Values: []Value{{Kind: Text, Text: "active"}, {Kind: String, Text: "pending"}}},
+			}},
+		},
+		"has": {
+			expr: `nickname:"tony*" updated_at:*`,
+			want: And{Exprs: []Expr{
+				Restriction{Field: "nickname", Op: OpHas, Values: []Value{{Kind: String, Text: "tony*"}}},
+				Restriction{Field: "updated_at", Op: OpHas, Values: []Value{{Kind: Any, Text: "*"}}},
+			}},
+		},
+		"or binds tighter than and": {
+			expr: `a = "1" AND b = "2" OR c = "3"`,
+			want: And{Exprs: []Expr{
+				eq("a", "1"),
+				Or{Exprs: []Expr{eq("b", "2"), eq("c", "3")}},
+			}},
+		},
+		"parentheses and negation": {
+			expr: `NOT (a = "1" AND b = "2") -c = "3"`,
+			want: And{Exprs: []Expr{
+				Not{Expr: And{Exprs: []Expr{eq("a", "1"), eq("b", "2")}}},
+				Not{Expr: eq("c", "3")},
+			}},
+		},
+		"escaped quotes": {
+			expr: `nickname = "say \"hi\""`,
+			want: eq("nickname", `say "hi"`),
+		},
+		"negative number": {
+			expr: `level > -1`,
+			want: Restriction{Field: "level", Op: OpGt, Values: []Value{{Kind: Text, Text: "-1"}}},
+		},
+	}
+
+	for name, tc := range tt {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		`country`,
+		`country =`,
+		`country = "GR`,
+		`country == "GR"`,
+		`country ! "GR"`,
+		`(country = "GR"`,
+		`country = "GR")`,
+		`country = "GR" AND`,
+		`country = "GR" OR`,
+		`AND = "GR"`,
+		`country = *`,
+		`status IN active`,
+		`status IN (active`,
+		`status IN ()`,
+		strings.Repeat("(", maxDepth+1) + `a = "1"` + strings.Repeat(")", maxDepth+1),
+		strings.Repeat("NOT ", maxDepth+1) + `a = "1"`,
+		`a = "` + strings.Repeat("x", maxLength) + `"`,
+	} {
+		_, err := Parse(expr)
+		require.ErrorIs(t, err, ErrInvalid, expr)
+	}
+
+	e, err := Parse("  ")
+	require.NoError(t, err)
+	require.Nil(t, e)
+}
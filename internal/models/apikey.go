@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// Scopes that may be granted to an API key.
+const (
+	ScopeUsersRead       = "users:read"
+	ScopeUsersWrite      = "users:write"
+	ScopeUsersAdmin      = "users:admin"
+	ScopeGroupsRead      = "groups:read"
+	ScopeGroupsWrite     = "groups:write"
+	ScopeAPIKeysWrite    = "apikeys:write"
+	ScopeAuditRead       = "audit:read"
+	ScopeConsentsRead    = "consents:read"
+	ScopeConsentsWrite   = "consents:write"
+	ScopeSettingsRead    = "settings:read"
+	ScopeSettingsWrite   = "settings:write"
+	ScopeIdentitiesRead  = "identities:read"
+	ScopeIdentitiesWrite = "identities:write"
+)
+
+// Scopes lists every scope known to the service.
+var Scopes = []string{
+	ScopeUsersRead,
+	ScopeUsersWrite,
+	ScopeUsersAdmin,
+	ScopeGroupsRead,
+	ScopeGroupsWrite,
+	ScopeAPIKeysWrite,
+	ScopeAuditRead,
+	ScopeConsentsRead,
+	ScopeConsentsWrite,
+	ScopeSettingsRead,
+	ScopeSettingsWrite,
+	ScopeIdentitiesRead,
+	ScopeIdentitiesWrite,
+}
+
+// APIKey represents a credential issued to a service account. Only the hash of
+// the secret is stored; the prefix is used to look the key up.
+type APIKey struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	Hash       []byte
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// NewAPIKey contains information needed to issue a new APIKey.
+type NewAPIKey struct {
+	UserID    uuid.UUID
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// Principal identifies the caller of a request.
+type Principal struct {
+	UserID   uuid.UUID
+	APIKeyID uuid.UUID
+	Scopes   []string
+}
+
+// HasScope reports whether the principal has been granted the scope.
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
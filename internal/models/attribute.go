@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// AttributeType is the type of the values of a custom attribute.
+type AttributeType string
+
+const (
+	AttributeTypeString AttributeType = "string"
+	AttributeTypeInt    AttributeType = "int"
+	AttributeTypeBool   AttributeType = "bool"
+	// AttributeTypeDate values are dates formatted as YYYY-MM-DD.
+	AttributeTypeDate AttributeType = "date"
+	// AttributeTypeEnum values are strings out of a fixed list.
+	AttributeTypeEnum AttributeType = "enum"
+)
+
+// AttributeDateLayout is the format of date attribute values.
+const AttributeDateLayout = "2006-01-02"
+
+// AttributeDefinition describes a custom attribute users may have. Values of
+// string, date and enum attributes are strings, of int attributes int64 and
+// of bool attributes bool.
+type AttributeDefinition struct {
+	Name        string
+	Type        AttributeType
+	EnumValues  []string
+	Description string
+	// Indexed attributes can be used to filter users.
+	Indexed   bool
+	CreatedAt time.Time
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditActionUserCreated       = "user.created"
+	AuditActionUserUpdated       = "user.updated"
+	AuditActionUserStatusChanged = "user.status_changed"
+	AuditActionUserDeleted       = "user.deleted"
+	AuditActionUserRestored      = "user.restored"
+	AuditActionUserPurged        = "user.purged"
+	AuditActionUserErased        = "user.erased"
+	AuditActionUserDataExported  = "user.data_exported"
+	AuditActionContactAdded      = "user.contact_added"
+	AuditActionContactRemoved    = "user.contact_removed"
+	AuditActionContactVerified   = "user.contact_verified"
+	AuditActionIdentityLinked    = "user.identity_linked"
+	AuditActionIdentityUnlinked  = "user.identity_unlinked"
+)
+
+// AuditEntry records a single mutation of a user. Before and After only hold
+// the fields that changed, with secrets redacted.
+type AuditEntry struct {
+	ID        int64
+	UserID    uuid.UUID
+	Actor     string
+	Action    string
+	Before    map[string]any
+	After     map[string]any
+	RequestID string
+	ClientIP  string
+	CreatedAt time.Time
+}
+
+// GetAuditEntriesOptions filters the audit log. Entries are returned newest
+// first, starting after the entry with ID Cursor when it is set.
+type GetAuditEntriesOptions struct {
+	UserID uuid.UUID
+	Actor  string
+	Action string
+	From   *time.Time
+	To     *time.Time
+	Cursor int64
+	Limit  uint64
+}
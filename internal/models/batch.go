@@ -0,0 +1,22 @@
+package models
+
+import (
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// BatchUpdate is an item of a batch update.
+type BatchUpdate struct {
+	UserID uuid.UUID
+	Update UpdateUser
+}
+
+// BatchResult is the outcome of an item of a batch.
+type BatchResult struct {
+	// UserID is the user the item created, updated or deleted; it is not set
+	// for failed items.
+	UserID uuid.UUID
+	// Err is why the item failed. Items of an atomic batch that did not fail
+	// themselves fail with ErrBatchAborted when another one does.
+	Err error
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// UserChangeKind is what happened to a user.
+type UserChangeKind string
+
+const (
+	UserChangeCreated UserChangeKind = "created"
+	UserChangeUpdated UserChangeKind = "updated"
+	UserChangeDeleted UserChangeKind = "deleted"
+	// UserChangeCheckpoint carries no user: it moves the cursor of a watch
+	// past changes that did not match its filter.
+	UserChangeCheckpoint UserChangeKind = "checkpoint"
+)
+
+// ChangeCursor is the position of a change in the sequence of changes to users,
+// ordered by transaction and then by id.
+type ChangeCursor struct {
+	TxID      int64
+	ID        int64
+	ChangedAt time.Time
+}
+
+// UserChange is a change to a user, along with the user as it is now.
+type UserChange struct {
+	Cursor ChangeCursor
+	Kind   UserChangeKind
+	User   User
+}
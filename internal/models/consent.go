@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// ConsentStatus is the state of a consent for a purpose.
+type ConsentStatus string
+
+const (
+	ConsentStatusGranted   ConsentStatus = "granted"
+	ConsentStatusWithdrawn ConsentStatus = "withdrawn"
+)
+
+// Consent is one record of the consent history of a user for a purpose. The
+// latest record of a purpose holds its current status.
+type Consent struct {
+	ID            int64
+	UserID        uuid.UUID
+	Purpose       string
+	Status        ConsentStatus
+	Source        string
+	PolicyVersion string
+	RecordedAt    time.Time
+}
+
+// ConsentChange contains information needed to grant or withdraw a consent.
+type ConsentChange struct {
+	UserID        uuid.UUID
+	Purpose       string
+	Source        string
+	PolicyVersion string
+}
+
+// GetConsentsOptions defines the information may be provided to fetch the consents of a user.
+type GetConsentsOptions struct {
+	Purpose        string
+	IncludeHistory bool
+}
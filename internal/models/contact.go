@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// UserEmail is an email address of a user. The primary address is also the
+// Email of the user.
+type UserEmail struct {
+	UserID     uuid.UUID
+	Email      string
+	Primary    bool
+	VerifiedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// UserPhone is a phone number of a user, in E.164 format.
+type UserPhone struct {
+	UserID     uuid.UUID
+	Number     string
+	Primary    bool
+	VerifiedAt *time.Time
+	CreatedAt  time.Time
+}
@@ -5,6 +5,63 @@ import (
 )
 
 var (
-	ErrUserNotFound = errors.New("ErrUserNotFound")
-	ErrEmailTaken   = errors.New("ErrEmailTaken")
+	ErrUserNotFound       = errors.New("ErrUserNotFound")
+	ErrEmailTaken         = errors.New("ErrEmailTaken")
+	ErrGroupNotFound      = errors.New("ErrGroupNotFound")
+	ErrGroupNameTaken     = errors.New("ErrGroupNameTaken")
+	ErrAlreadyGroupMember = errors.New("ErrAlreadyGroupMember")
+	ErrNotGroupMember     = errors.New("ErrNotGroupMember")
+	ErrAPIKeyNotFound     = errors.New("ErrAPIKeyNotFound")
+	ErrInvalidAPIKey      = errors.New("ErrInvalidAPIKey")
+	ErrInvalidScope       = errors.New("ErrInvalidScope")
+	ErrScopeNotGranted    = errors.New("ErrScopeNotGranted")
+	ErrNotAPIKeyOwner     = errors.New("ErrNotAPIKeyOwner")
+	ErrNotServiceAccount  = errors.New("ErrNotServiceAccount")
+
+	ErrInvalidStatusTransition = errors.New("ErrInvalidStatusTransition")
+	ErrInvalidExportFormat     = errors.New("ErrInvalidExportFormat")
+	ErrInvalidConsentPurpose   = errors.New("ErrInvalidConsentPurpose")
+	ErrConsentNotGranted       = errors.New("ErrConsentNotGranted")
+	ErrInvalidSettingKey       = errors.New("ErrInvalidSettingKey")
+	ErrInvalidSettingValue     = errors.New("ErrInvalidSettingValue")
+	ErrSettingNotFound         = errors.New("ErrSettingNotFound")
+	ErrSettingsLimitExceeded   = errors.New("ErrSettingsLimitExceeded")
+	ErrAttributeNotFound       = errors.New("ErrAttributeNotFound")
+	ErrAttributeExists         = errors.New("ErrAttributeExists")
+	ErrInvalidAttribute        = errors.New("ErrInvalidAttribute")
+	ErrAttributeNotFilterable  = errors.New("ErrAttributeNotFilterable")
+	ErrInvalidEmail            = errors.New("ErrInvalidEmail")
+	ErrEmailNotFound           = errors.New("ErrEmailNotFound")
+	ErrInvalidPhone            = errors.New("ErrInvalidPhone")
+	ErrPhoneNotFound           = errors.New("ErrPhoneNotFound")
+	ErrPhoneTaken              = errors.New("ErrPhoneTaken")
+	ErrPrimaryContact          = errors.New("ErrPrimaryContact")
+	ErrInvalidIdentity         = errors.New("ErrInvalidIdentity")
+	ErrIdentityNotFound        = errors.New("ErrIdentityNotFound")
+	ErrIdentityLinked          = errors.New("ErrIdentityLinked")
+	ErrOAuthClientNotFound     = errors.New("ErrOAuthClientNotFound")
+	ErrInvalidOAuthClient      = errors.New("ErrInvalidOAuthClient")
+	ErrInvalidRedirectURI      = errors.New("ErrInvalidRedirectURI")
+	ErrInvalidAuthRequest      = errors.New("ErrInvalidAuthRequest")
+	ErrUnsupportedResponseType = errors.New("ErrUnsupportedResponseType")
+	ErrInvalidCredentials      = errors.New("ErrInvalidCredentials")
+	ErrInvalidClient           = errors.New("ErrInvalidClient")
+	ErrInvalidGrant            = errors.New("ErrInvalidGrant")
+	ErrUnsupportedGrantType    = errors.New("ErrUnsupportedGrantType")
+	ErrInvalidAccessToken      = errors.New("ErrInvalidAccessToken")
+	ErrInvalidFilter           = errors.New("ErrInvalidFilter")
+	ErrCursorUserNotFound      = errors.New("ErrCursorUserNotFound")
+	ErrInvalidSearchQuery      = errors.New("ErrInvalidSearchQuery")
+	ErrInvalidUserType         = errors.New("ErrInvalidUserType")
+	ErrDuplicateImportRow      = errors.New("ErrDuplicateImportRow")
+	ErrImportTooLarge          = errors.New("ErrImportTooLarge")
+	ErrBatchTooLarge           = errors.New("ErrBatchTooLarge")
+	ErrBatchAborted            = errors.New("ErrBatchAborted")
+	ErrResumeTokenExpired      = errors.New("ErrResumeTokenExpired")
+	ErrWatchLagging            = errors.New("ErrWatchLagging")
+	ErrInvalidStatsQuery       = errors.New("ErrInvalidStatsQuery")
+	ErrTooManyStatsGroups      = errors.New("ErrTooManyStatsGroups")
+	ErrInvalidIdempotencyKey   = errors.New("ErrInvalidIdempotencyKey")
+	ErrIdempotencyKeyReused    = errors.New("ErrIdempotencyKeyReused")
+	ErrIdempotencyKeyInUse     = errors.New("ErrIdempotencyKeyInUse")
 )
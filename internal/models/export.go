@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// ExportFormat is the encoding of a user data export.
+type ExportFormat string
+
+const (
+	ExportFormatJSON ExportFormat = "json"
+	ExportFormatZIP  ExportFormat = "zip"
+)
+
+// UserDataExport holds everything the service keeps about a user, as handed
+// out to the user on a data subject access request. Credentials, such as the
+// password hash or API key hashes, are never part of it.
+type UserDataExport struct {
+	ExportedAt time.Time            `json:"exported_at"`
+	Profile    ExportedProfile      `json:"profile"`
+	Emails     []ExportedEmail      `json:"emails"`
+	Phones     []ExportedPhone      `json:"phones"`
+	Identities []ExportedIdentity   `json:"identities"`
+	Groups     []ExportedGroup      `json:"groups"`
+	APIKeys    []ExportedAPIKey     `json:"api_keys"`
+	Consents   []ExportedConsent    `json:"consents"`
+	Settings   []ExportedSetting    `json:"settings"`
+	AuditLog   []ExportedAuditEntry `json:"audit_log"`
+}
+
+type ExportedProfile struct {
+	ID         uuid.UUID      `json:"id"`
+	Type       UserType       `json:"type"`
+	Status     UserStatus     `json:"status"`
+	Email      string         `json:"email"`
+	FirstName  string         `json:"first_name"`
+	LastName   string         `json:"last_name"`
+	Nickname   string         `json:"nickname"`
+	Country    string         `json:"country"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  *time.Time     `json:"updated_at,omitempty"`
+}
+
+type ExportedEmail struct {
+	Email      string     `json:"email"`
+	Primary    bool       `json:"primary"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ExportedPhone struct {
+	Number     string     `json:"number"`
+	Primary    bool       `json:"primary"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ExportedIdentity struct {
+	Provider string         `json:"provider"`
+	Subject  string         `json:"subject"`
+	Claims   map[string]any `json:"claims,omitempty"`
+	LinkedAt time.Time      `json:"linked_at"`
+}
+
+type ExportedGroup struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type ExportedAPIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ExportedConsent struct {
+	Purpose       string        `json:"purpose"`
+	Status        ConsentStatus `json:"status"`
+	Source        string        `json:"source,omitempty"`
+	PolicyVersion string        `json:"policy_version,omitempty"`
+	RecordedAt    time.Time     `json:"recorded_at"`
+}
+
+type ExportedSetting struct {
+	Namespace string          `json:"namespace"`
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	UpdatedAt *time.Time      `json:"updated_at,omitempty"`
+}
+
+type ExportedAuditEntry struct {
+	Actor     string         `json:"actor"`
+	Action    string         `json:"action"`
+	Before    map[string]any `json:"before,omitempty"`
+	After     map[string]any `json:"after,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	ClientIP  string         `json:"client_ip,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
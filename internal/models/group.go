@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// Group represents a named collection of users.
+type Group struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdateAt  *time.Time
+}
+
+// GroupMember represents the membership of a user in a group.
+type GroupMember struct {
+	GroupID  uuid.UUID
+	UserID   uuid.UUID
+	JoinedAt time.Time
+}
+
+// GetGroupMembersOptions defines the information may be provided to fetch the members of a group.
+type GetGroupMembersOptions struct {
+	PageNumber uint64
+	PageSize   uint64
+}
+
+// GetGroupsOptions defines the information may be provided to fetch groups.
+type GetGroupsOptions struct {
+	PageNumber uint64
+	PageSize   uint64
+	Name       string
+}
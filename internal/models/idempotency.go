@@ -0,0 +1,24 @@
+package models
+
+// IdempotentCall is a call made with an idempotency key, along with its
+// response once it completed.
+type IdempotentCall struct {
+	// Caller is the id of the user who made the call; keys of different
+	// callers never collide.
+	Caller string
+	Key    string
+	// Method is the full name of the gRPC method called.
+	Method string
+	// RequestHash identifies the request, so that a key cannot be reused for
+	// another one.
+	RequestHash []byte
+	// ResponseType is the full name of the protobuf message of Response. Both
+	// are empty while the call is in progress.
+	ResponseType string
+	Response     []byte
+}
+
+// Completed reports whether the call has a response to replay.
+func (c IdempotentCall) Completed() bool {
+	return c.ResponseType != ""
+}
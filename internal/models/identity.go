@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// ExternalIdentity links a user to its account at an external identity
+// provider, such as Google or GitHub.
+type ExternalIdentity struct {
+	UserID   uuid.UUID
+	Provider string
+	// Subject identifies the account at the provider, e.g. the sub claim of
+	// an OpenID Connect ID token.
+	Subject string
+	// Claims are the raw claims of the provider, as last seen when linking.
+	Claims   map[string]any
+	LinkedAt time.Time
+}
+
+// ExternalIdentityLookup identifies an account at an external identity
+// provider. Provision, when set, holds the profile of the user to create when
+// no user is linked to the account yet.
+type ExternalIdentityLookup struct {
+	Provider  string
+	Subject   string
+	Claims    map[string]any
+	Provision *NewUser
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// ImportUsersOptions configures a bulk import of users.
+type ImportUsersOptions struct {
+	// DryRun validates the rows and reports what would be done, without writing anything.
+	DryRun bool
+	// Upsert updates the users whose primary email matches a row, instead of
+	// failing the row.
+	Upsert bool
+}
+
+// ImportAction is what was done with a row of an import.
+type ImportAction string
+
+const (
+	ImportActionCreated ImportAction = "created"
+	ImportActionUpdated ImportAction = "updated"
+	ImportActionFailed  ImportAction = "failed"
+)
+
+// ImportResult is the outcome of a row of an import. In dry runs, it is the
+// outcome the row would have, and new users have no ID.
+type ImportResult struct {
+	// Row is the position of the row in the import, starting at 1.
+	Row    int
+	UserID uuid.UUID
+	Action ImportAction
+	// Err is why the row failed.
+	Err error
+}
+
+// EmailOwner is the live user holding an email address.
+type EmailOwner struct {
+	UserID uuid.UUID
+	// Primary tells whether the email is the primary email of the user.
+	Primary bool
+}
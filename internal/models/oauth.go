@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// OpenID Connect scopes a client may request. openid is required; the others
+// release the matching claims of the user.
+const (
+	OIDCScopeOpenID  = "openid"
+	OIDCScopeProfile = "profile"
+	OIDCScopeEmail   = "email"
+)
+
+// OIDCScopes lists every scope supported by the OpenID Connect provider.
+var OIDCScopes = []string{
+	OIDCScopeOpenID,
+	OIDCScopeProfile,
+	OIDCScopeEmail,
+}
+
+// OAuthClient is an application signing users in through the OpenID Connect
+// provider. Confidential clients authenticate with a secret, of which only the
+// hash is stored; public clients, such as mobile apps, have none and rely on
+// PKCE alone.
+type OAuthClient struct {
+	ID           uuid.UUID
+	Name         string
+	SecretHash   []byte
+	RedirectURIs []string
+	CreatedAt    time.Time
+}
+
+// Public reports whether the client has no secret.
+func (c OAuthClient) Public() bool {
+	return c.SecretHash == nil
+}
+
+// NewOAuthClient contains information needed to register a new OAuthClient.
+type NewOAuthClient struct {
+	Name         string
+	RedirectURIs []string
+	Public       bool
+}
+
+// AuthorizationRequest holds the parameters of an authorization code request.
+type AuthorizationRequest struct {
+	ClientID            string
+	RedirectURI         string
+	ResponseType        string
+	Scopes              []string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// AuthorizationCode is granted to a client once the user authenticated and
+// consented. Only the hash of the code is stored.
+type AuthorizationCode struct {
+	Hash          []byte
+	ClientID      uuid.UUID
+	UserID        uuid.UUID
+	RedirectURI   string
+	Scopes        []string
+	Nonce         string
+	CodeChallenge string
+	AuthTime      time.Time
+	ExpiresAt     time.Time
+}
+
+// TokenRequest holds the parameters of a request to the token endpoint.
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	ClientID     string
+	ClientSecret string
+	CodeVerifier string
+}
+
+// Tokens are issued in exchange for an authorization code.
+type Tokens struct {
+	AccessToken string
+	IDToken     string
+	ExpiresIn   time.Duration
+	Scopes      []string
+}
@@ -0,0 +1,19 @@
+package models
+
+// SearchUsersOptions defines a search of users by email, name or nickname.
+type SearchUsersOptions struct {
+	Query  string
+	Status UserStatus
+	Offset uint64
+	Limit  uint64
+}
+
+// UserMatch is a user found by a search.
+type UserMatch struct {
+	User User
+	// Score is the relevance of the user to the query, between 0 and 1.
+	Score float64
+	// Highlights are the matching fields, HTML escaped, with the matching part
+	// enclosed in <em> tags.
+	Highlights map[string]string
+}
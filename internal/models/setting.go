@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+)
+
+// Setting is a JSON value stored for a user under a namespaced key.
+type Setting struct {
+	UserID    uuid.UUID
+	Namespace string
+	Key       string
+	Value     json.RawMessage
+	// IsDefault reports that the user has not set the key and Value is the
+	// default of its namespace.
+	IsDefault bool
+	UpdatedAt *time.Time
+}
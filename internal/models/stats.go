@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// StatsDimension is a property users are counted by.
+type StatsDimension string
+
+const (
+	StatsByCountry StatsDimension = "country"
+	StatsByStatus  StatsDimension = "status"
+	StatsByType    StatsDimension = "type"
+	// StatsByVerified counts users by whether their primary email is verified.
+	StatsByVerified StatsDimension = "verified"
+)
+
+// StatsBucket is the period signups are counted by, in UTC.
+type StatsBucket string
+
+const (
+	StatsBucketNone StatsBucket = ""
+	StatsBucketDay  StatsBucket = "day"
+	// StatsBucketWeek starts on Mondays.
+	StatsBucketWeek  StatsBucket = "week"
+	StatsBucketMonth StatsBucket = "month"
+)
+
+// UserStatsOptions selects how live users are counted.
+type UserStatsOptions struct {
+	GroupBy []StatsDimension
+	Bucket  StatsBucket
+	// CreatedAfter and CreatedBefore restrict the count to users created
+	// within [CreatedAfter, CreatedBefore).
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// Grouped reports whether users are counted by the dimension.
+func (o UserStatsOptions) Grouped(d StatsDimension) bool {
+	for _, g := range o.GroupBy {
+		if g == d {
+			return true
+		}
+	}
+	return false
+}
+
+// UserStatsGroup is the number of users sharing the values of the dimensions
+// counted by; the others are left zero.
+type UserStatsGroup struct {
+	// BucketStart is the start of the period of the group, zero without
+	// bucket.
+	BucketStart time.Time
+	Country     string
+	Status      UserStatus
+	Type        UserType
+	Verified    bool
+	Users       int64
+}
+
+// UserStats are the counts of users, as they were at ComputedAt.
+type UserStats struct {
+	Groups     []UserStatsGroup
+	ComputedAt time.Time
+}
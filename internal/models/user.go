@@ -5,29 +5,63 @@ import (
 
 	// 3rd party
 	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/filter"
+)
+
+// UserType distinguishes human users from automated principals.
+type UserType string
+
+const (
+	UserTypeHuman          UserType = "human"
+	UserTypeServiceAccount UserType = "service_account"
+)
+
+// UserStatus is the lifecycle state of a user.
+type UserStatus string
+
+const (
+	UserStatusPending     UserStatus = "pending"
+	UserStatusActive      UserStatus = "active"
+	UserStatusSuspended   UserStatus = "suspended"
+	UserStatusDeactivated UserStatus = "deactivated"
 )
 
 // User represents a user.
 type User struct {
 	ID        uuid.UUID
+	Type      UserType
+	Status    UserStatus
 	Email     string
 	FirstName string
 	LastName  string
 	Nickname  string
 	Country   string
 	Password  []byte
-	CreatedAt time.Time
-	UpdateAt  *time.Time
+	// Attributes holds the values of custom attributes, keyed by attribute name.
+	Attributes map[string]any
+	CreatedAt  time.Time
+	UpdateAt   *time.Time
+	DeletedAt  *time.Time
 }
 
 // NewUser contains information needed to create a new User.
 type NewUser struct {
+	Type      UserType
 	Email     string
 	FirstName string
 	LastName  string
 	Nickname  string
 	Country   string
 	Password  string
+	// Passwordless users sign in through an external identity provider and
+	// are created without a password.
+	Passwordless bool
+	// Pending users are created pending activation rather than active.
+	Pending bool
+	// Attributes are validated against the attribute definitions.
+	Attributes map[string]any
 }
 
 // UpdateUser defines the information may be provided to modify an existing user.
@@ -38,15 +72,59 @@ type UpdateUser struct {
 	Nickname  string
 	Country   string
 	Password  string
+	// Attributes are set on the user; a nil value removes the attribute.
+	Attributes map[string]any
+}
+
+// UserCursor is the position of the last user of a page. Users sorted by
+// creation time resume after CreatedAt and ID; users sorted by other fields
+// resume after the user with ID, as it is sorted now.
+type UserCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// UserOrderField is a field users can be sorted by.
+type UserOrderField string
+
+const (
+	UserOrderEmail     UserOrderField = "email"
+	UserOrderCreatedAt UserOrderField = "created_at"
+	UserOrderLastName  UserOrderField = "last_name"
+	UserOrderCountry   UserOrderField = "country"
+)
+
+type UserOrder struct {
+	Field UserOrderField
+	Desc  bool
+}
+
+// UserCount is the number of users matching a filter.
+type UserCount struct {
+	Total uint64
+	// Estimated reports that Total is estimated rather than counted.
+	Estimated bool
 }
 
 // GetUsersOptions defines the information may be provided to fetch users.
 type GetUsersOptions struct {
 	PageNumber uint64
 	PageSize   uint64
-	Filter     struct {
+	// After returns the users following the cursor; PageNumber is ignored.
+	After *UserCursor
+	// OrderBy sorts the users, in order of precedence; by creation time when empty.
+	OrderBy        []UserOrder
+	IncludeDeleted bool
+	Filter         struct {
 		Country  string
 		Email    string
 		Nickname string
+		Status   UserStatus
+		// ConsentPurpose restricts the result to users currently consenting to the purpose.
+		ConsentPurpose string
+		// Attributes restricts the result to users with these values of indexed attributes.
+		Attributes map[string]any
+		// Expression restricts the result to users matching a filter expression.
+		Expression filter.Expr
 	}
 }
@@ -0,0 +1,226 @@
+// Package oidc signs and verifies the JSON Web Tokens issued by the OpenID
+// Connect provider and publishes the keys verifying them.
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// Algorithm is the JWS algorithm of every token, the one OpenID Connect
+// providers must support.
+const Algorithm = "RS256"
+
+const rsaKeyBits = 2048
+
+var ErrInvalidToken = errors.New("oidc: invalid token")
+
+// Signer signs tokens with an RSA key, identified by the thumbprint of its
+// public key.
+type Signer struct {
+	key   *rsa.PrivateKey
+	keyID string
+}
+
+func NewSigner(key *rsa.PrivateKey) *Signer {
+	return &Signer{
+		key:   key,
+		keyID: thumbprint(&key.PublicKey),
+	}
+}
+
+// LoadSigningKey reads a PEM encoded RSA private key, in PKCS #1 or PKCS #8
+// form. Without a file a new key is generated, and tokens no longer verify
+// once the process exits.
+func LoadSigningKey(path string) (*rsa.PrivateKey, error) {
+	if path == "" {
+		return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key file: %w", err)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("oidc: signing key file is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signing key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("oidc: signing key is not an RSA key")
+	}
+	return key, nil
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// Sign returns the compact serialization of a JWT holding the claims.
+func (s *Signer) Sign(claims any) (string, error) {
+	h, err := json.Marshal(header{Algorithm: Algorithm, Type: "JWT", KeyID: s.keyID})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("marshaling claims: %w", err)
+	}
+
+	signingInput := encodeSegment(h) + "." + encodeSegment(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + encodeSegment(sig), nil
+}
+
+// Verify checks the signature of the token and decodes its claims. Checking
+// the claims themselves is left to the caller.
+func (s *Signer) Verify(token string, claims any) error {
+	return Verify(token, s.JWKS(), claims)
+}
+
+// Verify checks the signature of the token against the key of the set it
+// names and decodes its claims.
+func Verify(token string, keys JSONWebKeySet, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrInvalidToken
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Algorithm != Algorithm {
+		return ErrInvalidToken
+	}
+
+	pub, err := keys.publicKey(h.KeyID)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+		return ErrInvalidToken
+	}
+
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// JSONWebKey is the public part of a signing key, as defined by RFC 7517.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS returns the key set verifying the tokens of the signer.
+func (s *Signer) JWKS() JSONWebKeySet {
+	return JSONWebKeySet{
+		Keys: []JSONWebKey{{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: Algorithm,
+			KeyID:     s.keyID,
+			Modulus:   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	}
+}
+
+func (ks JSONWebKeySet) publicKey(keyID string) (*rsa.PublicKey, error) {
+	for _, k := range ks.Keys {
+		if k.KeyID != keyID || k.KeyType != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.Modulus)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.Exponent)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("oidc: unknown key %q", keyID)
+}
+
+// thumbprint is the RFC 7638 thumbprint of the key.
+func thumbprint(pub *rsa.PublicKey) string {
+	// Members in lexicographic order, without whitespace.
+	b, _ := json.Marshal(struct {
+		E   string `json:"e"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+	}{
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+	})
+
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,66 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+)
+
+type testClaims struct {
+	Subject string `json:"sub"`
+	Nonce   string `json:"nonce"`
+}
+
+func newTestSigner(t *testing.T) *Signer {
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	require.NoError(t, err)
+	return NewSigner(key)
+}
+
+func TestSigner_SignVerify(t *testing.T) {
+	s := newTestSigner(t)
+
+	token, err := s.Sign(testClaims{Subject: "b3ce8fed", Nonce: "n-0S6_WzA2Mj"})
+	require.NoError(t, err)
+
+	var got testClaims
+	require.NoError(t, Verify(token, s.JWKS(), &got))
+	require.Equal(t, testClaims{Subject: "b3ce8fed", Nonce: "n-0S6_WzA2Mj"}, got)
+
+	t.Log("tampered payload")
+	{
+		parts := strings.Split(token, ".")
+		forged, err := newTestSigner(t).Sign(testClaims{Subject: "attacker"})
+		require.NoError(t, err)
+		parts[1] = strings.Split(forged, ".")[1]
+
+		err = s.Verify(strings.Join(parts, "."), &got)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+
+	t.Log("other key")
+	{
+		err := Verify(token, newTestSigner(t).JWKS(), &got)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+
+	t.Log("malformed")
+	{
+		err := s.Verify("not-a-token", &got)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+}
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	// Example of RFC 7636, appendix B.
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	require.True(t, VerifyCodeChallenge(verifier, challenge))
+	require.False(t, VerifyCodeChallenge(verifier+"x", challenge))
+	require.False(t, VerifyCodeChallenge("short", challenge))
+}
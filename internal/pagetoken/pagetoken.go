@@ -0,0 +1,96 @@
+// Package pagetoken encodes pagination cursors into opaque page tokens. Tokens
+// are signed, so clients cannot forge cursors, and carry a fingerprint of the
+// query, so a token cannot be used with a query other than the one it was
+// issued for.
+package pagetoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, were not signed
+// with the key of the Codec or were issued for another query.
+var ErrInvalidToken = errors.New("invalid page token")
+
+type payload struct {
+	Cursor json.RawMessage `json:"c"`
+	Query  []byte          `json:"q"`
+}
+
+// Codec signs and verifies page tokens with a secret key.
+type Codec struct {
+	key []byte
+}
+
+func NewCodec(key []byte) *Codec {
+	return &Codec{
+		key: key,
+	}
+}
+
+// Encode returns the token of the cursor for the query fingerprint.
+func (c *Codec) Encode(cursor any, query []byte) (string, error) {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	p, err := json.Marshal(payload{Cursor: b, Query: fingerprint(query)})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(p) + "." + base64.RawURLEncoding.EncodeToString(c.sign(p)), nil
+}
+
+// Decode verifies the token against the query fingerprint and decodes its
+// cursor.
+func (c *Codec) Decode(token string, query []byte, cursor any) error {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidToken
+	}
+
+	p, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, c.sign(p)) {
+		return ErrInvalidToken
+	}
+
+	var decoded payload
+	if err := json.Unmarshal(p, &decoded); err != nil {
+		return ErrInvalidToken
+	}
+
+	if !hmac.Equal(decoded.Query, fingerprint(query)) {
+		return ErrInvalidToken
+	}
+
+	if err := json.Unmarshal(decoded.Cursor, cursor); err != nil {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+func (c *Codec) sign(p []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(p)
+	return mac.Sum(nil)
+}
+
+// fingerprint returns a digest of the query, short enough to be kept in
+// tokens.
+func fingerprint(query []byte) []byte {
+	sum := sha256.Sum256(query)
+	return sum[:16]
+}
@@ -0,0 +1,54 @@
+package pagetoken
+
+import (
+	"strings"
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+)
+
+type testCursor struct {
+	ID    string `json:"id"`
+	Index int    `json:"index"`
+}
+
+func TestCodec(t *testing.T) {
+	c := NewCodec([]byte("0123456789abcdef0123456789abcdef"))
+	query := []byte(`country = "GR"`)
+
+	token, err := c.Encode(testCursor{ID: "b3ce8fed", Index: 42}, query)
+	require.NoError(t, err)
+
+	var got testCursor
+	require.NoError(t, c.Decode(token, query, &got))
+	require.Equal(t, testCursor{ID: "b3ce8fed", Index: 42}, got)
+
+	t.Log("other query")
+	{
+		require.ErrorIs(t, c.Decode(token, []byte(`country = "US"`), &got), ErrInvalidToken)
+	}
+
+	t.Log("other key")
+	{
+		other := NewCodec([]byte("fedcba9876543210fedcba9876543210"))
+		require.ErrorIs(t, other.Decode(token, query, &got), ErrInvalidToken)
+	}
+
+	t.Log("tampered cursor")
+	{
+		forged, err := NewCodec([]byte("fedcba9876543210fedcba9876543210")).Encode(testCursor{ID: "b3ce8fed", Index: 0}, query)
+		require.NoError(t, err)
+
+		payload, _, _ := strings.Cut(forged, ".")
+		_, signature, _ := strings.Cut(token, ".")
+		require.ErrorIs(t, c.Decode(payload+"."+signature, query, &got), ErrInvalidToken)
+	}
+
+	t.Log("malformed")
+	{
+		for _, token := range []string{"", "abc", "abc.def", "!!.!!"} {
+			require.ErrorIs(t, c.Decode(token, query, &got), ErrInvalidToken)
+		}
+	}
+}
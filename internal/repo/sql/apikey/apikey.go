@@ -0,0 +1,200 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const apiKeysTable = "api_keys"
+
+var apiKeyColumns = []string{
+	"id", "user_id", "name", "prefix", "hash", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at",
+}
+
+type Repository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewRepository(db *sql.DB, log *zap.SugaredLogger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: log,
+	}
+}
+
+func (r *Repository) InsertAPIKey(ctx context.Context, key models.APIKey) (uuid.UUID, error) {
+	query, args, err := pg.QueryBuilder().
+		Insert(apiKeysTable).
+		Columns("id", "user_id", "name", "prefix", "hash", "scopes", "expires_at", "created_at").
+		Values(key.ID, key.UserID, key.Name, key.Prefix, key.Hash, pq.Array(key.Scopes), key.ExpiresAt, key.CreatedAt).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var keyID uuid.UUID
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&keyID)
+	if err != nil {
+		if pg.IsForeignKeyViolation(err) {
+			return uuid.Nil, models.ErrUserNotFound
+		}
+		return uuid.Nil, err
+	}
+
+	return keyID, nil
+}
+
+// GetAPIKeyByPrefix returns the key with the prefix. The keys of users that are
+// not active, or are soft deleted, are not found.
+func (r *Repository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (models.APIKey, error) {
+	columns := make([]string, 0, len(apiKeyColumns))
+	for _, c := range apiKeyColumns {
+		columns = append(columns, "k."+c)
+	}
+
+	query, args, err := pg.QueryBuilder().
+		Select(columns...).
+		From(apiKeysTable+" k").
+		Join("users u ON u.id = k.user_id").
+		Where("k.prefix = ?", prefix).
+		Where("u.status = ?", models.UserStatusActive).
+		Where("u.deleted_at IS NULL").
+		ToSql()
+
+	if err != nil {
+		return models.APIKey{}, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	k, err := scanAPIKey(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.APIKey{}, models.ErrAPIKeyNotFound
+		}
+		return models.APIKey{}, err
+	}
+
+	return k, nil
+}
+
+func (r *Repository) GetAPIKeysByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	query, args, err := pg.QueryBuilder().
+		Select(apiKeyColumns...).
+		From(apiKeysTable).
+		Where("user_id = ?", userID).
+		OrderBy("created_at").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey marks the key as revoked. Revoking an already revoked key is a no-op.
+// When owner is set, the keys of other users are reported as not found.
+func (r *Repository) RevokeAPIKey(ctx context.Context, keyID uuid.UUID, owner *uuid.UUID, revokedAt time.Time) error {
+	qb := pg.QueryBuilder().
+		Update(apiKeysTable).
+		Set("revoked_at", sq.Expr("COALESCE(revoked_at, ?)", revokedAt)).
+		Where("id = ?", keyID)
+
+	if owner != nil {
+		qb = qb.Where("user_id = ?", *owner)
+	}
+
+	query, args, err := qb.ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return models.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error {
+	query, args, err := pg.QueryBuilder().
+		Update(apiKeysTable).
+		Set("last_used_at", usedAt).
+		Where("id = ?", keyID).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var k models.APIKey
+	err := row.Scan(
+		&k.ID,
+		&k.UserID,
+		&k.Name,
+		&k.Prefix,
+		&k.Hash,
+		pq.Array(&k.Scopes),
+		&k.ExpiresAt,
+		&k.LastUsedAt,
+		&k.RevokedAt,
+		&k.CreatedAt,
+	)
+	return k, err
+}
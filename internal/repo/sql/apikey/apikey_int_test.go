@@ -0,0 +1,142 @@
+package apikey
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	// 3rd party
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/dockertest"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/sqltest"
+)
+
+var (
+	testDB *sqltest.DB
+)
+
+func TestMain(m *testing.M) {
+	exitCode := run(m)
+	os.Exit(exitCode)
+}
+
+func run(m *testing.M) int {
+
+	envArgs := []string{
+		"POSTGRES_USER=db_user",
+		"POSTGRES_PASSWORD=db_pwd",
+		"POSTGRES_DB=db_test",
+	}
+
+	teardown, pgHost, err := dockertest.SetupPostgres(envArgs)
+	if err != nil {
+		panic(err)
+	}
+	defer teardown()
+
+	cfg := sql.Config{
+		Host:     pgHost,
+		DBName:   "db_test",
+		User:     "db_user",
+		Password: "db_pwd",
+	}
+	db, err := sql.NewDB(cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	err = sql.StatusCheck(context.TODO(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	testDB = &sqltest.DB{
+		Db: db,
+	}
+
+	err = testDB.RunMigrations("file://./../../../../migrations/sql")
+	if err != nil {
+		panic(err)
+	}
+
+	return m.Run()
+}
+
+func TestRepository_APIKeys(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	userID := uuid.New()
+	_, err := testDB.Db.Exec(
+		`INSERT INTO users (id, type, email, first_name, last_name, nickname, country) VALUES ($1, 'service_account', 'ci@mail.com', 'ci', 'bot', 'ci', 'GR')`,
+		userID,
+	)
+	require.NoError(t, err)
+
+	key := models.APIKey{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      "ci",
+		Prefix:    "0a1b2c3d4e5f",
+		Hash:      []byte{1, 2, 3},
+		Scopes:    []string{models.ScopeUsersRead, models.ScopeGroupsRead},
+		CreatedAt: time.Now(),
+	}
+
+	keyID, err := repo.InsertAPIKey(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, key.ID, keyID)
+
+	_, err = repo.InsertAPIKey(ctx, models.APIKey{ID: uuid.New(), UserID: uuid.New(), Prefix: "ffffffffffff", Hash: []byte{1}})
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+
+	got, err := repo.GetAPIKeyByPrefix(ctx, key.Prefix)
+	require.NoError(t, err)
+	require.Equal(t, key.Scopes, got.Scopes)
+	require.Equal(t, key.Hash, got.Hash)
+	require.Nil(t, got.LastUsedAt)
+
+	require.NoError(t, repo.TouchAPIKey(ctx, keyID, time.Now()))
+	other := uuid.New()
+	require.ErrorIs(t, repo.RevokeAPIKey(ctx, keyID, &other, time.Now()), models.ErrAPIKeyNotFound)
+	require.NoError(t, repo.RevokeAPIKey(ctx, keyID, &userID, time.Now()))
+	require.NoError(t, repo.RevokeAPIKey(ctx, keyID, nil, time.Now()))
+	require.ErrorIs(t, repo.RevokeAPIKey(ctx, uuid.New(), nil, time.Now()), models.ErrAPIKeyNotFound)
+
+	keys, err := repo.GetAPIKeysByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.NotNil(t, keys[0].LastUsedAt)
+	require.NotNil(t, keys[0].RevokedAt)
+
+	_, err = repo.GetAPIKeyByPrefix(ctx, "unknown")
+	require.ErrorIs(t, err, models.ErrAPIKeyNotFound)
+
+	t.Log("owner not active")
+	{
+		_, err := testDB.Db.Exec(`UPDATE users SET status = 'suspended' WHERE id = $1`, userID)
+		require.NoError(t, err)
+
+		_, err = repo.GetAPIKeyByPrefix(ctx, key.Prefix)
+		require.ErrorIs(t, err, models.ErrAPIKeyNotFound)
+	}
+
+	t.Log("owner soft deleted")
+	{
+		_, err := testDB.Db.Exec(`UPDATE users SET status = 'active', deleted_at = NOW() WHERE id = $1`, userID)
+		require.NoError(t, err)
+
+		_, err = repo.GetAPIKeyByPrefix(ctx, key.Prefix)
+		require.ErrorIs(t, err, models.ErrAPIKeyNotFound)
+	}
+}
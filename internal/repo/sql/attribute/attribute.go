@@ -0,0 +1,143 @@
+package attribute
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const (
+	attributeDefinitionsTable = "attribute_definitions"
+	usersTable                = "users"
+)
+
+var attributeColumns = []string{
+	"name", "type", "enum_values", "description", "indexed", "created_at",
+}
+
+type Repository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewRepository(db *sql.DB, log *zap.SugaredLogger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: log,
+	}
+}
+
+func (r *Repository) InsertAttributeDefinition(ctx context.Context, def models.AttributeDefinition) error {
+	enumValues := def.EnumValues
+	if enumValues == nil {
+		enumValues = []string{}
+	}
+
+	query, args, err := pg.QueryBuilder().
+		Insert(attributeDefinitionsTable).
+		Columns(attributeColumns...).
+		Values(def.Name, def.Type, pq.Array(enumValues), def.Description, def.Indexed, def.CreatedAt).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		if pg.IsUniqueViolation(err) {
+			return models.ErrAttributeExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetAttributeDefinitions returns every attribute definition ordered by name.
+func (r *Repository) GetAttributeDefinitions(ctx context.Context) ([]models.AttributeDefinition, error) {
+	query, args, err := pg.QueryBuilder().
+		Select(attributeColumns...).
+		From(attributeDefinitionsTable).
+		OrderBy("name").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var defs []models.AttributeDefinition
+	for rows.Next() {
+		var def models.AttributeDefinition
+		err := rows.Scan(&def.Name, &def.Type, pq.Array(&def.EnumValues), &def.Description, &def.Indexed, &def.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return defs, nil
+}
+
+// DeleteAttributeDefinition removes the definition together with the values
+// users hold for the attribute.
+func (r *Repository) DeleteAttributeDefinition(ctx context.Context, name string) error {
+	deleteQuery, deleteArgs, err := pg.QueryBuilder().
+		Delete(attributeDefinitionsTable).
+		Where("name = ?", name).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	// ?? escapes the jsonb key-exists operator from placeholder replacement.
+	updateQuery, updateArgs, err := pg.QueryBuilder().
+		Update(usersTable).
+		Set("attributes", sq.Expr("attributes - ?::text", name)).
+		Where("attributes ?? ?", name).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	return pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, deleteQuery, deleteArgs...)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if n == 0 {
+			return models.ErrAttributeNotFound
+		}
+
+		_, err = tx.ExecContext(ctx, updateQuery, updateArgs...)
+		return err
+	})
+}
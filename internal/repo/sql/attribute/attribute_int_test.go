@@ -0,0 +1,133 @@
+package attribute
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	// 3rd party
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/dockertest"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/sqltest"
+)
+
+var (
+	testDB *sqltest.DB
+)
+
+func TestMain(m *testing.M) {
+	exitCode := run(m)
+	os.Exit(exitCode)
+}
+
+func run(m *testing.M) int {
+
+	envArgs := []string{
+		"POSTGRES_USER=db_user",
+		"POSTGRES_PASSWORD=db_pwd",
+		"POSTGRES_DB=db_test",
+	}
+
+	teardown, pgHost, err := dockertest.SetupPostgres(envArgs)
+	if err != nil {
+		panic(err)
+	}
+	defer teardown()
+
+	cfg := sql.Config{
+		Host:     pgHost,
+		DBName:   "db_test",
+		User:     "db_user",
+		Password: "db_pwd",
+	}
+	db, err := sql.NewDB(cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	err = sql.StatusCheck(context.TODO(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	testDB = &sqltest.DB{
+		Db: db,
+	}
+
+	err = testDB.RunMigrations("file://./../../../../migrations/sql")
+	if err != nil {
+		panic(err)
+	}
+
+	return m.Run()
+}
+
+func insertTestUser(t *testing.T, email string) uuid.UUID {
+	userID := uuid.New()
+	_, err := testDB.Db.Exec(
+		`INSERT INTO users (id, email, first_name, last_name, nickname, country) VALUES ($1, $2, 'tony', 'papath', 'TonyPath', 'GR')`,
+		userID, email,
+	)
+	require.NoError(t, err)
+	return userID
+}
+
+func TestRepository_AttributeDefinitions(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	tier := models.AttributeDefinition{
+		Name:       "tier",
+		Type:       models.AttributeTypeEnum,
+		EnumValues: []string{"gold", "silver"},
+		Indexed:    true,
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+	level := models.AttributeDefinition{
+		Name:      "level",
+		Type:      models.AttributeTypeInt,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	require.NoError(t, repo.InsertAttributeDefinition(ctx, tier))
+	require.NoError(t, repo.InsertAttributeDefinition(ctx, level))
+
+	err := repo.InsertAttributeDefinition(ctx, tier)
+	require.ErrorIs(t, err, models.ErrAttributeExists)
+
+	got, err := repo.GetAttributeDefinitions(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, "level", got[0].Name)
+	require.Empty(t, got[0].EnumValues)
+	require.Equal(t, "tier", got[1].Name)
+	require.Equal(t, []string{"gold", "silver"}, got[1].EnumValues)
+	require.True(t, got[1].Indexed)
+
+	t.Log("delete removes the values of users")
+	{
+		userID := insertTestUser(t, "attributes@mail.com")
+		_, err := testDB.Db.Exec(`UPDATE users SET attributes = '{"tier": "gold", "level": 3}' WHERE id = $1`, userID)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.DeleteAttributeDefinition(ctx, "tier"))
+
+		var attributes string
+		err = testDB.Db.QueryRow(`SELECT attributes FROM users WHERE id = $1`, userID).Scan(&attributes)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"level": 3}`, attributes)
+	}
+
+	err = repo.DeleteAttributeDefinition(ctx, "tier")
+	require.ErrorIs(t, err, models.ErrAttributeNotFound)
+}
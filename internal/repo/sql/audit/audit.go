@@ -0,0 +1,334 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/crypto"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/datakey"
+)
+
+const auditLogTable = "audit_log"
+
+type Repository struct {
+	db     *sql.DB
+	keys   *datakey.Store
+	logger *zap.SugaredLogger
+}
+
+func NewRepository(db *sql.DB, keys *datakey.Store, log *zap.SugaredLogger) *Repository {
+	return &Repository{
+		db:     db,
+		keys:   keys,
+		logger: log,
+	}
+}
+
+// values is the encrypted part of an entry.
+type values struct {
+	Before map[string]any `json:"before,omitempty"`
+	After  map[string]any `json:"after,omitempty"`
+}
+
+// InsertEntries appends entries to the audit log within tx, so that they are
+// committed together with the mutation they describe. The changed values are
+// encrypted with the data key of the user they belong to.
+func InsertEntries(ctx context.Context, tx *sql.Tx, keys *datakey.Store, entries ...models.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var userIDs []uuid.UUID
+	for _, e := range entries {
+		if e.Before != nil || e.After != nil {
+			userIDs = append(userIDs, e.UserID)
+		}
+	}
+
+	dataKeys, err := keys.ForUsers(ctx, tx, userIDs)
+	if err != nil {
+		return fmt.Errorf("fetching data keys: %w", err)
+	}
+
+	qb := pg.QueryBuilder().
+		Insert(auditLogTable).
+		Columns("user_id", "actor", "action", "encrypted_values", "request_id", "client_ip", "created_at")
+
+	for _, e := range entries {
+		var encrypted any
+		if e.Before != nil || e.After != nil {
+			if encrypted, err = encryptValues(dataKeys[e.UserID], e.UserID, values{Before: e.Before, After: e.After}); err != nil {
+				return err
+			}
+		}
+
+		qb = qb.Values(e.UserID, e.Actor, e.Action, encrypted, e.RequestID, e.ClientIP, e.CreatedAt)
+	}
+
+	query, args, err := qb.ToSql()
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// RedactEntries clears the values of the user's entries that were written in
+// plaintext, before values were encrypted.
+func RedactEntries(ctx context.Context, tx *sql.Tx, userID uuid.UUID) error {
+	query, args, err := pg.QueryBuilder().
+		Update(auditLogTable).
+		Set("old_values", nil).
+		Set("new_values", nil).
+		Where("user_id = ?", userID).
+		Where("(old_values IS NOT NULL OR new_values IS NOT NULL)").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// ReencryptEntries encrypts the values of the user's entries again with the
+// new data key of the user.
+func ReencryptEntries(ctx context.Context, tx *sql.Tx, userID uuid.UUID, oldKey []byte, newKey []byte) error {
+	query, args, err := pg.QueryBuilder().
+		Select("id", "encrypted_values").
+		From(auditLogTable).
+		Where("user_id = ?", userID).
+		Where("encrypted_values IS NOT NULL").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	encrypted := make(map[int64][]byte)
+	for rows.Next() {
+		var (
+			id         int64
+			ciphertext []byte
+		)
+		if err := rows.Scan(&id, &ciphertext); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		encrypted[id] = ciphertext
+	}
+	_ = rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, ciphertext := range encrypted {
+		reencrypted, err := crypto.Reencrypt(oldKey, newKey, ciphertext, userID[:])
+		if err != nil {
+			return fmt.Errorf("encrypting values of audit entry %d again: %w", id, err)
+		}
+
+		updateQuery, updateArgs, err := pg.QueryBuilder().
+			Update(auditLogTable).
+			Set("encrypted_values", reencrypted).
+			Where("id = ?", id).
+			ToSql()
+
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, updateQuery, updateArgs...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InsertAuditEntry records an action that is not part of a user mutation.
+func (r *Repository) InsertAuditEntry(ctx context.Context, entry models.AuditEntry) error {
+	return pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		return InsertEntries(ctx, tx, r.keys, entry)
+	})
+}
+
+// GetAuditEntries returns the entries matching opts. The values of entries
+// whose data key has been destroyed are left empty.
+func (r *Repository) GetAuditEntries(ctx context.Context, opts models.GetAuditEntriesOptions) ([]models.AuditEntry, error) {
+	qb := pg.QueryBuilder().
+		Select("id", "user_id", "actor", "action", "old_values", "new_values", "encrypted_values", "request_id", "client_ip", "created_at").
+		From(auditLogTable).
+		OrderBy("id DESC").
+		Limit(opts.Limit)
+
+	if opts.UserID != uuid.Nil {
+		qb = qb.Where("user_id = ?", opts.UserID)
+	}
+
+	if opts.Actor != "" {
+		qb = qb.Where("actor = ?", opts.Actor)
+	}
+
+	if opts.Action != "" {
+		qb = qb.Where("action = ?", opts.Action)
+	}
+
+	if opts.From != nil {
+		qb = qb.Where("created_at >= ?", *opts.From)
+	}
+
+	if opts.To != nil {
+		qb = qb.Where("created_at < ?", *opts.To)
+	}
+
+	if opts.Cursor > 0 {
+		qb = qb.Where("id < ?", opts.Cursor)
+	}
+
+	query, args, err := qb.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	entries, encrypted, err := r.queryEntries(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []uuid.UUID
+	for i := range entries {
+		if encrypted[i] != nil {
+			userIDs = append(userIDs, entries[i].UserID)
+		}
+	}
+
+	keys, err := r.keys.Lookup(ctx, r.db, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		key, ok := keys[entries[i].UserID]
+		if encrypted[i] == nil || !ok {
+			continue
+		}
+
+		v, err := decryptValues(key, entries[i].UserID, encrypted[i])
+		if err != nil {
+			return nil, err
+		}
+		entries[i].Before, entries[i].After = v.Before, v.After
+	}
+
+	return entries, nil
+}
+
+// queryEntries returns the entries along with their encrypted values, if any.
+func (r *Repository) queryEntries(ctx context.Context, query string, args []any) ([]models.AuditEntry, [][]byte, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var (
+		entries   []models.AuditEntry
+		encrypted [][]byte
+	)
+	for rows.Next() {
+		var (
+			e          models.AuditEntry
+			before     []byte
+			after      []byte
+			ciphertext []byte
+		)
+		if err := rows.Scan(
+			&e.ID,
+			&e.UserID,
+			&e.Actor,
+			&e.Action,
+			&before,
+			&after,
+			&ciphertext,
+			&e.RequestID,
+			&e.ClientIP,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, nil, err
+		}
+
+		if e.Before, err = unmarshalValues(before); err != nil {
+			return nil, nil, err
+		}
+
+		if e.After, err = unmarshalValues(after); err != nil {
+			return nil, nil, err
+		}
+
+		entries = append(entries, e)
+		encrypted = append(encrypted, ciphertext)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return entries, encrypted, nil
+}
+
+// The user id is bound to the ciphertext so that values cannot be moved
+// between entries of different users.
+func encryptValues(key []byte, userID uuid.UUID, v values) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal audit values: %w", err)
+	}
+	return crypto.Encrypt(key, b, userID[:])
+}
+
+func decryptValues(key []byte, userID uuid.UUID, ciphertext []byte) (values, error) {
+	b, err := crypto.Decrypt(key, ciphertext, userID[:])
+	if err != nil {
+		return values{}, fmt.Errorf("could not decrypt audit values: %w", err)
+	}
+
+	var v values
+	if err := json.Unmarshal(b, &v); err != nil {
+		return values{}, fmt.Errorf("could not unmarshal audit values: %w", err)
+	}
+	return v, nil
+}
+
+// unmarshalValues decodes the plaintext values of entries written before
+// values were encrypted.
+func unmarshalValues(b []byte) (map[string]any, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("could not unmarshal audit values: %w", err)
+	}
+	return values, nil
+}
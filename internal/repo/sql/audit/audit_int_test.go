@@ -0,0 +1,146 @@
+package audit
+
+import (
+	"context"
+	dbsql "database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	// 3rd party
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/dockertest"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/sqltest"
+)
+
+var (
+	testDB   *sqltest.DB
+	dataKeys = sqltest.NewDataKeys()
+)
+
+func TestMain(m *testing.M) {
+	exitCode := run(m)
+	os.Exit(exitCode)
+}
+
+func run(m *testing.M) int {
+
+	envArgs := []string{
+		"POSTGRES_USER=db_user",
+		"POSTGRES_PASSWORD=db_pwd",
+		"POSTGRES_DB=db_test",
+	}
+
+	teardown, pgHost, err := dockertest.SetupPostgres(envArgs)
+	if err != nil {
+		panic(err)
+	}
+	defer teardown()
+
+	cfg := sql.Config{
+		Host:     pgHost,
+		DBName:   "db_test",
+		User:     "db_user",
+		Password: "db_pwd",
+	}
+	db, err := sql.NewDB(cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	err = sql.StatusCheck(context.TODO(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	testDB = &sqltest.DB{
+		Db: db,
+	}
+
+	err = testDB.RunMigrations("file://./../../../../migrations/sql")
+	if err != nil {
+		panic(err)
+	}
+
+	return m.Run()
+}
+
+func TestRepository_AuditEntries(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	userA, userB := uuid.New(), uuid.New()
+	for i, userID := range []uuid.UUID{userA, userB} {
+		_, err := testDB.Db.Exec(
+			`INSERT INTO users (id, email, first_name, last_name, nickname, country) VALUES ($1, $2, 'tony', 'papath', 'TonyPath', 'GR')`,
+			userID, fmt.Sprintf("antonis+%d@mail.com", i),
+		)
+		require.NoError(t, err)
+	}
+	start := time.Now().UTC().Add(-time.Hour)
+
+	entries := []models.AuditEntry{
+		{UserID: userA, Actor: "alice", Action: models.AuditActionUserCreated, After: map[string]any{"email": "a@mail.com"}, CreatedAt: start},
+		{UserID: userA, Actor: "bob", Action: models.AuditActionUserUpdated, Before: map[string]any{"email": "a@mail.com"}, After: map[string]any{"email": "b@mail.com"}, CreatedAt: start.Add(time.Minute)},
+		{UserID: userB, Actor: "alice", Action: models.AuditActionUserCreated, CreatedAt: start.Add(2 * time.Minute)},
+		{UserID: userA, Actor: "alice", Action: models.AuditActionUserDeleted, CreatedAt: start.Add(3 * time.Minute)},
+	}
+
+	err := sql.WithinTx(ctx, testDB.Db, func(tx *dbsql.Tx) error {
+		return InsertEntries(ctx, tx, dataKeys, entries...)
+	})
+	require.NoError(t, err)
+	testDB.RequireTotalRows(t, "audit_log", 4)
+	testDB.RequireTotalRows(t, "user_data_keys", 1)
+
+	t.Log("filter by user, newest first")
+	{
+		got, err := repo.GetAuditEntries(ctx, models.GetAuditEntriesOptions{UserID: userA, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		require.Equal(t, models.AuditActionUserDeleted, got[0].Action)
+		require.Equal(t, map[string]any{"email": "b@mail.com"}, got[1].After)
+		require.Nil(t, got[2].Before)
+	}
+
+	t.Log("filter by actor and action")
+	{
+		got, err := repo.GetAuditEntries(ctx, models.GetAuditEntriesOptions{
+			Actor:  "alice",
+			Action: models.AuditActionUserCreated,
+			Limit:  10,
+		})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+	}
+
+	t.Log("filter by time range")
+	{
+		from, to := start.Add(time.Minute), start.Add(3*time.Minute)
+		got, err := repo.GetAuditEntries(ctx, models.GetAuditEntriesOptions{From: &from, To: &to, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+	}
+
+	t.Log("cursor")
+	{
+		page, err := repo.GetAuditEntries(ctx, models.GetAuditEntriesOptions{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+
+		next, err := repo.GetAuditEntries(ctx, models.GetAuditEntriesOptions{Cursor: page[1].ID, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, next, 2)
+		require.Less(t, next[0].ID, page[1].ID)
+	}
+}
@@ -0,0 +1,129 @@
+package consent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const (
+	consentsTable = "consents"
+	usersTable    = "users"
+)
+
+var consentColumns = []string{
+	"id", "user_id", "purpose", "status", "source", "policy_version", "created_at",
+}
+
+type Repository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewRepository(db *sql.DB, log *zap.SugaredLogger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: log,
+	}
+}
+
+// InsertConsent appends the consent to the history of the user and returns it
+// as stored. Users that do not exist or have been deleted cannot consent.
+func (r *Repository) InsertConsent(ctx context.Context, consent models.Consent) (models.Consent, error) {
+	liveUser := sq.Select().
+		Column("?::uuid", consent.UserID).
+		Column("?", consent.Purpose).
+		Column("?", consent.Status).
+		Column("?", consent.Source).
+		Column("?", consent.PolicyVersion).
+		From(usersTable).
+		Where("id = ?", consent.UserID).
+		Where("deleted_at IS NULL")
+
+	query, args, err := pg.QueryBuilder().
+		Insert(consentsTable).
+		Columns("user_id", "purpose", "status", "source", "policy_version").
+		Select(liveUser).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+
+	if err != nil {
+		return models.Consent{}, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&consent.ID, &consent.RecordedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Consent{}, models.ErrUserNotFound
+		}
+		return models.Consent{}, err
+	}
+
+	return consent, nil
+}
+
+// GetConsents returns the current consent of the user for every purpose, or
+// the full history, newest first, when asked to.
+func (r *Repository) GetConsents(ctx context.Context, userID uuid.UUID, opts models.GetConsentsOptions) ([]models.Consent, error) {
+	qb := pg.QueryBuilder().
+		Select(consentColumns...).
+		From(consentsTable).
+		Where("user_id = ?", userID)
+
+	if opts.Purpose != "" {
+		qb = qb.Where("purpose = ?", opts.Purpose)
+	}
+
+	if opts.IncludeHistory {
+		qb = qb.OrderBy("id DESC")
+	} else {
+		qb = qb.Options("DISTINCT ON (purpose)").OrderBy("purpose", "id DESC")
+	}
+
+	query, args, err := qb.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var consents []models.Consent
+	for rows.Next() {
+		var c models.Consent
+		err := rows.Scan(
+			&c.ID,
+			&c.UserID,
+			&c.Purpose,
+			&c.Status,
+			&c.Source,
+			&c.PolicyVersion,
+			&c.RecordedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		consents = append(consents, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return consents, nil
+}
@@ -0,0 +1,123 @@
+package consent
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	// 3rd party
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/dockertest"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/sqltest"
+	sqlusers "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/user"
+)
+
+var (
+	testDB    *sqltest.DB
+	testUsers *sqlusers.Repository
+)
+
+func TestMain(m *testing.M) {
+	exitCode := run(m)
+	os.Exit(exitCode)
+}
+
+func run(m *testing.M) int {
+
+	envArgs := []string{
+		"POSTGRES_USER=db_user",
+		"POSTGRES_PASSWORD=db_pwd",
+		"POSTGRES_DB=db_test",
+	}
+
+	teardown, pgHost, err := dockertest.SetupPostgres(envArgs)
+	if err != nil {
+		panic(err)
+	}
+	defer teardown()
+
+	cfg := sql.Config{
+		Host:     pgHost,
+		DBName:   "db_test",
+		User:     "db_user",
+		Password: "db_pwd",
+	}
+	db, err := sql.NewDB(cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	err = sql.StatusCheck(context.TODO(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	testDB = &sqltest.DB{
+		Db: db,
+	}
+
+	err = testDB.RunMigrations("file://./../../../../migrations/sql")
+	if err != nil {
+		panic(err)
+	}
+
+	testUsers = sqlusers.NewRepository(db, sqltest.NewDataKeys(), sqltest.NewBlindIndex(), zap.NewNop().Sugar())
+
+	return m.Run()
+}
+
+func TestRepository_Consents(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	userID := dockertest.InsertUser(t, testUsers, "consent@mail.com")
+
+	record := func(purpose string, status models.ConsentStatus) models.Consent {
+		c, err := repo.InsertConsent(ctx, models.Consent{
+			UserID:        userID,
+			Purpose:       purpose,
+			Status:        status,
+			Source:        "signup_form",
+			PolicyVersion: "2023-01",
+		})
+		require.NoError(t, err)
+		require.NotZero(t, c.ID)
+		require.False(t, c.RecordedAt.IsZero())
+		return c
+	}
+
+	record("newsletter", models.ConsentStatusGranted)
+	record("profiling", models.ConsentStatusGranted)
+	withdrawn := record("newsletter", models.ConsentStatusWithdrawn)
+
+	_, err := repo.InsertConsent(ctx, models.Consent{UserID: uuid.New(), Purpose: "newsletter", Status: models.ConsentStatusGranted})
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+
+	t.Log("current")
+	{
+		got, err := repo.GetConsents(ctx, userID, models.GetConsentsOptions{})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.Equal(t, withdrawn, got[0])
+		require.Equal(t, "profiling", got[1].Purpose)
+		require.Equal(t, models.ConsentStatusGranted, got[1].Status)
+	}
+
+	t.Log("history")
+	{
+		got, err := repo.GetConsents(ctx, userID, models.GetConsentsOptions{Purpose: "newsletter", IncludeHistory: true})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.Equal(t, models.ConsentStatusWithdrawn, got[0].Status)
+		require.Equal(t, models.ConsentStatusGranted, got[1].Status)
+	}
+}
@@ -0,0 +1,272 @@
+package datakey
+
+import (
+	"context"
+	"fmt"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/crypto"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const userDataKeysTable = "user_data_keys"
+
+// Store keeps the data key of every user, wrapped by a master key of the keyring.
+type Store struct {
+	keyring *crypto.Keyring
+}
+
+func NewStore(keyring *crypto.Keyring) *Store {
+	return &Store{
+		keyring: keyring,
+	}
+}
+
+// ForUser returns the data key of the user, creating it on first use. A new
+// key must be created in the transaction that inserts the user.
+func (s *Store) ForUser(ctx context.Context, q pg.Querier, userID uuid.UUID) ([]byte, error) {
+	keys, err := s.ForUsers(ctx, q, []uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys[userID], nil
+}
+
+// ForUsers returns the data keys of the users, creating the missing ones in a
+// single statement. The keys are locked for share until the transaction ends,
+// so that they cannot be replaced while it writes with them.
+func (s *Store) ForUsers(ctx context.Context, q pg.Querier, userIDs []uuid.UUID) (map[uuid.UUID][]byte, error) {
+	keys, err := s.lookup(ctx, q, userIDs, "FOR SHARE")
+	if err != nil {
+		return nil, err
+	}
+
+	qb := pg.QueryBuilder().
+		Insert(userDataKeysTable).
+		Columns("user_id", "master_key_id", "wrapped_key").
+		Suffix("ON CONFLICT (user_id) DO NOTHING")
+
+	missing := make(map[uuid.UUID]bool)
+	for _, userID := range userIDs {
+		if _, ok := keys[userID]; ok || missing[userID] {
+			continue
+		}
+
+		_, wrapped, keyID, err := s.keyring.NewDataKey()
+		if err != nil {
+			return nil, err
+		}
+		qb = qb.Values(userID, keyID, wrapped)
+		missing[userID] = true
+	}
+
+	if len(missing) == 0 {
+		return keys, nil
+	}
+
+	insertQuery, insertArgs, err := qb.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	if _, err := q.ExecContext(ctx, insertQuery, insertArgs...); err != nil {
+		return nil, err
+	}
+
+	// A concurrent transaction may have created some keys first; read back whichever won.
+	return s.lookup(ctx, q, userIDs, "FOR SHARE")
+}
+
+// Lookup returns the data keys of the given users. Users whose key has been
+// destroyed are missing from the result.
+func (s *Store) Lookup(ctx context.Context, q pg.Querier, userIDs []uuid.UUID) (map[uuid.UUID][]byte, error) {
+	return s.lookup(ctx, q, userIDs, "")
+}
+
+// lookup returns the data keys of the given users, locking their rows as
+// the suffix asks.
+func (s *Store) lookup(ctx context.Context, q pg.Querier, userIDs []uuid.UUID, suffix string) (map[uuid.UUID][]byte, error) {
+	keys := make(map[uuid.UUID][]byte, len(userIDs))
+	if len(userIDs) == 0 {
+		return keys, nil
+	}
+
+	qb := pg.QueryBuilder().
+		Select("user_id", "master_key_id", "wrapped_key").
+		From(userDataKeysTable).
+		Where(sq.Eq{"user_id": userIDs})
+
+	if suffix != "" {
+		qb = qb.Suffix(suffix)
+	}
+
+	query, args, err := qb.ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var (
+			userID  uuid.UUID
+			keyID   string
+			wrapped []byte
+		)
+		if err := rows.Scan(&userID, &keyID, &wrapped); err != nil {
+			return nil, err
+		}
+
+		key, err := s.keyring.UnwrapDataKey(keyID, wrapped)
+		if err != nil {
+			return nil, fmt.Errorf("unwrapping data key of user %s: %w", userID, err)
+		}
+		keys[userID] = key
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Rewrap wraps up to limit data keys that are not wrapped by the current
+// master key with it, and returns how many were rewrapped. The data keys
+// themselves do not change, so nothing encrypted with them is affected.
+func (s *Store) Rewrap(ctx context.Context, q pg.Querier, limit uint64) (int, error) {
+	query, args, err := pg.QueryBuilder().
+		Select("user_id", "master_key_id", "wrapped_key").
+		From(userDataKeysTable).
+		Where("master_key_id <> ?", s.keyring.CurrentKeyID()).
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	type wrappedKey struct {
+		userID  uuid.UUID
+		keyID   string
+		wrapped []byte
+	}
+
+	var batch []wrappedKey
+	for rows.Next() {
+		var k wrappedKey
+		if err := rows.Scan(&k.userID, &k.keyID, &k.wrapped); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		batch = append(batch, k)
+	}
+	_ = rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, k := range batch {
+		key, err := s.keyring.UnwrapDataKey(k.keyID, k.wrapped)
+		if err != nil {
+			return 0, fmt.Errorf("unwrapping data key of user %s: %w", k.userID, err)
+		}
+
+		wrapped, err := s.keyring.WrapDataKey(key)
+		if err != nil {
+			return 0, err
+		}
+
+		updateQuery, updateArgs, err := pg.QueryBuilder().
+			Update(userDataKeysTable).
+			Set("master_key_id", s.keyring.CurrentKeyID()).
+			Set("wrapped_key", wrapped).
+			Where("user_id = ?", k.userID).
+			ToSql()
+
+		if err != nil {
+			return 0, fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := q.ExecContext(ctx, updateQuery, updateArgs...); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(batch), nil
+}
+
+// Replace gives the user a fresh data key, and returns the old and the new
+// key so that whatever was encrypted with the old one can be encrypted again
+// in the same transaction. The key is locked until the transaction ends; it
+// fails with models.ErrUserNotFound when the user has no data key.
+func (s *Store) Replace(ctx context.Context, q pg.Querier, userID uuid.UUID) ([]byte, []byte, error) {
+	keys, err := s.lookup(ctx, q, []uuid.UUID{userID}, "FOR UPDATE")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oldKey, ok := keys[userID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no data key for %s", models.ErrUserNotFound, userID)
+	}
+
+	newKey, wrapped, keyID, err := s.keyring.NewDataKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query, args, err := pg.QueryBuilder().
+		Update(userDataKeysTable).
+		Set("master_key_id", keyID).
+		Set("wrapped_key", wrapped).
+		Set("created_at", sq.Expr("NOW()")).
+		Where("user_id = ?", userID).
+		ToSql()
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	if _, err := q.ExecContext(ctx, query, args...); err != nil {
+		return nil, nil, err
+	}
+
+	return oldKey, newKey, nil
+}
+
+// Destroy deletes the data key of the user, making everything encrypted with
+// it unreadable.
+func (s *Store) Destroy(ctx context.Context, q pg.Querier, userID uuid.UUID) error {
+	query, args, err := pg.QueryBuilder().
+		Delete(userDataKeysTable).
+		Where("user_id = ?", userID).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	_, err = q.ExecContext(ctx, query, args...)
+	return err
+}
@@ -2,7 +2,10 @@ package sql
 
 import "github.com/lib/pq"
 
-const uniqueViolation = "23505"
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
 
 func IsUniqueViolation(err error) bool {
 	if pqerr, ok := err.(*pq.Error); ok && pqerr.Code == uniqueViolation {
@@ -10,3 +13,10 @@ func IsUniqueViolation(err error) bool {
 	}
 	return false
 }
+
+func IsForeignKeyViolation(err error) bool {
+	if pqerr, ok := err.(*pq.Error); ok && pqerr.Code == foreignKeyViolation {
+		return true
+	}
+	return false
+}
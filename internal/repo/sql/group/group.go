@@ -0,0 +1,314 @@
+package group
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const (
+	groupsTable       = "groups"
+	groupMembersTable = "group_members"
+	usersTable        = "users"
+)
+
+type Repository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewRepository(db *sql.DB, log *zap.SugaredLogger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: log,
+	}
+}
+
+func (r *Repository) InsertGroup(ctx context.Context, group models.Group) (uuid.UUID, error) {
+	query, args, err := pg.QueryBuilder().
+		Insert(groupsTable).
+		Columns("id", "name", "created_at").
+		Values(group.ID, group.Name, group.CreatedAt).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var groupID uuid.UUID
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&groupID)
+	if err != nil {
+		if pg.IsUniqueViolation(err) {
+			return uuid.Nil, models.ErrGroupNameTaken
+		}
+		return uuid.Nil, err
+	}
+
+	return groupID, nil
+}
+
+func (r *Repository) UpdateGroupName(ctx context.Context, groupID uuid.UUID, name string, updatedAt time.Time) error {
+	query, args, err := pg.QueryBuilder().
+		Update(groupsTable).
+		Set("name", name).
+		Set("updated_at", updatedAt).
+		Where("id = ?", groupID).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		if pg.IsUniqueViolation(err) {
+			return models.ErrGroupNameTaken
+		}
+		return err
+	}
+
+	return requireAffected(res, models.ErrGroupNotFound)
+}
+
+// DeleteGroup removes the group. Memberships are removed by the database on cascade.
+func (r *Repository) DeleteGroup(ctx context.Context, groupID uuid.UUID) error {
+	query, args, err := pg.QueryBuilder().
+		Delete(groupsTable).
+		Where("id = ?", groupID).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res, models.ErrGroupNotFound)
+}
+
+func (r *Repository) GetGroupByID(ctx context.Context, groupID uuid.UUID) (models.Group, error) {
+	query, args, err := pg.QueryBuilder().
+		Select("id", "name", "created_at", "updated_at").
+		From(groupsTable).
+		Where("id = ?", groupID).
+		ToSql()
+
+	if err != nil {
+		return models.Group{}, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var g models.Group
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&g.ID,
+		&g.Name,
+		&g.CreatedAt,
+		&g.UpdateAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Group{}, models.ErrGroupNotFound
+		}
+		return models.Group{}, err
+	}
+
+	return g, nil
+}
+
+// GetGroups returns the groups ordered by name. A non-empty opts.Name matches
+// the group name exactly.
+func (r *Repository) GetGroups(ctx context.Context, opts models.GetGroupsOptions) ([]models.Group, error) {
+	qb := pg.QueryBuilder().
+		Select("id", "name", "created_at", "updated_at").
+		From(groupsTable).
+		OrderBy("name").
+		Suffix("OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", (opts.PageNumber-1)*opts.PageSize, opts.PageSize)
+
+	if opts.Name != "" {
+		qb = qb.Where("name = ?", opts.Name)
+	}
+
+	query, args, err := qb.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var groups []models.Group
+	for rows.Next() {
+		var g models.Group
+		if err := rows.Scan(&g.ID, &g.Name, &g.CreatedAt, &g.UpdateAt); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// InsertMember adds the user to the group. Users that do not exist or have been
+// deleted cannot become members.
+func (r *Repository) InsertMember(ctx context.Context, member models.GroupMember) error {
+	liveUser := sq.Select().
+		Column("?::uuid", member.GroupID).
+		Column("?::uuid", member.UserID).
+		Column("?::timestamptz", member.JoinedAt).
+		From(usersTable).
+		Where("id = ?", member.UserID).
+		Where("deleted_at IS NULL")
+
+	query, args, err := pg.QueryBuilder().
+		Insert(groupMembersTable).
+		Columns("group_id", "user_id", "created_at").
+		Select(liveUser).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		switch {
+		case pg.IsUniqueViolation(err):
+			return models.ErrAlreadyGroupMember
+		case pg.IsForeignKeyViolation(err):
+			return models.ErrGroupNotFound
+		}
+		return err
+	}
+
+	return requireAffected(res, models.ErrUserNotFound)
+}
+
+func (r *Repository) DeleteMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+	query, args, err := pg.QueryBuilder().
+		Delete(groupMembersTable).
+		Where("group_id = ?", groupID).
+		Where("user_id = ?", userID).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res, models.ErrNotGroupMember)
+}
+
+func (r *Repository) GetMembers(ctx context.Context, groupID uuid.UUID, opts models.GetGroupMembersOptions) ([]models.GroupMember, error) {
+	query, args, err := pg.QueryBuilder().
+		Select("group_id", "user_id", "created_at").
+		From(groupMembersTable).
+		Where("group_id = ?", groupID).
+		OrderBy("created_at", "user_id").
+		Suffix("OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", (opts.PageNumber-1)*opts.PageSize, opts.PageSize).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var members []models.GroupMember
+	for rows.Next() {
+		var m models.GroupMember
+		if err := rows.Scan(&m.GroupID, &m.UserID, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *Repository) GetGroupsByUser(ctx context.Context, userID uuid.UUID) ([]models.Group, error) {
+	query, args, err := pg.QueryBuilder().
+		Select("g.id", "g.name", "g.created_at", "g.updated_at").
+		From(groupsTable+" g").
+		Join(groupMembersTable+" gm ON gm.group_id = g.id").
+		Where("gm.user_id = ?", userID).
+		OrderBy("g.name").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var groups []models.Group
+	for rows.Next() {
+		var g models.Group
+		if err := rows.Scan(&g.ID, &g.Name, &g.CreatedAt, &g.UpdateAt); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func requireAffected(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package group
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	// 3rd party
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/dockertest"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/sqltest"
+)
+
+var (
+	testDB *sqltest.DB
+)
+
+func TestMain(m *testing.M) {
+	exitCode := run(m)
+	os.Exit(exitCode)
+}
+
+func run(m *testing.M) int {
+
+	envArgs := []string{
+		"POSTGRES_USER=db_user",
+		"POSTGRES_PASSWORD=db_pwd",
+		"POSTGRES_DB=db_test",
+	}
+
+	teardown, pgHost, err := dockertest.SetupPostgres(envArgs)
+	if err != nil {
+		panic(err)
+	}
+	defer teardown()
+
+	cfg := sql.Config{
+		Host:     pgHost,
+		DBName:   "db_test",
+		User:     "db_user",
+		Password: "db_pwd",
+	}
+	db, err := sql.NewDB(cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	err = sql.StatusCheck(context.TODO(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	testDB = &sqltest.DB{
+		Db: db,
+	}
+
+	err = testDB.RunMigrations("file://./../../../../migrations/sql")
+	if err != nil {
+		panic(err)
+	}
+
+	return m.Run()
+}
+
+func insertTestUser(t *testing.T, email string) uuid.UUID {
+	userID := uuid.New()
+	_, err := testDB.Db.Exec(
+		`INSERT INTO users (id, email, first_name, last_name, nickname, country) VALUES ($1, $2, 'tony', 'papath', 'TonyPath', 'GR')`,
+		userID, email,
+	)
+	require.NoError(t, err)
+	return userID
+}
+
+func TestRepository_Groups(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	groupID, err := repo.InsertGroup(ctx, models.Group{
+		ID:        uuid.New(),
+		Name:      "admins",
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	_, err = repo.InsertGroup(ctx, models.Group{
+		ID:        uuid.New(),
+		Name:      "admins",
+		CreatedAt: time.Now(),
+	})
+	require.ErrorIs(t, err, models.ErrGroupNameTaken)
+
+	require.NoError(t, repo.UpdateGroupName(ctx, groupID, "operators", time.Now()))
+	require.ErrorIs(t, repo.UpdateGroupName(ctx, uuid.New(), "operators", time.Now()), models.ErrGroupNotFound)
+
+	group, err := repo.GetGroupByID(ctx, groupID)
+	require.NoError(t, err)
+	require.Equal(t, "operators", group.Name)
+
+	groups, err := repo.GetGroups(ctx, models.GetGroupsOptions{PageNumber: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+
+	groups, err = repo.GetGroups(ctx, models.GetGroupsOptions{PageNumber: 1, PageSize: 10, Name: "admins"})
+	require.NoError(t, err)
+	require.Empty(t, groups)
+
+	t.Log("members")
+	{
+		for _, email := range []string{"a@mail.com", "b@mail.com", "c@mail.com"} {
+			userID := insertTestUser(t, email)
+			require.NoError(t, repo.InsertMember(ctx, models.GroupMember{
+				GroupID:  groupID,
+				UserID:   userID,
+				JoinedAt: time.Now(),
+			}))
+		}
+		testDB.RequireTotalRows(t, "group_members", 3)
+
+		err := repo.InsertMember(ctx, models.GroupMember{GroupID: groupID, UserID: uuid.New(), JoinedAt: time.Now()})
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+
+		members, err := repo.GetMembers(ctx, groupID, models.GetGroupMembersOptions{PageNumber: 1, PageSize: 2})
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+
+		members, err = repo.GetMembers(ctx, groupID, models.GetGroupMembersOptions{PageNumber: 2, PageSize: 2})
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+
+		err = repo.InsertMember(ctx, members[0])
+		require.ErrorIs(t, err, models.ErrAlreadyGroupMember)
+
+		groups, err := repo.GetGroupsByUser(ctx, members[0].UserID)
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+
+		require.NoError(t, repo.DeleteMember(ctx, groupID, members[0].UserID))
+		require.ErrorIs(t, repo.DeleteMember(ctx, groupID, members[0].UserID), models.ErrNotGroupMember)
+		testDB.RequireTotalRows(t, "group_members", 2)
+	}
+
+	require.NoError(t, repo.DeleteGroup(ctx, groupID))
+	require.ErrorIs(t, repo.DeleteGroup(ctx, groupID), models.ErrGroupNotFound)
+	testDB.RequireTotalRows(t, "group_members", 0)
+}
@@ -0,0 +1,159 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	// 3rd party
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const idempotencyKeysTable = "idempotency_keys"
+
+type Repository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewRepository(db *sql.DB, log *zap.SugaredLogger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: log,
+	}
+}
+
+// ReserveCall records the call as in progress, locked until lockedUntil and
+// kept until expiresAt, unless its key is already used. A key is free when it
+// was never used, its call expired, or its call never completed and is no
+// longer locked. When the key is not free, the call holding it is returned
+// along with false.
+func (r *Repository) ReserveCall(ctx context.Context, call models.IdempotentCall, lockedUntil time.Time, expiresAt time.Time) (models.IdempotentCall, bool, error) {
+	insertQuery, insertArgs, err := pg.QueryBuilder().
+		Insert(idempotencyKeysTable).
+		Columns("caller", "idempotency_key", "method", "request_hash", "locked_until", "expires_at").
+		Values(call.Caller, call.Key, call.Method, call.RequestHash, lockedUntil, expiresAt).
+		Suffix(`ON CONFLICT (caller, idempotency_key) DO UPDATE SET
+			method = EXCLUDED.method, request_hash = EXCLUDED.request_hash, response_type = NULL, response = NULL,
+			locked_until = EXCLUDED.locked_until, expires_at = EXCLUDED.expires_at, created_at = NOW()
+			WHERE ` + idempotencyKeysTable + `.expires_at <= NOW()
+			OR (` + idempotencyKeysTable + `.response_type IS NULL AND ` + idempotencyKeysTable + `.locked_until <= NOW())
+			RETURNING TRUE`).
+		ToSql()
+
+	if err != nil {
+		return models.IdempotentCall{}, false, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var reserved bool
+	err = r.db.QueryRowContext(ctx, insertQuery, insertArgs...).Scan(&reserved)
+	if err == nil {
+		return models.IdempotentCall{}, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.IdempotentCall{}, false, err
+	}
+
+	selectQuery, selectArgs, err := pg.QueryBuilder().
+		Select("caller", "idempotency_key", "method", "request_hash", "COALESCE(response_type, '')", "response").
+		From(idempotencyKeysTable).
+		Where("caller = ?", call.Caller).
+		Where("idempotency_key = ?", call.Key).
+		ToSql()
+
+	if err != nil {
+		return models.IdempotentCall{}, false, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var held models.IdempotentCall
+	err = r.db.QueryRowContext(ctx, selectQuery, selectArgs...).
+		Scan(&held.Caller, &held.Key, &held.Method, &held.RequestHash, &held.ResponseType, &held.Response)
+	if err != nil {
+		// The call expired and was purged in between; a retry reserves
+		// the key.
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.IdempotentCall{}, false, models.ErrIdempotencyKeyInUse
+		}
+		return models.IdempotentCall{}, false, err
+	}
+
+	return held, false, nil
+}
+
+// CompleteCall records the response of the call.
+func (r *Repository) CompleteCall(ctx context.Context, call models.IdempotentCall) error {
+	query, args, err := pg.QueryBuilder().
+		Update(idempotencyKeysTable).
+		Set("response_type", call.ResponseType).
+		Set("response", call.Response).
+		Where("caller = ?", call.Caller).
+		Where("idempotency_key = ?", call.Key).
+		Where("request_hash = ?", call.RequestHash).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// ReleaseCall frees the key of a call in progress, so that it can be retried.
+func (r *Repository) ReleaseCall(ctx context.Context, call models.IdempotentCall) error {
+	query, args, err := pg.QueryBuilder().
+		Delete(idempotencyKeysTable).
+		Where("caller = ?", call.Caller).
+		Where("idempotency_key = ?", call.Key).
+		Where("request_hash = ?", call.RequestHash).
+		Where("response_type IS NULL").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// PurgeExpiredCalls removes the calls expired before the time.
+func (r *Repository) PurgeExpiredCalls(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	query, args, err := pg.QueryBuilder().
+		Delete(idempotencyKeysTable).
+		Where("expires_at < ?", expiredBefore).
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// DeleteCalls removes the calls made by the caller within tx, along with the
+// responses recorded for them.
+func DeleteCalls(ctx context.Context, tx *sql.Tx, caller string) error {
+	query, args, err := pg.QueryBuilder().
+		Delete(idempotencyKeysTable).
+		Where("caller = ?", caller).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	return err
+}
@@ -0,0 +1,168 @@
+package idempotency
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	// 3rd party
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/dockertest"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/sqltest"
+)
+
+var (
+	testDB *sqltest.DB
+)
+
+func TestMain(m *testing.M) {
+	exitCode := run(m)
+	os.Exit(exitCode)
+}
+
+func run(m *testing.M) int {
+
+	envArgs := []string{
+		"POSTGRES_USER=db_user",
+		"POSTGRES_PASSWORD=db_pwd",
+		"POSTGRES_DB=db_test",
+	}
+
+	teardown, pgHost, err := dockertest.SetupPostgres(envArgs)
+	if err != nil {
+		panic(err)
+	}
+	defer teardown()
+
+	cfg := sql.Config{
+		Host:     pgHost,
+		DBName:   "db_test",
+		User:     "db_user",
+		Password: "db_pwd",
+	}
+	db, err := sql.NewDB(cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	err = sql.StatusCheck(context.TODO(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	testDB = &sqltest.DB{
+		Db: db,
+	}
+
+	err = testDB.RunMigrations("file://./../../../../migrations/sql")
+	if err != nil {
+		panic(err)
+	}
+
+	return m.Run()
+}
+
+func TestRepository_Calls(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	ctx := context.TODO()
+	now := time.Now().UTC()
+
+	call := models.IdempotentCall{
+		Caller:      "b3ce8fed-d5e8-4583-8783-b95969b5bc0c",
+		Key:         "key-1",
+		Method:      "/services.user.User/CreateUser",
+		RequestHash: []byte("hash"),
+	}
+
+	_, reserved, err := repo.ReserveCall(ctx, call, now.Add(time.Minute), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	t.Log("in progress")
+	{
+		held, reserved, err := repo.ReserveCall(ctx, call, now.Add(time.Minute), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.False(t, reserved)
+		require.Equal(t, call, held)
+		require.False(t, held.Completed())
+	}
+
+	t.Log("completed")
+	{
+		completed := call
+		completed.ResponseType = "services.user.CreateUserResponse"
+		completed.Response = []byte("response")
+		require.NoError(t, repo.CompleteCall(ctx, completed))
+
+		held, reserved, err := repo.ReserveCall(ctx, call, now.Add(time.Minute), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.False(t, reserved)
+		require.Equal(t, completed, held)
+
+		// Completed calls are not released.
+		require.NoError(t, repo.ReleaseCall(ctx, call))
+		testDB.RequireTotalRows(t, idempotencyKeysTable, 1)
+	}
+
+	t.Log("other callers have their own keys")
+	{
+		other := call
+		other.Caller = "another"
+		_, reserved, err := repo.ReserveCall(ctx, other, now.Add(time.Minute), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		require.NoError(t, repo.ReleaseCall(ctx, other))
+		testDB.RequireTotalRows(t, idempotencyKeysTable, 1)
+	}
+
+	t.Log("abandoned calls are taken over")
+	{
+		abandoned := call
+		abandoned.Key = "key-2"
+		_, reserved, err := repo.ReserveCall(ctx, abandoned, now.Add(-time.Second), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		_, reserved, err = repo.ReserveCall(ctx, abandoned, now.Add(time.Minute), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.True(t, reserved)
+	}
+
+	t.Log("expired calls are taken over, then purged")
+	{
+		expired := call
+		expired.Key = "key-3"
+		_, reserved, err := repo.ReserveCall(ctx, expired, now.Add(time.Minute), now.Add(-time.Second))
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		n, err := repo.PurgeExpiredCalls(ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+		testDB.RequireTotalRows(t, idempotencyKeysTable, 2)
+	}
+	t.Log("deleted with their caller")
+	{
+		other := call
+		other.Caller = "another"
+		_, reserved, err := repo.ReserveCall(ctx, other, now.Add(time.Minute), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		tx, err := testDB.Db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		require.NoError(t, DeleteCalls(ctx, tx, call.Caller))
+		require.NoError(t, tx.Commit())
+		testDB.RequireTotalRows(t, idempotencyKeysTable, 1)
+	}
+}
@@ -0,0 +1,338 @@
+package identity
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/crypto"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	auditlog "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/datakey"
+)
+
+const (
+	externalIdentitiesTable = "external_identities"
+	usersTable              = "users"
+)
+
+type Repository struct {
+	db     *sql.DB
+	keys   *datakey.Store
+	logger *zap.SugaredLogger
+}
+
+func NewRepository(db *sql.DB, keys *datakey.Store, log *zap.SugaredLogger) *Repository {
+	return &Repository{
+		db:     db,
+		keys:   keys,
+		logger: log,
+	}
+}
+
+// InsertIdentity links the external identity to the user, which must exist
+// and not be deleted. An identity is linked to a single user.
+func (r *Repository) InsertIdentity(ctx context.Context, identity models.ExternalIdentity) error {
+	err := pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		key, err := r.keys.ForUser(ctx, tx, identity.UserID)
+		if err != nil {
+			return err
+		}
+
+		claims, err := encryptClaims(key, identity.UserID, identity.Claims)
+		if err != nil {
+			return err
+		}
+
+		liveUser := sq.Select().
+			Column("?", identity.Provider).
+			Column("?", identity.Subject).
+			Column("id").
+			Column("?::bytea", claims).
+			Column("?::timestamptz", identity.LinkedAt).
+			From(usersTable).
+			Where("id = ?", identity.UserID).
+			Where("deleted_at IS NULL")
+
+		query, args, err := pg.QueryBuilder().
+			Insert(externalIdentitiesTable).
+			Columns("provider", "subject", "user_id", "claims_ciphertext", "linked_at").
+			Select(liveUser).
+			ToSql()
+
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if n == 0 {
+			return models.ErrUserNotFound
+		}
+
+		entry := audit.NewEntry(ctx, identity.UserID, models.AuditActionIdentityLinked, nil, map[string]any{
+			"provider": identity.Provider,
+			"subject":  identity.Subject,
+		})
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
+
+	if err != nil {
+		if pg.IsUniqueViolation(err) {
+			return models.ErrIdentityLinked
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteIdentity(ctx context.Context, userID uuid.UUID, provider string, subject string) error {
+	query, args, err := pg.QueryBuilder().
+		Delete(externalIdentitiesTable).
+		Where("provider = ?", provider).
+		Where("subject = ?", subject).
+		Where("user_id = ?", userID).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	return pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if n == 0 {
+			return models.ErrIdentityNotFound
+		}
+
+		entry := audit.NewEntry(ctx, userID, models.AuditActionIdentityUnlinked, map[string]any{
+			"provider": provider,
+			"subject":  subject,
+		}, nil)
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
+}
+
+// GetUserIDByIdentity returns the id of the user the external identity is
+// linked to. It fails with ErrUserNotFound when that user is soft deleted.
+func (r *Repository) GetUserIDByIdentity(ctx context.Context, provider string, subject string) (uuid.UUID, error) {
+	query, args, err := pg.QueryBuilder().
+		Select("e.user_id", "u.deleted_at IS NOT NULL").
+		From(externalIdentitiesTable+" e").
+		Join(usersTable+" u ON u.id = e.user_id").
+		Where("e.provider = ?", provider).
+		Where("e.subject = ?", subject).
+		ToSql()
+
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var (
+		userID  uuid.UUID
+		deleted bool
+	)
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&userID, &deleted); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, models.ErrIdentityNotFound
+		}
+		return uuid.Nil, err
+	}
+
+	if deleted {
+		return uuid.Nil, models.ErrUserNotFound
+	}
+
+	return userID, nil
+}
+
+// GetIdentities returns the external identities of the user, oldest first.
+func (r *Repository) GetIdentities(ctx context.Context, userID uuid.UUID) ([]models.ExternalIdentity, error) {
+	query, args, err := pg.QueryBuilder().
+		Select("provider", "subject", "claims_ciphertext", "linked_at").
+		From(externalIdentitiesTable).
+		Where("user_id = ?", userID).
+		OrderBy("linked_at", "provider", "subject").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var (
+		identities []models.ExternalIdentity
+		claims     [][]byte
+	)
+	for rows.Next() {
+		var (
+			identity   models.ExternalIdentity
+			ciphertext []byte
+		)
+		if err := rows.Scan(&identity.Provider, &identity.Subject, &ciphertext, &identity.LinkedAt); err != nil {
+			return nil, err
+		}
+		identity.UserID = userID
+		identities = append(identities, identity)
+		claims = append(claims, ciphertext)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(identities) == 0 {
+		return nil, nil
+	}
+
+	keys, err := r.keys.Lookup(ctx, r.db, []uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := keys[userID]
+	if !ok {
+		return nil, fmt.Errorf("missing data key of user %s", userID)
+	}
+
+	for i, ciphertext := range claims {
+		if identities[i].Claims, err = decryptClaims(key, userID, ciphertext); err != nil {
+			return nil, err
+		}
+	}
+
+	return identities, nil
+}
+
+// ReencryptClaims encrypts the claims of the user's identities again with the
+// new data key of the user.
+func ReencryptClaims(ctx context.Context, tx *sql.Tx, userID uuid.UUID, oldKey []byte, newKey []byte) error {
+	query, args, err := pg.QueryBuilder().
+		Select("provider", "subject", "claims_ciphertext").
+		From(externalIdentitiesTable).
+		Where("user_id = ?", userID).
+		Where("claims_ciphertext IS NOT NULL").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	type claimsRow struct {
+		provider   string
+		subject    string
+		ciphertext []byte
+	}
+
+	var claims []claimsRow
+	for rows.Next() {
+		var c claimsRow
+		if err := rows.Scan(&c.provider, &c.subject, &c.ciphertext); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		claims = append(claims, c)
+	}
+	_ = rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range claims {
+		reencrypted, err := crypto.Reencrypt(oldKey, newKey, c.ciphertext, claimsAAD(userID))
+		if err != nil {
+			return fmt.Errorf("could not encrypt claims again: %w", err)
+		}
+
+		updateQuery, updateArgs, err := pg.QueryBuilder().
+			Update(externalIdentitiesTable).
+			Set("claims_ciphertext", reencrypted).
+			Where("provider = ?", c.provider).
+			Where("subject = ?", c.subject).
+			ToSql()
+
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, updateQuery, updateArgs...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func claimsAAD(userID uuid.UUID) []byte {
+	return append(userID[:], "claims"...)
+}
+
+func encryptClaims(key []byte, userID uuid.UUID, claims map[string]any) ([]byte, error) {
+	if len(claims) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal claims: %w", err)
+	}
+
+	return crypto.Encrypt(key, b, claimsAAD(userID))
+}
+
+func decryptClaims(key []byte, userID uuid.UUID, ciphertext []byte) (map[string]any, error) {
+	if ciphertext == nil {
+		return nil, nil
+	}
+
+	b, err := crypto.Decrypt(key, ciphertext, claimsAAD(userID))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt claims: %w", err)
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return nil, fmt.Errorf("could not unmarshal claims: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,147 @@
+package identity
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	// 3rd party
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/dockertest"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/sqltest"
+)
+
+var (
+	testDB   *sqltest.DB
+	dataKeys = sqltest.NewDataKeys()
+)
+
+func TestMain(m *testing.M) {
+	exitCode := run(m)
+	os.Exit(exitCode)
+}
+
+func run(m *testing.M) int {
+
+	envArgs := []string{
+		"POSTGRES_USER=db_user",
+		"POSTGRES_PASSWORD=db_pwd",
+		"POSTGRES_DB=db_test",
+	}
+
+	teardown, pgHost, err := dockertest.SetupPostgres(envArgs)
+	if err != nil {
+		panic(err)
+	}
+	defer teardown()
+
+	cfg := sql.Config{
+		Host:     pgHost,
+		DBName:   "db_test",
+		User:     "db_user",
+		Password: "db_pwd",
+	}
+	db, err := sql.NewDB(cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	err = sql.StatusCheck(context.TODO(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	testDB = &sqltest.DB{
+		Db: db,
+	}
+
+	err = testDB.RunMigrations("file://./../../../../migrations/sql")
+	if err != nil {
+		panic(err)
+	}
+
+	return m.Run()
+}
+
+func insertTestUser(t *testing.T, email string) uuid.UUID {
+	userID := uuid.New()
+	_, err := testDB.Db.Exec(
+		`INSERT INTO users (id, email, first_name, last_name, nickname, country) VALUES ($1, $2, 'tony', 'papath', 'TonyPath', 'GR')`,
+		userID, email,
+	)
+	require.NoError(t, err)
+	return userID
+}
+
+func TestRepository_Identities(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	userID := insertTestUser(t, "identities@mail.com")
+	identity := models.ExternalIdentity{
+		UserID:   userID,
+		Provider: "google",
+		Subject:  "108542",
+		Claims:   map[string]any{"email": "identities@mail.com", "email_verified": true},
+		LinkedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	require.NoError(t, repo.InsertIdentity(ctx, identity))
+
+	err := repo.InsertIdentity(ctx, models.ExternalIdentity{UserID: insertTestUser(t, "other@mail.com"), Provider: "google", Subject: "108542"})
+	require.ErrorIs(t, err, models.ErrIdentityLinked)
+
+	err = repo.InsertIdentity(ctx, models.ExternalIdentity{UserID: uuid.New(), Provider: "github", Subject: "1"})
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+
+	gotID, err := repo.GetUserIDByIdentity(ctx, "google", "108542")
+	require.NoError(t, err)
+	require.Equal(t, userID, gotID)
+
+	_, err = repo.GetUserIDByIdentity(ctx, "github", "108542")
+	require.ErrorIs(t, err, models.ErrIdentityNotFound)
+
+	got, err := repo.GetIdentities(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, identity, got[0])
+
+	t.Log("claims are stored encrypted")
+	{
+		var ciphertext []byte
+		err := testDB.Db.QueryRow(`SELECT claims_ciphertext FROM external_identities WHERE user_id = $1`, userID).Scan(&ciphertext)
+		require.NoError(t, err)
+		require.NotContains(t, string(ciphertext), "identities@mail.com")
+	}
+
+	t.Log("identities of deleted users do not resolve")
+	{
+		_, err := testDB.Db.Exec(`UPDATE users SET deleted_at = now() WHERE id = $1`, userID)
+		require.NoError(t, err)
+
+		_, err = repo.GetUserIDByIdentity(ctx, "google", "108542")
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+
+		_, err = testDB.Db.Exec(`UPDATE users SET deleted_at = NULL WHERE id = $1`, userID)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, repo.DeleteIdentity(ctx, userID, "google", "108542"))
+
+	err = repo.DeleteIdentity(ctx, userID, "google", "108542")
+	require.ErrorIs(t, err, models.ErrIdentityNotFound)
+
+	got, err = repo.GetIdentities(ctx, userID)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
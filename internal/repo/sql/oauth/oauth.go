@@ -0,0 +1,231 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const (
+	oauthClientsTable       = "oauth_clients"
+	authorizationCodesTable = "oauth_authorization_codes"
+)
+
+var oauthClientColumns = []string{
+	"id", "name", "secret_hash", "redirect_uris", "created_at",
+}
+
+type Repository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewRepository(db *sql.DB, log *zap.SugaredLogger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: log,
+	}
+}
+
+func (r *Repository) InsertClient(ctx context.Context, client models.OAuthClient) error {
+	query, args, err := pg.QueryBuilder().
+		Insert(oauthClientsTable).
+		Columns(oauthClientColumns...).
+		Values(client.ID, client.Name, client.SecretHash, pq.Array(client.RedirectURIs), client.CreatedAt).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *Repository) GetClient(ctx context.Context, clientID uuid.UUID) (models.OAuthClient, error) {
+	query, args, err := pg.QueryBuilder().
+		Select(oauthClientColumns...).
+		From(oauthClientsTable).
+		Where("id = ?", clientID).
+		ToSql()
+
+	if err != nil {
+		return models.OAuthClient{}, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	c, err := scanClient(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OAuthClient{}, models.ErrOAuthClientNotFound
+		}
+		return models.OAuthClient{}, err
+	}
+
+	return c, nil
+}
+
+func (r *Repository) GetClients(ctx context.Context) ([]models.OAuthClient, error) {
+	query, args, err := pg.QueryBuilder().
+		Select(oauthClientColumns...).
+		From(oauthClientsTable).
+		OrderBy("created_at", "id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var clients []models.OAuthClient
+	for rows.Next() {
+		c, err := scanClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+// DeleteClient removes the client along with its pending authorization codes.
+// Tokens already issued to it stay valid until they expire.
+func (r *Repository) DeleteClient(ctx context.Context, clientID uuid.UUID) error {
+	query, args, err := pg.QueryBuilder().
+		Delete(oauthClientsTable).
+		Where("id = ?", clientID).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return models.ErrOAuthClientNotFound
+	}
+
+	return nil
+}
+
+// InsertAuthorizationCode stores the code, dropping the codes that expired
+// without being exchanged on the way.
+func (r *Repository) InsertAuthorizationCode(ctx context.Context, code models.AuthorizationCode) error {
+	query, args, err := pg.QueryBuilder().
+		Insert(authorizationCodesTable).
+		Columns("code_hash", "client_id", "user_id", "redirect_uri", "scopes", "nonce", "code_challenge", "auth_time", "expires_at").
+		Values(code.Hash, code.ClientID, code.UserID, code.RedirectURI, pq.Array(code.Scopes), code.Nonce, code.CodeChallenge, code.AuthTime, code.ExpiresAt).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	cleanupQuery, cleanupArgs, err := pg.QueryBuilder().
+		Delete(authorizationCodesTable).
+		Where("expires_at < ?", code.AuthTime).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	err = pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, cleanupQuery, cleanupArgs...); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	})
+
+	if err != nil {
+		if pg.IsForeignKeyViolation(err) {
+			return models.ErrOAuthClientNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ConsumeAuthorizationCode deletes the code and returns it, so that a code is
+// exchanged at most once. Unknown codes are reported as models.ErrInvalidGrant;
+// checking expiry is left to the caller.
+func (r *Repository) ConsumeAuthorizationCode(ctx context.Context, hash []byte) (models.AuthorizationCode, error) {
+	query, args, err := pg.QueryBuilder().
+		Delete(authorizationCodesTable).
+		Where("code_hash = ?", hash).
+		Suffix("RETURNING code_hash, client_id, user_id, redirect_uri, scopes, nonce, code_challenge, auth_time, expires_at").
+		ToSql()
+
+	if err != nil {
+		return models.AuthorizationCode{}, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var c models.AuthorizationCode
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&c.Hash,
+		&c.ClientID,
+		&c.UserID,
+		&c.RedirectURI,
+		pq.Array(&c.Scopes),
+		&c.Nonce,
+		&c.CodeChallenge,
+		&c.AuthTime,
+		&c.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AuthorizationCode{}, models.ErrInvalidGrant
+		}
+		return models.AuthorizationCode{}, err
+	}
+
+	return c, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanClient(row rowScanner) (models.OAuthClient, error) {
+	var c models.OAuthClient
+	err := row.Scan(
+		&c.ID,
+		&c.Name,
+		&c.SecretHash,
+		pq.Array(&c.RedirectURIs),
+		&c.CreatedAt,
+	)
+	return c, err
+}
@@ -0,0 +1,174 @@
+package oauth
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	// 3rd party
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/dockertest"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/sqltest"
+)
+
+var (
+	testDB *sqltest.DB
+)
+
+func TestMain(m *testing.M) {
+	exitCode := run(m)
+	os.Exit(exitCode)
+}
+
+func run(m *testing.M) int {
+
+	envArgs := []string{
+		"POSTGRES_USER=db_user",
+		"POSTGRES_PASSWORD=db_pwd",
+		"POSTGRES_DB=db_test",
+	}
+
+	teardown, pgHost, err := dockertest.SetupPostgres(envArgs)
+	if err != nil {
+		panic(err)
+	}
+	defer teardown()
+
+	cfg := sql.Config{
+		Host:     pgHost,
+		DBName:   "db_test",
+		User:     "db_user",
+		Password: "db_pwd",
+	}
+	db, err := sql.NewDB(cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	err = sql.StatusCheck(context.TODO(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	testDB = &sqltest.DB{
+		Db: db,
+	}
+
+	err = testDB.RunMigrations("file://./../../../../migrations/sql")
+	if err != nil {
+		panic(err)
+	}
+
+	return m.Run()
+}
+
+func insertTestUser(t *testing.T, email string) uuid.UUID {
+	userID := uuid.New()
+	_, err := testDB.Db.Exec(
+		`INSERT INTO users (id, email, first_name, last_name, nickname, country) VALUES ($1, $2, 'tony', 'papath', 'TonyPath', 'GR')`,
+		userID, email,
+	)
+	require.NoError(t, err)
+	return userID
+}
+
+func TestRepository_Clients(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	confidential := models.OAuthClient{
+		ID:           uuid.New(),
+		Name:         "partner",
+		SecretHash:   []byte("secret-hash"),
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+	}
+	public := models.OAuthClient{
+		ID:           uuid.New(),
+		Name:         "mobile",
+		RedirectURIs: []string{"com.example.app:/callback", "http://127.0.0.1/callback"},
+		CreatedAt:    confidential.CreatedAt.Add(time.Second),
+	}
+
+	require.NoError(t, repo.InsertClient(ctx, confidential))
+	require.NoError(t, repo.InsertClient(ctx, public))
+
+	got, err := repo.GetClient(ctx, public.ID)
+	require.NoError(t, err)
+	require.True(t, got.Public())
+	require.Equal(t, public.RedirectURIs, got.RedirectURIs)
+
+	clients, err := repo.GetClients(ctx)
+	require.NoError(t, err)
+	require.Len(t, clients, 2)
+	require.Equal(t, confidential.ID, clients[0].ID)
+	require.Equal(t, []byte("secret-hash"), clients[0].SecretHash)
+
+	require.NoError(t, repo.DeleteClient(ctx, public.ID))
+
+	_, err = repo.GetClient(ctx, public.ID)
+	require.ErrorIs(t, err, models.ErrOAuthClientNotFound)
+
+	err = repo.DeleteClient(ctx, public.ID)
+	require.ErrorIs(t, err, models.ErrOAuthClientNotFound)
+}
+
+func TestRepository_AuthorizationCodes(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	client := models.OAuthClient{
+		ID:           uuid.New(),
+		Name:         "codes",
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		CreatedAt:    time.Now().UTC(),
+	}
+	require.NoError(t, repo.InsertClient(ctx, client))
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	code := models.AuthorizationCode{
+		Hash:          []byte("code-hash"),
+		ClientID:      client.ID,
+		UserID:        insertTestUser(t, "codes@mail.com"),
+		RedirectURI:   "https://app.example.com/callback",
+		Scopes:        []string{"openid", "email"},
+		Nonce:         "n-0S6_WzA2Mj",
+		CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		AuthTime:      now,
+		ExpiresAt:     now.Add(time.Minute),
+	}
+	require.NoError(t, repo.InsertAuthorizationCode(ctx, code))
+
+	got, err := repo.ConsumeAuthorizationCode(ctx, []byte("code-hash"))
+	require.NoError(t, err)
+	require.Equal(t, code, got)
+
+	_, err = repo.ConsumeAuthorizationCode(ctx, []byte("code-hash"))
+	require.ErrorIs(t, err, models.ErrInvalidGrant)
+
+	t.Log("expired codes are dropped")
+	{
+		expired := code
+		expired.Hash = []byte("expired-hash")
+		expired.ExpiresAt = now.Add(-time.Minute)
+		require.NoError(t, repo.InsertAuthorizationCode(ctx, expired))
+
+		next := code
+		next.Hash = []byte("next-hash")
+		require.NoError(t, repo.InsertAuthorizationCode(ctx, next))
+
+		_, err := repo.ConsumeAuthorizationCode(ctx, []byte("expired-hash"))
+		require.ErrorIs(t, err, models.ErrInvalidGrant)
+	}
+}
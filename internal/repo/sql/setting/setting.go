@@ -0,0 +1,182 @@
+package setting
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const (
+	userSettingsTable = "user_settings"
+	usersTable        = "users"
+)
+
+type Repository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewRepository(db *sql.DB, log *zap.SugaredLogger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: log,
+	}
+}
+
+// UpsertSettings sets the values of the keys of the namespace. The user row is
+// locked so that concurrent writes cannot push the namespace over maxKeys.
+func (r *Repository) UpsertSettings(ctx context.Context, userID uuid.UUID, namespace string, values map[string]json.RawMessage, updatedAt time.Time, maxKeys int) error {
+	lockQuery, lockArgs, err := pg.QueryBuilder().
+		Select("id").
+		From(usersTable).
+		Where("id = ?", userID).
+		Where("deleted_at IS NULL").
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	ib := pg.QueryBuilder().
+		Insert(userSettingsTable).
+		Columns("user_id", "namespace", "key", "value", "updated_at").
+		Suffix("ON CONFLICT (user_id, namespace, key) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at")
+
+	for key, value := range values {
+		ib = ib.Values(userID, namespace, key, string(value), updatedAt)
+	}
+
+	upsertQuery, upsertArgs, err := ib.ToSql()
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	countQuery, countArgs, err := pg.QueryBuilder().
+		Select("COUNT(*)").
+		From(userSettingsTable).
+		Where("user_id = ?", userID).
+		Where("namespace = ?", namespace).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	return pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		var id uuid.UUID
+		if err := tx.QueryRowContext(ctx, lockQuery, lockArgs...).Scan(&id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.ErrUserNotFound
+			}
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, upsertQuery, upsertArgs...); err != nil {
+			return err
+		}
+
+		var n int
+		if err := tx.QueryRowContext(ctx, countQuery, countArgs...).Scan(&n); err != nil {
+			return err
+		}
+
+		if n > maxKeys {
+			return fmt.Errorf("%w: namespace %s would hold %d keys, at most %d are allowed", models.ErrSettingsLimitExceeded, namespace, n, maxKeys)
+		}
+
+		return nil
+	})
+}
+
+// GetSettings returns the stored settings of the user, ordered by namespace
+// and key. An empty namespace returns the settings of every namespace and no
+// keys returns every key.
+func (r *Repository) GetSettings(ctx context.Context, userID uuid.UUID, namespace string, keys []string) ([]models.Setting, error) {
+	qb := pg.QueryBuilder().
+		Select("user_id", "namespace", "key", "value", "updated_at").
+		From(userSettingsTable).
+		Where("user_id = ?", userID).
+		OrderBy("namespace", "key")
+
+	if namespace != "" {
+		qb = qb.Where("namespace = ?", namespace)
+	}
+
+	if len(keys) > 0 {
+		qb = qb.Where(sq.Eq{"key": keys})
+	}
+
+	query, args, err := qb.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var settings []models.Setting
+	for rows.Next() {
+		var (
+			s     models.Setting
+			value []byte
+		)
+		if err := rows.Scan(&s.UserID, &s.Namespace, &s.Key, &value, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		s.Value = value
+		settings = append(settings, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (r *Repository) DeleteSetting(ctx context.Context, userID uuid.UUID, namespace string, key string) error {
+	query, args, err := pg.QueryBuilder().
+		Delete(userSettingsTable).
+		Where("user_id = ?", userID).
+		Where("namespace = ?", namespace).
+		Where("key = ?", key).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return models.ErrSettingNotFound
+	}
+
+	return nil
+}
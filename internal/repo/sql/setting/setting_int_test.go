@@ -0,0 +1,129 @@
+package setting
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	// 3rd party
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/dockertest"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/sqltest"
+)
+
+var (
+	testDB *sqltest.DB
+)
+
+func TestMain(m *testing.M) {
+	exitCode := run(m)
+	os.Exit(exitCode)
+}
+
+func run(m *testing.M) int {
+
+	envArgs := []string{
+		"POSTGRES_USER=db_user",
+		"POSTGRES_PASSWORD=db_pwd",
+		"POSTGRES_DB=db_test",
+	}
+
+	teardown, pgHost, err := dockertest.SetupPostgres(envArgs)
+	if err != nil {
+		panic(err)
+	}
+	defer teardown()
+
+	cfg := sql.Config{
+		Host:     pgHost,
+		DBName:   "db_test",
+		User:     "db_user",
+		Password: "db_pwd",
+	}
+	db, err := sql.NewDB(cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	err = sql.StatusCheck(context.TODO(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	testDB = &sqltest.DB{
+		Db: db,
+	}
+
+	err = testDB.RunMigrations("file://./../../../../migrations/sql")
+	if err != nil {
+		panic(err)
+	}
+
+	return m.Run()
+}
+
+func insertTestUser(t *testing.T, email string) uuid.UUID {
+	userID := uuid.New()
+	_, err := testDB.Db.Exec(
+		`INSERT INTO users (id, email, first_name, last_name, nickname, country) VALUES ($1, $2, 'tony', 'papath', 'TonyPath', 'GR')`,
+		userID, email,
+	)
+	require.NoError(t, err)
+	return userID
+}
+
+func TestRepository_Settings(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	userID := insertTestUser(t, "settings@mail.com")
+
+	err := repo.UpsertSettings(ctx, userID, "ui", map[string]json.RawMessage{
+		"theme":  json.RawMessage(`"dark"`),
+		"layout": json.RawMessage(`{"columns": 2}`),
+	}, time.Now(), 2)
+	require.NoError(t, err)
+
+	err = repo.UpsertSettings(ctx, userID, "ui", map[string]json.RawMessage{
+		"theme": json.RawMessage(`"light"`),
+	}, time.Now(), 2)
+	require.NoError(t, err)
+
+	t.Log("limit")
+	{
+		err := repo.UpsertSettings(ctx, userID, "ui", map[string]json.RawMessage{
+			"sidebar": json.RawMessage(`true`),
+		}, time.Now(), 2)
+		require.ErrorIs(t, err, models.ErrSettingsLimitExceeded)
+	}
+
+	err = repo.UpsertSettings(ctx, uuid.New(), "ui", map[string]json.RawMessage{"theme": json.RawMessage(`"dark"`)}, time.Now(), 2)
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+
+	got, err := repo.GetSettings(ctx, userID, "ui", nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, "layout", got[0].Key)
+	require.JSONEq(t, `{"columns": 2}`, string(got[0].Value))
+	require.Equal(t, "theme", got[1].Key)
+	require.JSONEq(t, `"light"`, string(got[1].Value))
+
+	got, err = repo.GetSettings(ctx, userID, "ui", []string{"theme"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	require.NoError(t, repo.DeleteSetting(ctx, userID, "ui", "theme"))
+	require.ErrorIs(t, repo.DeleteSetting(ctx, userID, "ui", "theme"), models.ErrSettingNotFound)
+	testDB.RequireTotalRows(t, "user_settings", 1)
+}
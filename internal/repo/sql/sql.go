@@ -3,6 +3,7 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"net/url"
 	"time"
 
@@ -18,7 +19,8 @@ type Config struct {
 	Password string
 }
 
-func NewDB(cfg Config) (*sql.DB, error) {
+// URL returns the connection string of the database.
+func (cfg Config) URL() string {
 	q := make(url.Values)
 	q.Set("sslmode", "disable")
 	q.Set("timezone", "utc")
@@ -31,7 +33,11 @@ func NewDB(cfg Config) (*sql.DB, error) {
 		RawQuery: q.Encode(),
 	}
 
-	db, err := sql.Open("postgres", u.String())
+	return u.String()
+}
+
+func NewDB(cfg Config) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.URL())
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +67,85 @@ func StatusCheck(ctx context.Context, db *sql.DB) error {
 	return db.QueryRowContext(ctx, query).Scan(&tmp)
 }
 
+// Querier is implemented by both *sql.DB and *sql.Tx.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 func QueryBuilder() sq.StatementBuilderType {
 	return sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 }
+
+// WithinTx runs fn inside a transaction. The transaction is committed when fn
+// succeeds and rolled back otherwise. When ctx already carries a transaction,
+// started by RunInTx, fn runs within it behind a savepoint instead, so that its
+// changes are undone on failure while the enclosing transaction goes on.
+func WithinTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return withinSavepoint(ctx, tx, fn)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+const savepoint = "within_tx"
+
+func withinSavepoint(ctx context.Context, tx *sql.Tx, fn func(tx *sql.Tx) error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("could not create savepoint: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_, _ = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("could not release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+type txKey struct{}
+
+// RunInTx runs fn with a context carrying a new transaction, which the
+// repositories use for every statement run with that context: reads through
+// Conn and writes through WithinTx. The transaction is committed when fn
+// succeeds and rolled back otherwise. The context must not be used from
+// several goroutines at once.
+func RunInTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return WithinTx(ctx, db, func(*sql.Tx) error {
+			return fn(ctx)
+		})
+	}
+
+	return WithinTx(ctx, db, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction carried by ctx, if any, and db otherwise.
+func Conn(ctx context.Context, db *sql.DB) Querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
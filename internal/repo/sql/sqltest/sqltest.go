@@ -1,6 +1,7 @@
 package sqltest
 
 import (
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"testing"
@@ -9,6 +10,10 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/stretchr/testify/require"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/crypto"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/datakey"
 )
 
 // DB struct represents a wrapper for a db handle
@@ -44,3 +49,33 @@ func (tdb *DB) RequireTotalRows(t *testing.T, table string, expectedCount int) {
 	require.NoError(t, err)
 	require.Equal(t, expectedCount, n)
 }
+
+// NewDataKeys returns a data key store backed by a random master key
+func NewDataKeys() *datakey.Store {
+	masterKey := make([]byte, crypto.KeySize)
+	if _, err := rand.Read(masterKey); err != nil {
+		panic(err)
+	}
+
+	keyring, err := crypto.NewKeyring(masterKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return datakey.NewStore(keyring)
+}
+
+// NewBlindIndex returns a blind index with a random key
+func NewBlindIndex() *crypto.BlindIndex {
+	key := make([]byte, crypto.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+
+	index, err := crypto.NewBlindIndex(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return index
+}
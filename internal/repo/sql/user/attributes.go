@@ -0,0 +1,56 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// marshalAttributes encodes the custom attributes of a user for the JSONB
+// attributes column.
+func marshalAttributes(attributes map[string]any) (string, error) {
+	if len(attributes) == 0 {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(attributes)
+	if err != nil {
+		return "", fmt.Errorf("encoding attributes: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// unmarshalAttributes decodes the attributes column. Numbers are decoded as
+// int64, the only numeric attribute type.
+func unmarshalAttributes(b []byte) (map[string]any, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var attributes map[string]any
+	if err := dec.Decode(&attributes); err != nil {
+		return nil, err
+	}
+
+	for name, value := range attributes {
+		n, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", name, err)
+		}
+		attributes[name] = i
+	}
+
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+
+	return attributes, nil
+}
@@ -0,0 +1,338 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const (
+	userChangesTable = "user_changes"
+	// changesChannel is notified with the id of the last change of every
+	// transaction changing users.
+	changesChannel = "user_changes"
+	// changesSettled restricts a query to the changes of transactions older
+	// than any in progress. Transactions commit in any order, so reading the
+	// changes in the order of their transaction, and only once they settled,
+	// guarantees that readers never skip a change committed late.
+	changesSettled = "tx_id < pg_snapshot_xmin(pg_current_snapshot())"
+	// changesTxID reads the transaction of a change as a number.
+	changesTxID = "tx_id::text::bigint"
+)
+
+// recordChanges records that the users changed, within the transaction of the
+// change, along with the id of the transaction.
+func recordChanges(ctx context.Context, tx *sql.Tx, kind models.UserChangeKind, userIDs ...uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	qb := pg.QueryBuilder().
+		Insert(userChangesTable).
+		Columns("user_id", "kind").
+		Suffix("RETURNING id")
+	for _, id := range userIDs {
+		qb = qb.Values(id, kind)
+	}
+
+	insert, args, err := qb.ToSql()
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	query := "WITH changes AS (" + insert + ") SELECT pg_notify('" + changesChannel + "', max(id)::text) FROM changes"
+	_, err = tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// LatestUserChange returns the cursor of the last settled change to users,
+// the zero cursor when there is none.
+func (r *Repository) LatestUserChange(ctx context.Context) (models.ChangeCursor, error) {
+	query, args, err := pg.QueryBuilder().
+		Select(changesTxID, "id", "changed_at").
+		From(userChangesTable).
+		Where(changesSettled).
+		OrderBy("tx_id DESC", "id DESC").
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return models.ChangeCursor{}, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var cursor models.ChangeCursor
+	err = pg.Conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&cursor.TxID, &cursor.ID, &cursor.ChangedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return models.ChangeCursor{}, err
+	}
+
+	return cursor, nil
+}
+
+// GetUserChanges reads up to limit settled changes following the cursor and
+// returns those to users matching the filter of opts, soft deleted or not,
+// along with the cursor of the last change read. Users are matched as they are
+// now. Erased users can no longer be matched, so their deletions are returned
+// whatever the filter, carrying the id of the user alone.
+func (r *Repository) GetUserChanges(ctx context.Context, opts models.GetUsersOptions, after models.ChangeCursor, limit uint64) ([]models.UserChange, models.ChangeCursor, error) {
+	query, args, err := pg.QueryBuilder().
+		Select(changesTxID, "id", "user_id", "kind", "changed_at").
+		From(userChangesTable).
+		Where("(tx_id, id) > (?::text::xid8, ?)", after.TxID, after.ID).
+		Where(changesSettled).
+		OrderBy("tx_id", "id").
+		Limit(limit).
+		ToSql()
+
+	if err != nil {
+		return nil, after, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := pg.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, after, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var (
+		changes []models.UserChange
+		userIDs []uuid.UUID
+	)
+	for rows.Next() {
+		var c models.UserChange
+		if err := rows.Scan(&c.Cursor.TxID, &c.Cursor.ID, &c.User.ID, &c.Kind, &c.Cursor.ChangedAt); err != nil {
+			return nil, after, err
+		}
+		changes = append(changes, c)
+		userIDs = append(userIDs, c.User.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, after, err
+	}
+
+	if len(changes) == 0 {
+		return nil, after, nil
+	}
+	last := changes[len(changes)-1].Cursor
+
+	opts.IncludeDeleted = true
+	qb, err := r.filterUsers(pg.QueryBuilder().Select(userColumns...).From(usersTable), opts)
+	if err != nil {
+		return nil, after, err
+	}
+
+	users, err := r.queryUsers(ctx, qb.Where(sq.Eq{"id": userIDs}))
+	if err != nil {
+		return nil, after, err
+	}
+
+	byID := make(map[uuid.UUID]models.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	// The deletions of users no longer matching may be erasures.
+	var unmatched []uuid.UUID
+	for _, c := range changes {
+		if _, ok := byID[c.User.ID]; !ok && c.Kind == models.UserChangeDeleted {
+			unmatched = append(unmatched, c.User.ID)
+		}
+	}
+
+	erased, err := r.erasedUsers(ctx, unmatched)
+	if err != nil {
+		return nil, after, err
+	}
+
+	matching := changes[:0]
+	for _, c := range changes {
+		if u, ok := byID[c.User.ID]; ok {
+			c.User = u
+		} else if c.Kind != models.UserChangeDeleted || !erased[c.User.ID] {
+			continue
+		}
+		matching = append(matching, c)
+	}
+
+	return matching, last, nil
+}
+
+// erasedUsers returns which of the users no longer exist, soft deleted or not.
+func (r *Repository) erasedUsers(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := pg.QueryBuilder().
+		Select("id").
+		From(usersTable).
+		Where(sq.Eq{"id": userIDs}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := pg.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	erased := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		erased[id] = true
+	}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		delete(erased, id)
+	}
+
+	return erased, rows.Err()
+}
+
+// CountUserChanges counts the settled changes following the cursor, up to
+// limit.
+func (r *Repository) CountUserChanges(ctx context.Context, after models.ChangeCursor, limit uint64) (int64, error) {
+	unread := pg.QueryBuilder().
+		Select("1").
+		From(userChangesTable).
+		Where("(tx_id, id) > (?::text::xid8, ?)", after.TxID, after.ID).
+		Where(changesSettled).
+		Limit(limit)
+
+	query, args, err := pg.QueryBuilder().
+		Select("COUNT(*)").
+		FromSelect(unread, "unread").
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var n int64
+	err = pg.Conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&n)
+	return n, err
+}
+
+// PurgeUserChanges removes the changes made before the time.
+func (r *Repository) PurgeUserChanges(ctx context.Context, changedBefore time.Time) (int64, error) {
+	query, args, err := pg.QueryBuilder().
+		Delete(userChangesTable).
+		Where("changed_at < ?", changedBefore).
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	res, err := pg.Conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// ChangeListener listens to the notifications of changes to users and wakes
+// its subscribers up. Notifications carry no data besides the id of the last
+// change: subscribers read the changes themselves, at their own pace.
+type ChangeListener struct {
+	listener *pq.Listener
+	logger   *zap.SugaredLogger
+
+	mu   sync.Mutex
+	subs map[chan struct{}]struct{}
+}
+
+// NewChangeListener returns a listener connecting to the database of the
+// configuration. It listens once Run is called.
+func NewChangeListener(cfg pg.Config, log *zap.SugaredLogger) *ChangeListener {
+	l := &ChangeListener{
+		logger: log,
+		subs:   make(map[chan struct{}]struct{}),
+	}
+
+	l.listener = pq.NewListener(cfg.URL(), time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Errorw("user changes listener", "event", event, "error", err)
+		}
+	})
+
+	return l
+}
+
+// Run listens to notifications until ctx is cancelled.
+func (l *ChangeListener) Run(ctx context.Context) error {
+	if err := l.listener.Listen(changesChannel); err != nil {
+		return fmt.Errorf("could not listen to %s: %w", changesChannel, err)
+	}
+	defer func() {
+		_ = l.listener.Close()
+	}()
+
+	l.logger.Infow("startup", "status", "user changes listener started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.logger.Infow("shutdown", "status", "user changes listener stopped")
+			return nil
+		case <-l.listener.Notify:
+			// A nil notification follows a reconnection, after which
+			// notifications may have been missed: subscribers read the
+			// changes all the same.
+			l.wake()
+		}
+	}
+}
+
+// Subscribe returns a channel receiving a value whenever users changed, and a
+// function ending the subscription. Notifications are coalesced: a subscriber
+// busy when several arrive receives one of them.
+func (l *ChangeListener) Subscribe() (<-chan struct{}, func()) {
+	c := make(chan struct{}, 1)
+
+	l.mu.Lock()
+	l.subs[c] = struct{}{}
+	l.mu.Unlock()
+
+	return c, func() {
+		l.mu.Lock()
+		delete(l.subs, c)
+		l.mu.Unlock()
+	}
+}
+
+func (l *ChangeListener) wake() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for c := range l.subs {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,530 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/crypto"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	auditlog "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/audit"
+)
+
+const (
+	userEmailsTable = "user_emails"
+	userPhonesTable = "user_phones"
+
+	phoneColumn = "phone"
+)
+
+// contactKind describes the table holding one kind of contact point. Values
+// are encrypted with the data key of the user and blind indexed, like the PII
+// columns of users.
+type contactKind struct {
+	table    string
+	column   string
+	notFound error
+	taken    error
+	// primaryIsUserEmail is set when the primary value is kept in sync with
+	// the email of the user.
+	primaryIsUserEmail bool
+}
+
+var (
+	emailContact = contactKind{
+		table:              userEmailsTable,
+		column:             emailColumn,
+		notFound:           models.ErrEmailNotFound,
+		taken:              models.ErrEmailTaken,
+		primaryIsUserEmail: true,
+	}
+	phoneContact = contactKind{
+		table:    userPhonesTable,
+		column:   phoneColumn,
+		notFound: models.ErrPhoneNotFound,
+		taken:    models.ErrPhoneTaken,
+	}
+)
+
+func (k contactKind) ciphertextColumn() string {
+	return k.column + "_ciphertext"
+}
+
+func (k contactKind) indexColumn() string {
+	return k.column + "_index"
+}
+
+// contact is a row of a contact table, decrypted.
+type contact struct {
+	value      string
+	primary    bool
+	verifiedAt *time.Time
+	createdAt  time.Time
+}
+
+// InsertEmail adds a secondary, unverified email address to the user.
+func (r *Repository) InsertEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	return r.insertContact(ctx, emailContact, userID, email)
+}
+
+// InsertPhone adds an unverified phone number to the user. The first phone
+// number of a user becomes its primary one.
+func (r *Repository) InsertPhone(ctx context.Context, userID uuid.UUID, number string) error {
+	return r.insertContact(ctx, phoneContact, userID, number)
+}
+
+// DeleteEmail removes a secondary email address of the user.
+func (r *Repository) DeleteEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	return r.deleteContact(ctx, emailContact, userID, email)
+}
+
+// DeletePhone removes a phone number of the user. The primary phone number
+// can only be removed when it is the last one.
+func (r *Repository) DeletePhone(ctx context.Context, userID uuid.UUID, number string) error {
+	return r.deleteContact(ctx, phoneContact, userID, number)
+}
+
+// SetPrimaryEmail makes the address the primary one, which also makes it the
+// email of the user. The previous primary address is kept as a secondary one.
+func (r *Repository) SetPrimaryEmail(ctx context.Context, userID uuid.UUID, email string, updatedAt time.Time) error {
+	return r.setPrimaryContact(ctx, emailContact, userID, email, updatedAt)
+}
+
+func (r *Repository) SetPrimaryPhone(ctx context.Context, userID uuid.UUID, number string, updatedAt time.Time) error {
+	return r.setPrimaryContact(ctx, phoneContact, userID, number, updatedAt)
+}
+
+func (r *Repository) VerifyEmail(ctx context.Context, userID uuid.UUID, email string, verifiedAt time.Time) error {
+	return r.verifyContact(ctx, emailContact, userID, email, verifiedAt)
+}
+
+func (r *Repository) VerifyPhone(ctx context.Context, userID uuid.UUID, number string, verifiedAt time.Time) error {
+	return r.verifyContact(ctx, phoneContact, userID, number, verifiedAt)
+}
+
+// GetEmails returns the email addresses of the user, primary first.
+func (r *Repository) GetEmails(ctx context.Context, userID uuid.UUID) ([]models.UserEmail, error) {
+	contacts, err := r.getContacts(ctx, emailContact, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make([]models.UserEmail, len(contacts))
+	for i, c := range contacts {
+		emails[i] = models.UserEmail{
+			UserID:     userID,
+			Email:      c.value,
+			Primary:    c.primary,
+			VerifiedAt: c.verifiedAt,
+			CreatedAt:  c.createdAt,
+		}
+	}
+
+	return emails, nil
+}
+
+// GetPhones returns the phone numbers of the user, primary first.
+func (r *Repository) GetPhones(ctx context.Context, userID uuid.UUID) ([]models.UserPhone, error) {
+	contacts, err := r.getContacts(ctx, phoneContact, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	phones := make([]models.UserPhone, len(contacts))
+	for i, c := range contacts {
+		phones[i] = models.UserPhone{
+			UserID:     userID,
+			Number:     c.value,
+			Primary:    c.primary,
+			VerifiedAt: c.verifiedAt,
+			CreatedAt:  c.createdAt,
+		}
+	}
+
+	return phones, nil
+}
+
+func (r *Repository) insertContact(ctx context.Context, k contactKind, userID uuid.UUID, value string) error {
+	err := pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := r.lockUser(ctx, tx, userID, notDeleted); err != nil {
+			return err
+		}
+
+		key, err := r.keys.ForUser(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		ciphertext, err := crypto.Encrypt(key, []byte(value), piiAAD(userID, k.column))
+		if err != nil {
+			return fmt.Errorf("encrypting %s: %w", k.column, err)
+		}
+
+		// The primary email is set along with the user, any other kind of
+		// contact becomes primary when it is the first one.
+		primary := sq.Expr("FALSE")
+		if !k.primaryIsUserEmail {
+			primary = sq.Expr("NOT EXISTS (SELECT 1 FROM "+k.table+" WHERE user_id = ? AND is_primary)", userID)
+		}
+
+		query, args, err := pg.QueryBuilder().
+			Insert(k.table).
+			Columns("user_id", k.ciphertextColumn(), k.indexColumn(), "is_primary").
+			Values(userID, ciphertext, r.blindIndex(k.column, value), primary).
+			ToSql()
+
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		entry := audit.NewEntry(ctx, userID, models.AuditActionContactAdded, nil, map[string]any{k.column: value})
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
+
+	if err != nil {
+		if pg.IsUniqueViolation(err) {
+			return k.taken
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) deleteContact(ctx context.Context, k contactKind, userID uuid.UUID, value string) error {
+	index := r.blindIndex(k.column, value)
+
+	countQuery, countArgs, err := pg.QueryBuilder().
+		Select("COUNT(*)").
+		From(k.table).
+		Where("user_id = ?", userID).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	deleteQuery, deleteArgs, err := pg.QueryBuilder().
+		Delete(k.table).
+		Where("user_id = ?", userID).
+		Where(sq.Eq{k.indexColumn(): index}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	return pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := r.lockUser(ctx, tx, userID, notDeleted); err != nil {
+			return err
+		}
+
+		primary, err := r.lockContact(ctx, tx, k, userID, index)
+		if err != nil {
+			return err
+		}
+
+		if primary {
+			if k.primaryIsUserEmail {
+				return fmt.Errorf("%w: the primary %s cannot be removed", models.ErrPrimaryContact, k.column)
+			}
+
+			var n int
+			if err := tx.QueryRowContext(ctx, countQuery, countArgs...).Scan(&n); err != nil {
+				return err
+			}
+			if n > 1 {
+				return fmt.Errorf("%w: set another primary %s first", models.ErrPrimaryContact, k.column)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+			return err
+		}
+
+		entry := audit.NewEntry(ctx, userID, models.AuditActionContactRemoved, map[string]any{k.column: value}, nil)
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
+}
+
+func (r *Repository) setPrimaryContact(ctx context.Context, k contactKind, userID uuid.UUID, value string, updatedAt time.Time) error {
+	index := r.blindIndex(k.column, value)
+
+	unsetQuery, unsetArgs, err := pg.QueryBuilder().
+		Update(k.table).
+		Set("is_primary", false).
+		Where("user_id = ?", userID).
+		Where("is_primary").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	setQuery, setArgs, err := pg.QueryBuilder().
+		Update(k.table).
+		Set("is_primary", true).
+		Where("user_id = ?", userID).
+		Where(sq.Eq{k.indexColumn(): index}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	err = pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		current, err := r.lockUser(ctx, tx, userID, notDeleted)
+		if err != nil {
+			return err
+		}
+
+		primary, err := r.lockContact(ctx, tx, k, userID, index)
+		if err != nil {
+			return err
+		}
+
+		if primary {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx, unsetQuery, unsetArgs...); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, setQuery, setArgs...); err != nil {
+			return err
+		}
+
+		if !k.primaryIsUserEmail {
+			entry := audit.NewEntry(ctx, userID, models.AuditActionUserUpdated, nil, map[string]any{"primary_" + k.column: value})
+			return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+		}
+
+		key, err := r.keys.ForUser(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		updated := current
+		updated.Email = value
+		updated.UpdateAt = &updatedAt
+		sealed, err := r.sealPII(key, updated)
+		if err != nil {
+			return err
+		}
+
+		qb := pg.QueryBuilder().
+			Update(usersTable).
+			Set("updated_at", updatedAt).
+			Where("id = ?", userID)
+
+		query, args, err := setPII(qb, sealed).ToSql()
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		if err := recordChanges(ctx, tx, models.UserChangeUpdated, userID); err != nil {
+			return err
+		}
+
+		before, after := audit.Diff(current, updated)
+		entry := audit.NewEntry(ctx, userID, models.AuditActionUserUpdated, before, after)
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
+
+	if err != nil {
+		if pg.IsUniqueViolation(err) {
+			return k.taken
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) verifyContact(ctx context.Context, k contactKind, userID uuid.UUID, value string, verifiedAt time.Time) error {
+	query, args, err := pg.QueryBuilder().
+		Update(k.table).
+		Set("verified_at", verifiedAt).
+		Where("user_id = ?", userID).
+		Where(sq.Eq{k.indexColumn(): r.blindIndex(k.column, value)}).
+		Where(notDeleted).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	return pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if n == 0 {
+			return k.notFound
+		}
+
+		entry := audit.NewEntry(ctx, userID, models.AuditActionContactVerified, nil, map[string]any{k.column: value})
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
+}
+
+// lockContact locks the contact row with the given blind index and reports
+// whether it is the primary one.
+func (r *Repository) lockContact(ctx context.Context, tx *sql.Tx, k contactKind, userID uuid.UUID, index []byte) (bool, error) {
+	query, args, err := pg.QueryBuilder().
+		Select("is_primary").
+		From(k.table).
+		Where("user_id = ?", userID).
+		Where(sq.Eq{k.indexColumn(): index}).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var primary bool
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&primary); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, k.notFound
+		}
+		return false, err
+	}
+
+	return primary, nil
+}
+
+func (r *Repository) getContacts(ctx context.Context, k contactKind, userID uuid.UUID) ([]contact, error) {
+	query, args, err := pg.QueryBuilder().
+		Select(k.ciphertextColumn(), "is_primary", "verified_at", "created_at").
+		From(k.table).
+		Where("user_id = ?", userID).
+		OrderBy("is_primary DESC", "created_at").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := pg.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var (
+		contacts    []contact
+		ciphertexts [][]byte
+	)
+	for rows.Next() {
+		var (
+			c          contact
+			ciphertext []byte
+		)
+		if err := rows.Scan(&ciphertext, &c.primary, &c.verifiedAt, &c.createdAt); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, c)
+		ciphertexts = append(ciphertexts, ciphertext)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+
+	keys, err := r.keys.Lookup(ctx, pg.Conn(ctx, r.db), []uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := keys[userID]
+	if !ok {
+		return nil, fmt.Errorf("missing data key of user %s", userID)
+	}
+
+	for i, ciphertext := range ciphertexts {
+		plaintext, err := crypto.Decrypt(key, ciphertext, piiAAD(userID, k.column))
+		if err != nil {
+			return nil, fmt.Errorf("decrypting %s of user %s: %w", k.column, userID, err)
+		}
+		contacts[i].value = string(plaintext)
+	}
+
+	return contacts, nil
+}
+
+// upsertPrimaryEmail stores the email of the user as its primary address,
+// replacing the previous one, which is no longer verified. deletedAt is the
+// deletion time of the user.
+func upsertPrimaryEmail(ctx context.Context, tx *sql.Tx, userID uuid.UUID, s sealedPII, deletedAt *time.Time) error {
+	query, args, err := pg.QueryBuilder().
+		Insert(userEmailsTable).
+		Columns("user_id", "email_ciphertext", emailIndexColumn, "is_primary", "deleted_at").
+		Values(userID, s.email, s.emailIndex, true, deletedAt).
+		Suffix("ON CONFLICT (user_id) WHERE is_primary DO UPDATE SET " +
+			"email_ciphertext = EXCLUDED.email_ciphertext, email_index = EXCLUDED.email_index, email_index_normalized = TRUE, " +
+			"verified_at = NULL, created_at = NOW()").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// setContactsDeletedAt marks the contacts of the user as deleted along with
+// it, so that others may take them, or clears the mark on restore.
+func setContactsDeletedAt(ctx context.Context, tx *sql.Tx, userID uuid.UUID, deletedAt *time.Time) error {
+	for _, k := range []contactKind{emailContact, phoneContact} {
+		query, args, err := pg.QueryBuilder().
+			Update(k.table).
+			Set("deleted_at", deletedAt).
+			Where("user_id = ?", userID).
+			ToSql()
+
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			if pg.IsUniqueViolation(err) {
+				return k.taken
+			}
+			return err
+		}
+	}
+
+	return nil
+}
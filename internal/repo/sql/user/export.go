@@ -0,0 +1,83 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const (
+	exportCursor = "export_users"
+	// exportBatchSize is the number of users fetched from the cursor at a time.
+	exportBatchSize = 500
+)
+
+// StreamUsers calls fn with every user matching the filter of opts, in order
+// of creation. Users are read through a cursor, exportBatchSize at a time, so
+// memory use does not grow with the number of users. Paging and sorting
+// options are ignored. The first error of fn stops the stream and is returned.
+func (r *Repository) StreamUsers(ctx context.Context, opts models.GetUsersOptions, fn func(models.User) error) error {
+	qb, err := r.filterUsers(pg.QueryBuilder().Select(userColumns...).From(usersTable), opts)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := qb.OrderBy("created_at", "id").ToSql()
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	return pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DECLARE "+exportCursor+" NO SCROLL CURSOR FOR "+query, args...); err != nil {
+			return fmt.Errorf("could not declare cursor: %w", err)
+		}
+
+		fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", exportBatchSize, exportCursor)
+		for {
+			batch, err := fetchUsers(ctx, tx, fetch)
+			if err != nil {
+				return err
+			}
+
+			users, err := r.openUsers(ctx, tx, batch)
+			if err != nil {
+				return err
+			}
+
+			for _, u := range users {
+				if err := fn(u); err != nil {
+					return err
+				}
+			}
+
+			if len(batch) < exportBatchSize {
+				return nil
+			}
+		}
+	})
+}
+
+func fetchUsers(ctx context.Context, tx *sql.Tx, fetch string) ([]userRow, error) {
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	batch := make([]userRow, 0, exportBatchSize)
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, u)
+	}
+
+	return batch, rows.Err()
+}
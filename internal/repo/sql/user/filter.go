@@ -0,0 +1,227 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/filter"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+type fieldKind int
+
+const (
+	textField fieldKind = iota
+	// encryptedField values are only compared for equality, through their blind index.
+	encryptedField
+	enumField
+	uuidField
+	timeField
+)
+
+// filterField is a field filter expressions may restrict.
+type filterField struct {
+	kind     fieldKind
+	column   string
+	nullable bool
+	// values are the accepted values of enum fields.
+	values []string
+}
+
+// filterFields are the fields filter expressions may restrict.
+var filterFields = map[string]filterField{
+	"id":         {kind: uuidField, column: "id"},
+	"email":      {kind: encryptedField, column: emailColumn},
+	"country":    {kind: encryptedField, column: countryColumn},
+	"nickname":   {kind: textField, column: "nickname", nullable: true},
+	"created_at": {kind: timeField, column: "created_at"},
+	"updated_at": {kind: timeField, column: "updated_at", nullable: true},
+	"status": {kind: enumField, column: "status", values: []string{
+		string(models.UserStatusPending),
+		string(models.UserStatusActive),
+		string(models.UserStatusSuspended),
+		string(models.UserStatusDeactivated),
+	}},
+	"type": {kind: enumField, column: "type", values: []string{
+		string(models.UserTypeHuman),
+		string(models.UserTypeServiceAccount),
+	}},
+}
+
+// encryptedIndexColumns are the blind index columns of encrypted fields.
+var encryptedIndexColumns = map[string]string{
+	emailColumn:   emailIndexColumn,
+	countryColumn: countryIndexColumn,
+}
+
+// not negates a predicate, treating NULL as false so that, for instance,
+// `nickname != "x"` matches users without a nickname.
+type not struct {
+	pred sq.Sqlizer
+}
+
+func (n not) ToSql() (string, []any, error) {
+	query, args, err := n.pred.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return "NOT COALESCE((" + query + "), false)", args, nil
+}
+
+// compileFilter compiles a filter expression into a predicate on the users
+// table. Values are always passed as arguments.
+func (r *Repository) compileFilter(e filter.Expr) (sq.Sqlizer, error) {
+	switch e := e.(type) {
+	case filter.And:
+		preds := make(sq.And, 0, len(e.Exprs))
+		for _, sub := range e.Exprs {
+			pred, err := r.compileFilter(sub)
+			if err != nil {
+				return nil, err
+			}
+			preds = append(preds, pred)
+		}
+		return preds, nil
+	case filter.Or:
+		preds := make(sq.Or, 0, len(e.Exprs))
+		for _, sub := range e.Exprs {
+			pred, err := r.compileFilter(sub)
+			if err != nil {
+				return nil, err
+			}
+			preds = append(preds, pred)
+		}
+		return preds, nil
+	case filter.Not:
+		pred, err := r.compileFilter(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return not{pred: pred}, nil
+	case filter.Restriction:
+		return r.compileRestriction(e)
+	default:
+		return nil, fmt.Errorf("%w: unsupported expression %T", models.ErrInvalidFilter, e)
+	}
+}
+
+func (r *Repository) compileRestriction(e filter.Restriction) (sq.Sqlizer, error) {
+	f, ok := filterFields[e.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown field %s", models.ErrInvalidFilter, e.Field)
+	}
+
+	unsupported := fmt.Errorf("%w: %s does not support %s", models.ErrInvalidFilter, e.Field, e.Op)
+	v := e.Values[0]
+
+	// Null checks: `field = null`, `field != null` and `field:*`.
+	if v.Null() || v.Kind == filter.Any {
+		if !f.nullable {
+			return nil, fmt.Errorf("%w: %s is never null", models.ErrInvalidFilter, e.Field)
+		}
+		switch {
+		case e.Op == filter.OpEq && v.Null():
+			return sq.Eq{f.column: nil}, nil
+		case e.Op == filter.OpNe && v.Null(), e.Op == filter.OpHas && v.Kind == filter.Any:
+			return sq.NotEq{f.column: nil}, nil
+		default:
+			return nil, unsupported
+		}
+	}
+
+	switch e.Op {
+	case filter.OpEq:
+		return r.compileEq(e.Field, f, v)
+	case filter.OpNe:
+		pred, err := r.compileEq(e.Field, f, v)
+		if err != nil {
+			return nil, err
+		}
+		return not{pred: pred}, nil
+	case filter.OpIn:
+		preds := make(sq.Or, 0, len(e.Values))
+		for _, v := range e.Values {
+			pred, err := r.compileEq(e.Field, f, v)
+			if err != nil {
+				return nil, err
+			}
+			preds = append(preds, pred)
+		}
+		return preds, nil
+	case filter.OpHas:
+		if prefix, ok := strings.CutSuffix(v.Text, "*"); ok && v.Kind == filter.String {
+			if f.kind != textField {
+				return nil, fmt.Errorf("%w: %s does not support prefixes", models.ErrInvalidFilter, e.Field)
+			}
+			return sq.Like{f.column: escapeLike(prefix) + "%"}, nil
+		}
+		return r.compileEq(e.Field, f, v)
+	case filter.OpLt, filter.OpLe, filter.OpGt, filter.OpGe:
+		if f.kind != timeField {
+			return nil, unsupported
+		}
+		t, err := parseFilterTime(e.Field, v)
+		if err != nil {
+			return nil, err
+		}
+		return sq.Expr(f.column+" "+string(e.Op)+" ?", t), nil
+	default:
+		return nil, unsupported
+	}
+}
+
+// compileEq compiles the equality of the field to a value.
+func (r *Repository) compileEq(name string, f filterField, v filter.Value) (sq.Sqlizer, error) {
+	if v.Null() || v.Kind == filter.Any {
+		return nil, fmt.Errorf("%w: %s cannot be compared to %s", models.ErrInvalidFilter, name, v.Text)
+	}
+
+	switch f.kind {
+	case encryptedField:
+		return r.piiFilter(f.column, encryptedIndexColumns[f.column], v.Text), nil
+	case enumField:
+		for _, allowed := range f.values {
+			if strings.EqualFold(v.Text, allowed) {
+				return sq.Eq{f.column: allowed}, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %s must be one of %s", models.ErrInvalidFilter, name, strings.Join(f.values, ", "))
+	case uuidField:
+		id, err := uuid.Parse(v.Text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a uuid", models.ErrInvalidFilter, name)
+		}
+		return sq.Eq{f.column: id}, nil
+	case timeField:
+		t, err := parseFilterTime(name, v)
+		if err != nil {
+			return nil, err
+		}
+		return sq.Eq{f.column: t}, nil
+	default:
+		return sq.Eq{f.column: v.Text}, nil
+	}
+}
+
+// parseFilterTime parses RFC 3339 timestamps and YYYY-MM-DD dates, the
+// latter as midnight UTC.
+func parseFilterTime(name string, v filter.Value) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, v.Text); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, v.Text); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: %s must be an RFC 3339 timestamp or a YYYY-MM-DD date", models.ErrInvalidFilter, name)
+}
+
+// escapeLike escapes the wildcards of LIKE patterns.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
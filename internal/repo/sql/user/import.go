@@ -0,0 +1,201 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	auditlog "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/audit"
+)
+
+// InsertUsers inserts the users in a single transaction, with one multi-row
+// statement per table, as InsertUser does for a single user. Either all of
+// them are inserted or none is; ErrEmailTaken does not tell which email was
+// taken.
+func (r *Repository) InsertUsers(ctx context.Context, users []models.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	err := pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		userIDs := make([]uuid.UUID, len(users))
+		for i, u := range users {
+			userIDs[i] = u.ID
+		}
+
+		keys, err := r.keys.ForUsers(ctx, tx, userIDs)
+		if err != nil {
+			return err
+		}
+
+		usersQB := pg.QueryBuilder().
+			Insert(usersTable).
+			Columns("id", "type", "status", "nickname", "password",
+				"email_ciphertext", "first_name_ciphertext", "last_name_ciphertext", "country_ciphertext",
+				emailIndexColumn, countryIndexColumn, searchTokensColumn, "attributes", "created_at")
+
+		emailsQB := pg.QueryBuilder().
+			Insert(userEmailsTable).
+			Columns("user_id", "email_ciphertext", emailIndexColumn, "is_primary")
+
+		var (
+			emails  int
+			entries = make([]models.AuditEntry, 0, len(users))
+		)
+		for _, u := range users {
+			sealed, err := r.sealPII(keys[u.ID], u)
+			if err != nil {
+				return err
+			}
+
+			attributes, err := marshalAttributes(u.Attributes)
+			if err != nil {
+				return err
+			}
+
+			usersQB = usersQB.Values(u.ID, u.Type, u.Status, u.Nickname, u.Password,
+				sealed.email, sealed.firstName, sealed.lastName, sealed.country,
+				sealed.emailIndex, sealed.countryIndex, searchVector(sealed), attributes, u.CreatedAt)
+
+			if u.Email != "" {
+				emailsQB = emailsQB.Values(u.ID, sealed.email, sealed.emailIndex, true)
+				emails++
+			}
+
+			entries = append(entries, audit.NewEntry(ctx, u.ID, models.AuditActionUserCreated, nil, audit.Snapshot(u)))
+		}
+
+		qbs := []sq.InsertBuilder{usersQB}
+		if emails > 0 {
+			qbs = append(qbs, emailsQB)
+		}
+
+		for _, qb := range qbs {
+			query, args, err := qb.ToSql()
+			if err != nil {
+				return fmt.Errorf("could not build query sql query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return err
+			}
+		}
+
+		if err := recordChanges(ctx, tx, models.UserChangeCreated, userIDs...); err != nil {
+			return err
+		}
+
+		return auditlog.InsertEntries(ctx, tx, r.keys, entries...)
+	})
+
+	if err != nil {
+		if pg.IsUniqueViolation(err) {
+			return models.ErrEmailTaken
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetEmailOwners returns the live users holding the emails, as primary or
+// secondary email, keyed by email. Emails nobody holds are missing. Like
+// piiFilter, it also matches users still stored in plaintext, whose email is
+// their only, primary one.
+func (r *Repository) GetEmailOwners(ctx context.Context, emails []string) (map[string]models.EmailOwner, error) {
+	owners := make(map[string]models.EmailOwner, len(emails))
+	if len(emails) == 0 {
+		return owners, nil
+	}
+
+	// Emails differing in case only share an index.
+	byIndex := make(map[string][]string, len(emails))
+	indexes := make([][]byte, 0, len(emails))
+	for _, email := range emails {
+		index := r.blindIndex(emailColumn, email)
+		byIndex[string(index)] = append(byIndex[string(index)], email)
+		indexes = append(indexes, index)
+	}
+
+	query, args, err := pg.QueryBuilder().
+		Select("user_id", emailIndexColumn, "is_primary").
+		From(userEmailsTable).
+		Where(sq.Eq{emailIndexColumn: indexes}).
+		Where("deleted_at IS NULL").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := pg.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var (
+			owner models.EmailOwner
+			index []byte
+		)
+		if err := rows.Scan(&owner.UserID, &index, &owner.Primary); err != nil {
+			return nil, err
+		}
+		for _, email := range byIndex[string(index)] {
+			owners[email] = owner
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return owners, r.getPlaintextEmailOwners(ctx, emails, owners)
+}
+
+// getPlaintextEmailOwners adds the live users stored in plaintext holding the
+// emails to owners.
+func (r *Repository) getPlaintextEmailOwners(ctx context.Context, emails []string, owners map[string]models.EmailOwner) error {
+	query, args, err := pg.QueryBuilder().
+		Select("id", emailColumn).
+		From(usersTable).
+		Where(sq.Eq{emailColumn: emails}).
+		Where(notDeleted).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := pg.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var (
+			owner = models.EmailOwner{Primary: true}
+			email string
+		)
+		if err := rows.Scan(&owner.UserID, &email); err != nil {
+			return err
+		}
+		owners[email] = owner
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,452 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/crypto"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+// Columns holding PII are encrypted with the data key of the user. Email and
+// country also get a blind index so they can still be filtered on.
+const (
+	emailColumn     = "email"
+	firstNameColumn = "first_name"
+	lastNameColumn  = "last_name"
+	countryColumn   = "country"
+
+	emailIndexColumn   = "email_index"
+	countryIndexColumn = "country_index"
+
+	// emailIndexNormalizedColumn is unset on the rows whose email index was
+	// computed before emails were normalized.
+	emailIndexNormalizedColumn = "email_index_normalized"
+)
+
+// sealedPII holds the encrypted PII columns of a user.
+type sealedPII struct {
+	email        []byte
+	firstName    []byte
+	lastName     []byte
+	country      []byte
+	emailIndex   []byte
+	countryIndex []byte
+	// emailTokens and nameTokens are the search tokens of the email and of
+	// the first and last name.
+	emailTokens string
+	nameTokens  string
+}
+
+// userRow is a users row as read, before its PII is decrypted. Rows written
+// before encryption have plaintext PII and no ciphertext.
+type userRow struct {
+	user       models.User
+	plain      plainPII
+	sealed     sealedPII
+	attributes []byte
+}
+
+// plainPII holds the PII columns of a user written before encryption.
+type plainPII struct {
+	email     sql.NullString
+	firstName sql.NullString
+	lastName  sql.NullString
+	country   sql.NullString
+}
+
+func (s sealedPII) encrypted() bool {
+	return s.email != nil
+}
+
+// sealPII encrypts the PII of the user with its data key. The user id and the
+// column name are authenticated, so ciphertexts cannot be moved between rows
+// or columns.
+func (r *Repository) sealPII(key []byte, u models.User) (sealedPII, error) {
+	var s sealedPII
+
+	fields := []struct {
+		column string
+		value  string
+		dst    *[]byte
+	}{
+		{emailColumn, u.Email, &s.email},
+		{firstNameColumn, u.FirstName, &s.firstName},
+		{lastNameColumn, u.LastName, &s.lastName},
+		{countryColumn, u.Country, &s.country},
+	}
+
+	for _, f := range fields {
+		ciphertext, err := crypto.Encrypt(key, []byte(f.value), piiAAD(u.ID, f.column))
+		if err != nil {
+			return sealedPII{}, fmt.Errorf("encrypting %s: %w", f.column, err)
+		}
+		*f.dst = ciphertext
+	}
+
+	s.emailIndex = r.blindIndex(emailColumn, u.Email)
+	s.countryIndex = r.blindIndex(countryColumn, u.Country)
+	s.emailTokens = r.searchTokens(u.Email)
+	s.nameTokens = r.searchTokens(u.FirstName, u.LastName)
+
+	return s, nil
+}
+
+// openPII decrypts the PII of the row into its user.
+func openPII(key []byte, row *userRow) error {
+	fields := []struct {
+		column     string
+		ciphertext []byte
+		dst        *string
+	}{
+		{emailColumn, row.sealed.email, &row.user.Email},
+		{firstNameColumn, row.sealed.firstName, &row.user.FirstName},
+		{lastNameColumn, row.sealed.lastName, &row.user.LastName},
+		{countryColumn, row.sealed.country, &row.user.Country},
+	}
+
+	for _, f := range fields {
+		plaintext, err := crypto.Decrypt(key, f.ciphertext, piiAAD(row.user.ID, f.column))
+		if err != nil {
+			return fmt.Errorf("decrypting %s of user %s: %w", f.column, row.user.ID, err)
+		}
+		*f.dst = string(plaintext)
+	}
+
+	return nil
+}
+
+func piiAAD(userID uuid.UUID, column string) []byte {
+	return append(userID[:], column...)
+}
+
+// blindIndex computes the blind index of the value of the column. Emails are
+// normalized first, so that they match whatever their case.
+func (r *Repository) blindIndex(column string, value string) []byte {
+	if column == emailColumn {
+		value = normalizeEmail(value)
+	}
+	return r.index.Compute(column, value)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// setPII adds the encrypted PII to an update and clears any plaintext.
+func setPII(qb sq.UpdateBuilder, s sealedPII) sq.UpdateBuilder {
+	return qb.
+		Set(emailColumn, nil).
+		Set(firstNameColumn, nil).
+		Set(lastNameColumn, nil).
+		Set(countryColumn, nil).
+		Set("email_ciphertext", s.email).
+		Set("first_name_ciphertext", s.firstName).
+		Set("last_name_ciphertext", s.lastName).
+		Set("country_ciphertext", s.country).
+		Set(emailIndexColumn, s.emailIndex).
+		Set(emailIndexNormalizedColumn, true).
+		Set(countryIndexColumn, s.countryIndex).
+		Set(searchTokensColumn, searchVector(s))
+}
+
+// piiFilter matches rows whose column equals value, whether encrypted or not.
+func (r *Repository) piiFilter(column string, indexColumn string, value string) sq.Sqlizer {
+	return sq.Or{
+		sq.Eq{indexColumn: r.blindIndex(column, value)},
+		sq.Eq{column: value},
+	}
+}
+
+// openUsers decrypts the PII of the rows.
+func (r *Repository) openUsers(ctx context.Context, q pg.Querier, rows []userRow) ([]models.User, error) {
+	var ids []uuid.UUID
+	for _, row := range rows {
+		if row.sealed.encrypted() {
+			ids = append(ids, row.user.ID)
+		}
+	}
+
+	keys, err := r.keys.Lookup(ctx, q, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]models.User, 0, len(rows))
+	for _, row := range rows {
+		if row.sealed.encrypted() {
+			key, ok := keys[row.user.ID]
+			if !ok {
+				return nil, fmt.Errorf("missing data key of user %s", row.user.ID)
+			}
+			if err := openPII(key, &row); err != nil {
+				return nil, err
+			}
+		} else {
+			row.user.Email = row.plain.email.String
+			row.user.FirstName = row.plain.firstName.String
+			row.user.LastName = row.plain.lastName.String
+			row.user.Country = row.plain.country.String
+		}
+
+		row.user.Attributes, err = unmarshalAttributes(row.attributes)
+		if err != nil {
+			return nil, fmt.Errorf("decoding attributes of user %s: %w", row.user.ID, err)
+		}
+
+		users = append(users, row.user)
+	}
+
+	return users, nil
+}
+
+// EncryptPlaintextUsers encrypts the PII of up to limit users written before
+// encryption, and returns how many were encrypted. Their email is recorded as
+// their primary address on the way.
+func (r *Repository) EncryptPlaintextUsers(ctx context.Context, limit uint64) (int, error) {
+	query, args, err := pg.QueryBuilder().
+		Select("id", emailColumn, firstNameColumn, lastNameColumn, countryColumn, "deleted_at").
+		From(usersTable).
+		Where("email_ciphertext IS NULL").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var encrypted int
+	err = pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		var users []models.User
+		for rows.Next() {
+			var u models.User
+			if err := rows.Scan(&u.ID, &u.Email, &u.FirstName, &u.LastName, &u.Country, &u.DeletedAt); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			users = append(users, u)
+		}
+		_ = rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			key, err := r.keys.ForUser(ctx, tx, u.ID)
+			if err != nil {
+				return err
+			}
+
+			sealed, err := r.sealPII(key, u)
+			if err != nil {
+				return err
+			}
+
+			updateQuery, updateArgs, err := setPII(pg.QueryBuilder().Update(usersTable), sealed).
+				Where("id = ?", u.ID).
+				ToSql()
+
+			if err != nil {
+				return fmt.Errorf("could not build query sql query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, updateQuery, updateArgs...); err != nil {
+				return err
+			}
+
+			if u.Email != "" {
+				if err := upsertPrimaryEmail(ctx, tx, u.ID, sealed, u.DeletedAt); err != nil {
+					return err
+				}
+			}
+		}
+
+		encrypted = len(users)
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return encrypted, nil
+}
+
+// NormalizeEmailIndexes computes again the email index of up to limit users,
+// and of up to limit of their email addresses, indexed before emails were
+// normalized. It returns how many rows were indexed. It fails with
+// models.ErrEmailTaken when two live addresses only differ in case; one of
+// them has to be changed first.
+func (r *Repository) NormalizeEmailIndexes(ctx context.Context, limit uint64) (int, error) {
+	usersQuery, usersArgs, err := pg.QueryBuilder().
+		Select(userColumns...).
+		From(usersTable).
+		Where("NOT " + emailIndexNormalizedColumn).
+		Where("email_ciphertext IS NOT NULL").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	emailsQuery, emailsArgs, err := pg.QueryBuilder().
+		Select("user_id", "email_ciphertext", emailIndexColumn).
+		From(userEmailsTable).
+		Where("NOT " + emailIndexNormalizedColumn).
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var indexed int
+	err = pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, usersQuery, usersArgs...)
+		if err != nil {
+			return err
+		}
+
+		var userRows []userRow
+		for rows.Next() {
+			u, err := scanUser(rows)
+			if err != nil {
+				_ = rows.Close()
+				return err
+			}
+			userRows = append(userRows, u)
+		}
+		_ = rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		users, err := r.openUsers(ctx, tx, userRows)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			updateQuery, updateArgs, err := pg.QueryBuilder().
+				Update(usersTable).
+				Set(emailIndexColumn, r.blindIndex(emailColumn, u.Email)).
+				Set(emailIndexNormalizedColumn, true).
+				Where("id = ?", u.ID).
+				ToSql()
+
+			if err != nil {
+				return fmt.Errorf("could not build query sql query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, updateQuery, updateArgs...); err != nil {
+				if pg.IsUniqueViolation(err) {
+					return fmt.Errorf("normalizing email index of user %s: %w", u.ID, models.ErrEmailTaken)
+				}
+				return err
+			}
+		}
+
+		n, err := r.normalizeContactEmailIndexes(ctx, tx, emailsQuery, emailsArgs)
+		if err != nil {
+			return err
+		}
+
+		indexed = len(users) + n
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return indexed, nil
+}
+
+func (r *Repository) normalizeContactEmailIndexes(ctx context.Context, tx *sql.Tx, query string, args []any) (int, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	type emailRow struct {
+		userID     uuid.UUID
+		ciphertext []byte
+		index      []byte
+	}
+
+	var (
+		emails  []emailRow
+		userIDs []uuid.UUID
+	)
+	for rows.Next() {
+		var e emailRow
+		if err := rows.Scan(&e.userID, &e.ciphertext, &e.index); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		emails = append(emails, e)
+		userIDs = append(userIDs, e.userID)
+	}
+	_ = rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	keys, err := r.keys.Lookup(ctx, tx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range emails {
+		key, ok := keys[e.userID]
+		if !ok {
+			return 0, fmt.Errorf("missing data key of user %s", e.userID)
+		}
+
+		plaintext, err := crypto.Decrypt(key, e.ciphertext, piiAAD(e.userID, emailColumn))
+		if err != nil {
+			return 0, fmt.Errorf("decrypting %s of user %s: %w", emailColumn, e.userID, err)
+		}
+
+		updateQuery, updateArgs, err := pg.QueryBuilder().
+			Update(userEmailsTable).
+			Set(emailIndexColumn, r.blindIndex(emailColumn, string(plaintext))).
+			Set(emailIndexNormalizedColumn, true).
+			Where("user_id = ?", e.userID).
+			Where(sq.Eq{emailIndexColumn: e.index}).
+			ToSql()
+
+		if err != nil {
+			return 0, fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, updateQuery, updateArgs...); err != nil {
+			if pg.IsUniqueViolation(err) {
+				return 0, fmt.Errorf("normalizing email index of user %s: %w", e.userID, models.ErrEmailTaken)
+			}
+			return 0, err
+		}
+	}
+
+	return len(emails), nil
+}
@@ -0,0 +1,225 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/crypto"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	auditlog "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/identity"
+)
+
+// piiColumns are the columns of users encrypted with the data key of the user.
+var piiColumns = []string{emailColumn, firstNameColumn, lastNameColumn, countryColumn}
+
+// ReplaceDataKeys gives a fresh data key to up to limit users whose key was
+// created before createdBefore, and encrypts everything encrypted with their
+// old key again with it: their PII, contact points, audit entries and
+// identity claims. Users are processed by id, starting after the given one;
+// the id of the last user processed is returned with their number, to resume
+// after it. Replaced keys are created after createdBefore, so the users that
+// were processed are not processed again.
+func (r *Repository) ReplaceDataKeys(ctx context.Context, createdBefore time.Time, after uuid.UUID, limit uint64) (int, uuid.UUID, error) {
+	// The users are locked before their keys, in the order writers lock them.
+	query, args, err := pg.QueryBuilder().
+		Select("users.id").
+		From(usersTable).
+		Join("user_data_keys ON user_data_keys.user_id = users.id").
+		Where("user_data_keys.created_at < ?", createdBefore).
+		Where("users.id > ?", after).
+		OrderBy("users.id").
+		Limit(limit).
+		Suffix("FOR NO KEY UPDATE OF users").
+		ToSql()
+
+	if err != nil {
+		return 0, uuid.Nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var userIDs []uuid.UUID
+	err = pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var userID uuid.UUID
+			if err := rows.Scan(&userID); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			userIDs = append(userIDs, userID)
+		}
+		_ = rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			if err := r.replaceDataKey(ctx, tx, userID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, uuid.Nil, err
+	}
+
+	if len(userIDs) == 0 {
+		return 0, after, nil
+	}
+
+	return len(userIDs), userIDs[len(userIDs)-1], nil
+}
+
+func (r *Repository) replaceDataKey(ctx context.Context, tx *sql.Tx, userID uuid.UUID) error {
+	oldKey, newKey, err := r.keys.Replace(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := reencryptPII(ctx, tx, userID, oldKey, newKey); err != nil {
+		return err
+	}
+
+	for _, k := range []contactKind{emailContact, phoneContact} {
+		if err := reencryptContacts(ctx, tx, k, userID, oldKey, newKey); err != nil {
+			return err
+		}
+	}
+
+	if err := auditlog.ReencryptEntries(ctx, tx, userID, oldKey, newKey); err != nil {
+		return err
+	}
+
+	return identity.ReencryptClaims(ctx, tx, userID, oldKey, newKey)
+}
+
+// reencryptPII encrypts the PII columns of the user again. Users written
+// before encryption have nothing to encrypt again.
+func reencryptPII(ctx context.Context, tx *sql.Tx, userID uuid.UUID, oldKey []byte, newKey []byte) error {
+	qb := pg.QueryBuilder().
+		Select().
+		From(usersTable).
+		Where("id = ?", userID).
+		Where("email_ciphertext IS NOT NULL")
+
+	for _, column := range piiColumns {
+		qb = qb.Column(column + "_ciphertext")
+	}
+
+	query, args, err := qb.ToSql()
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	ciphertexts := make([][]byte, len(piiColumns))
+	dest := make([]any, len(piiColumns))
+	for i := range ciphertexts {
+		dest[i] = &ciphertexts[i]
+	}
+
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(dest...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	update := pg.QueryBuilder().
+		Update(usersTable).
+		Where("id = ?", userID)
+
+	for i, column := range piiColumns {
+		reencrypted, err := crypto.Reencrypt(oldKey, newKey, ciphertexts[i], piiAAD(userID, column))
+		if err != nil {
+			return fmt.Errorf("encrypting %s of user %s again: %w", column, userID, err)
+		}
+		update = update.Set(column+"_ciphertext", reencrypted)
+	}
+
+	updateQuery, updateArgs, err := update.ToSql()
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, updateQuery, updateArgs...)
+	return err
+}
+
+// reencryptContacts encrypts the contact points of the kind of the user
+// again, deleted ones included.
+func reencryptContacts(ctx context.Context, tx *sql.Tx, k contactKind, userID uuid.UUID, oldKey []byte, newKey []byte) error {
+	query, args, err := pg.QueryBuilder().
+		Select(k.indexColumn(), k.ciphertextColumn()).
+		From(k.table).
+		Where("user_id = ?", userID).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	type contactRow struct {
+		index      []byte
+		ciphertext []byte
+	}
+
+	var contacts []contactRow
+	for rows.Next() {
+		var c contactRow
+		if err := rows.Scan(&c.index, &c.ciphertext); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		contacts = append(contacts, c)
+	}
+	_ = rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range contacts {
+		reencrypted, err := crypto.Reencrypt(oldKey, newKey, c.ciphertext, piiAAD(userID, k.column))
+		if err != nil {
+			return fmt.Errorf("encrypting %s of user %s again: %w", k.column, userID, err)
+		}
+
+		updateQuery, updateArgs, err := pg.QueryBuilder().
+			Update(k.table).
+			Set(k.ciphertextColumn(), reencrypted).
+			Where("user_id = ?", userID).
+			Where(sq.Eq{k.indexColumn(): c.index}).
+			ToSql()
+
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, updateQuery, updateArgs...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	"github.com/TonyPath/user-mng-grpc-service/internal/search"
+)
+
+const (
+	searchTokensColumn = "search_tokens"
+	// searchTokenField is the blind index field of search tokens. Every PII
+	// column shares it, so that a query matches any of them.
+	searchTokenField = "search"
+	// maxSearchCandidates is the number of users a search returns for ranking.
+	// Every page of results decrypts all of them, so it is kept small.
+	maxSearchCandidates = 200
+)
+
+// searchTokens returns the blind tokens of the words and trigrams of the
+// values, separated by spaces. Tokens are truncated HMACs, so the search index
+// reveals which users share words or trigrams but not what they are.
+func (r *Repository) searchTokens(values ...string) string {
+	var tokens []string
+	for _, v := range values {
+		for _, w := range search.Words(v) {
+			tokens = append(tokens, r.searchToken("w", w))
+		}
+		for _, t := range search.Trigrams(v) {
+			tokens = append(tokens, r.searchToken("t", t))
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+func (r *Repository) searchToken(kind string, s string) string {
+	return kind + hex.EncodeToString(r.index.Compute(searchTokenField, kind+s)[:8])
+}
+
+// searchVector is the tsvector of the search tokens of sealed PII, weighting
+// email matches above name matches.
+func searchVector(s sealedPII) sq.Sqlizer {
+	return sq.Expr("setweight(to_tsvector('simple', ?), 'A') || setweight(to_tsvector('simple', ?), 'B')",
+		s.emailTokens, s.nameTokens)
+}
+
+// SearchUsers returns up to maxSearchCandidates live users that may match the
+// query, roughly the best first: users sharing words or trigrams with the
+// query in their email, first or last name, and users with a similar
+// nickname. Ranking them is left to the caller, which sees their PII.
+func (r *Repository) SearchUsers(ctx context.Context, opts models.SearchUsersOptions) ([]models.User, error) {
+	tokens := strings.Fields(r.searchTokens(opts.Query))
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	tsquery := strings.Join(tokens, " | ")
+
+	qb := pg.QueryBuilder().
+		Select(userColumns...).
+		From(usersTable).
+		Where(notDeleted).
+		Where(sq.Or{
+			sq.Expr(searchTokensColumn+" @@ to_tsquery('simple', ?)", tsquery),
+			sq.Expr("nickname % ?", opts.Query),
+		}).
+		OrderByClause("ts_rank("+searchTokensColumn+", to_tsquery('simple', ?)) + similarity(nickname, ?) DESC", tsquery, opts.Query).
+		OrderBy("id").
+		Suffix("FETCH FIRST ? ROWS ONLY", maxSearchCandidates)
+
+	if opts.Status != "" {
+		qb = qb.Where("status = ?", opts.Status)
+	}
+
+	return r.queryUsers(ctx, qb)
+}
+
+// IndexSearchTokens computes the search tokens of up to limit encrypted users
+// written before search was introduced, and returns how many were indexed.
+func (r *Repository) IndexSearchTokens(ctx context.Context, limit uint64) (int, error) {
+	query, args, err := pg.QueryBuilder().
+		Select(userColumns...).
+		From(usersTable).
+		Where(searchTokensColumn + " IS NULL").
+		Where("email_ciphertext IS NOT NULL").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var indexed int
+	err = pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		var userRows []userRow
+		for rows.Next() {
+			u, err := scanUser(rows)
+			if err != nil {
+				_ = rows.Close()
+				return err
+			}
+			userRows = append(userRows, u)
+		}
+		_ = rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		users, err := r.openUsers(ctx, tx, userRows)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			s := sealedPII{
+				emailTokens: r.searchTokens(u.Email),
+				nameTokens:  r.searchTokens(u.FirstName, u.LastName),
+			}
+
+			updateQuery, updateArgs, err := pg.QueryBuilder().
+				Update(usersTable).
+				Set(searchTokensColumn, searchVector(s)).
+				Where("id = ?", u.ID).
+				ToSql()
+
+			if err != nil {
+				return fmt.Errorf("could not build query sql query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, updateQuery, updateArgs...); err != nil {
+				return err
+			}
+		}
+
+		indexed = len(users)
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return indexed, nil
+}
@@ -0,0 +1,136 @@
+package user
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+// getSortedUsers sorts the users by encrypted fields, which the database
+// cannot order. Every matching user is decrypted and compared, but only the
+// users up to the end of the page are held, so memory use grows with the page
+// rather than with the number of users. Pages start after the user of
+// opts.After when it is set, or are skipped by page number otherwise.
+func (r *Repository) getSortedUsers(ctx context.Context, opts models.GetUsersOptions) ([]models.User, error) {
+	skip := (opts.PageNumber - 1) * opts.PageSize
+
+	var after *models.User
+	if opts.After != nil {
+		u, err := r.getCursorUser(ctx, opts.After)
+		if err != nil {
+			return nil, err
+		}
+		after, skip = &u, 0
+	}
+
+	page := &sortedUsers{orderBy: opts.OrderBy, limit: skip + opts.PageSize}
+	err := r.StreamUsers(ctx, opts, func(u models.User) error {
+		if after == nil || lessUser(*after, u, opts.OrderBy) {
+			page.add(u)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := page.sorted()
+	if skip >= uint64(len(users)) {
+		return nil, nil
+	}
+
+	return users[skip:], nil
+}
+
+// getCursorUser returns the user a page sorted by encrypted fields ended with,
+// soft deleted or not, to resume after it.
+func (r *Repository) getCursorUser(ctx context.Context, cursor *models.UserCursor) (models.User, error) {
+	users, err := r.queryUsers(ctx, pg.QueryBuilder().
+		Select(userColumns...).
+		From(usersTable).
+		Where("id = ?", cursor.ID))
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if len(users) == 0 {
+		return models.User{}, fmt.Errorf("%w: %s", models.ErrCursorUserNotFound, cursor.ID)
+	}
+
+	return users[0], nil
+}
+
+// sortedUsers keeps the first limit users added, in order. The users are held
+// in a heap whose root is the last of them, replaced by any user sorting
+// before it once the limit is reached.
+type sortedUsers struct {
+	orderBy []models.UserOrder
+	limit   uint64
+	users   []models.User
+}
+
+func (s *sortedUsers) add(u models.User) {
+	switch {
+	case uint64(len(s.users)) < s.limit:
+		heap.Push(s, u)
+	case s.limit > 0 && lessUser(u, s.users[0], s.orderBy):
+		s.users[0] = u
+		heap.Fix(s, 0)
+	}
+}
+
+// sorted returns the users kept, in order.
+func (s *sortedUsers) sorted() []models.User {
+	sort.Slice(s.users, func(i, j int) bool {
+		return lessUser(s.users[i], s.users[j], s.orderBy)
+	})
+	return s.users
+}
+
+func (s *sortedUsers) Len() int { return len(s.users) }
+
+// Less orders the heap in reverse, so that its root is the last user.
+func (s *sortedUsers) Less(i, j int) bool { return lessUser(s.users[j], s.users[i], s.orderBy) }
+
+func (s *sortedUsers) Swap(i, j int) { s.users[i], s.users[j] = s.users[j], s.users[i] }
+
+func (s *sortedUsers) Push(x any) { s.users = append(s.users, x.(models.User)) }
+
+func (s *sortedUsers) Pop() any {
+	last := s.users[len(s.users)-1]
+	s.users = s.users[:len(s.users)-1]
+	return last
+}
+
+// lessUser reports whether a sorts before b. Strings are compared case
+// insensitively and ties are broken by creation time and id.
+func lessUser(a models.User, b models.User, orderBy []models.UserOrder) bool {
+	for _, o := range orderBy {
+		var c int
+		switch o.Field {
+		case models.UserOrderEmail:
+			c = strings.Compare(strings.ToLower(a.Email), strings.ToLower(b.Email))
+		case models.UserOrderLastName:
+			c = strings.Compare(strings.ToLower(a.LastName), strings.ToLower(b.LastName))
+		case models.UserOrderCountry:
+			c = strings.Compare(strings.ToLower(a.Country), strings.ToLower(b.Country))
+		case models.UserOrderCreatedAt:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if c != 0 {
+			return (c < 0) != o.Desc
+		}
+	}
+
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c < 0
+	}
+	return bytes.Compare(a.ID[:], b.ID[:]) < 0
+}
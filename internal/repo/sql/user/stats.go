@@ -0,0 +1,357 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	// 3rd party
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/crypto"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+)
+
+const (
+	userStatsTable = "user_stats_daily"
+	// statsLock keeps replicas from refreshing the rollup at the same time.
+	statsLock = "SELECT pg_try_advisory_xact_lock(hashtext('user_stats_daily'))"
+	// verifiedColumn tells whether the primary email of a user is verified.
+	verifiedColumn = "EXISTS (SELECT 1 FROM " + userEmailsTable + " e WHERE e.user_id = users.id AND e.is_primary AND e.verified_at IS NOT NULL)"
+)
+
+// statsSource is where users are counted from: the users themselves, or the
+// daily rollup of their counts.
+type statsSource struct {
+	table string
+	// signup is the signup time of the row, as a UTC timestamp.
+	signup   string
+	verified string
+	count    string
+	// where restricts the rows to those created within [after, before).
+	where func(qb sq.SelectBuilder, after, before *time.Time) sq.SelectBuilder
+}
+
+var (
+	liveStats = statsSource{
+		table:    usersTable,
+		signup:   "created_at AT TIME ZONE 'UTC'",
+		verified: verifiedColumn,
+		count:    "count(*)",
+		where: func(qb sq.SelectBuilder, after, before *time.Time) sq.SelectBuilder {
+			qb = qb.Where("deleted_at IS NULL")
+			if after != nil {
+				qb = qb.Where("created_at >= ?", *after)
+			}
+			if before != nil {
+				qb = qb.Where("created_at < ?", *before)
+			}
+			return qb
+		},
+	}
+
+	// rollupStats only supports bounds falling on UTC days.
+	rollupStats = statsSource{
+		table:    userStatsTable,
+		signup:   "day::timestamp",
+		verified: "verified",
+		count:    "COALESCE(sum(users), 0)",
+		where: func(qb sq.SelectBuilder, after, before *time.Time) sq.SelectBuilder {
+			if after != nil {
+				qb = qb.Where("day >= ?::date", after.UTC().Format(time.DateOnly))
+			}
+			if before != nil {
+				qb = qb.Where("day < ?::date", before.UTC().Format(time.DateOnly))
+			}
+			return qb
+		},
+	}
+)
+
+var statsBuckets = map[models.StatsBucket]string{
+	models.StatsBucketDay:   "day",
+	models.StatsBucketWeek:  "week",
+	models.StatsBucketMonth: "month",
+}
+
+// GetUserStats counts the live users, returning up to limit groups.
+func (r *Repository) GetUserStats(ctx context.Context, opts models.UserStatsOptions, limit uint64) ([]models.UserStatsGroup, error) {
+	return r.userStats(ctx, liveStats, opts, limit)
+}
+
+// GetUserStatsRollup counts the live users from the daily rollup, returning up
+// to limit groups along with the time of the refresh they were counted at. The
+// time is zero when the rollup was never refreshed.
+func (r *Repository) GetUserStatsRollup(ctx context.Context, opts models.UserStatsOptions, limit uint64) ([]models.UserStatsGroup, time.Time, error) {
+	query, args, err := pg.QueryBuilder().
+		Select("refreshed_at").
+		From(userStatsTable).
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	// Rows are rewritten together, so they share their refresh time.
+	var refreshedAt time.Time
+	err = pg.Conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&refreshedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	groups, err := r.userStats(ctx, rollupStats, opts, limit)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return groups, refreshedAt, nil
+}
+
+// RefreshUserStatsRollup rewrites the daily rollup from the live users. It
+// returns false without refreshing when another refresh is running.
+func (r *Repository) RefreshUserStatsRollup(ctx context.Context) (bool, error) {
+	insert, args, err := pg.QueryBuilder().
+		Insert(userStatsTable).
+		Columns("day", countryIndexColumn, countryColumn, "status", "type", "verified", "users", "refreshed_at").
+		Select(pg.QueryBuilder().
+			Select("(created_at AT TIME ZONE 'UTC')::date", countryIndexColumn, countryColumn, "status", "type", verifiedColumn, "count(*)", "NOW()").
+			From(usersTable).
+			Where("deleted_at IS NULL").
+			GroupBy("1", "2", "3", "4", "5", "6")).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var refreshed bool
+	err = pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, statsLock).Scan(&refreshed); err != nil || !refreshed {
+			return err
+		}
+
+		// Readers keep seeing the previous rollup until the transaction
+		// commits.
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+userStatsTable); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, insert, args...)
+		return err
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return refreshed, nil
+}
+
+func (r *Repository) userStats(ctx context.Context, src statsSource, opts models.UserStatsOptions, limit uint64) ([]models.UserStatsGroup, error) {
+	// Dimensions users are not counted by are selected as constants, so that
+	// rows always scan the same way.
+	columns := []string{"NULL::timestamptz", "NULL::bytea", "NULL::varchar", "''", "''", "FALSE", src.count}
+	var groupBy []string
+
+	if opts.Bucket != models.StatsBucketNone {
+		unit, ok := statsBuckets[opts.Bucket]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown bucket %q", models.ErrInvalidStatsQuery, opts.Bucket)
+		}
+		columns[0] = "date_trunc('" + unit + "', " + src.signup + ") AT TIME ZONE 'UTC'"
+		groupBy = append(groupBy, "1")
+	}
+	if opts.Grouped(models.StatsByCountry) {
+		columns[1], columns[2] = countryIndexColumn, countryColumn
+		groupBy = append(groupBy, "2", "3")
+	}
+	if opts.Grouped(models.StatsByStatus) {
+		columns[3] = "status"
+		groupBy = append(groupBy, "4")
+	}
+	if opts.Grouped(models.StatsByType) {
+		columns[4] = "type"
+		groupBy = append(groupBy, "5")
+	}
+	if opts.Grouped(models.StatsByVerified) {
+		columns[5] = src.verified
+		groupBy = append(groupBy, "6")
+	}
+
+	qb := src.where(pg.QueryBuilder().Select(columns...).From(src.table), opts.CreatedAfter, opts.CreatedBefore)
+	if len(groupBy) > 0 {
+		qb = qb.GroupBy(groupBy...)
+	}
+
+	query, args, err := qb.Limit(limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	q := pg.Conn(ctx, r.db)
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	type statsRow struct {
+		group        models.UserStatsGroup
+		countryIndex []byte
+	}
+
+	var (
+		statsRows []statsRow
+		indexes   [][]byte
+	)
+	for rows.Next() {
+		var (
+			row         statsRow
+			bucketStart sql.NullTime
+			country     sql.NullString
+		)
+		err := rows.Scan(&bucketStart, &row.countryIndex, &country, &row.group.Status, &row.group.Type, &row.group.Verified, &row.group.Users)
+		if err != nil {
+			return nil, err
+		}
+		row.group.BucketStart = bucketStart.Time.UTC()
+		row.group.Country = country.String
+		if row.countryIndex != nil {
+			indexes = append(indexes, row.countryIndex)
+		}
+		statsRows = append(statsRows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	countries, err := r.countryNames(ctx, q, indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users written before encryption and after it are counted apart, and
+	// are merged once their countries are known.
+	merged := make(map[models.UserStatsGroup]int64, len(statsRows))
+	for _, row := range statsRows {
+		if row.countryIndex != nil {
+			row.group.Country = countries[string(row.countryIndex)]
+		}
+		users := row.group.Users
+		row.group.Users = 0
+		merged[row.group] += users
+	}
+
+	groups := make([]models.UserStatsGroup, 0, len(merged))
+	for g, users := range merged {
+		g.Users = users
+		groups = append(groups, g)
+	}
+	sortStatsGroups(groups)
+
+	return groups, nil
+}
+
+// countryNames returns the countries of the blind indexes, by decrypting the
+// country of a user having each. Countries no user has anymore are left out.
+func (r *Repository) countryNames(ctx context.Context, q pg.Querier, indexes [][]byte) (map[string]string, error) {
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := pg.QueryBuilder().
+		Select("DISTINCT ON ("+countryIndexColumn+") id", countryIndexColumn, "country_ciphertext").
+		From(usersTable).
+		Where(sq.Eq{countryIndexColumn: indexes}).
+		Where("country_ciphertext IS NOT NULL").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	type sample struct {
+		userID     uuid.UUID
+		index      []byte
+		ciphertext []byte
+	}
+
+	var (
+		samples []sample
+		userIDs []uuid.UUID
+	)
+	for rows.Next() {
+		var s sample
+		if err := rows.Scan(&s.userID, &s.index, &s.ciphertext); err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+		userIDs = append(userIDs, s.userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	keys, err := r.keys.Lookup(ctx, q, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(samples))
+	for _, s := range samples {
+		key, ok := keys[s.userID]
+		if !ok {
+			continue
+		}
+		country, err := crypto.Decrypt(key, s.ciphertext, piiAAD(s.userID, countryColumn))
+		if err != nil {
+			return nil, fmt.Errorf("decrypting %s of user %s: %w", countryColumn, s.userID, err)
+		}
+		names[string(s.index)] = string(country)
+	}
+
+	return names, nil
+}
+
+// sortStatsGroups orders groups by bucket, then by the values of their
+// dimensions.
+func sortStatsGroups(groups []models.UserStatsGroup) {
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		switch {
+		case !a.BucketStart.Equal(b.BucketStart):
+			return a.BucketStart.Before(b.BucketStart)
+		case a.Country != b.Country:
+			return a.Country < b.Country
+		case a.Status != b.Status:
+			return a.Status < b.Status
+		case a.Type != b.Type:
+			return a.Type < b.Type
+		default:
+			return !a.Verified && b.Verified
+		}
+	})
+}
@@ -3,48 +3,122 @@ package user
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	// 3rd party
+	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/crypto"
 	"github.com/TonyPath/user-mng-grpc-service/internal/models"
 	pg "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	auditlog "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/datakey"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/idempotency"
 )
 
-const usersTable = "users"
+const (
+	usersTable        = "users"
+	groupMembersTable = "group_members"
+	apiKeysTable      = "api_keys"
+
+	// notDeleted restricts a query to users that have not been soft deleted.
+	notDeleted = "deleted_at IS NULL"
+	// deleted restricts a query to soft deleted users.
+	deleted = "deleted_at IS NOT NULL"
+	// consentStatus is the current status of the consent of a user for a purpose.
+	consentStatus = "(SELECT c.status FROM consents c WHERE c.user_id = users.id AND c.purpose = ? ORDER BY c.id DESC LIMIT 1) = ?"
+
+	// exactCountLimit is the number of users counted exactly.
+	exactCountLimit = 10000
+)
+
+var userColumns = []string{
+	"id", "type", "status", "email", "first_name", "last_name", "nickname", "password", "country", "created_at", "updated_at", "deleted_at",
+	"email_ciphertext", "first_name_ciphertext", "last_name_ciphertext", "country_ciphertext",
+	"attributes",
+}
 
 type Repository struct {
 	db     *sql.DB
+	keys   *datakey.Store
+	index  *crypto.BlindIndex
 	logger *zap.SugaredLogger
 }
 
-func NewRepository(db *sql.DB, log *zap.SugaredLogger) *Repository {
+func NewRepository(db *sql.DB, keys *datakey.Store, index *crypto.BlindIndex, log *zap.SugaredLogger) *Repository {
 	return &Repository{
 		db:     db,
+		keys:   keys,
+		index:  index,
 		logger: log,
 	}
 }
 
+// RunInTx runs fn in a single transaction: the methods of the repository
+// called with the context passed to fn take part in it.
+func (r *Repository) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return pg.RunInTx(ctx, r.db, fn)
+}
+
+// InsertUser stores the user with its PII encrypted by a new data key.
 func (r *Repository) InsertUser(ctx context.Context, user models.User) (uuid.UUID, error) {
-	query, args, err := pg.QueryBuilder().
-		Insert(usersTable).
-		Columns("id", "email", "first_name", "last_name", "nickname", "password", "country").
-		Values(user.ID, user.Email, user.FirstName, user.LastName, user.Nickname, user.Password, user.Country).
-		Suffix("RETURNING id").
-		ToSql()
+	var userID uuid.UUID
+	err := pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		key, err := r.keys.ForUser(ctx, tx, user.ID)
+		if err != nil {
+			return err
+		}
 
-	if err != nil {
-		return uuid.Nil, nil
-	}
+		sealed, err := r.sealPII(key, user)
+		if err != nil {
+			return err
+		}
 
-	row := r.db.QueryRowContext(ctx, query, args...)
+		attributes, err := marshalAttributes(user.Attributes)
+		if err != nil {
+			return err
+		}
+
+		query, args, err := pg.QueryBuilder().
+			Insert(usersTable).
+			Columns("id", "type", "status", "nickname", "password",
+				"email_ciphertext", "first_name_ciphertext", "last_name_ciphertext", "country_ciphertext",
+				emailIndexColumn, countryIndexColumn, searchTokensColumn, "attributes").
+			Values(user.ID, user.Type, user.Status, user.Nickname, user.Password,
+				sealed.email, sealed.firstName, sealed.lastName, sealed.country,
+				sealed.emailIndex, sealed.countryIndex, searchVector(sealed), attributes).
+			Suffix("RETURNING id").
+			ToSql()
+
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&userID); err != nil {
+			return err
+		}
+
+		if user.Email != "" {
+			if err := upsertPrimaryEmail(ctx, tx, userID, sealed, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := recordChanges(ctx, tx, models.UserChangeCreated, userID); err != nil {
+			return err
+		}
+
+		entry := audit.NewEntry(ctx, userID, models.AuditActionUserCreated, nil, audit.Snapshot(user))
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
 
-	var userID uuid.UUID
-	err = row.Scan(&userID)
 	if err != nil {
 		if pg.IsUniqueViolation(err) {
 			return uuid.Nil, models.ErrEmailTaken
@@ -55,24 +129,64 @@ func (r *Repository) InsertUser(ctx context.Context, user models.User) (uuid.UUI
 	return userID, nil
 }
 
+// UpdateUser replaces the profile of the user. PII written before encryption
+// is encrypted on the way.
 func (r *Repository) UpdateUser(ctx context.Context, userID uuid.UUID, user models.User) error {
-	query, args, err := pg.QueryBuilder().
-		Update(usersTable).
-		Set("email", user.Email).
-		Set("first_name", user.FirstName).
-		Set("last_name", user.LastName).
-		Set("nickname", user.Nickname).
-		Set("password", user.Password).
-		Set("country", user.Country).
-		Set("updated_at", user.UpdateAt).
-		Where("id = ?", userID).
-		ToSql()
+	err := pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		current, err := r.lockUser(ctx, tx, userID, notDeleted)
+		if err != nil {
+			return err
+		}
 
-	if err != nil {
-		return err
-	}
+		key, err := r.keys.ForUser(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		user.ID = userID
+		sealed, err := r.sealPII(key, user)
+		if err != nil {
+			return err
+		}
+
+		attributes, err := marshalAttributes(user.Attributes)
+		if err != nil {
+			return err
+		}
+
+		qb := pg.QueryBuilder().
+			Update(usersTable).
+			Set("nickname", user.Nickname).
+			Set("password", user.Password).
+			Set("attributes", attributes).
+			Set("updated_at", user.UpdateAt).
+			Where("id = ?", userID).
+			Where(notDeleted)
+
+		query, args, err := setPII(qb, sealed).ToSql()
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		if user.Email != current.Email {
+			if err := upsertPrimaryEmail(ctx, tx, userID, sealed, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := recordChanges(ctx, tx, models.UserChangeUpdated, userID); err != nil {
+			return err
+		}
+
+		before, after := audit.Diff(current, user)
+		entry := audit.NewEntry(ctx, userID, models.AuditActionUserUpdated, before, after)
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
 
-	_, err = r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		if pg.IsUniqueViolation(err) {
 			return models.ErrEmailTaken
@@ -83,48 +197,472 @@ func (r *Repository) UpdateUser(ctx context.Context, userID uuid.UUID, user mode
 	return nil
 }
 
+// UpdateUserStatus moves the user from the status from to status. It fails with
+// models.ErrInvalidStatusTransition when the user is no longer in status from,
+// checked under the row lock so that concurrent changes cannot both apply.
+func (r *Repository) UpdateUserStatus(ctx context.Context, userID uuid.UUID, from models.UserStatus, status models.UserStatus, reason string, changedAt time.Time) error {
+	query, args, err := pg.QueryBuilder().
+		Update(usersTable).
+		Set("status", status).
+		Set("status_reason", reason).
+		Set("status_changed_at", changedAt).
+		Set("updated_at", changedAt).
+		Where("id = ?", userID).
+		Where("status = ?", from).
+		Where(notDeleted).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	return pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		current, err := r.lockUser(ctx, tx, userID, notDeleted)
+		if err != nil {
+			return err
+		}
+
+		if current.Status != from {
+			return fmt.Errorf("%w: %s -> %s", models.ErrInvalidStatusTransition, current.Status, status)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		if err := recordChanges(ctx, tx, models.UserChangeUpdated, userID); err != nil {
+			return err
+		}
+
+		entry := audit.NewEntry(ctx, userID, models.AuditActionUserStatusChanged,
+			map[string]any{"status": string(current.Status)},
+			map[string]any{"status": string(status), "status_reason": reason},
+		)
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
+}
+
+// DeleteUser soft deletes the user. Group memberships are removed and API keys
+// revoked in the same transaction; the row itself is kept until it is purged.
+// Its email addresses and phone numbers are released for other users.
 func (r *Repository) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	now := time.Now().UTC()
+
+	membersQuery, membersArgs, err := pg.QueryBuilder().
+		Delete(groupMembersTable).
+		Where("user_id = ?", userID).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	keysQuery, keysArgs, err := pg.QueryBuilder().
+		Update(apiKeysTable).
+		Set("revoked_at", now).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
 	query, args, err := pg.QueryBuilder().
-		Delete(usersTable).
+		Update(usersTable).
+		Set("deleted_at", now).
 		Where("id = ?", userID).
+		Where(notDeleted).
 		ToSql()
 
 	if err != nil {
 		return fmt.Errorf("could not build query sql query: %w", err)
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	return pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		current, err := r.lockUser(ctx, tx, userID, notDeleted)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, membersQuery, membersArgs...); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, keysQuery, keysArgs...); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		if err := setContactsDeletedAt(ctx, tx, userID, &now); err != nil {
+			return err
+		}
+
+		if err := recordChanges(ctx, tx, models.UserChangeDeleted, userID); err != nil {
+			return err
+		}
+
+		entry := audit.NewEntry(ctx, userID, models.AuditActionUserDeleted, audit.Snapshot(current), nil)
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
+}
+
+// RestoreUser brings back a soft deleted user, unless another user has taken
+// one of its email addresses or phone numbers meanwhile.
+func (r *Repository) RestoreUser(ctx context.Context, userID uuid.UUID, restoredAt time.Time) error {
+	query, args, err := pg.QueryBuilder().
+		Update(usersTable).
+		Set("deleted_at", nil).
+		Set("updated_at", restoredAt).
+		Where("id = ?", userID).
+		Where(deleted).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	err = pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		current, err := r.lockUser(ctx, tx, userID, deleted)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		if err := setContactsDeletedAt(ctx, tx, userID, nil); err != nil {
+			return err
+		}
+
+		if err := recordChanges(ctx, tx, models.UserChangeUpdated, userID); err != nil {
+			return err
+		}
+
+		entry := audit.NewEntry(ctx, userID, models.AuditActionUserRestored, nil, audit.Snapshot(current))
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
+
 	if err != nil {
+		if pg.IsUniqueViolation(err) {
+			return models.ErrEmailTaken
+		}
 		return err
 	}
 
 	return nil
 }
 
+// PurgeDeletedUsers permanently removes up to limit users soft deleted before
+// deletedBefore and returns their ids.
+func (r *Repository) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time, limit uint64) ([]uuid.UUID, error) {
+	selectQuery, selectArgs, err := pg.QueryBuilder().
+		Select("id").
+		From(usersTable).
+		Where("deleted_at < ?", deletedBefore).
+		OrderBy("deleted_at").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	var purged []uuid.UUID
+	err = pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, selectQuery, selectArgs...)
+		if err != nil {
+			return err
+		}
+
+		var ids []uuid.UUID
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		_ = rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, table := range []string{groupMembersTable, apiKeysTable} {
+			query, args, err := pg.QueryBuilder().
+				Delete(table).
+				Where(sq.Eq{"user_id": ids}).
+				ToSql()
+			if err != nil {
+				return fmt.Errorf("could not build query sql query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return err
+			}
+		}
+
+		query, args, err := pg.QueryBuilder().
+			Delete(usersTable).
+			Where(sq.Eq{"id": ids}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		entries := make([]models.AuditEntry, 0, len(ids))
+		for _, id := range ids {
+			entries = append(entries, audit.NewEntry(ctx, id, models.AuditActionUserPurged, nil, nil))
+		}
+
+		if err := auditlog.InsertEntries(ctx, tx, r.keys, entries...); err != nil {
+			return err
+		}
+
+		purged = ids
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
+
+// EraseUser permanently removes the user, whether soft deleted or not, along
+// with everything held about it. The data key of the user is destroyed, so
+// audit history encrypted with it can no longer be read.
+func (r *Repository) EraseUser(ctx context.Context, userID uuid.UUID) error {
+	return pg.WithinTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := r.lockUser(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		for _, table := range []string{groupMembersTable, apiKeysTable} {
+			query, args, err := pg.QueryBuilder().
+				Delete(table).
+				Where("user_id = ?", userID).
+				ToSql()
+			if err != nil {
+				return fmt.Errorf("could not build query sql query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return err
+			}
+		}
+
+		// Idempotent calls are recorded by the id of the user who made them.
+		if err := idempotency.DeleteCalls(ctx, tx, userID.String()); err != nil {
+			return err
+		}
+
+		if err := r.keys.Destroy(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		if err := auditlog.RedactEntries(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		query, args, err := pg.QueryBuilder().
+			Delete(usersTable).
+			Where("id = ?", userID).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("could not build query sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		if err := recordChanges(ctx, tx, models.UserChangeDeleted, userID); err != nil {
+			return err
+		}
+
+		entry := audit.NewEntry(ctx, userID, models.AuditActionUserErased, nil, nil)
+		return auditlog.InsertEntries(ctx, tx, r.keys, entry)
+	})
+}
+
+// GetUsersByFilter returns the users ordered by opts.OrderBy, by creation time
+// by default. Pages start after opts.After when it is set, or are skipped by
+// page number otherwise.
 func (r *Repository) GetUsersByFilter(ctx context.Context, opts models.GetUsersOptions) ([]models.User, error) {
+	if len(opts.OrderBy) > 0 && opts.OrderBy[0].Field != models.UserOrderCreatedAt {
+		return r.getSortedUsers(ctx, opts)
+	}
+
+	// Creation times practically never tie, so later sort fields are ignored;
+	// ties are broken by id.
+	desc := len(opts.OrderBy) > 0 && opts.OrderBy[0].Desc
+
 	qb := pg.QueryBuilder().
-		Select("id", "email", "first_name", "last_name", "nickname", "password", "country", "created_at", "updated_at").
-		From(usersTable).
-		Suffix("OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", (opts.PageNumber-1)*opts.PageSize, opts.PageSize)
+		Select(userColumns...).
+		From(usersTable)
+
+	if desc {
+		qb = qb.OrderBy("created_at DESC", "id DESC")
+	} else {
+		qb = qb.OrderBy("created_at", "id")
+	}
+
+	if opts.After != nil {
+		after := "(created_at, id) > (?, ?)"
+		if desc {
+			after = "(created_at, id) < (?, ?)"
+		}
+		qb = qb.Where(after, opts.After.CreatedAt, opts.After.ID).
+			Suffix("FETCH FIRST ? ROWS ONLY", opts.PageSize)
+	} else {
+		qb = qb.Suffix("OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", (opts.PageNumber-1)*opts.PageSize, opts.PageSize)
+	}
+
+	qb, err := r.filterUsers(qb, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.queryUsers(ctx, qb)
+}
+
+// CountUsers counts the users matching the filter. Counting stops after
+// exactCountLimit users, larger counts are estimated by the query planner.
+func (r *Repository) CountUsers(ctx context.Context, opts models.GetUsersOptions) (models.UserCount, error) {
+	matching, err := r.filterUsers(pg.QueryBuilder().Select("1").From(usersTable), opts)
+	if err != nil {
+		return models.UserCount{}, err
+	}
+
+	query, args, err := pg.QueryBuilder().
+		Select("count(*)").
+		FromSelect(matching.Suffix("FETCH FIRST ? ROWS ONLY", exactCountLimit+1), "matching").
+		ToSql()
+	if err != nil {
+		return models.UserCount{}, err
+	}
+
+	var count uint64
+	if err := pg.Conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return models.UserCount{}, err
+	}
+
+	if count <= exactCountLimit {
+		return models.UserCount{Total: count}, nil
+	}
+
+	estimate, err := r.estimateRows(ctx, matching)
+	if err != nil {
+		return models.UserCount{}, err
+	}
+
+	// Statistics may be stale, yet more users than the limit were seen.
+	if estimate <= exactCountLimit {
+		estimate = exactCountLimit + 1
+	}
+
+	return models.UserCount{Total: estimate, Estimated: true}, nil
+}
+
+// estimateRows returns the number of rows the planner expects the query to
+// return, derived from the table statistics in pg_class.
+func (r *Repository) estimateRows(ctx context.Context, qb sq.SelectBuilder) (uint64, error) {
+	query, args, err := qb.Prefix("EXPLAIN (FORMAT JSON)").ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var b []byte
+	if err := pg.Conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&b); err != nil {
+		return 0, err
+	}
+
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(b, &plans); err != nil {
+		return 0, fmt.Errorf("could not parse query plan: %w", err)
+	}
+	if len(plans) == 0 {
+		return 0, errors.New("empty query plan")
+	}
+
+	return uint64(plans[0].Plan.Rows), nil
+}
+
+// filterUsers restricts the query to the users matching the filter of opts.
+func (r *Repository) filterUsers(qb sq.SelectBuilder, opts models.GetUsersOptions) (sq.SelectBuilder, error) {
+	if !opts.IncludeDeleted {
+		qb = qb.Where(notDeleted)
+	}
 
 	if opts.Filter.Country != "" {
-		qb = qb.Where("country = ?", opts.Filter.Country)
+		qb = qb.Where(r.piiFilter(countryColumn, countryIndexColumn, opts.Filter.Country))
 	}
 
 	if opts.Filter.Email != "" {
-		qb = qb.Where("email = ?", opts.Filter.Email)
+		qb = qb.Where(r.piiFilter(emailColumn, emailIndexColumn, opts.Filter.Email))
 	}
 
 	if opts.Filter.Nickname != "" {
 		qb = qb.Where("nickname = ?", opts.Filter.Nickname)
 	}
 
+	if opts.Filter.Status != "" {
+		qb = qb.Where("status = ?", opts.Filter.Status)
+	}
+
+	if opts.Filter.ConsentPurpose != "" {
+		qb = qb.Where(consentStatus, opts.Filter.ConsentPurpose, models.ConsentStatusGranted)
+	}
+
+	if len(opts.Filter.Attributes) > 0 {
+		attributes, err := marshalAttributes(opts.Filter.Attributes)
+		if err != nil {
+			return qb, err
+		}
+		qb = qb.Where("attributes @> ?::jsonb", attributes)
+	}
+
+	if opts.Filter.Expression != nil {
+		pred, err := r.compileFilter(opts.Filter.Expression)
+		if err != nil {
+			return qb, err
+		}
+		qb = qb.Where(pred)
+	}
+
+	return qb, nil
+}
+
+// queryUsers runs the query, selecting userColumns, and returns the users
+// with their PII decrypted.
+func (r *Repository) queryUsers(ctx context.Context, qb sq.SelectBuilder) ([]models.User, error) {
 	query, args, err := qb.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := pg.Conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -132,20 +670,10 @@ func (r *Repository) GetUsersByFilter(ctx context.Context, opts models.GetUsersO
 		_ = rows.Close()
 	}()
 
-	var users []models.User
+	var users []userRow
 	for rows.Next() {
-		var u models.User
-		if err := rows.Scan(
-			&u.ID,
-			&u.Email,
-			&u.FirstName,
-			&u.LastName,
-			&u.Nickname,
-			&u.Password,
-			&u.Country,
-			&u.CreatedAt,
-			&u.UpdateAt,
-		); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
 		users = append(users, u)
@@ -155,34 +683,22 @@ func (r *Repository) GetUsersByFilter(ctx context.Context, opts models.GetUsersO
 		return nil, err
 	}
 
-	return users, nil
+	return r.openUsers(ctx, pg.Conn(ctx, r.db), users)
 }
 
 func (r *Repository) GetUserByID(ctx context.Context, userID uuid.UUID) (models.User, error) {
 	qb := pg.QueryBuilder().
-		Select("id", "email", "first_name", "last_name", "nickname", "password", "country", "created_at", "updated_at").
+		Select(userColumns...).
 		From(usersTable).
-		Where("id = ?", userID)
+		Where("id = ?", userID).
+		Where(notDeleted)
 
 	query, args, err := qb.ToSql()
 	if err != nil {
 		return models.User{}, err
 	}
 
-	var u models.User
-	row := r.db.QueryRowContext(ctx, query, args...)
-	err = row.Scan(
-		&u.ID,
-		&u.Email,
-		&u.FirstName,
-		&u.LastName,
-		&u.Nickname,
-		&u.Password,
-		&u.Country,
-		&u.CreatedAt,
-		&u.UpdateAt,
-	)
-
+	u, err := scanUser(pg.Conn(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, models.ErrUserNotFound
@@ -190,7 +706,12 @@ func (r *Repository) GetUserByID(ctx context.Context, userID uuid.UUID) (models.
 		return models.User{}, err
 	}
 
-	return u, nil
+	users, err := r.openUsers(ctx, pg.Conn(ctx, r.db), []userRow{u})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return users[0], nil
 }
 
 func (r *Repository) ExistsByID(ctx context.Context, userID uuid.UUID) (bool, error) {
@@ -199,6 +720,7 @@ func (r *Repository) ExistsByID(ctx context.Context, userID uuid.UUID) (bool, er
 		Prefix("SELECT EXISTS(").
 		From(usersTable).
 		Where("id = ?", userID).
+		Where(notDeleted).
 		Limit(1).
 		Suffix(")").
 		ToSql()
@@ -208,7 +730,7 @@ func (r *Repository) ExistsByID(ctx context.Context, userID uuid.UUID) (bool, er
 	}
 
 	var exists bool
-	row := r.db.QueryRowContext(ctx, query, args...)
+	row := pg.Conn(ctx, r.db).QueryRowContext(ctx, query, args...)
 	err = row.Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("could not query select: %w", err)
@@ -216,3 +738,65 @@ func (r *Repository) ExistsByID(ctx context.Context, userID uuid.UUID) (bool, er
 
 	return exists, nil
 }
+
+// lockUser loads the user matching conds and locks its row until tx ends.
+func (r *Repository) lockUser(ctx context.Context, tx *sql.Tx, userID uuid.UUID, conds ...string) (models.User, error) {
+	qb := pg.QueryBuilder().
+		Select(userColumns...).
+		From(usersTable).
+		Where("id = ?", userID).
+		Suffix("FOR UPDATE")
+
+	for _, cond := range conds {
+		qb = qb.Where(cond)
+	}
+
+	query, args, err := qb.ToSql()
+
+	if err != nil {
+		return models.User{}, fmt.Errorf("could not build query sql query: %w", err)
+	}
+
+	u, err := scanUser(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, models.ErrUserNotFound
+		}
+		return models.User{}, err
+	}
+
+	users, err := r.openUsers(ctx, tx, []userRow{u})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return users[0], nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (userRow, error) {
+	var u userRow
+	err := row.Scan(
+		&u.user.ID,
+		&u.user.Type,
+		&u.user.Status,
+		&u.plain.email,
+		&u.plain.firstName,
+		&u.plain.lastName,
+		&u.user.Nickname,
+		&u.user.Password,
+		&u.plain.country,
+		&u.user.CreatedAt,
+		&u.user.UpdateAt,
+		&u.user.DeletedAt,
+		&u.sealed.email,
+		&u.sealed.firstName,
+		&u.sealed.lastName,
+		&u.sealed.country,
+		&u.attributes,
+	)
+	return u, err
+}
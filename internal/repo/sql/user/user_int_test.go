@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -15,13 +16,19 @@ import (
 
 	// internal
 	"github.com/TonyPath/user-mng-grpc-service/dockertest"
+	"github.com/TonyPath/user-mng-grpc-service/internal/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/filter"
 	"github.com/TonyPath/user-mng-grpc-service/internal/models"
 	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql"
+	auditlog "github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/identity"
 	"github.com/TonyPath/user-mng-grpc-service/internal/repo/sql/sqltest"
 )
 
 var (
-	testDB *sqltest.DB
+	testDB     *sqltest.DB
+	dataKeys   = sqltest.NewDataKeys()
+	blindIndex = sqltest.NewBlindIndex()
 )
 
 func TestMain(m *testing.M) {
@@ -74,12 +81,14 @@ func run(m *testing.M) int {
 
 func TestRepository_InsertUser(t *testing.T) {
 
-	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
 
 	for i := 1; i <= 15; i++ {
 		userID := uuid.New()
 		user := models.User{
 			ID:        userID,
+			Type:      models.UserTypeHuman,
+			Status:    models.UserStatusActive,
 			Email:     fmt.Sprintf("antonis+%d@mail.com", i),
 			FirstName: fmt.Sprintf("tony+%d", i),
 			LastName:  fmt.Sprintf("papath+%d", i),
@@ -95,12 +104,13 @@ func TestRepository_InsertUser(t *testing.T) {
 		require.Equal(t, userID, gotUserID)
 
 		testDB.RequireTotalRows(t, "users", i)
+		testDB.RequireTotalRows(t, "audit_log", i)
 	}
 }
 
 func TestRepository_GetUsersByFilter(t *testing.T) {
 
-	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
 
 	t.Log("1st page")
 	{
@@ -121,11 +131,600 @@ func TestRepository_GetUsersByFilter(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, gotUsers, 5)
 	}
+
+	t.Log("keyset pages")
+	{
+		firstPage, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
+			PageNumber: 1,
+			PageSize:   10,
+		})
+		require.NoError(t, err)
+
+		last := firstPage[len(firstPage)-1]
+		gotUsers, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
+			PageSize: 10,
+			After:    &models.UserCursor{CreatedAt: last.CreatedAt, ID: last.ID},
+		})
+		require.NoError(t, err)
+		require.Len(t, gotUsers, 5)
+		for _, u := range gotUsers {
+			require.False(t, u.CreatedAt.Before(last.CreatedAt))
+			require.NotEqual(t, last.ID, u.ID)
+		}
+	}
+
+	t.Log("newest first")
+	{
+		gotUsers, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
+			PageNumber: 1,
+			PageSize:   20,
+			OrderBy:    []models.UserOrder{{Field: models.UserOrderCreatedAt, Desc: true}},
+		})
+		require.NoError(t, err)
+		require.Len(t, gotUsers, 15)
+		for i := 1; i < len(gotUsers); i++ {
+			require.False(t, gotUsers[i].CreatedAt.After(gotUsers[i-1].CreatedAt))
+		}
+	}
+
+	t.Log("sorted by encrypted email")
+	{
+		opts := models.GetUsersOptions{
+			PageNumber: 1,
+			PageSize:   10,
+			OrderBy:    []models.UserOrder{{Field: models.UserOrderEmail, Desc: true}},
+		}
+		firstPage, err := repo.GetUsersByFilter(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Len(t, firstPage, 10)
+		for i := 1; i < len(firstPage); i++ {
+			require.GreaterOrEqual(t, firstPage[i-1].Email, firstPage[i].Email)
+		}
+
+		opts.PageNumber = 2
+		secondPage, err := repo.GetUsersByFilter(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Len(t, secondPage, 5)
+
+		last := firstPage[9]
+		opts.After = &models.UserCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		gotUsers, err := repo.GetUsersByFilter(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Equal(t, secondPage, gotUsers)
+		require.GreaterOrEqual(t, last.Email, gotUsers[0].Email)
+
+		opts.After = &models.UserCursor{ID: uuid.New()}
+		_, err = repo.GetUsersByFilter(context.TODO(), opts)
+		require.ErrorIs(t, err, models.ErrCursorUserNotFound)
+	}
+}
+
+func TestRepository_CountUsers(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+
+	count, err := repo.CountUsers(context.TODO(), models.GetUsersOptions{})
+	require.NoError(t, err)
+	require.Equal(t, models.UserCount{Total: 15}, count)
+
+	opts := models.GetUsersOptions{}
+	opts.Filter.Email = "antonis+1@mail.com"
+
+	count, err = repo.CountUsers(context.TODO(), opts)
+	require.NoError(t, err)
+	require.Equal(t, models.UserCount{Total: 1}, count)
+
+	t.Log("estimate")
+	{
+		estimate, err := repo.estimateRows(context.TODO(), sql.QueryBuilder().Select("1").From(usersTable).Where(notDeleted))
+		require.NoError(t, err)
+		require.Greater(t, estimate, uint64(0))
+	}
+}
+
+func TestRepository_StreamUsers(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+
+	var users []models.User
+	err := repo.StreamUsers(context.TODO(), models.GetUsersOptions{}, func(u models.User) error {
+		users = append(users, u)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 15)
+	require.Equal(t, "antonis+1@mail.com", users[0].Email)
+	for i := 1; i < len(users); i++ {
+		require.False(t, users[i].CreatedAt.Before(users[i-1].CreatedAt))
+	}
+
+	t.Log("filtered")
+	{
+		opts := models.GetUsersOptions{}
+		opts.Filter.Email = "antonis+2@mail.com"
+
+		var emails []string
+		err := repo.StreamUsers(context.TODO(), opts, func(u models.User) error {
+			emails = append(emails, u.Email)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"antonis+2@mail.com"}, emails)
+	}
+
+	t.Log("stopped by the callback")
+	{
+		stop := errors.New("stop")
+
+		var n int
+		err := repo.StreamUsers(context.TODO(), models.GetUsersOptions{}, func(u models.User) error {
+			n++
+			return stop
+		})
+		require.ErrorIs(t, err, stop)
+		require.Equal(t, 1, n)
+	}
+}
+
+func TestRepository_GetUsersByFilter_Expression(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+
+	for expr, want := range map[string]int{
+		`country = "GR"`:         15,
+		`country != "GR"`:        0,
+		`nickname:"TonyPath+1*"`: 7,
+		`nickname:"TonyPath+1*" AND NOT nickname = "TonyPath+10"`: 6,
+		`email IN ("antonis+1@mail.com", "antonis+2@mail.com")`:   2,
+		`status = ACTIVE type = human created_at > "2000-01-01"`:  15,
+		`created_at < "2000-01-01" OR nickname = "TonyPath+3"`:    1,
+		`nickname:*`: 15,
+	} {
+		e, err := filter.Parse(expr)
+		require.NoError(t, err, expr)
+
+		opts := models.GetUsersOptions{PageNumber: 1, PageSize: 20}
+		opts.Filter.Expression = e
+
+		gotUsers, err := repo.GetUsersByFilter(context.TODO(), opts)
+		require.NoError(t, err, expr)
+		require.Len(t, gotUsers, want, expr)
+	}
+
+	for _, expr := range []string{
+		`password = "secret"`,
+		`email:"antonis*"`,
+		`status = banned`,
+		`created_at = null`,
+		`country > "GR"`,
+		`id = 42`,
+	} {
+		e, err := filter.Parse(expr)
+		require.NoError(t, err, expr)
+
+		opts := models.GetUsersOptions{PageNumber: 1, PageSize: 20}
+		opts.Filter.Expression = e
+
+		_, err = repo.GetUsersByFilter(context.TODO(), opts)
+		require.ErrorIs(t, err, models.ErrInvalidFilter, expr)
+	}
+}
+
+func TestRepository_SearchUsers(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+
+	gotUsers, err := repo.SearchUsers(context.TODO(), models.SearchUsersOptions{Query: "antonsi"})
+	require.NoError(t, err)
+	require.Len(t, gotUsers, 15)
+
+	var tokens string
+	err = testDB.Db.QueryRow(`SELECT search_tokens::text FROM users WHERE search_tokens IS NOT NULL LIMIT 1`).Scan(&tokens)
+	require.NoError(t, err)
+	require.NotContains(t, tokens, "antonis")
+
+	t.Log("similar nickname")
+	{
+		gotUsers, err := repo.SearchUsers(context.TODO(), models.SearchUsersOptions{Query: "TonyPat+3"})
+		require.NoError(t, err)
+		require.NotEmpty(t, gotUsers)
+	}
+
+	t.Log("status")
+	{
+		gotUsers, err := repo.SearchUsers(context.TODO(), models.SearchUsersOptions{Query: "antonsi", Status: models.UserStatusSuspended})
+		require.NoError(t, err)
+		require.Empty(t, gotUsers)
+	}
+
+	t.Log("users indexed later")
+	{
+		_, err := testDB.Db.Exec(`UPDATE users SET search_tokens = NULL`)
+		require.NoError(t, err)
+
+		gotUsers, err := repo.SearchUsers(context.TODO(), models.SearchUsersOptions{Query: "papath"})
+		require.NoError(t, err)
+		require.Empty(t, gotUsers)
+
+		indexed, err := repo.IndexSearchTokens(context.TODO(), 100)
+		require.NoError(t, err)
+		require.Equal(t, 15, indexed)
+
+		gotUsers, err = repo.SearchUsers(context.TODO(), models.SearchUsersOptions{Query: "papath"})
+		require.NoError(t, err)
+		require.Len(t, gotUsers, 15)
+	}
+}
+
+func TestRepository_UpdateUserStatus(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+
+	users, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   1,
+	})
+	require.NoError(t, err)
+
+	err = repo.UpdateUserStatus(context.TODO(), users[0].ID, models.UserStatusActive, models.UserStatusSuspended, "spam", time.Now())
+	require.NoError(t, err)
+
+	// A concurrent change already moved the user out of the active status.
+	err = repo.UpdateUserStatus(context.TODO(), users[0].ID, models.UserStatusActive, models.UserStatusDeactivated, "", time.Now())
+	require.ErrorIs(t, err, models.ErrInvalidStatusTransition)
+
+	err = repo.UpdateUserStatus(context.TODO(), uuid.New(), models.UserStatusActive, models.UserStatusSuspended, "spam", time.Now())
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+
+	opts := models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   20,
+	}
+	opts.Filter.Status = models.UserStatusSuspended
+
+	gotUsers, err := repo.GetUsersByFilter(context.TODO(), opts)
+	require.NoError(t, err)
+	require.Len(t, gotUsers, 1)
+	require.Equal(t, users[0].ID, gotUsers[0].ID)
+
+	err = repo.UpdateUserStatus(context.TODO(), users[0].ID, models.UserStatusSuspended, models.UserStatusActive, "", time.Now())
+	require.NoError(t, err)
+}
+
+func TestRepository_GetUsersByFilter_Consent(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+
+	users, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   2,
+	})
+	require.NoError(t, err)
+
+	for _, c := range []struct {
+		userID uuid.UUID
+		status models.ConsentStatus
+	}{
+		{users[0].ID, models.ConsentStatusGranted},
+		{users[1].ID, models.ConsentStatusGranted},
+		{users[1].ID, models.ConsentStatusWithdrawn},
+	} {
+		_, err := testDB.Db.Exec(`INSERT INTO consents (user_id, purpose, status) VALUES ($1, 'newsletter', $2)`, c.userID, c.status)
+		require.NoError(t, err)
+	}
+
+	opts := models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   20,
+	}
+	opts.Filter.ConsentPurpose = "newsletter"
+
+	gotUsers, err := repo.GetUsersByFilter(context.TODO(), opts)
+	require.NoError(t, err)
+	require.Len(t, gotUsers, 1)
+	require.Equal(t, users[0].ID, gotUsers[0].ID)
+}
+
+func TestRepository_GetUsersByFilter_Attributes(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+
+	users, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   2,
+	})
+	require.NoError(t, err)
+
+	user := users[0]
+	user.Attributes = map[string]any{"tier": "gold", "level": int64(3), "vip": true}
+	err = repo.UpdateUser(context.TODO(), user.ID, user)
+	require.NoError(t, err)
+
+	other := users[1]
+	other.Attributes = map[string]any{"tier": "silver"}
+	err = repo.UpdateUser(context.TODO(), other.ID, other)
+	require.NoError(t, err)
+
+	got, err := repo.GetUserByID(context.TODO(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Attributes, got.Attributes)
+
+	opts := models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   20,
+	}
+	opts.Filter.Attributes = map[string]any{"tier": "gold", "level": int64(3)}
+
+	gotUsers, err := repo.GetUsersByFilter(context.TODO(), opts)
+	require.NoError(t, err)
+	require.Len(t, gotUsers, 1)
+	require.Equal(t, user.ID, gotUsers[0].ID)
+}
+
+func TestRepository_UpdateUser_Audit(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+
+	users, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   1,
+	})
+	require.NoError(t, err)
+
+	ctx := audit.WithMetadata(context.TODO(), audit.Metadata{
+		Actor:     "backoffice:alice",
+		RequestID: "req-1",
+		ClientIP:  "203.0.113.9",
+	})
+
+	user := users[0]
+	user.Email = "audited@mail.com"
+	user.Password = []byte(`new-secret`)
+	err = repo.UpdateUser(ctx, user.ID, user)
+	require.NoError(t, err)
+
+	var encrypted []byte
+	err = testDB.Db.QueryRow(
+		`SELECT encrypted_values FROM audit_log WHERE user_id = $1 AND action = $2`,
+		user.ID, models.AuditActionUserUpdated,
+	).Scan(&encrypted)
+	require.NoError(t, err)
+	require.NotContains(t, string(encrypted), "audited@mail.com")
+
+	entries, err := auditlog.NewRepository(testDB.Db, dataKeys, zap.NewNop().Sugar()).GetAuditEntries(context.TODO(), models.GetAuditEntriesOptions{
+		UserID: user.ID,
+		Action: models.AuditActionUserUpdated,
+		Limit:  1,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "backoffice:alice", entries[0].Actor)
+	require.Equal(t, "req-1", entries[0].RequestID)
+	require.Equal(t, "203.0.113.9", entries[0].ClientIP)
+	require.Equal(t, map[string]any{"email": users[0].Email, "password": audit.Redacted}, entries[0].Before)
+	require.Equal(t, map[string]any{"email": "audited@mail.com", "password": audit.Redacted}, entries[0].After)
+
+	t.Log("audit log is append-only")
+	{
+		_, err := testDB.Db.Exec(`DELETE FROM audit_log WHERE user_id = $1`, user.ID)
+		require.Error(t, err)
+	}
+}
+
+func TestRepository_EncryptedPII(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+
+	user := models.User{
+		ID:        uuid.New(),
+		Type:      models.UserTypeHuman,
+		Status:    models.UserStatusActive,
+		Email:     "secret@mail.com",
+		FirstName: "hidden",
+		LastName:  "name",
+		Nickname:  "visible",
+		Country:   "CY",
+		Password:  []byte(`secret`),
+	}
+	_, err := repo.InsertUser(context.TODO(), user)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, repo.EraseUser(context.TODO(), user.ID))
+	}()
+
+	var (
+		email     *string
+		encrypted []byte
+	)
+	err = testDB.Db.QueryRow(`SELECT email, email_ciphertext FROM users WHERE id = $1`, user.ID).Scan(&email, &encrypted)
+	require.NoError(t, err)
+	require.Nil(t, email)
+	require.NotContains(t, string(encrypted), "secret@mail.com")
+
+	_, err = repo.InsertUser(context.TODO(), models.User{ID: uuid.New(), Type: models.UserTypeHuman, Status: models.UserStatusActive, Email: user.Email})
+	require.ErrorIs(t, err, models.ErrEmailTaken)
+
+	opts := models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   10,
+	}
+	opts.Filter.Email = user.Email
+
+	gotUsers, err := repo.GetUsersByFilter(context.TODO(), opts)
+	require.NoError(t, err)
+	require.Len(t, gotUsers, 1)
+	require.Equal(t, user.Email, gotUsers[0].Email)
+	require.Equal(t, user.FirstName, gotUsers[0].FirstName)
+	require.Equal(t, user.Country, gotUsers[0].Country)
+
+	t.Log("emails match whatever their case")
+	{
+		opts.Filter.Email = " Secret@Mail.COM"
+		gotUsers, err := repo.GetUsersByFilter(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Len(t, gotUsers, 1)
+
+		_, err = repo.InsertUser(context.TODO(), models.User{ID: uuid.New(), Type: models.UserTypeHuman, Status: models.UserStatusActive, Email: "SECRET@mail.com"})
+		require.ErrorIs(t, err, models.ErrEmailTaken)
+	}
+
+	t.Log("emails indexed before they were normalized")
+	{
+		mixed := models.User{ID: uuid.New(), Type: models.UserTypeHuman, Status: models.UserStatusActive, Email: "Mixed@Mail.com"}
+		_, err := repo.InsertUser(context.TODO(), mixed)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, repo.EraseUser(context.TODO(), mixed.ID))
+		}()
+
+		raw := blindIndex.Compute(emailColumn, mixed.Email)
+		for _, table := range []string{usersTable, userEmailsTable} {
+			column := "id"
+			if table == userEmailsTable {
+				column = "user_id"
+			}
+			_, err := testDB.Db.Exec(`UPDATE `+table+` SET email_index = $1, email_index_normalized = FALSE WHERE `+column+` = $2`, raw, mixed.ID)
+			require.NoError(t, err)
+		}
+
+		opts.Filter.Email = "mixed@mail.com"
+		gotUsers, err := repo.GetUsersByFilter(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Empty(t, gotUsers)
+
+		n, err := repo.NormalizeEmailIndexes(context.TODO(), 100)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+
+		n, err = repo.NormalizeEmailIndexes(context.TODO(), 100)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		gotUsers, err = repo.GetUsersByFilter(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Len(t, gotUsers, 1)
+		require.Equal(t, mixed.ID, gotUsers[0].ID)
+
+		owners, err := repo.GetEmailOwners(context.TODO(), []string{"mixed@mail.com", "MIXED@mail.com"})
+		require.NoError(t, err)
+		require.Len(t, owners, 2)
+	}
+
+	t.Log("users written before encryption")
+	{
+		legacyID := uuid.New()
+		_, err := testDB.Db.Exec(
+			`INSERT INTO users (id, email, first_name, last_name, nickname, country) VALUES ($1, 'legacy@mail.com', 'old', 'row', 'legacy', 'GR')`,
+			legacyID,
+		)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, repo.EraseUser(context.TODO(), legacyID))
+		}()
+
+		legacy, err := repo.GetUserByID(context.TODO(), legacyID)
+		require.NoError(t, err)
+		require.Equal(t, "legacy@mail.com", legacy.Email)
+
+		n, err := repo.EncryptPlaintextUsers(context.TODO(), 100)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		n, err = repo.EncryptPlaintextUsers(context.TODO(), 100)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		opts.Filter.Email = "legacy@mail.com"
+		gotUsers, err := repo.GetUsersByFilter(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Len(t, gotUsers, 1)
+		require.Equal(t, legacyID, gotUsers[0].ID)
+		require.Equal(t, "old", gotUsers[0].FirstName)
+	}
+
+	t.Log("data keys replaced")
+	{
+		ctx := context.TODO()
+		auditRepo := auditlog.NewRepository(testDB.Db, dataKeys, zap.NewNop().Sugar())
+		identityRepo := identity.NewRepository(testDB.Db, dataKeys, zap.NewNop().Sugar())
+
+		updated := user
+		updated.Nickname = "rekeyed"
+		require.NoError(t, repo.UpdateUser(ctx, user.ID, updated))
+		require.NoError(t, repo.InsertPhone(ctx, user.ID, "+35799000000"))
+		require.NoError(t, identityRepo.InsertIdentity(ctx, models.ExternalIdentity{
+			UserID:   user.ID,
+			Provider: "google",
+			Subject:  "secret-subject",
+			Claims:   map[string]any{"email": user.Email},
+			LinkedAt: time.Now(),
+		}))
+
+		var oldWrapped []byte
+		require.NoError(t, testDB.Db.QueryRow(`SELECT wrapped_key FROM user_data_keys WHERE user_id = $1`, user.ID).Scan(&oldWrapped))
+
+		cutoff := time.Now()
+		var (
+			after    uuid.UUID
+			replaced int
+		)
+		for {
+			n, last, err := repo.ReplaceDataKeys(ctx, cutoff, after, 2)
+			require.NoError(t, err)
+			if n == 0 {
+				require.Equal(t, after, last)
+				break
+			}
+			require.Greater(t, last.String(), after.String())
+			replaced += n
+			after = last
+		}
+		require.Positive(t, replaced)
+
+		n, _, err := repo.ReplaceDataKeys(ctx, cutoff, uuid.Nil, 100)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		var newWrapped []byte
+		require.NoError(t, testDB.Db.QueryRow(`SELECT wrapped_key FROM user_data_keys WHERE user_id = $1`, user.ID).Scan(&newWrapped))
+		require.NotEqual(t, oldWrapped, newWrapped)
+
+		got, err := repo.GetUserByID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, user.Email, got.Email)
+		require.Equal(t, user.FirstName, got.FirstName)
+		require.Equal(t, user.LastName, got.LastName)
+		require.Equal(t, user.Country, got.Country)
+
+		emails, err := repo.GetEmails(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, emails, 1)
+		require.Equal(t, user.Email, emails[0].Email)
+
+		phones, err := repo.GetPhones(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, phones, 1)
+		require.Equal(t, "+35799000000", phones[0].Number)
+
+		entries, err := auditRepo.GetAuditEntries(ctx, models.GetAuditEntriesOptions{
+			UserID: user.ID,
+			Action: models.AuditActionUserUpdated,
+			Limit:  100,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+		require.Equal(t, "rekeyed", entries[0].After["nickname"])
+
+		identities, err := identityRepo.GetIdentities(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, identities, 1)
+		require.Equal(t, user.Email, identities[0].Claims["email"])
+	}
 }
 
 func TestRepository_DeleteUser(t *testing.T) {
 
-	repo := NewRepository(testDB.Db, zap.NewNop().Sugar())
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
 
 	users, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
 		PageNumber: 1,
@@ -133,7 +732,544 @@ func TestRepository_DeleteUser(t *testing.T) {
 	})
 	require.NoError(t, err)
 
+	groupID := uuid.New()
+	_, err = testDB.Db.Exec(`INSERT INTO groups (id, name) VALUES ($1, 'admins')`, groupID)
+	require.NoError(t, err)
+	_, err = testDB.Db.Exec(`INSERT INTO group_members (group_id, user_id) VALUES ($1, $2)`, groupID, users[0].ID)
+	require.NoError(t, err)
+
 	err = repo.DeleteUser(context.TODO(), users[0].ID)
 	require.NoError(t, err)
-	testDB.RequireTotalRows(t, "users", 14)
+	testDB.RequireTotalRows(t, "users", 15)
+	testDB.RequireTotalRows(t, "group_members", 0)
+
+	_, err = repo.GetUserByID(context.TODO(), users[0].ID)
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+
+	live, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   20,
+	})
+	require.NoError(t, err)
+	require.Len(t, live, 14)
+
+	all, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
+		PageNumber:     1,
+		PageSize:       20,
+		IncludeDeleted: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, all, 15)
+
+	t.Log("restore")
+	{
+		err := repo.RestoreUser(context.TODO(), users[0].ID, time.Now())
+		require.NoError(t, err)
+
+		err = repo.RestoreUser(context.TODO(), users[0].ID, time.Now())
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+
+		_, err = repo.GetUserByID(context.TODO(), users[0].ID)
+		require.NoError(t, err)
+	}
+
+	t.Log("purge")
+	{
+		require.NoError(t, repo.DeleteUser(context.TODO(), users[0].ID))
+
+		purged, err := repo.PurgeDeletedUsers(context.TODO(), time.Now().Add(-time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, purged, 0)
+
+		purged, err = repo.PurgeDeletedUsers(context.TODO(), time.Now().Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{users[0].ID}, purged)
+		testDB.RequireTotalRows(t, "users", 14)
+	}
+}
+
+func TestRepository_EraseUser(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+	auditRepo := auditlog.NewRepository(testDB.Db, dataKeys, zap.NewNop().Sugar())
+
+	users, err := repo.GetUsersByFilter(context.TODO(), models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   1,
+	})
+	require.NoError(t, err)
+	user := users[0]
+
+	user.Nickname = "erased"
+	require.NoError(t, repo.UpdateUser(context.TODO(), user.ID, user))
+
+	// An entry written before values were encrypted.
+	_, err = testDB.Db.Exec(
+		`INSERT INTO audit_log (user_id, actor, action, new_values) VALUES ($1, 'alice', 'user.updated', '{"email":"legacy@mail.com"}')`,
+		user.ID,
+	)
+	require.NoError(t, err)
+
+	// A call the user made with an idempotency key.
+	_, err = testDB.Db.Exec(
+		`INSERT INTO idempotency_keys (caller, idempotency_key, method, request_hash, locked_until, expires_at) VALUES ($1, 'key-1', '/services.user.User/UpdateUser', 'hash', NOW(), NOW() + INTERVAL '1 day')`,
+		user.ID.String(),
+	)
+	require.NoError(t, err)
+
+	err = repo.EraseUser(context.TODO(), user.ID)
+	require.NoError(t, err)
+
+	err = repo.EraseUser(context.TODO(), user.ID)
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+
+	var n int
+	require.NoError(t, testDB.Db.QueryRow(`SELECT COUNT(*) FROM users WHERE id = $1`, user.ID).Scan(&n))
+	require.Zero(t, n)
+	require.NoError(t, testDB.Db.QueryRow(`SELECT COUNT(*) FROM user_data_keys WHERE user_id = $1`, user.ID).Scan(&n))
+	require.Zero(t, n)
+	require.NoError(t, testDB.Db.QueryRow(`SELECT COUNT(*) FROM idempotency_keys WHERE caller = $1`, user.ID.String()).Scan(&n))
+	require.Zero(t, n)
+
+	entries, err := auditRepo.GetAuditEntries(context.TODO(), models.GetAuditEntriesOptions{
+		UserID: user.ID,
+		Limit:  100,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	require.Equal(t, models.AuditActionUserErased, entries[0].Action)
+	for _, e := range entries {
+		require.Nil(t, e.Before)
+		require.Nil(t, e.After)
+	}
+}
+
+func TestRepository_Contacts(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	newUser := func(email string) uuid.UUID {
+		userID, err := repo.InsertUser(ctx, models.User{
+			ID:     uuid.New(),
+			Type:   models.UserTypeHuman,
+			Status: models.UserStatusActive,
+			Email:  email,
+		})
+		require.NoError(t, err)
+		return userID
+	}
+
+	bruce := newUser("bruce@wayne.com")
+	clark := newUser("clark@dailyplanet.com")
+
+	emails, err := repo.GetEmails(ctx, bruce)
+	require.NoError(t, err)
+	require.Len(t, emails, 1)
+	require.Equal(t, "bruce@wayne.com", emails[0].Email)
+	require.True(t, emails[0].Primary)
+
+	require.NoError(t, repo.InsertEmail(ctx, bruce, "batman@gotham.com"))
+	require.ErrorIs(t, repo.InsertEmail(ctx, clark, "batman@gotham.com"), models.ErrEmailTaken)
+	require.ErrorIs(t, repo.InsertEmail(ctx, clark, "bruce@wayne.com"), models.ErrEmailTaken)
+	require.ErrorIs(t, repo.VerifyEmail(ctx, bruce, "robin@gotham.com", time.Now()), models.ErrEmailNotFound)
+	require.NoError(t, repo.VerifyEmail(ctx, bruce, "batman@gotham.com", time.Now()))
+
+	t.Log("set primary email")
+	{
+		from, err := repo.LatestUserChange(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.SetPrimaryEmail(ctx, bruce, "batman@gotham.com", time.Now()))
+
+		changes, _, err := repo.GetUserChanges(ctx, models.GetUsersOptions{}, from, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, models.UserChangeUpdated, changes[0].Kind)
+		require.Equal(t, "batman@gotham.com", changes[0].User.Email)
+
+		user, err := repo.GetUserByID(ctx, bruce)
+		require.NoError(t, err)
+		require.Equal(t, "batman@gotham.com", user.Email)
+
+		opts := models.GetUsersOptions{PageNumber: 1, PageSize: 10}
+		opts.Filter.Email = "batman@gotham.com"
+		users, err := repo.GetUsersByFilter(ctx, opts)
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		require.Equal(t, bruce, users[0].ID)
+
+		emails, err := repo.GetEmails(ctx, bruce)
+		require.NoError(t, err)
+		require.Len(t, emails, 2)
+		require.Equal(t, "batman@gotham.com", emails[0].Email)
+		require.True(t, emails[0].Primary)
+		require.NotNil(t, emails[0].VerifiedAt)
+		require.False(t, emails[1].Primary)
+
+		require.ErrorIs(t, repo.DeleteEmail(ctx, bruce, "batman@gotham.com"), models.ErrPrimaryContact)
+		require.NoError(t, repo.DeleteEmail(ctx, bruce, "bruce@wayne.com"))
+	}
+
+	t.Log("phones")
+	{
+		require.NoError(t, repo.InsertPhone(ctx, bruce, "+14155552671"))
+		require.NoError(t, repo.InsertPhone(ctx, bruce, "+14155552672"))
+		require.ErrorIs(t, repo.InsertPhone(ctx, clark, "+14155552671"), models.ErrPhoneTaken)
+
+		phones, err := repo.GetPhones(ctx, bruce)
+		require.NoError(t, err)
+		require.Len(t, phones, 2)
+		require.Equal(t, "+14155552671", phones[0].Number)
+		require.True(t, phones[0].Primary)
+
+		require.ErrorIs(t, repo.DeletePhone(ctx, bruce, "+14155552671"), models.ErrPrimaryContact)
+		require.NoError(t, repo.SetPrimaryPhone(ctx, bruce, "+14155552672", time.Now()))
+		require.NoError(t, repo.DeletePhone(ctx, bruce, "+14155552671"))
+		require.NoError(t, repo.DeletePhone(ctx, bruce, "+14155552672"))
+		require.ErrorIs(t, repo.DeletePhone(ctx, bruce, "+14155552672"), models.ErrPhoneNotFound)
+
+		require.NoError(t, repo.InsertPhone(ctx, bruce, "+14155552671"))
+	}
+
+	t.Log("contacts of deleted users are released")
+	{
+		require.NoError(t, repo.DeleteUser(ctx, bruce))
+		require.NoError(t, repo.InsertPhone(ctx, clark, "+14155552671"))
+
+		require.ErrorIs(t, repo.RestoreUser(ctx, bruce, time.Now()), models.ErrPhoneTaken)
+	}
+}
+
+func TestRepository_InsertUsers(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	newUser := func(email string) models.User {
+		return models.User{
+			ID:        uuid.New(),
+			Type:      models.UserTypeHuman,
+			Status:    models.UserStatusActive,
+			Email:     email,
+			FirstName: "imported",
+			Country:   "CY",
+			Password:  []byte(`secret`),
+			CreatedAt: time.Now().UTC(),
+		}
+	}
+
+	users := []models.User{newUser("import+1@mail.com"), newUser("import+2@mail.com")}
+	require.NoError(t, repo.InsertUsers(ctx, users))
+	defer func() {
+		for _, u := range users {
+			require.NoError(t, repo.EraseUser(ctx, u.ID))
+		}
+	}()
+
+	got, err := repo.GetUserByID(ctx, users[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, "import+2@mail.com", got.Email)
+	require.Equal(t, "imported", got.FirstName)
+
+	owners, err := repo.GetEmailOwners(ctx, []string{"import+1@mail.com", "import+2@mail.com", "nobody@mail.com"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]models.EmailOwner{
+		"import+1@mail.com": {UserID: users[0].ID, Primary: true},
+		"import+2@mail.com": {UserID: users[1].ID, Primary: true},
+	}, owners)
+
+	t.Log("secondary emails")
+	{
+		require.NoError(t, repo.InsertEmail(ctx, users[0].ID, "import+secondary@mail.com"))
+
+		owners, err := repo.GetEmailOwners(ctx, []string{"import+secondary@mail.com"})
+		require.NoError(t, err)
+		require.Equal(t, models.EmailOwner{UserID: users[0].ID}, owners["import+secondary@mail.com"])
+	}
+
+	t.Log("users written before encryption")
+	{
+		legacyID := uuid.New()
+		_, err := testDB.Db.Exec(
+			`INSERT INTO users (id, email, first_name, last_name, nickname, country) VALUES ($1, 'import+legacy@mail.com', 'old', 'row', 'legacy', 'GR')`,
+			legacyID,
+		)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, repo.EraseUser(ctx, legacyID))
+		}()
+
+		owners, err := repo.GetEmailOwners(ctx, []string{"import+legacy@mail.com"})
+		require.NoError(t, err)
+		require.Equal(t, models.EmailOwner{UserID: legacyID, Primary: true}, owners["import+legacy@mail.com"])
+	}
+
+	t.Log("taken email inserts none")
+	{
+		fresh := newUser("import+3@mail.com")
+		err := repo.InsertUsers(ctx, []models.User{fresh, newUser("import+1@mail.com")})
+		require.ErrorIs(t, err, models.ErrEmailTaken)
+
+		_, err = repo.GetUserByID(ctx, fresh.ID)
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+	}
+}
+
+func TestRepository_RunInTx(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	newUser := func(email string) models.User {
+		return models.User{ID: uuid.New(), Type: models.UserTypeHuman, Status: models.UserStatusActive, Email: email, CreatedAt: time.Now().UTC()}
+	}
+
+	t.Log("rolled back on failure")
+	{
+		user := newUser("tx+1@mail.com")
+		fail := errors.New("fail")
+
+		err := repo.RunInTx(ctx, func(ctx context.Context) error {
+			if _, err := repo.InsertUser(ctx, user); err != nil {
+				return err
+			}
+
+			got, err := repo.GetUserByID(ctx, user.ID)
+			require.NoError(t, err)
+			require.Equal(t, user.Email, got.Email)
+
+			return fail
+		})
+		require.ErrorIs(t, err, fail)
+
+		_, err = repo.GetUserByID(ctx, user.ID)
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+	}
+
+	t.Log("failed statements do not abort the transaction")
+	{
+		user := newUser("tx+2@mail.com")
+
+		err := repo.RunInTx(ctx, func(ctx context.Context) error {
+			if _, err := repo.InsertUser(ctx, user); err != nil {
+				return err
+			}
+
+			_, err := repo.InsertUser(ctx, newUser(user.Email))
+			require.ErrorIs(t, err, models.ErrEmailTaken)
+
+			return repo.UpdateUser(ctx, user.ID, models.User{ID: user.ID, Type: user.Type, Status: user.Status, Email: user.Email, Nickname: "committed"})
+		})
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, repo.EraseUser(ctx, user.ID))
+		}()
+
+		got, err := repo.GetUserByID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, "committed", got.Nickname)
+	}
+}
+
+func TestRepository_UserChanges(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	start, err := repo.LatestUserChange(ctx)
+	require.NoError(t, err)
+
+	gr := models.User{ID: uuid.New(), Type: models.UserTypeHuman, Status: models.UserStatusActive, Email: "changes+gr@mail.com", Country: "GR", CreatedAt: time.Now().UTC()}
+	cy := models.User{ID: uuid.New(), Type: models.UserTypeHuman, Status: models.UserStatusActive, Email: "changes+cy@mail.com", Country: "CY", CreatedAt: time.Now().UTC()}
+	for _, u := range []models.User{gr, cy} {
+		_, err := repo.InsertUser(ctx, u)
+		require.NoError(t, err)
+		defer func(userID uuid.UUID) {
+			require.NoError(t, repo.EraseUser(ctx, userID))
+		}(u.ID)
+	}
+
+	gr.Nickname = "updated"
+	require.NoError(t, repo.UpdateUser(ctx, gr.ID, gr))
+
+	latest, err := repo.LatestUserChange(ctx)
+	require.NoError(t, err)
+	require.Equal(t, start.ID+3, latest.ID)
+
+	opts := models.GetUsersOptions{}
+	opts.Filter.Country = "GR"
+
+	changes, last, err := repo.GetUserChanges(ctx, opts, start, 10)
+	require.NoError(t, err)
+	require.Equal(t, latest.ID, last.ID)
+	require.Len(t, changes, 2)
+	require.Equal(t, models.UserChangeCreated, changes[0].Kind)
+	require.Equal(t, models.UserChangeUpdated, changes[1].Kind)
+	require.Equal(t, gr.ID, changes[1].User.ID)
+	require.Equal(t, "updated", changes[1].User.Nickname)
+
+	t.Log("paged")
+	{
+		changes, last, err := repo.GetUserChanges(ctx, opts, start, 1)
+		require.NoError(t, err)
+		require.Equal(t, start.ID+1, last.ID)
+		require.Len(t, changes, 1)
+
+		changes, last, err = repo.GetUserChanges(ctx, opts, latest, 10)
+		require.NoError(t, err)
+		require.Equal(t, latest.ID, last.ID)
+		require.Empty(t, changes)
+	}
+
+	t.Log("held back while an older transaction is in progress")
+	{
+		older := models.User{ID: uuid.New(), Type: models.UserTypeHuman, Status: models.UserStatusActive, Email: "changes+older@mail.com", CreatedAt: time.Now().UTC()}
+		newer := models.User{ID: uuid.New(), Type: models.UserTypeHuman, Status: models.UserStatusActive, Email: "changes+newer@mail.com", CreatedAt: time.Now().UTC()}
+
+		err := repo.RunInTx(ctx, func(txCtx context.Context) error {
+			if _, err := repo.InsertUser(txCtx, older); err != nil {
+				return err
+			}
+
+			// Committed while the transaction of older is in progress.
+			if _, err := repo.InsertUser(ctx, newer); err != nil {
+				return err
+			}
+
+			changes, _, err := repo.GetUserChanges(ctx, models.GetUsersOptions{}, latest, 10)
+			require.NoError(t, err)
+			require.Empty(t, changes)
+			return nil
+		})
+		require.NoError(t, err)
+		for _, u := range []models.User{older, newer} {
+			defer func(userID uuid.UUID) {
+				require.NoError(t, repo.EraseUser(ctx, userID))
+			}(u.ID)
+		}
+
+		changes, _, err := repo.GetUserChanges(ctx, models.GetUsersOptions{}, latest, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.Equal(t, older.ID, changes[0].User.ID)
+		require.Equal(t, newer.ID, changes[1].User.ID)
+	}
+
+	t.Log("erased")
+	{
+		from, err := repo.LatestUserChange(ctx)
+		require.NoError(t, err)
+
+		erased := models.User{ID: uuid.New(), Type: models.UserTypeHuman, Status: models.UserStatusActive, Email: "changes+erased@mail.com", Country: "GR", CreatedAt: time.Now().UTC()}
+		_, err = repo.InsertUser(ctx, erased)
+		require.NoError(t, err)
+		require.NoError(t, repo.EraseUser(ctx, erased.ID))
+
+		unread, err := repo.CountUserChanges(ctx, from, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), unread)
+
+		changes, _, err := repo.GetUserChanges(ctx, opts, from, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, models.UserChangeDeleted, changes[0].Kind)
+		require.Equal(t, models.User{ID: erased.ID}, changes[0].User)
+	}
+
+	t.Log("purged")
+	{
+		_, err := repo.PurgeUserChanges(ctx, time.Now().UTC().Add(time.Minute))
+		require.NoError(t, err)
+
+		changes, _, err := repo.GetUserChanges(ctx, opts, start, 10)
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	}
+}
+
+func TestRepository_GetUserStats(t *testing.T) {
+
+	repo := NewRepository(testDB.Db, dataKeys, blindIndex, zap.NewNop().Sugar())
+	ctx := context.TODO()
+
+	// Users are backdated to 2001, out of the way of the other tests.
+	users := []struct {
+		user      models.User
+		createdAt time.Time
+	}{
+		{models.User{Status: models.UserStatusActive, Email: "stats+1@mail.com", Country: "GR"}, time.Date(2001, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{models.User{Status: models.UserStatusSuspended, Email: "stats+2@mail.com", Country: "GR"}, time.Date(2001, 1, 2, 12, 0, 0, 0, time.UTC)},
+		{models.User{Status: models.UserStatusActive, Email: "stats+3@mail.com", Country: "CY"}, time.Date(2001, 1, 9, 8, 0, 0, 0, time.UTC)},
+	}
+	for i := range users {
+		users[i].user.ID = uuid.New()
+		users[i].user.Type = models.UserTypeHuman
+	}
+	for _, u := range users {
+		_, err := repo.InsertUser(ctx, u.user)
+		require.NoError(t, err)
+		defer func(userID uuid.UUID) {
+			require.NoError(t, repo.EraseUser(ctx, userID))
+		}(u.user.ID)
+
+		_, err = testDB.Db.ExecContext(ctx, "UPDATE users SET created_at = $1 WHERE id = $2", u.createdAt, u.user.ID)
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.VerifyEmail(ctx, users[0].user.ID, users[0].user.Email, time.Now()))
+
+	after := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2001, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Log("by country and week")
+	{
+		groups, err := repo.GetUserStats(ctx, models.UserStatsOptions{
+			GroupBy:       []models.StatsDimension{models.StatsByCountry},
+			Bucket:        models.StatsBucketWeek,
+			CreatedAfter:  &after,
+			CreatedBefore: &before,
+		}, 100)
+		require.NoError(t, err)
+		require.Equal(t, []models.UserStatsGroup{
+			{BucketStart: time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC), Country: "GR", Users: 2},
+			{BucketStart: time.Date(2001, 1, 8, 0, 0, 0, 0, time.UTC), Country: "CY", Users: 1},
+		}, groups)
+	}
+
+	t.Log("by verified")
+	{
+		groups, err := repo.GetUserStats(ctx, models.UserStatsOptions{
+			GroupBy:       []models.StatsDimension{models.StatsByVerified},
+			CreatedAfter:  &after,
+			CreatedBefore: &before,
+		}, 100)
+		require.NoError(t, err)
+		require.Equal(t, []models.UserStatsGroup{
+			{Verified: false, Users: 2},
+			{Verified: true, Users: 1},
+		}, groups)
+	}
+
+	t.Log("from the rollup")
+	{
+		refreshed, err := repo.RefreshUserStatsRollup(ctx)
+		require.NoError(t, err)
+		require.True(t, refreshed)
+
+		opts := models.UserStatsOptions{
+			GroupBy:       []models.StatsDimension{models.StatsByCountry, models.StatsByStatus},
+			Bucket:        models.StatsBucketMonth,
+			CreatedAfter:  &after,
+			CreatedBefore: &before,
+		}
+
+		live, err := repo.GetUserStats(ctx, opts, 100)
+		require.NoError(t, err)
+		require.Len(t, live, 3)
+
+		rollup, refreshedAt, err := repo.GetUserStatsRollup(ctx, opts, 100)
+		require.NoError(t, err)
+		require.False(t, refreshedAt.IsZero())
+		require.Equal(t, live, rollup)
+	}
 }
@@ -0,0 +1,126 @@
+// Package search scores how well text matches a search query, tolerating
+// typos and partial words. Like pg_trgm, it compares the trigrams of words:
+// the three character sequences of the word padded with two spaces in front
+// and one behind.
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MinScore is the lowest score of a match.
+	MinScore = 0.3
+	// containedScore is the lowest score of a value containing the query.
+	containedScore = 0.5
+)
+
+// Words returns the lower-cased words of s, split on anything but letters
+// and digits.
+func Words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Trigrams returns the distinct trigrams of the words of s.
+func Trigrams(s string) []string {
+	seen := make(map[string]bool)
+	var trigrams []string
+	for _, w := range Words(s) {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			t := string(padded[i : i+3])
+			if !seen[t] {
+				seen[t] = true
+				trigrams = append(trigrams, t)
+			}
+		}
+	}
+	return trigrams
+}
+
+// Similarity returns the number of trigrams a and b share over the number of
+// their distinct trigrams, between 0 and 1.
+func Similarity(a string, b string) float64 {
+	ta, tb := Trigrams(a), Trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	inA := make(map[string]bool, len(ta))
+	for _, t := range ta {
+		inA[t] = true
+	}
+
+	var shared int
+	for _, t := range tb {
+		if inA[t] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// Score returns how well the value matches the query, between 0 and 1: the
+// best similarity of the query to the value or any of its words, and at least
+// containedScore when the value contains the query.
+func Score(query string, value string) float64 {
+	score := Similarity(query, value)
+	for _, w := range Words(value) {
+		if s := Similarity(query, w); s > score {
+			score = s
+		}
+	}
+
+	q, v := strings.ToLower(strings.TrimSpace(query)), strings.ToLower(value)
+	if q != "" && strings.Contains(v, q) {
+		if s := containedScore + (1-containedScore)*float64(len(q))/float64(len(v)); s > score {
+			score = s
+		}
+	}
+
+	return score
+}
+
+// Highlight returns the value, HTML escaped, with the part matching the query
+// enclosed in <em> tags: the query itself when the value contains it, or the
+// word most similar to the query.
+func Highlight(query string, value string) string {
+	// Offsets in the lower-cased value only hold when lower-casing kept its length.
+	q, lower := strings.ToLower(strings.TrimSpace(query)), strings.ToLower(value)
+	if i := strings.Index(lower, q); q != "" && i >= 0 && len(lower) == len(value) {
+		return emphasize(value, i, i+len(q))
+	}
+
+	var (
+		best      float64
+		start     int
+		end       int
+		wordStart = -1
+	)
+	for i, r := range value + " " {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && wordStart < 0:
+			wordStart = i
+		case !inWord && wordStart >= 0:
+			if s := Similarity(query, value[wordStart:i]); s > best {
+				best, start, end = s, wordStart, i
+			}
+			wordStart = -1
+		}
+	}
+
+	if best == 0 {
+		return html.EscapeString(value)
+	}
+	return emphasize(value, start, end)
+}
+
+func emphasize(value string, start int, end int) string {
+	return html.EscapeString(value[:start]) + "<em>" + html.EscapeString(value[start:end]) + "</em>" + html.EscapeString(value[end:])
+}
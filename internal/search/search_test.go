@@ -0,0 +1,52 @@
+package search
+
+import (
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrigrams(t *testing.T) {
+	require.Equal(t, []string{"  c", " ca", "cat", "at "}, Trigrams("Cat"))
+	require.Equal(t, []string{"  a", " an", "ant", "nt ", "  g", " gr", "gr "}, Trigrams("ant@gr"))
+	require.Empty(t, Trigrams(" +@ "))
+}
+
+func TestSimilarity(t *testing.T) {
+	require.Equal(t, 1.0, Similarity("antonis", "Antonis"))
+	require.Equal(t, 0.0, Similarity("antonis", "maria"))
+	require.InDelta(t, 0.45, Similarity("antonis", "antonsi"), 0.01)
+}
+
+func TestScore(t *testing.T) {
+	tt := map[string]struct {
+		query string
+		value string
+		min   float64
+		max   float64
+	}{
+		"exact":               {query: "papath", value: "Papath", min: 1, max: 1},
+		"misspelled":          {query: "antnois", value: "antonis+1@mail.com", min: MinScore, max: 0.9},
+		"partial word":        {query: "anto", value: "antonis", min: 0.5, max: 0.9},
+		"misspelled email":    {query: "antonis@mial.com", value: "antonis@mail.com", min: MinScore, max: 0.9},
+		"unrelated":           {query: "maria", value: "antonis@mail.com", min: 0, max: MinScore - 0.01},
+		"contained, not word": {query: "nis+1@", value: "antonis+1@mail.com", min: 0.5, max: 0.9},
+	}
+
+	for name, tc := range tt {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			score := Score(tc.query, tc.value)
+			require.GreaterOrEqual(t, score, tc.min)
+			require.LessOrEqual(t, score, tc.max)
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	require.Equal(t, "<em>Anto</em>nis", Highlight("anto", "Antonis"))
+	require.Equal(t, "Antonis <em>Papath</em>", Highlight("papaht", "Antonis Papath"))
+	require.Equal(t, "&lt;b&gt; <em>Tony</em>", Highlight("tony", "<b> Tony"))
+	require.Equal(t, "Maria", Highlight("antonis", "Maria"))
+}
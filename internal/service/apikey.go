@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+const (
+	apiKeyPrefix      = "umk"
+	apiKeyLookupBytes = 6
+	apiKeySecretBytes = 32
+	apiKeyPartSep     = "_"
+	apiKeyParts       = 3
+)
+
+//go:generate moq -out apikey_storage_mock_test.go . APIKeyStorage
+type APIKeyStorage interface {
+	InsertAPIKey(ctx context.Context, key models.APIKey) (uuid.UUID, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (models.APIKey, error)
+	GetAPIKeysByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID uuid.UUID, owner *uuid.UUID, revokedAt time.Time) error
+	TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error
+}
+
+type APIKeyService struct {
+	repo  APIKeyStorage
+	users UserStorage
+}
+
+func NewAPIKeyService(repo APIKeyStorage, users UserStorage) *APIKeyService {
+	return &APIKeyService{
+		repo:  repo,
+		users: users,
+	}
+}
+
+// CreateAPIKey issues a key for a service account. The returned plaintext key is
+// not stored anywhere and cannot be recovered later. A caller authenticated with
+// an API key can only grant the scopes it holds.
+func (kSvc *APIKeyService) CreateAPIKey(ctx context.Context, nk models.NewAPIKey) (models.APIKey, string, error) {
+	if err := validateScopes(nk.Scopes); err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		for _, s := range nk.Scopes {
+			if !principal.HasScope(s) {
+				return models.APIKey{}, "", fmt.Errorf("%w: %s", models.ErrScopeNotGranted, s)
+			}
+		}
+	}
+
+	user, err := kSvc.users.GetUserByID(ctx, nk.UserID)
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	if user.Type != models.UserTypeServiceAccount {
+		return models.APIKey{}, "", models.ErrNotServiceAccount
+	}
+
+	plaintext, prefix, err := generateAPIKey()
+	if err != nil {
+		return models.APIKey{}, "", fmt.Errorf("generating api key: %w", err)
+	}
+
+	key := models.APIKey{
+		ID:        uuid.New(),
+		UserID:    nk.UserID,
+		Name:      nk.Name,
+		Prefix:    prefix,
+		Hash:      hashAPIKey(plaintext),
+		Scopes:    nk.Scopes,
+		ExpiresAt: nk.ExpiresAt,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if _, err := kSvc.repo.InsertAPIKey(ctx, key); err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	return key, plaintext, nil
+}
+
+// ListAPIKeys lists the keys of the user. A caller authenticated with an API
+// key can only list its own keys, unless it has the users:admin scope.
+func (kSvc *APIKeyService) ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	if owner := keyOwner(ctx); owner != nil && *owner != userID {
+		return nil, models.ErrNotAPIKeyOwner
+	}
+
+	return kSvc.repo.GetAPIKeysByUser(ctx, userID)
+}
+
+// RevokeAPIKey revokes the key. A caller authenticated with an API key can
+// only revoke its own keys, unless it has the users:admin scope; the keys of
+// other users are reported as not found.
+func (kSvc *APIKeyService) RevokeAPIKey(ctx context.Context, keyID uuid.UUID) error {
+	return kSvc.repo.RevokeAPIKey(ctx, keyID, keyOwner(ctx), time.Now().UTC())
+}
+
+// keyOwner returns the user whose keys the caller may manage, or nil when it
+// may manage the keys of every user.
+func keyOwner(ctx context.Context) *uuid.UUID {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.HasScope(models.ScopeUsersAdmin) {
+		return nil
+	}
+	return &principal.UserID
+}
+
+// Authenticate resolves a plaintext API key to the principal it was issued for.
+// Unknown, revoked and expired keys, and the keys of users that are not active,
+// are all reported as models.ErrInvalidAPIKey.
+func (kSvc *APIKeyService) Authenticate(ctx context.Context, plaintext string) (models.Principal, error) {
+	parts := strings.SplitN(plaintext, apiKeyPartSep, apiKeyParts)
+	if len(parts) != apiKeyParts || parts[0] != apiKeyPrefix {
+		return models.Principal{}, models.ErrInvalidAPIKey
+	}
+
+	key, err := kSvc.repo.GetAPIKeyByPrefix(ctx, parts[1])
+	if err != nil {
+		if errors.Is(err, models.ErrAPIKeyNotFound) {
+			return models.Principal{}, models.ErrInvalidAPIKey
+		}
+		return models.Principal{}, err
+	}
+
+	if subtle.ConstantTimeCompare(key.Hash, hashAPIKey(plaintext)) != 1 {
+		return models.Principal{}, models.ErrInvalidAPIKey
+	}
+
+	now := time.Now().UTC()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)) {
+		return models.Principal{}, models.ErrInvalidAPIKey
+	}
+
+	_ = kSvc.repo.TouchAPIKey(ctx, key.ID, now)
+
+	return models.Principal{
+		UserID:   key.UserID,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+func validateScopes(scopes []string) error {
+	for _, s := range scopes {
+		known := false
+		for _, k := range models.Scopes {
+			if s == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: %s", models.ErrInvalidScope, s)
+		}
+	}
+	return nil
+}
+
+func generateAPIKey() (plaintext string, prefix string, err error) {
+	lookup := make([]byte, apiKeyLookupBytes)
+	if _, err := rand.Read(lookup); err != nil {
+		return "", "", err
+	}
+
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	prefix = hex.EncodeToString(lookup)
+	plaintext = strings.Join([]string{apiKeyPrefix, prefix, base64.RawURLEncoding.EncodeToString(secret)}, apiKeyPartSep)
+
+	return plaintext, prefix, nil
+}
+
+func hashAPIKey(plaintext string) []byte {
+	sum := sha256.Sum256([]byte(plaintext))
+	return sum[:]
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	var stored models.APIKey
+	repoMock := APIKeyStorageMock{
+		InsertAPIKeyFunc: func(ctx context.Context, key models.APIKey) (uuid.UUID, error) {
+			stored = key
+			return key.ID, nil
+		},
+		GetAPIKeyByPrefixFunc: func(ctx context.Context, prefix string) (models.APIKey, error) {
+			if prefix != stored.Prefix {
+				return models.APIKey{}, models.ErrAPIKeyNotFound
+			}
+			return stored, nil
+		},
+		TouchAPIKeyFunc: func(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error {
+			return nil
+		},
+	}
+
+	usersMock := UserStorageMock{
+		GetUserByIDFunc: func(ctx context.Context, uID uuid.UUID) (models.User, error) {
+			return models.User{ID: uID, Type: models.UserTypeServiceAccount}, nil
+		},
+	}
+
+	s := NewAPIKeyService(&repoMock, &usersMock)
+
+	key, plaintext, err := s.CreateAPIKey(context.TODO(), models.NewAPIKey{
+		UserID: userID,
+		Name:   "ci",
+		Scopes: []string{models.ScopeUsersRead},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, plaintext)
+	require.NotContains(t, string(stored.Hash), plaintext)
+	require.Equal(t, key.Prefix, stored.Prefix)
+
+	principal, err := s.Authenticate(context.TODO(), plaintext)
+	require.NoError(t, err)
+	require.Equal(t, userID, principal.UserID)
+	require.Equal(t, key.ID, principal.APIKeyID)
+	require.True(t, principal.HasScope(models.ScopeUsersRead))
+	require.False(t, principal.HasScope(models.ScopeUsersWrite))
+	require.Len(t, repoMock.TouchAPIKeyCalls(), 1)
+
+	t.Log("tampered secret")
+	{
+		_, err := s.Authenticate(context.TODO(), plaintext+"x")
+		require.ErrorIs(t, err, models.ErrInvalidAPIKey)
+	}
+
+	t.Log("malformed key")
+	{
+		_, err := s.Authenticate(context.TODO(), "not-a-key")
+		require.ErrorIs(t, err, models.ErrInvalidAPIKey)
+	}
+
+	t.Log("revoked key")
+	{
+		revokedAt := time.Now()
+		stored.RevokedAt = &revokedAt
+		_, err := s.Authenticate(context.TODO(), plaintext)
+		require.ErrorIs(t, err, models.ErrInvalidAPIKey)
+		stored.RevokedAt = nil
+	}
+
+	t.Log("expired key")
+	{
+		expiresAt := time.Now().Add(-time.Minute)
+		stored.ExpiresAt = &expiresAt
+		_, err := s.Authenticate(context.TODO(), plaintext)
+		require.ErrorIs(t, err, models.ErrInvalidAPIKey)
+	}
+}
+
+func TestAPIKeyService_CreateAPIKey_Fail(t *testing.T) {
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		users   *UserStorageMock
+		newKey  models.NewAPIKey
+		checkFn func(t *testing.T, err error)
+	}{
+		{
+			name:  "ErrInvalidScope",
+			users: &UserStorageMock{},
+			newKey: models.NewAPIKey{
+				UserID: userID,
+				Scopes: []string{"everything"},
+			},
+			checkFn: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, models.ErrInvalidScope)
+			},
+		},
+		{
+			name: "ErrScopeNotGranted",
+			ctx: auth.WithPrincipal(context.TODO(), models.Principal{
+				Scopes: []string{models.ScopeAPIKeysWrite, models.ScopeUsersRead},
+			}),
+			users: &UserStorageMock{},
+			newKey: models.NewAPIKey{
+				UserID: userID,
+				Scopes: []string{models.ScopeUsersRead, models.ScopeUsersAdmin},
+			},
+			checkFn: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, models.ErrScopeNotGranted)
+			},
+		},
+		{
+			name: "ErrUserNotFound",
+			users: &UserStorageMock{
+				GetUserByIDFunc: func(ctx context.Context, uID uuid.UUID) (models.User, error) {
+					return models.User{}, models.ErrUserNotFound
+				},
+			},
+			newKey: models.NewAPIKey{
+				UserID: userID,
+			},
+			checkFn: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, models.ErrUserNotFound)
+			},
+		},
+		{
+			name: "ErrNotServiceAccount",
+			users: &UserStorageMock{
+				GetUserByIDFunc: func(ctx context.Context, uID uuid.UUID) (models.User, error) {
+					return models.User{ID: uID, Type: models.UserTypeHuman}, nil
+				},
+			},
+			newKey: models.NewAPIKey{
+				UserID: userID,
+			},
+			checkFn: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, models.ErrNotServiceAccount)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := APIKeyStorageMock{}
+
+			s := NewAPIKeyService(&repoMock, tt.users)
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.TODO()
+			}
+			_, _, err := s.CreateAPIKey(ctx, tt.newKey)
+			tt.checkFn(t, err)
+			require.Len(t, repoMock.InsertAPIKeyCalls(), 0)
+		})
+	}
+}
+
+func TestAPIKeyService_ListAndRevoke_Owner(t *testing.T) {
+	ownerID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+	otherID := uuid.MustParse("0e1b7a6c-3f5d-4a8e-9b2c-6d4f1e8a7c3b")
+	keyID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	repoMock := &APIKeyStorageMock{
+		GetAPIKeysByUserFunc: func(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+			return []models.APIKey{{ID: keyID, UserID: userID}}, nil
+		},
+		RevokeAPIKeyFunc: func(ctx context.Context, keyID uuid.UUID, owner *uuid.UUID, revokedAt time.Time) error {
+			if owner != nil && *owner != ownerID {
+				return models.ErrAPIKeyNotFound
+			}
+			return nil
+		},
+	}
+	s := NewAPIKeyService(repoMock, &UserStorageMock{})
+
+	owner := auth.WithPrincipal(context.TODO(), models.Principal{UserID: ownerID, Scopes: []string{models.ScopeAPIKeysWrite}})
+	other := auth.WithPrincipal(context.TODO(), models.Principal{UserID: otherID, Scopes: []string{models.ScopeAPIKeysWrite}})
+	admin := auth.WithPrincipal(context.TODO(), models.Principal{UserID: otherID, Scopes: []string{models.ScopeAPIKeysWrite, models.ScopeUsersAdmin}})
+
+	t.Log("owner")
+	{
+		keys, err := s.ListAPIKeys(owner, ownerID)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.NoError(t, s.RevokeAPIKey(owner, keyID))
+		require.Equal(t, &ownerID, repoMock.RevokeAPIKeyCalls()[0].Owner)
+	}
+
+	t.Log("another user")
+	{
+		_, err := s.ListAPIKeys(other, ownerID)
+		require.ErrorIs(t, err, models.ErrNotAPIKeyOwner)
+		require.ErrorIs(t, s.RevokeAPIKey(other, keyID), models.ErrAPIKeyNotFound)
+		require.Len(t, repoMock.GetAPIKeysByUserCalls(), 1)
+	}
+
+	t.Log("admin")
+	{
+		_, err := s.ListAPIKeys(admin, ownerID)
+		require.NoError(t, err)
+		require.NoError(t, s.RevokeAPIKey(admin, keyID))
+		require.Nil(t, repoMock.RevokeAPIKeyCalls()[2].Owner)
+	}
+}
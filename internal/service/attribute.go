@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+// attributeNamePattern restricts attribute names to identifiers that are safe
+// to use as JSON keys and in audit field names.
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+//go:generate moq -out attribute_storage_mock_test.go . AttributeStorage
+type AttributeStorage interface {
+	InsertAttributeDefinition(ctx context.Context, def models.AttributeDefinition) error
+	GetAttributeDefinitions(ctx context.Context) ([]models.AttributeDefinition, error)
+	DeleteAttributeDefinition(ctx context.Context, name string) error
+}
+
+// AttributeService manages the registry of the custom attributes users may have.
+type AttributeService struct {
+	repo AttributeStorage
+}
+
+func NewAttributeService(repo AttributeStorage) *AttributeService {
+	return &AttributeService{
+		repo: repo,
+	}
+}
+
+func (aSvc *AttributeService) DefineAttribute(ctx context.Context, def models.AttributeDefinition) (models.AttributeDefinition, error) {
+	if !attributeNamePattern.MatchString(def.Name) {
+		return models.AttributeDefinition{}, fmt.Errorf("%w: invalid name %q", models.ErrInvalidAttribute, def.Name)
+	}
+
+	switch def.Type {
+	case models.AttributeTypeString, models.AttributeTypeInt, models.AttributeTypeBool, models.AttributeTypeDate:
+		if len(def.EnumValues) > 0 {
+			return models.AttributeDefinition{}, fmt.Errorf("%w: enum values are only allowed for enum attributes", models.ErrInvalidAttribute)
+		}
+	case models.AttributeTypeEnum:
+		if len(def.EnumValues) == 0 {
+			return models.AttributeDefinition{}, fmt.Errorf("%w: enum attributes need at least one value", models.ErrInvalidAttribute)
+		}
+		seen := make(map[string]bool, len(def.EnumValues))
+		for _, v := range def.EnumValues {
+			if v == "" || seen[v] {
+				return models.AttributeDefinition{}, fmt.Errorf("%w: enum values must be unique and not empty", models.ErrInvalidAttribute)
+			}
+			seen[v] = true
+		}
+	default:
+		return models.AttributeDefinition{}, fmt.Errorf("%w: invalid type %q", models.ErrInvalidAttribute, def.Type)
+	}
+
+	def.CreatedAt = time.Now().UTC()
+
+	if err := aSvc.repo.InsertAttributeDefinition(ctx, def); err != nil {
+		return models.AttributeDefinition{}, err
+	}
+
+	return def, nil
+}
+
+func (aSvc *AttributeService) ListAttributeDefinitions(ctx context.Context) ([]models.AttributeDefinition, error) {
+	return aSvc.repo.GetAttributeDefinitions(ctx)
+}
+
+// DeleteAttributeDefinition removes the attribute and its values from every user.
+func (aSvc *AttributeService) DeleteAttributeDefinition(ctx context.Context, name string) error {
+	return aSvc.repo.DeleteAttributeDefinition(ctx, name)
+}
+
+// attributeDefinitions returns the definitions keyed by name.
+func attributeDefinitions(ctx context.Context, repo AttributeStorage) (map[string]models.AttributeDefinition, error) {
+	defs, err := repo.GetAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]models.AttributeDefinition, len(defs))
+	for _, def := range defs {
+		byName[def.Name] = def
+	}
+
+	return byName, nil
+}
+
+// normalizeAttribute checks the value against the definition and returns it
+// in its stored form: string, int64 or bool.
+func normalizeAttribute(def models.AttributeDefinition, value any) (any, error) {
+	invalid := func() error {
+		return fmt.Errorf("%w: %s must be a valid %s", models.ErrInvalidAttribute, def.Name, def.Type)
+	}
+
+	switch def.Type {
+	case models.AttributeTypeString:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case models.AttributeTypeInt:
+		switch v := value.(type) {
+		case int:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+				return int64(v), nil
+			}
+		}
+	case models.AttributeTypeBool:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case models.AttributeTypeDate:
+		if s, ok := value.(string); ok {
+			if _, err := time.Parse(models.AttributeDateLayout, s); err == nil {
+				return s, nil
+			}
+		}
+	case models.AttributeTypeEnum:
+		if s, ok := value.(string); ok {
+			for _, v := range def.EnumValues {
+				if v == s {
+					return s, nil
+				}
+			}
+		}
+	}
+
+	return nil, invalid()
+}
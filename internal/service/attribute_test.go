@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func newAttributeStorageMock() *AttributeStorageMock {
+	return &AttributeStorageMock{
+		GetAttributeDefinitionsFunc: func(ctx context.Context) ([]models.AttributeDefinition, error) {
+			return []models.AttributeDefinition{
+				{Name: "department", Type: models.AttributeTypeString, Indexed: true},
+				{Name: "level", Type: models.AttributeTypeInt},
+				{Name: "hired_on", Type: models.AttributeTypeDate},
+				{Name: "tier", Type: models.AttributeTypeEnum, EnumValues: []string{"gold", "silver"}, Indexed: true},
+			}, nil
+		},
+	}
+}
+
+func TestAttributeService_DefineAttribute(t *testing.T) {
+	tests := []struct {
+		name        string
+		def         models.AttributeDefinition
+		insertCalls int
+		wantErr     error
+	}{
+		{
+			name:        "string",
+			def:         models.AttributeDefinition{Name: "department", Type: models.AttributeTypeString, Indexed: true},
+			insertCalls: 1,
+		},
+		{
+			name:        "enum",
+			def:         models.AttributeDefinition{Name: "tier", Type: models.AttributeTypeEnum, EnumValues: []string{"gold", "silver"}},
+			insertCalls: 1,
+		},
+		{
+			name:    "invalid name",
+			def:     models.AttributeDefinition{Name: "Department", Type: models.AttributeTypeString},
+			wantErr: models.ErrInvalidAttribute,
+		},
+		{
+			name:    "invalid type",
+			def:     models.AttributeDefinition{Name: "department", Type: "float"},
+			wantErr: models.ErrInvalidAttribute,
+		},
+		{
+			name:    "enum without values",
+			def:     models.AttributeDefinition{Name: "tier", Type: models.AttributeTypeEnum},
+			wantErr: models.ErrInvalidAttribute,
+		},
+		{
+			name:    "duplicate enum values",
+			def:     models.AttributeDefinition{Name: "tier", Type: models.AttributeTypeEnum, EnumValues: []string{"gold", "gold"}},
+			wantErr: models.ErrInvalidAttribute,
+		},
+		{
+			name:    "enum values on string",
+			def:     models.AttributeDefinition{Name: "department", Type: models.AttributeTypeString, EnumValues: []string{"hr"}},
+			wantErr: models.ErrInvalidAttribute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := AttributeStorageMock{
+				InsertAttributeDefinitionFunc: func(ctx context.Context, def models.AttributeDefinition) error {
+					return nil
+				},
+			}
+
+			s := NewAttributeService(&repoMock)
+
+			def, err := s.DefineAttribute(context.TODO(), tt.def)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.def.Name, def.Name)
+				require.False(t, def.CreatedAt.IsZero())
+			}
+			require.Len(t, repoMock.InsertAttributeDefinitionCalls(), tt.insertCalls)
+		})
+	}
+}
+
+func TestUserService_UpdateUser_Attributes(t *testing.T) {
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	tests := []struct {
+		name    string
+		changes map[string]any
+		want    map[string]any
+		wantErr error
+	}{
+		{
+			name:    "set and remove",
+			changes: map[string]any{"level": float64(3), "hired_on": "2023-04-01", "department": nil},
+			want:    map[string]any{"level": int64(3), "hired_on": "2023-04-01", "tier": "gold"},
+		},
+		{
+			name:    "unknown attribute",
+			changes: map[string]any{"shoe_size": float64(42)},
+			wantErr: models.ErrInvalidAttribute,
+		},
+		{
+			name:    "fractional int",
+			changes: map[string]any{"level": 3.5},
+			wantErr: models.ErrInvalidAttribute,
+		},
+		{
+			name:    "invalid date",
+			changes: map[string]any{"hired_on": "01/04/2023"},
+			wantErr: models.ErrInvalidAttribute,
+		},
+		{
+			name:    "value out of enum",
+			changes: map[string]any{"tier": "bronze"},
+			wantErr: models.ErrInvalidAttribute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := make(chan struct{}, 1)
+
+			var stored models.User
+			repoMock := UserStorageMock{
+				GetUserByIDFunc: func(ctx context.Context, userID uuid.UUID) (models.User, error) {
+					return models.User{
+						ID:         userID,
+						Attributes: map[string]any{"department": "sales", "tier": "gold"},
+					}, nil
+				},
+				UpdateUserFunc: func(ctx context.Context, userID uuid.UUID, user models.User) error {
+					stored = user
+					return nil
+				},
+			}
+			publisherMock := EventPublisherMock{
+				PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+					guard <- struct{}{}
+					return nil
+				},
+			}
+
+			s := NewUserService(&repoMock, newAttributeStorageMock(), &publisherMock)
+
+			err := s.UpdateUser(context.TODO(), userID, models.UpdateUser{Attributes: tt.changes})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Len(t, repoMock.UpdateUserCalls(), 0)
+				return
+			}
+
+			<-guard
+			require.NoError(t, err)
+			require.Equal(t, tt.want, stored.Attributes)
+		})
+	}
+}
+
+func TestUserService_GetUsers_AttributeFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  map[string]any
+		wantErr error
+	}{
+		{
+			name:   "indexed attributes",
+			filter: map[string]any{"department": "sales", "tier": "gold"},
+		},
+		{
+			name:    "attribute not indexed",
+			filter:  map[string]any{"level": float64(3)},
+			wantErr: models.ErrAttributeNotFilterable,
+		},
+		{
+			name:    "unknown attribute",
+			filter:  map[string]any{"shoe_size": float64(42)},
+			wantErr: models.ErrAttributeNotFilterable,
+		},
+		{
+			name:    "invalid value",
+			filter:  map[string]any{"tier": "bronze"},
+			wantErr: models.ErrInvalidAttribute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := UserStorageMock{
+				GetUsersByFilterFunc: func(ctx context.Context, opts models.GetUsersOptions) ([]models.User, error) {
+					return nil, nil
+				},
+			}
+
+			s := NewUserService(&repoMock, newAttributeStorageMock(), &EventPublisherMock{})
+
+			opts := models.GetUsersOptions{PageNumber: 1, PageSize: 10}
+			opts.Filter.Attributes = tt.filter
+
+			_, err := s.GetUsers(context.TODO(), opts)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Len(t, repoMock.GetUsersByFilterCalls(), 0)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, repoMock.GetUsersByFilterCalls(), 1)
+			require.Equal(t, tt.filter, repoMock.GetUsersByFilterCalls()[0].Opts.Filter.Attributes)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+//go:generate moq -out audit_storage_mock_test.go . AuditStorage
+type AuditStorage interface {
+	InsertAuditEntry(ctx context.Context, entry models.AuditEntry) error
+	GetAuditEntries(ctx context.Context, opts models.GetAuditEntriesOptions) ([]models.AuditEntry, error)
+}
+
+type AuditService struct {
+	repo AuditStorage
+}
+
+func NewAuditService(repo AuditStorage) *AuditService {
+	return &AuditService{
+		repo: repo,
+	}
+}
+
+// ListAuditEntries returns up to opts.Limit entries together with the cursor of
+// the next page, which is zero when there are no more entries.
+func (aSvc *AuditService) ListAuditEntries(ctx context.Context, opts models.GetAuditEntriesOptions) ([]models.AuditEntry, int64, error) {
+	limit := opts.Limit
+
+	// Ask for one more entry than requested to find out whether another page follows.
+	opts.Limit++
+	entries, err := aSvc.repo.GetAuditEntries(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if uint64(len(entries)) <= limit {
+		return entries, 0, nil
+	}
+
+	entries = entries[:limit]
+	return entries, entries[len(entries)-1].ID, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestAuditService_ListAuditEntries(t *testing.T) {
+	log := []models.AuditEntry{{ID: 5}, {ID: 4}, {ID: 3}}
+
+	tests := []struct {
+		name       string
+		limit      uint64
+		wantIDs    []int64
+		wantCursor int64
+	}{
+		{
+			name:       "more pages",
+			limit:      2,
+			wantIDs:    []int64{5, 4},
+			wantCursor: 4,
+		},
+		{
+			name:       "last page",
+			limit:      3,
+			wantIDs:    []int64{5, 4, 3},
+			wantCursor: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := AuditStorageMock{
+				GetAuditEntriesFunc: func(ctx context.Context, opts models.GetAuditEntriesOptions) ([]models.AuditEntry, error) {
+					require.Equal(t, tt.limit+1, opts.Limit)
+					if opts.Limit > uint64(len(log)) {
+						return log, nil
+					}
+					return log[:opts.Limit], nil
+				},
+			}
+
+			s := NewAuditService(&repoMock)
+
+			entries, cursor, err := s.ListAuditEntries(context.TODO(), models.GetAuditEntriesOptions{Limit: tt.limit})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCursor, cursor)
+
+			ids := make([]int64, len(entries))
+			for i, e := range entries {
+				ids[i] = e.ID
+			}
+			require.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestAuditService_ListAuditEntries_Fail(t *testing.T) {
+	repoMock := AuditStorageMock{
+		GetAuditEntriesFunc: func(ctx context.Context, opts models.GetAuditEntriesOptions) ([]models.AuditEntry, error) {
+			return nil, errors.New("internal error")
+		},
+	}
+
+	s := NewAuditService(&repoMock)
+
+	_, _, err := s.ListAuditEntries(context.TODO(), models.GetAuditEntriesOptions{Limit: 10})
+	require.Error(t, err)
+}
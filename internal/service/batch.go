@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	// 3rd party
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+// maxBatchItems is the largest number of items of a batch.
+const maxBatchItems = 100
+
+// errItemFailed rolls an atomic batch back once one of its items failed.
+var errItemFailed = errors.New("batch item failed")
+
+// BatchCreateUsers creates the users as CreateUser does, returning the outcome
+// of each. When atomic, either all of them are created or none is; otherwise
+// each is created on its own. A UserCreated event is published for every user
+// created.
+func (uSvc *UserService) BatchCreateUsers(ctx context.Context, users []models.NewUser, atomic bool) ([]models.BatchResult, error) {
+	hashes := make([][]byte, len(users))
+	prepare := func(i int) (err error) {
+		hashes[i], err = newUserPassword(users[i])
+		return err
+	}
+
+	return uSvc.runBatch(ctx, len(users), atomic, prepare, func(ctx context.Context, i int) (uuid.UUID, func(), error) {
+		user, err := uSvc.createUser(ctx, users[i], hashes[i])
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+		return user.ID, func() { uSvc.publishUserCreated(user) }, nil
+	})
+}
+
+// BatchUpdateUsers updates the users as UpdateUser does, returning the outcome
+// of each, atomically or not as BatchCreateUsers. A UserUpdated event is
+// published for every user updated.
+func (uSvc *UserService) BatchUpdateUsers(ctx context.Context, updates []models.BatchUpdate, atomic bool) ([]models.BatchResult, error) {
+	hashes := make([][]byte, len(updates))
+	prepare := func(i int) (err error) {
+		if len(updates[i].Update.Password) != 0 {
+			hashes[i], err = bcryptPassword(updates[i].Update.Password)
+		}
+		return err
+	}
+
+	return uSvc.runBatch(ctx, len(updates), atomic, prepare, func(ctx context.Context, i int) (uuid.UUID, func(), error) {
+		bu := updates[i]
+
+		defs, err := uSvc.attributeDefinitions(ctx, bu.Update.Attributes)
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+
+		user, err := uSvc.updateUser(ctx, bu.UserID, bu.Update, hashes[i], defs)
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+		return user.ID, func() { uSvc.publishUserUpdated(user) }, nil
+	})
+}
+
+// BatchDeleteUsers soft deletes the users as DeleteUser does, returning the
+// outcome of each, atomically or not as BatchCreateUsers. A UserDeleted event
+// is published for every user deleted.
+func (uSvc *UserService) BatchDeleteUsers(ctx context.Context, userIDs []uuid.UUID, atomic bool) ([]models.BatchResult, error) {
+	return uSvc.runBatch(ctx, len(userIDs), atomic, nil, func(ctx context.Context, i int) (uuid.UUID, func(), error) {
+		deletedAt, err := uSvc.deleteUser(ctx, userIDs[i])
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+		return userIDs[i], func() { uSvc.publishUserDeleted(userIDs[i], deletedAt) }, nil
+	})
+}
+
+// runBatch applies the n items of a batch in order. apply returns the user
+// of the item and publishes its event through the returned function, which is
+// only called once the item is there to stay: right away, or after the commit
+// of an atomic batch. prepare, when set, readies every item before any is
+// applied, outside of the transaction of an atomic batch, so that slow work
+// such as hashing passwords does not hold it open; an item it fails for fails
+// as if applied.
+func (uSvc *UserService) runBatch(ctx context.Context, n int, atomic bool, prepare func(i int) error, apply func(ctx context.Context, i int) (uuid.UUID, func(), error)) ([]models.BatchResult, error) {
+	if n > maxBatchItems {
+		return nil, fmt.Errorf("%w: more than %d items", models.ErrBatchTooLarge, maxBatchItems)
+	}
+
+	results := make([]models.BatchResult, n)
+
+	if prepare != nil {
+		for i := range results {
+			if err := prepare(i); err != nil {
+				results[i].Err = err
+				if atomic {
+					return abortBatch(results, i), nil
+				}
+			}
+		}
+	}
+
+	if !atomic {
+		for i := range results {
+			if results[i].Err != nil {
+				continue
+			}
+			userID, publish, err := apply(ctx, i)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			results[i].UserID = userID
+			publish()
+		}
+		return results, nil
+	}
+
+	publishers := make([]func(), n)
+	failed := -1
+	err := uSvc.repo.RunInTx(ctx, func(ctx context.Context) error {
+		for i := range results {
+			userID, publish, err := apply(ctx, i)
+			if err != nil {
+				results[i].Err = err
+				failed = i
+				return errItemFailed
+			}
+			results[i].UserID = userID
+			publishers[i] = publish
+		}
+		return nil
+	})
+
+	if failed >= 0 {
+		return abortBatch(results, failed), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, publish := range publishers {
+		publish()
+	}
+
+	return results, nil
+}
+
+// abortBatch fails every item of an atomic batch but the one that failed.
+func abortBatch(results []models.BatchResult, failed int) []models.BatchResult {
+	for i := range results {
+		if i != failed {
+			results[i] = models.BatchResult{Err: models.ErrBatchAborted}
+		}
+	}
+	return results
+}
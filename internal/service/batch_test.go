@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestUserService_BatchDeleteUsers(t *testing.T) {
+	alive := uuid.New()
+	missing := uuid.New()
+	other := uuid.New()
+
+	newRepo := func() *UserStorageMock {
+		return &UserStorageMock{
+			ExistsByIDFunc: func(ctx context.Context, userID uuid.UUID) (bool, error) {
+				return userID != missing, nil
+			},
+			DeleteUserFunc: func(ctx context.Context, userID uuid.UUID) error {
+				return nil
+			},
+			RunInTxFunc: func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			},
+		}
+	}
+
+	var (
+		mu     sync.Mutex
+		topics []string
+		wg     sync.WaitGroup
+	)
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			mu.Lock()
+			defer mu.Unlock()
+			topics = append(topics, topic)
+			wg.Done()
+			return nil
+		},
+	}
+
+	t.Log("best effort")
+	{
+		repoMock := newRepo()
+		s := NewUserService(repoMock, nil, &publisherMock)
+
+		wg.Add(2)
+		results, err := s.BatchDeleteUsers(context.TODO(), []uuid.UUID{alive, missing, other}, false)
+		require.NoError(t, err)
+		wg.Wait()
+
+		require.Equal(t, alive, results[0].UserID)
+		require.ErrorIs(t, results[1].Err, models.ErrUserNotFound)
+		require.Equal(t, uuid.Nil, results[1].UserID)
+		require.Equal(t, other, results[2].UserID)
+		require.Len(t, repoMock.DeleteUserCalls(), 2)
+		require.Len(t, repoMock.RunInTxCalls(), 0)
+		require.Equal(t, []string{"UserDeleted", "UserDeleted"}, topics)
+	}
+
+	t.Log("atomic")
+	{
+		topics = nil
+		repoMock := newRepo()
+		s := NewUserService(repoMock, nil, &publisherMock)
+
+		results, err := s.BatchDeleteUsers(context.TODO(), []uuid.UUID{alive, missing, other}, true)
+		require.NoError(t, err)
+
+		require.Equal(t, []models.BatchResult{
+			{Err: models.ErrBatchAborted},
+			{Err: models.ErrUserNotFound},
+			{Err: models.ErrBatchAborted},
+		}, results)
+		require.Len(t, repoMock.RunInTxCalls(), 1)
+		require.Len(t, repoMock.DeleteUserCalls(), 1)
+		require.Empty(t, topics)
+
+		wg.Add(2)
+		results, err = s.BatchDeleteUsers(context.TODO(), []uuid.UUID{alive, other}, true)
+		require.NoError(t, err)
+		wg.Wait()
+
+		require.Equal(t, []models.BatchResult{{UserID: alive}, {UserID: other}}, results)
+		require.Equal(t, []string{"UserDeleted", "UserDeleted"}, topics)
+	}
+
+	t.Log("too many items")
+	{
+		s := NewUserService(newRepo(), nil, &publisherMock)
+
+		_, err := s.BatchDeleteUsers(context.TODO(), make([]uuid.UUID, maxBatchItems+1), true)
+		require.ErrorIs(t, err, models.ErrBatchTooLarge)
+	}
+}
+
+func TestUserService_BatchCreateUsers(t *testing.T) {
+	repoMock := UserStorageMock{
+		InsertUserFunc: func(ctx context.Context, user models.User) (uuid.UUID, error) {
+			if user.Email == "taken@mail.com" {
+				return uuid.Nil, models.ErrEmailTaken
+			}
+			return user.ID, nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			require.Equal(t, "UserCreated", topic)
+			wg.Done()
+			return nil
+		},
+	}
+
+	s := NewUserService(&repoMock, nil, &publisherMock)
+
+	wg.Add(1)
+	results, err := s.BatchCreateUsers(context.TODO(), []models.NewUser{
+		{Email: "antonis@mail.com", Password: "pass"},
+		{Email: "taken@mail.com", Password: "pass"},
+	}, false)
+	require.NoError(t, err)
+	wg.Wait()
+
+	require.Equal(t, repoMock.InsertUserCalls()[0].User.ID, results[0].UserID)
+	require.NoError(t, results[0].Err)
+	require.ErrorIs(t, results[1].Err, models.ErrEmailTaken)
+
+	t.Log("passwords hashed before the transaction")
+	{
+		repoMock.RunInTxFunc = func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}
+
+		results, err := s.BatchCreateUsers(context.TODO(), []models.NewUser{
+			{Email: "bruce@mail.com", Password: "pass"},
+			{Email: "clark@mail.com", Password: strings.Repeat("x", 73)},
+		}, true)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, results[0].Err, models.ErrBatchAborted)
+		require.ErrorIs(t, results[1].Err, bcrypt.ErrPasswordTooLong)
+		require.Len(t, repoMock.RunInTxCalls(), 0)
+		require.Len(t, repoMock.InsertUserCalls(), 2)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pbevents "github.com/TonyPath/user-mng-grpc-service/proto/events/user"
+)
+
+const maxConsentPurposeLength = 255
+
+//go:generate moq -out consent_storage_mock_test.go . ConsentStorage
+type ConsentStorage interface {
+	InsertConsent(ctx context.Context, consent models.Consent) (models.Consent, error)
+	GetConsents(ctx context.Context, userID uuid.UUID, opts models.GetConsentsOptions) ([]models.Consent, error)
+}
+
+// ConsentService keeps the history of the consents users give for each purpose.
+type ConsentService struct {
+	repo           ConsentStorage
+	eventPublisher EventPublisher
+}
+
+func NewConsentService(repo ConsentStorage, publisher EventPublisher) *ConsentService {
+	return &ConsentService{
+		repo:           repo,
+		eventPublisher: publisher,
+	}
+}
+
+// RecordConsent records that the user grants consent for the purpose.
+func (cSvc *ConsentService) RecordConsent(ctx context.Context, change models.ConsentChange) (models.Consent, error) {
+	return cSvc.record(ctx, change, models.ConsentStatusGranted)
+}
+
+// WithdrawConsent records that the user withdraws a consent previously granted.
+func (cSvc *ConsentService) WithdrawConsent(ctx context.Context, change models.ConsentChange) (models.Consent, error) {
+	if err := validateConsentPurpose(change.Purpose); err != nil {
+		return models.Consent{}, err
+	}
+
+	current, err := cSvc.repo.GetConsents(ctx, change.UserID, models.GetConsentsOptions{Purpose: change.Purpose})
+	if err != nil {
+		return models.Consent{}, err
+	}
+
+	if len(current) == 0 || current[0].Status != models.ConsentStatusGranted {
+		return models.Consent{}, models.ErrConsentNotGranted
+	}
+
+	return cSvc.record(ctx, change, models.ConsentStatusWithdrawn)
+}
+
+func (cSvc *ConsentService) ListConsents(ctx context.Context, userID uuid.UUID, opts models.GetConsentsOptions) ([]models.Consent, error) {
+	return cSvc.repo.GetConsents(ctx, userID, opts)
+}
+
+func (cSvc *ConsentService) record(ctx context.Context, change models.ConsentChange, status models.ConsentStatus) (models.Consent, error) {
+	if err := validateConsentPurpose(change.Purpose); err != nil {
+		return models.Consent{}, err
+	}
+
+	consent, err := cSvc.repo.InsertConsent(ctx, models.Consent{
+		UserID:        change.UserID,
+		Purpose:       change.Purpose,
+		Status:        status,
+		Source:        change.Source,
+		PolicyVersion: change.PolicyVersion,
+	})
+	if err != nil {
+		return models.Consent{}, err
+	}
+
+	go func() {
+		ctx := context.Background()
+		evt := pbevents.ConsentChanged{
+			UserId:        consent.UserID.String(),
+			Purpose:       consent.Purpose,
+			Status:        string(consent.Status),
+			Source:        consent.Source,
+			PolicyVersion: consent.PolicyVersion,
+			ChangedAt:     timestamppb.New(consent.RecordedAt),
+		}
+		_ = cSvc.eventPublisher.Publish(ctx, "ConsentChanged", consent.UserID.String(), &evt)
+	}()
+
+	return consent, nil
+}
+
+func validateConsentPurpose(purpose string) error {
+	if purpose == "" || len(purpose) > maxConsentPurposeLength {
+		return fmt.Errorf("%w: %q", models.ErrInvalidConsentPurpose, purpose)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pbevents "github.com/TonyPath/user-mng-grpc-service/proto/events/user"
+)
+
+func TestConsentService_RecordConsent(t *testing.T) {
+	guard := make(chan struct{})
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	repoMock := ConsentStorageMock{
+		InsertConsentFunc: func(ctx context.Context, consent models.Consent) (models.Consent, error) {
+			consent.ID = 1
+			consent.RecordedAt = time.Now()
+			return consent, nil
+		},
+	}
+
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			require.Equal(t, "ConsentChanged", topic)
+			require.Equal(t, userID.String(), key)
+			require.Equal(t, string(models.ConsentStatusGranted), pbMessage.(*pbevents.ConsentChanged).GetStatus())
+			guard <- struct{}{}
+			return nil
+		},
+	}
+
+	s := NewConsentService(&repoMock, &publisherMock)
+
+	consent, err := s.RecordConsent(context.TODO(), models.ConsentChange{
+		UserID:        userID,
+		Purpose:       "newsletter",
+		Source:        "signup_form",
+		PolicyVersion: "2023-01",
+	})
+
+	<-guard
+
+	require.NoError(t, err)
+	require.Equal(t, models.ConsentStatusGranted, consent.Status)
+	require.Equal(t, "2023-01", consent.PolicyVersion)
+	require.Len(t, repoMock.InsertConsentCalls(), 1)
+}
+
+func TestConsentService_WithdrawConsent(t *testing.T) {
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	tests := []struct {
+		name               string
+		purpose            string
+		current            []models.Consent
+		checkFn            func(t *testing.T, consent models.Consent, err error)
+		insertConsentCalls int
+	}{
+		{
+			name:    "granted",
+			purpose: "newsletter",
+			current: []models.Consent{{Purpose: "newsletter", Status: models.ConsentStatusGranted}},
+			checkFn: func(t *testing.T, consent models.Consent, err error) {
+				require.NoError(t, err)
+				require.Equal(t, models.ConsentStatusWithdrawn, consent.Status)
+			},
+			insertConsentCalls: 1,
+		},
+		{
+			name:    "already withdrawn",
+			purpose: "newsletter",
+			current: []models.Consent{{Purpose: "newsletter", Status: models.ConsentStatusWithdrawn}},
+			checkFn: func(t *testing.T, consent models.Consent, err error) {
+				require.ErrorIs(t, err, models.ErrConsentNotGranted)
+			},
+			insertConsentCalls: 0,
+		},
+		{
+			name:    "never granted",
+			purpose: "newsletter",
+			checkFn: func(t *testing.T, consent models.Consent, err error) {
+				require.ErrorIs(t, err, models.ErrConsentNotGranted)
+			},
+			insertConsentCalls: 0,
+		},
+		{
+			name:    "empty purpose",
+			purpose: "",
+			checkFn: func(t *testing.T, consent models.Consent, err error) {
+				require.ErrorIs(t, err, models.ErrInvalidConsentPurpose)
+			},
+			insertConsentCalls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			published := make(chan struct{}, 1)
+
+			repoMock := ConsentStorageMock{
+				GetConsentsFunc: func(ctx context.Context, uID uuid.UUID, opts models.GetConsentsOptions) ([]models.Consent, error) {
+					require.Equal(t, tt.purpose, opts.Purpose)
+					require.False(t, opts.IncludeHistory)
+					return tt.current, nil
+				},
+				InsertConsentFunc: func(ctx context.Context, consent models.Consent) (models.Consent, error) {
+					return consent, nil
+				},
+			}
+
+			publisherMock := EventPublisherMock{
+				PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+					published <- struct{}{}
+					return nil
+				},
+			}
+
+			s := NewConsentService(&repoMock, &publisherMock)
+
+			consent, err := s.WithdrawConsent(context.TODO(), models.ConsentChange{UserID: userID, Purpose: tt.purpose})
+			tt.checkFn(t, consent, err)
+			require.Len(t, repoMock.InsertConsentCalls(), tt.insertConsentCalls)
+
+			if tt.insertConsentCalls > 0 {
+				<-published
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pbevents "github.com/TonyPath/user-mng-grpc-service/proto/events/user"
+)
+
+//go:generate moq -out contact_storage_mock_test.go . ContactStorage
+type ContactStorage interface {
+	InsertEmail(ctx context.Context, userID uuid.UUID, email string) error
+	DeleteEmail(ctx context.Context, userID uuid.UUID, email string) error
+	SetPrimaryEmail(ctx context.Context, userID uuid.UUID, email string, updatedAt time.Time) error
+	VerifyEmail(ctx context.Context, userID uuid.UUID, email string, verifiedAt time.Time) error
+	GetEmails(ctx context.Context, userID uuid.UUID) ([]models.UserEmail, error)
+	InsertPhone(ctx context.Context, userID uuid.UUID, number string) error
+	DeletePhone(ctx context.Context, userID uuid.UUID, number string) error
+	SetPrimaryPhone(ctx context.Context, userID uuid.UUID, number string, updatedAt time.Time) error
+	VerifyPhone(ctx context.Context, userID uuid.UUID, number string, verifiedAt time.Time) error
+	GetPhones(ctx context.Context, userID uuid.UUID) ([]models.UserPhone, error)
+}
+
+// ContactService manages the email addresses and phone numbers of users.
+type ContactService struct {
+	repo           ContactStorage
+	users          UserStorage
+	eventPublisher EventPublisher
+}
+
+func NewContactService(repo ContactStorage, users UserStorage, publisher EventPublisher) *ContactService {
+	return &ContactService{
+		repo:           repo,
+		users:          users,
+		eventPublisher: publisher,
+	}
+}
+
+func (cSvc *ContactService) AddEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+	return cSvc.repo.InsertEmail(ctx, userID, email)
+}
+
+func (cSvc *ContactService) RemoveEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	return cSvc.repo.DeleteEmail(ctx, userID, strings.TrimSpace(email))
+}
+
+// SetPrimaryEmail makes the address, which must already belong to the user,
+// its email.
+func (cSvc *ContactService) SetPrimaryEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	now := time.Now().UTC()
+
+	if err := cSvc.repo.SetPrimaryEmail(ctx, userID, strings.TrimSpace(email), now); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx := context.Background()
+		evt := pbevents.UserUpdated{
+			UserId:    userID.String(),
+			UpdatedAt: timestamppb.New(now),
+		}
+		_ = cSvc.eventPublisher.Publish(ctx, "UserUpdated", userID.String(), &evt)
+	}()
+
+	return nil
+}
+
+// VerifyEmail records that the user proved it owns the address.
+func (cSvc *ContactService) VerifyEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	return cSvc.repo.VerifyEmail(ctx, userID, strings.TrimSpace(email), time.Now().UTC())
+}
+
+func (cSvc *ContactService) ListEmails(ctx context.Context, userID uuid.UUID) ([]models.UserEmail, error) {
+	if err := cSvc.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return cSvc.repo.GetEmails(ctx, userID)
+}
+
+func (cSvc *ContactService) AddPhone(ctx context.Context, userID uuid.UUID, number string) error {
+	number, err := normalizePhone(number)
+	if err != nil {
+		return err
+	}
+	return cSvc.repo.InsertPhone(ctx, userID, number)
+}
+
+func (cSvc *ContactService) RemovePhone(ctx context.Context, userID uuid.UUID, number string) error {
+	number, err := normalizePhone(number)
+	if err != nil {
+		return err
+	}
+	return cSvc.repo.DeletePhone(ctx, userID, number)
+}
+
+func (cSvc *ContactService) SetPrimaryPhone(ctx context.Context, userID uuid.UUID, number string) error {
+	number, err := normalizePhone(number)
+	if err != nil {
+		return err
+	}
+	return cSvc.repo.SetPrimaryPhone(ctx, userID, number, time.Now().UTC())
+}
+
+// VerifyPhone records that the user proved it owns the number.
+func (cSvc *ContactService) VerifyPhone(ctx context.Context, userID uuid.UUID, number string) error {
+	number, err := normalizePhone(number)
+	if err != nil {
+		return err
+	}
+	return cSvc.repo.VerifyPhone(ctx, userID, number, time.Now().UTC())
+}
+
+func (cSvc *ContactService) ListPhones(ctx context.Context, userID uuid.UUID) ([]models.UserPhone, error) {
+	if err := cSvc.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return cSvc.repo.GetPhones(ctx, userID)
+}
+
+func (cSvc *ContactService) ensureUser(ctx context.Context, userID uuid.UUID) error {
+	exists, err := cSvc.users.ExistsByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// normalizeEmail accepts a bare address, without display name.
+func normalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", fmt.Errorf("%w: %q", models.ErrInvalidEmail, email)
+	}
+
+	return email, nil
+}
+
+// normalizePhone returns the number in E.164 format: a + followed by the
+// country code and subscriber number, at most 15 digits. Numbers must be given
+// in international format, with a leading + or 00; spaces, dots, dashes and
+// parentheses are dropped.
+func normalizePhone(number string) (string, error) {
+	invalid := fmt.Errorf("%w: %q", models.ErrInvalidPhone, number)
+
+	s := strings.TrimSpace(number)
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, "00"):
+		s = s[2:]
+	default:
+		return "", invalid
+	}
+
+	digits := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+		case c == ' ' || c == '.' || c == '-' || c == '(' || c == ')':
+		default:
+			return "", invalid
+		}
+	}
+
+	// Country codes do not start with 0.
+	if len(digits) < 7 || len(digits) > 15 || digits[0] == '0' {
+		return "", invalid
+	}
+
+	return "+" + string(digits), nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "+30 210 123 4567", want: "+302101234567"},
+		{in: "0030 (210) 123-4567", want: "+302101234567"},
+		{in: "+1.415.555.2671", want: "+14155552671"},
+		{in: "  +442071838750 ", want: "+442071838750"},
+		{in: "2101234567", wantErr: true},
+		{in: "+0302101234567", wantErr: true},
+		{in: "+30 210 123 456a", wantErr: true},
+		{in: "+123", wantErr: true},
+		{in: "+1234567890123456", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := normalizePhone(tt.in)
+			if tt.wantErr {
+				require.ErrorIs(t, err, models.ErrInvalidPhone)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestContactService_AddEmail(t *testing.T) {
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	tests := []struct {
+		name        string
+		email       string
+		insertCalls int
+		wantErr     error
+	}{
+		{name: "valid", email: " bruce@wayne.com ", insertCalls: 1},
+		{name: "missing domain", email: "bruce", wantErr: models.ErrInvalidEmail},
+		{name: "display name", email: "Bruce <bruce@wayne.com>", wantErr: models.ErrInvalidEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := ContactStorageMock{
+				InsertEmailFunc: func(ctx context.Context, userID uuid.UUID, email string) error {
+					return nil
+				},
+			}
+
+			s := NewContactService(&repoMock, &UserStorageMock{}, &EventPublisherMock{})
+
+			err := s.AddEmail(context.TODO(), userID, tt.email)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Len(t, repoMock.InsertEmailCalls(), tt.insertCalls)
+			if tt.insertCalls > 0 {
+				require.Equal(t, "bruce@wayne.com", repoMock.InsertEmailCalls()[0].Email)
+			}
+		})
+	}
+}
+
+func TestContactService_AddPhone(t *testing.T) {
+	repoMock := ContactStorageMock{
+		InsertPhoneFunc: func(ctx context.Context, userID uuid.UUID, number string) error {
+			return nil
+		},
+	}
+
+	s := NewContactService(&repoMock, &UserStorageMock{}, &EventPublisherMock{})
+
+	err := s.AddPhone(context.TODO(), uuid.New(), "+30 210 123 4567")
+	require.NoError(t, err)
+	require.Equal(t, "+302101234567", repoMock.InsertPhoneCalls()[0].Number)
+
+	err = s.AddPhone(context.TODO(), uuid.New(), "210 123 4567")
+	require.ErrorIs(t, err, models.ErrInvalidPhone)
+	require.Len(t, repoMock.InsertPhoneCalls(), 1)
+}
+
+func TestContactService_SetPrimaryEmail(t *testing.T) {
+	guard := make(chan struct{})
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	repoMock := ContactStorageMock{
+		SetPrimaryEmailFunc: func(ctx context.Context, userID uuid.UUID, email string, updatedAt time.Time) error {
+			return nil
+		},
+	}
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			guard <- struct{}{}
+			return nil
+		},
+	}
+
+	s := NewContactService(&repoMock, &UserStorageMock{}, &publisherMock)
+
+	err := s.SetPrimaryEmail(context.TODO(), userID, "bruce@wayne.com")
+
+	<-guard
+
+	require.NoError(t, err)
+	require.Len(t, repoMock.SetPrimaryEmailCalls(), 1)
+	require.Equal(t, "UserUpdated", publisherMock.PublishCalls()[0].Topic)
+	require.Equal(t, userID.String(), publisherMock.PublishCalls()[0].Key)
+}
+
+func TestContactService_ListPhones_UserNotFound(t *testing.T) {
+	repoMock := ContactStorageMock{}
+	usersMock := UserStorageMock{
+		ExistsByIDFunc: func(ctx context.Context, userID uuid.UUID) (bool, error) {
+			return false, nil
+		},
+	}
+
+	s := NewContactService(&repoMock, &usersMock, &EventPublisherMock{})
+
+	_, err := s.ListPhones(context.TODO(), uuid.New())
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+	require.Len(t, repoMock.GetPhonesCalls(), 0)
+}
@@ -22,6 +22,9 @@ var _ EventPublisher = &EventPublisherMock{}
 // 			PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
 // 				panic("mock out the Publish method")
 // 			},
+// 			PublishTombstoneFunc: func(ctx context.Context, topic string, key string) error {
+// 				panic("mock out the PublishTombstone method")
+// 			},
 // 		}
 //
 // 		// use mockedEventPublisher in code that requires EventPublisher
@@ -32,6 +35,9 @@ type EventPublisherMock struct {
 	// PublishFunc mocks the Publish method.
 	PublishFunc func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error
 
+	// PublishTombstoneFunc mocks the PublishTombstone method.
+	PublishTombstoneFunc func(ctx context.Context, topic string, key string) error
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// Publish holds details about calls to the Publish method.
@@ -45,8 +51,18 @@ type EventPublisherMock struct {
 			// PbMessage is the pbMessage argument value.
 			PbMessage protoreflect.ProtoMessage
 		}
+		// PublishTombstone holds details about calls to the PublishTombstone method.
+		PublishTombstone []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Topic is the topic argument value.
+			Topic string
+			// Key is the key argument value.
+			Key string
+		}
 	}
-	lockPublish sync.RWMutex
+	lockPublish          sync.RWMutex
+	lockPublishTombstone sync.RWMutex
 }
 
 // Publish calls PublishFunc.
@@ -91,3 +107,42 @@ func (mock *EventPublisherMock) PublishCalls() []struct {
 	mock.lockPublish.RUnlock()
 	return calls
 }
+
+// PublishTombstone calls PublishTombstoneFunc.
+func (mock *EventPublisherMock) PublishTombstone(ctx context.Context, topic string, key string) error {
+	if mock.PublishTombstoneFunc == nil {
+		panic("EventPublisherMock.PublishTombstoneFunc: method is nil but EventPublisher.PublishTombstone was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Topic string
+		Key   string
+	}{
+		Ctx:   ctx,
+		Topic: topic,
+		Key:   key,
+	}
+	mock.lockPublishTombstone.Lock()
+	mock.calls.PublishTombstone = append(mock.calls.PublishTombstone, callInfo)
+	mock.lockPublishTombstone.Unlock()
+	return mock.PublishTombstoneFunc(ctx, topic, key)
+}
+
+// PublishTombstoneCalls gets all the calls that were made to PublishTombstone.
+// Check the length with:
+//     len(mockedEventPublisher.PublishTombstoneCalls())
+func (mock *EventPublisherMock) PublishTombstoneCalls() []struct {
+	Ctx   context.Context
+	Topic string
+	Key   string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Topic string
+		Key   string
+	}
+	mock.lockPublishTombstone.RLock()
+	calls = mock.calls.PublishTombstone
+	mock.lockPublishTombstone.RUnlock()
+	return calls
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+const exportAuditPageSize = 500
+
+// ExportService assembles the data held about a user for data subject access requests.
+type ExportService struct {
+	users      UserStorage
+	contacts   ContactStorage
+	identities IdentityStorage
+	groups     GroupStorage
+	apiKeys    APIKeyStorage
+	consents   ConsentStorage
+	settings   SettingsStorage
+	audit      AuditStorage
+}
+
+func NewExportService(users UserStorage, contacts ContactStorage, identities IdentityStorage, groups GroupStorage, apiKeys APIKeyStorage, consents ConsentStorage, settings SettingsStorage, audit AuditStorage) *ExportService {
+	return &ExportService{
+		users:      users,
+		contacts:   contacts,
+		identities: identities,
+		groups:     groups,
+		apiKeys:    apiKeys,
+		consents:   consents,
+		settings:   settings,
+		audit:      audit,
+	}
+}
+
+// ExportUserData writes everything held about the user to w in the given
+// format. The export is recorded in the audit log before anything is written.
+func (eSvc *ExportService) ExportUserData(ctx context.Context, userID uuid.UUID, format models.ExportFormat, w io.Writer) error {
+	if format != models.ExportFormatJSON && format != models.ExportFormatZIP {
+		return fmt.Errorf("%w: %q", models.ErrInvalidExportFormat, format)
+	}
+
+	data, err := eSvc.collect(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	entry := audit.NewEntry(ctx, userID, models.AuditActionUserDataExported, nil, map[string]any{"format": string(format)})
+	if err := eSvc.audit.InsertAuditEntry(ctx, entry); err != nil {
+		return fmt.Errorf("recording export: %w", err)
+	}
+
+	if format == models.ExportFormatZIP {
+		return writeExportZIP(w, data)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+func (eSvc *ExportService) collect(ctx context.Context, userID uuid.UUID) (models.UserDataExport, error) {
+	user, err := eSvc.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.UserDataExport{}, err
+	}
+
+	data := models.UserDataExport{
+		ExportedAt: time.Now().UTC(),
+		Profile: models.ExportedProfile{
+			ID:         user.ID,
+			Type:       user.Type,
+			Status:     user.Status,
+			Email:      user.Email,
+			FirstName:  user.FirstName,
+			LastName:   user.LastName,
+			Nickname:   user.Nickname,
+			Country:    user.Country,
+			Attributes: user.Attributes,
+			CreatedAt:  user.CreatedAt,
+			UpdatedAt:  user.UpdateAt,
+		},
+		Emails:     []models.ExportedEmail{},
+		Phones:     []models.ExportedPhone{},
+		Identities: []models.ExportedIdentity{},
+		Groups:     []models.ExportedGroup{},
+		APIKeys:    []models.ExportedAPIKey{},
+		Consents:   []models.ExportedConsent{},
+		Settings:   []models.ExportedSetting{},
+		AuditLog:   []models.ExportedAuditEntry{},
+	}
+
+	emails, err := eSvc.contacts.GetEmails(ctx, userID)
+	if err != nil {
+		return models.UserDataExport{}, fmt.Errorf("fetching emails: %w", err)
+	}
+
+	for _, e := range emails {
+		data.Emails = append(data.Emails, models.ExportedEmail{
+			Email:      e.Email,
+			Primary:    e.Primary,
+			VerifiedAt: e.VerifiedAt,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+
+	phones, err := eSvc.contacts.GetPhones(ctx, userID)
+	if err != nil {
+		return models.UserDataExport{}, fmt.Errorf("fetching phones: %w", err)
+	}
+
+	for _, p := range phones {
+		data.Phones = append(data.Phones, models.ExportedPhone{
+			Number:     p.Number,
+			Primary:    p.Primary,
+			VerifiedAt: p.VerifiedAt,
+			CreatedAt:  p.CreatedAt,
+		})
+	}
+
+	identities, err := eSvc.identities.GetIdentities(ctx, userID)
+	if err != nil {
+		return models.UserDataExport{}, fmt.Errorf("fetching identities: %w", err)
+	}
+
+	for _, i := range identities {
+		data.Identities = append(data.Identities, models.ExportedIdentity{
+			Provider: i.Provider,
+			Subject:  i.Subject,
+			Claims:   i.Claims,
+			LinkedAt: i.LinkedAt,
+		})
+	}
+
+	groups, err := eSvc.groups.GetGroupsByUser(ctx, userID)
+	if err != nil {
+		return models.UserDataExport{}, fmt.Errorf("fetching groups: %w", err)
+	}
+
+	for _, g := range groups {
+		data.Groups = append(data.Groups, models.ExportedGroup{
+			ID:   g.ID,
+			Name: g.Name,
+		})
+	}
+
+	keys, err := eSvc.apiKeys.GetAPIKeysByUser(ctx, userID)
+	if err != nil {
+		return models.UserDataExport{}, fmt.Errorf("fetching api keys: %w", err)
+	}
+
+	for _, k := range keys {
+		data.APIKeys = append(data.APIKeys, models.ExportedAPIKey{
+			ID:         k.ID,
+			Name:       k.Name,
+			Prefix:     k.Prefix,
+			Scopes:     k.Scopes,
+			ExpiresAt:  k.ExpiresAt,
+			LastUsedAt: k.LastUsedAt,
+			RevokedAt:  k.RevokedAt,
+			CreatedAt:  k.CreatedAt,
+		})
+	}
+
+	consents, err := eSvc.consents.GetConsents(ctx, userID, models.GetConsentsOptions{IncludeHistory: true})
+	if err != nil {
+		return models.UserDataExport{}, fmt.Errorf("fetching consents: %w", err)
+	}
+
+	for _, c := range consents {
+		data.Consents = append(data.Consents, models.ExportedConsent{
+			Purpose:       c.Purpose,
+			Status:        c.Status,
+			Source:        c.Source,
+			PolicyVersion: c.PolicyVersion,
+			RecordedAt:    c.RecordedAt,
+		})
+	}
+
+	settings, err := eSvc.settings.GetSettings(ctx, userID, "", nil)
+	if err != nil {
+		return models.UserDataExport{}, fmt.Errorf("fetching settings: %w", err)
+	}
+
+	for _, st := range settings {
+		data.Settings = append(data.Settings, models.ExportedSetting{
+			Namespace: st.Namespace,
+			Key:       st.Key,
+			Value:     st.Value,
+			UpdatedAt: st.UpdatedAt,
+		})
+	}
+
+	opts := models.GetAuditEntriesOptions{
+		UserID: userID,
+		Limit:  exportAuditPageSize,
+	}
+	for {
+		entries, err := eSvc.audit.GetAuditEntries(ctx, opts)
+		if err != nil {
+			return models.UserDataExport{}, fmt.Errorf("fetching audit log: %w", err)
+		}
+
+		for _, e := range entries {
+			data.AuditLog = append(data.AuditLog, models.ExportedAuditEntry{
+				Actor:     e.Actor,
+				Action:    e.Action,
+				Before:    e.Before,
+				After:     e.After,
+				RequestID: e.RequestID,
+				ClientIP:  e.ClientIP,
+				CreatedAt: e.CreatedAt,
+			})
+		}
+
+		if uint64(len(entries)) < opts.Limit {
+			break
+		}
+		opts.Cursor = entries[len(entries)-1].ID
+	}
+
+	return data, nil
+}
+
+// writeExportZIP writes every section of the export to its own JSON file.
+func writeExportZIP(w io.Writer, data models.UserDataExport) error {
+	sections := []struct {
+		name  string
+		value any
+	}{
+		{name: "profile.json", value: data.Profile},
+		{name: "emails.json", value: data.Emails},
+		{name: "phones.json", value: data.Phones},
+		{name: "identities.json", value: data.Identities},
+		{name: "groups.json", value: data.Groups},
+		{name: "api_keys.json", value: data.APIKeys},
+		{name: "consents.json", value: data.Consents},
+		{name: "settings.json", value: data.Settings},
+		{name: "audit_log.json", value: data.AuditLog},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, s := range sections {
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     s.name,
+			Method:   zip.Deflate,
+			Modified: data.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(s.value); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
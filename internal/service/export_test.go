@@ -0,0 +1,188 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func newExportMocks(userID uuid.UUID) (*UserStorageMock, *ContactStorageMock, *IdentityStorageMock, *GroupStorageMock, *APIKeyStorageMock, *ConsentStorageMock, *SettingsStorageMock, *AuditStorageMock) {
+	users := &UserStorageMock{
+		GetUserByIDFunc: func(ctx context.Context, uID uuid.UUID) (models.User, error) {
+			if uID != userID {
+				return models.User{}, models.ErrUserNotFound
+			}
+			return models.User{
+				ID:       userID,
+				Email:    "bruce@wayne.com",
+				Password: []byte("$2a$10$bcrypt-hash"),
+			}, nil
+		},
+	}
+
+	contacts := &ContactStorageMock{
+		GetEmailsFunc: func(ctx context.Context, uID uuid.UUID) ([]models.UserEmail, error) {
+			return []models.UserEmail{
+				{UserID: userID, Email: "bruce@wayne.com", Primary: true},
+				{UserID: userID, Email: "batman@gotham.com"},
+			}, nil
+		},
+		GetPhonesFunc: func(ctx context.Context, uID uuid.UUID) ([]models.UserPhone, error) {
+			return []models.UserPhone{{UserID: userID, Number: "+14155552671", Primary: true}}, nil
+		},
+	}
+
+	identities := &IdentityStorageMock{
+		GetIdentitiesFunc: func(ctx context.Context, uID uuid.UUID) ([]models.ExternalIdentity, error) {
+			return []models.ExternalIdentity{{UserID: userID, Provider: "google", Subject: "1234"}}, nil
+		},
+	}
+
+	groups := &GroupStorageMock{
+		GetGroupsByUserFunc: func(ctx context.Context, uID uuid.UUID) ([]models.Group, error) {
+			return []models.Group{{ID: uuid.New(), Name: "admins"}}, nil
+		},
+	}
+
+	apiKeys := &APIKeyStorageMock{
+		GetAPIKeysByUserFunc: func(ctx context.Context, uID uuid.UUID) ([]models.APIKey, error) {
+			return []models.APIKey{{ID: uuid.New(), Prefix: "abc", Hash: []byte("key-hash")}}, nil
+		},
+	}
+
+	consents := &ConsentStorageMock{
+		GetConsentsFunc: func(ctx context.Context, uID uuid.UUID, opts models.GetConsentsOptions) ([]models.Consent, error) {
+			return []models.Consent{
+				{UserID: userID, Purpose: "newsletter", Status: models.ConsentStatusWithdrawn},
+				{UserID: userID, Purpose: "newsletter", Status: models.ConsentStatusGranted},
+			}, nil
+		},
+	}
+
+	settings := &SettingsStorageMock{
+		GetSettingsFunc: func(ctx context.Context, uID uuid.UUID, namespace string, keys []string) ([]models.Setting, error) {
+			return []models.Setting{{UserID: userID, Namespace: "ui", Key: "theme", Value: json.RawMessage(`"dark"`)}}, nil
+		},
+	}
+
+	auditLog := &AuditStorageMock{
+		InsertAuditEntryFunc: func(ctx context.Context, entry models.AuditEntry) error {
+			return nil
+		},
+		GetAuditEntriesFunc: func(ctx context.Context, opts models.GetAuditEntriesOptions) ([]models.AuditEntry, error) {
+			if opts.Cursor != 0 {
+				return nil, nil
+			}
+			entries := make([]models.AuditEntry, opts.Limit)
+			for i := range entries {
+				entries[i] = models.AuditEntry{ID: int64(len(entries) - i), UserID: userID, Action: models.AuditActionUserUpdated}
+			}
+			return entries, nil
+		},
+	}
+
+	return users, contacts, identities, groups, apiKeys, consents, settings, auditLog
+}
+
+func TestExportService_ExportUserData_JSON(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+	users, contacts, identities, groups, apiKeys, consents, settings, auditLog := newExportMocks(userID)
+
+	s := NewExportService(users, contacts, identities, groups, apiKeys, consents, settings, auditLog)
+
+	var buf bytes.Buffer
+	err := s.ExportUserData(context.TODO(), userID, models.ExportFormatJSON, &buf)
+	require.NoError(t, err)
+	require.NotContains(t, buf.String(), "bcrypt-hash")
+	require.NotContains(t, buf.String(), "key-hash")
+
+	var got models.UserDataExport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	require.Equal(t, "bruce@wayne.com", got.Profile.Email)
+	require.Len(t, got.Emails, 2)
+	require.Equal(t, "+14155552671", got.Phones[0].Number)
+	require.Equal(t, "google", got.Identities[0].Provider)
+	require.Len(t, got.Groups, 1)
+	require.Len(t, got.APIKeys, 1)
+	require.Len(t, got.Consents, 2)
+	require.Equal(t, json.RawMessage(`"dark"`), got.Settings[0].Value)
+	require.True(t, consents.GetConsentsCalls()[0].Opts.IncludeHistory)
+	require.Len(t, got.AuditLog, exportAuditPageSize)
+	require.Len(t, auditLog.GetAuditEntriesCalls(), 2)
+
+	require.Len(t, auditLog.InsertAuditEntryCalls(), 1)
+	entry := auditLog.InsertAuditEntryCalls()[0].Entry
+	require.Equal(t, userID, entry.UserID)
+	require.Equal(t, models.AuditActionUserDataExported, entry.Action)
+}
+
+func TestExportService_ExportUserData_ZIP(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+	users, contacts, identities, groups, apiKeys, consents, settings, auditLog := newExportMocks(userID)
+
+	s := NewExportService(users, contacts, identities, groups, apiKeys, consents, settings, auditLog)
+
+	var buf bytes.Buffer
+	err := s.ExportUserData(context.TODO(), userID, models.ExportFormatZIP, &buf)
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"profile.json", "emails.json", "phones.json", "identities.json", "groups.json", "api_keys.json", "consents.json", "settings.json", "audit_log.json"}, names)
+}
+
+func TestExportService_ExportUserData_Fail(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		format  models.ExportFormat
+		checkFn func(t *testing.T, err error)
+	}{
+		{
+			name:   "ErrUserNotFound",
+			userID: uuid.New(),
+			format: models.ExportFormatJSON,
+			checkFn: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, models.ErrUserNotFound)
+			},
+		},
+		{
+			name:   "ErrInvalidExportFormat",
+			userID: userID,
+			format: "csv",
+			checkFn: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, models.ErrInvalidExportFormat)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, contacts, identities, groups, apiKeys, consents, settings, auditLog := newExportMocks(userID)
+
+			s := NewExportService(users, contacts, identities, groups, apiKeys, consents, settings, auditLog)
+
+			var buf bytes.Buffer
+			err := s.ExportUserData(context.TODO(), tt.userID, tt.format, &buf)
+			tt.checkFn(t, err)
+			require.Zero(t, buf.Len())
+			require.Len(t, auditLog.InsertAuditEntryCalls(), 0)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pbevents "github.com/TonyPath/user-mng-grpc-service/proto/events/user"
+)
+
+//go:generate moq -out group_storage_mock_test.go . GroupStorage
+type GroupStorage interface {
+	InsertGroup(ctx context.Context, group models.Group) (uuid.UUID, error)
+	UpdateGroupName(ctx context.Context, groupID uuid.UUID, name string, updatedAt time.Time) error
+	DeleteGroup(ctx context.Context, groupID uuid.UUID) error
+	GetGroupByID(ctx context.Context, groupID uuid.UUID) (models.Group, error)
+	GetGroups(ctx context.Context, opts models.GetGroupsOptions) ([]models.Group, error)
+	InsertMember(ctx context.Context, member models.GroupMember) error
+	DeleteMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error
+	GetMembers(ctx context.Context, groupID uuid.UUID, opts models.GetGroupMembersOptions) ([]models.GroupMember, error)
+	GetGroupsByUser(ctx context.Context, userID uuid.UUID) ([]models.Group, error)
+}
+
+type GroupService struct {
+	repo           GroupStorage
+	eventPublisher EventPublisher
+}
+
+func NewGroupService(repo GroupStorage, publisher EventPublisher) *GroupService {
+	return &GroupService{
+		repo:           repo,
+		eventPublisher: publisher,
+	}
+}
+
+func (gSvc *GroupService) CreateGroup(ctx context.Context, name string) (uuid.UUID, error) {
+	group := models.Group{
+		ID:        uuid.New(),
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	return gSvc.repo.InsertGroup(ctx, group)
+}
+
+func (gSvc *GroupService) RenameGroup(ctx context.Context, groupID uuid.UUID, name string) error {
+	return gSvc.repo.UpdateGroupName(ctx, groupID, name, time.Now().UTC())
+}
+
+func (gSvc *GroupService) DeleteGroup(ctx context.Context, groupID uuid.UUID) error {
+	return gSvc.repo.DeleteGroup(ctx, groupID)
+}
+
+func (gSvc *GroupService) GetGroup(ctx context.Context, groupID uuid.UUID) (models.Group, error) {
+	return gSvc.repo.GetGroupByID(ctx, groupID)
+}
+
+func (gSvc *GroupService) GetGroups(ctx context.Context, opts models.GetGroupsOptions) ([]models.Group, error) {
+	return gSvc.repo.GetGroups(ctx, opts)
+}
+
+func (gSvc *GroupService) AddMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+	if _, err := gSvc.repo.GetGroupByID(ctx, groupID); err != nil {
+		return err
+	}
+
+	member := models.GroupMember{
+		GroupID:  groupID,
+		UserID:   userID,
+		JoinedAt: time.Now().UTC(),
+	}
+
+	if err := gSvc.repo.InsertMember(ctx, member); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx := context.Background()
+		evt := pbevents.GroupMemberAdded{
+			GroupId: groupID.String(),
+			UserId:  userID.String(),
+			AddedAt: timestamppb.New(member.JoinedAt),
+		}
+		_ = gSvc.eventPublisher.Publish(ctx, "GroupMemberAdded", groupID.String(), &evt)
+	}()
+
+	return nil
+}
+
+func (gSvc *GroupService) RemoveMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+	if err := gSvc.repo.DeleteMember(ctx, groupID, userID); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	go func() {
+		ctx := context.Background()
+		evt := pbevents.GroupMemberRemoved{
+			GroupId:   groupID.String(),
+			UserId:    userID.String(),
+			RemovedAt: timestamppb.New(now),
+		}
+		_ = gSvc.eventPublisher.Publish(ctx, "GroupMemberRemoved", groupID.String(), &evt)
+	}()
+
+	return nil
+}
+
+func (gSvc *GroupService) GetMembers(ctx context.Context, groupID uuid.UUID, opts models.GetGroupMembersOptions) ([]models.GroupMember, error) {
+	if _, err := gSvc.repo.GetGroupByID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	return gSvc.repo.GetMembers(ctx, groupID, opts)
+}
+
+func (gSvc *GroupService) GetUserGroups(ctx context.Context, userID uuid.UUID) ([]models.Group, error) {
+	return gSvc.repo.GetGroupsByUser(ctx, userID)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestGroupService_AddMember_Success(t *testing.T) {
+	guard := make(chan struct{})
+	groupID := uuid.MustParse("5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11")
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	repoMock := GroupStorageMock{
+		GetGroupByIDFunc: func(ctx context.Context, gID uuid.UUID) (models.Group, error) {
+			return models.Group{ID: gID, Name: "admins", CreatedAt: time.Now()}, nil
+		},
+		InsertMemberFunc: func(ctx context.Context, member models.GroupMember) error {
+			return nil
+		},
+	}
+
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			require.Equal(t, "GroupMemberAdded", topic)
+			require.Equal(t, groupID.String(), key)
+			guard <- struct{}{}
+			return nil
+		},
+	}
+
+	s := NewGroupService(&repoMock, &publisherMock)
+
+	err := s.AddMember(context.TODO(), groupID, userID)
+
+	<-guard
+
+	require.NoError(t, err)
+	require.Len(t, repoMock.InsertMemberCalls(), 1)
+	require.Equal(t, userID, repoMock.InsertMemberCalls()[0].Member.UserID)
+	require.Len(t, publisherMock.PublishCalls(), 1)
+}
+
+func TestGroupService_AddMember_Fail(t *testing.T) {
+	groupID := uuid.MustParse("5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11")
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	tests := []struct {
+		name              string
+		repo              *GroupStorageMock
+		checkFn           func(t *testing.T, err error)
+		insertMemberCalls int
+	}{
+		{
+			name: "ErrGroupNotFound",
+			repo: &GroupStorageMock{
+				GetGroupByIDFunc: func(ctx context.Context, gID uuid.UUID) (models.Group, error) {
+					return models.Group{}, models.ErrGroupNotFound
+				},
+			},
+			checkFn: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, models.ErrGroupNotFound)
+			},
+			insertMemberCalls: 0,
+		},
+		{
+			name: "ErrAlreadyGroupMember",
+			repo: &GroupStorageMock{
+				GetGroupByIDFunc: func(ctx context.Context, gID uuid.UUID) (models.Group, error) {
+					return models.Group{ID: gID}, nil
+				},
+				InsertMemberFunc: func(ctx context.Context, member models.GroupMember) error {
+					return models.ErrAlreadyGroupMember
+				},
+			},
+			checkFn: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, models.ErrAlreadyGroupMember)
+			},
+			insertMemberCalls: 1,
+		},
+		{
+			name: "Internal error",
+			repo: &GroupStorageMock{
+				GetGroupByIDFunc: func(ctx context.Context, gID uuid.UUID) (models.Group, error) {
+					return models.Group{ID: gID}, nil
+				},
+				InsertMemberFunc: func(ctx context.Context, member models.GroupMember) error {
+					return errors.New("internal error")
+				},
+			},
+			checkFn: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+			insertMemberCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisherMock := EventPublisherMock{}
+
+			s := NewGroupService(tt.repo, &publisherMock)
+
+			err := s.AddMember(context.TODO(), groupID, userID)
+			tt.checkFn(t, err)
+			require.Len(t, tt.repo.InsertMemberCalls(), tt.insertMemberCalls)
+			require.Len(t, publisherMock.PublishCalls(), 0)
+		})
+	}
+}
+
+func TestGroupService_RemoveMember(t *testing.T) {
+	groupID := uuid.MustParse("5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11")
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	t.Run("success", func(t *testing.T) {
+		guard := make(chan struct{})
+
+		repoMock := GroupStorageMock{
+			DeleteMemberFunc: func(ctx context.Context, gID uuid.UUID, uID uuid.UUID) error {
+				return nil
+			},
+		}
+
+		publisherMock := EventPublisherMock{
+			PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+				require.Equal(t, "GroupMemberRemoved", topic)
+				guard <- struct{}{}
+				return nil
+			},
+		}
+
+		s := NewGroupService(&repoMock, &publisherMock)
+
+		err := s.RemoveMember(context.TODO(), groupID, userID)
+
+		<-guard
+
+		require.NoError(t, err)
+		require.Len(t, repoMock.DeleteMemberCalls(), 1)
+		require.Len(t, publisherMock.PublishCalls(), 1)
+	})
+
+	t.Run("ErrNotGroupMember", func(t *testing.T) {
+		repoMock := GroupStorageMock{
+			DeleteMemberFunc: func(ctx context.Context, gID uuid.UUID, uID uuid.UUID) error {
+				return models.ErrNotGroupMember
+			},
+		}
+
+		publisherMock := EventPublisherMock{}
+
+		s := NewGroupService(&repoMock, &publisherMock)
+
+		err := s.RemoveMember(context.TODO(), groupID, userID)
+		require.ErrorIs(t, err, models.ErrNotGroupMember)
+		require.Len(t, publisherMock.PublishCalls(), 0)
+	})
+}
+
+func TestGroupService_GetMembers_GroupNotFound(t *testing.T) {
+	repoMock := GroupStorageMock{
+		GetGroupByIDFunc: func(ctx context.Context, gID uuid.UUID) (models.Group, error) {
+			return models.Group{}, models.ErrGroupNotFound
+		},
+	}
+
+	s := NewGroupService(&repoMock, &EventPublisherMock{})
+
+	_, err := s.GetMembers(context.TODO(), uuid.New(), models.GetGroupMembersOptions{PageNumber: 1, PageSize: 10})
+	require.ErrorIs(t, err, models.ErrGroupNotFound)
+	require.Len(t, repoMock.GetMembersCalls(), 0)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	// 3rd party
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+//go:generate moq -out idempotency_storage_mock_test.go . IdempotencyStorage
+type IdempotencyStorage interface {
+	ReserveCall(ctx context.Context, call models.IdempotentCall, lockedUntil time.Time, expiresAt time.Time) (models.IdempotentCall, bool, error)
+	CompleteCall(ctx context.Context, call models.IdempotentCall) error
+	ReleaseCall(ctx context.Context, call models.IdempotentCall) error
+	PurgeExpiredCalls(ctx context.Context, expiredBefore time.Time) (int64, error)
+}
+
+// IdempotencyConfig controls how the responses of calls made with an
+// idempotency key are kept.
+type IdempotencyConfig struct {
+	// TTL is how long a response is replayed to retries.
+	TTL time.Duration
+	// LockTimeout is the time after which a retry takes over a call that
+	// never completed, e.g. because the replica handling it went away. It
+	// should exceed the time calls take.
+	LockTimeout time.Duration
+	// PurgeInterval is the time between two purges of the expired calls.
+	PurgeInterval time.Duration
+}
+
+// maxIdempotencyKeyLength caps the length of idempotency keys.
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyService makes the retries of calls carrying the same idempotency
+// key replay the response of the first one.
+type IdempotencyService struct {
+	calls  IdempotencyStorage
+	cfg    IdempotencyConfig
+	logger *zap.SugaredLogger
+}
+
+func NewIdempotencyService(calls IdempotencyStorage, cfg IdempotencyConfig, logger *zap.SugaredLogger) *IdempotencyService {
+	return &IdempotencyService{
+		calls:  calls,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Begin reserves the key of the call, which must then be completed or
+// released. When the key was used by a completed call, that call is returned
+// along with true and the response is to be replayed.
+//
+// The key cannot be reused for another method or request, and not while the
+// call holding it is in progress.
+func (iSvc *IdempotencyService) Begin(ctx context.Context, call models.IdempotentCall) (models.IdempotentCall, bool, error) {
+	if call.Key == "" || len(call.Key) > maxIdempotencyKeyLength {
+		return models.IdempotentCall{}, false, models.ErrInvalidIdempotencyKey
+	}
+
+	now := time.Now().UTC()
+	held, reserved, err := iSvc.calls.ReserveCall(ctx, call, now.Add(iSvc.cfg.LockTimeout), now.Add(iSvc.cfg.TTL))
+	if err != nil || reserved {
+		return models.IdempotentCall{}, false, err
+	}
+
+	if held.Method != call.Method || !bytes.Equal(held.RequestHash, call.RequestHash) {
+		return models.IdempotentCall{}, false, models.ErrIdempotencyKeyReused
+	}
+	if !held.Completed() {
+		return models.IdempotentCall{}, false, models.ErrIdempotencyKeyInUse
+	}
+
+	return held, true, nil
+}
+
+// Complete records the response of the call, replayed to its retries.
+func (iSvc *IdempotencyService) Complete(ctx context.Context, call models.IdempotentCall) error {
+	return iSvc.calls.CompleteCall(ctx, call)
+}
+
+// Release frees the key of a failed call, so that it can be retried.
+func (iSvc *IdempotencyService) Release(ctx context.Context, call models.IdempotentCall) error {
+	return iSvc.calls.ReleaseCall(ctx, call)
+}
+
+// Run removes the expired calls every PurgeInterval, until ctx is cancelled.
+func (iSvc *IdempotencyService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(iSvc.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			n, err := iSvc.calls.PurgeExpiredCalls(ctx, time.Now().UTC())
+			if err != nil {
+				iSvc.logger.Errorw("purge idempotency keys", "error", err)
+				continue
+			}
+			if n > 0 {
+				iSvc.logger.Infow("purge idempotency keys", "purged", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	cfg := IdempotencyConfig{TTL: 24 * time.Hour, LockTimeout: time.Minute}
+
+	call := models.IdempotentCall{
+		Caller:      "b3ce8fed-d5e8-4583-8783-b95969b5bc0c",
+		Key:         "key-1",
+		Method:      "/services.user.User/CreateUser",
+		RequestHash: []byte("hash"),
+	}
+
+	holding := func(held models.IdempotentCall) *IdempotencyStorageMock {
+		return &IdempotencyStorageMock{
+			ReserveCallFunc: func(ctx context.Context, call models.IdempotentCall, lockedUntil time.Time, expiresAt time.Time) (models.IdempotentCall, bool, error) {
+				return held, false, nil
+			},
+		}
+	}
+
+	t.Log("first call")
+	{
+		storage := &IdempotencyStorageMock{
+			ReserveCallFunc: func(ctx context.Context, call models.IdempotentCall, lockedUntil time.Time, expiresAt time.Time) (models.IdempotentCall, bool, error) {
+				return models.IdempotentCall{}, true, nil
+			},
+		}
+		s := NewIdempotencyService(storage, cfg, zap.NewNop().Sugar())
+
+		_, replay, err := s.Begin(context.TODO(), call)
+		require.NoError(t, err)
+		require.False(t, replay)
+
+		reserve := storage.ReserveCallCalls()[0]
+		require.Equal(t, call, reserve.Call)
+		require.WithinDuration(t, time.Now().Add(time.Minute), reserve.LockedUntil, time.Second)
+		require.WithinDuration(t, time.Now().Add(24*time.Hour), reserve.ExpiresAt, time.Second)
+	}
+
+	t.Log("retry of a completed call")
+	{
+		completed := call
+		completed.ResponseType = "services.user.CreateUserResponse"
+		completed.Response = []byte("response")
+		s := NewIdempotencyService(holding(completed), cfg, zap.NewNop().Sugar())
+
+		held, replay, err := s.Begin(context.TODO(), call)
+		require.NoError(t, err)
+		require.True(t, replay)
+		require.Equal(t, completed, held)
+	}
+
+	t.Log("retry of a call in progress")
+	{
+		s := NewIdempotencyService(holding(call), cfg, zap.NewNop().Sugar())
+
+		_, _, err := s.Begin(context.TODO(), call)
+		require.ErrorIs(t, err, models.ErrIdempotencyKeyInUse)
+	}
+
+	t.Log("key reused for another request")
+	{
+		other := call
+		other.RequestHash = []byte("other")
+		other.ResponseType = "services.user.CreateUserResponse"
+		s := NewIdempotencyService(holding(other), cfg, zap.NewNop().Sugar())
+
+		_, _, err := s.Begin(context.TODO(), call)
+		require.ErrorIs(t, err, models.ErrIdempotencyKeyReused)
+	}
+
+	t.Log("invalid key")
+	{
+		storage := &IdempotencyStorageMock{}
+		s := NewIdempotencyService(storage, cfg, zap.NewNop().Sugar())
+
+		long := call
+		long.Key = strings.Repeat("k", 256)
+		_, _, err := s.Begin(context.TODO(), long)
+		require.ErrorIs(t, err, models.ErrInvalidIdempotencyKey)
+		require.Empty(t, storage.ReserveCallCalls())
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+//go:generate moq -out identity_storage_mock_test.go . IdentityStorage
+type IdentityStorage interface {
+	InsertIdentity(ctx context.Context, identity models.ExternalIdentity) error
+	DeleteIdentity(ctx context.Context, userID uuid.UUID, provider string, subject string) error
+	GetUserIDByIdentity(ctx context.Context, provider string, subject string) (uuid.UUID, error)
+	GetIdentities(ctx context.Context, userID uuid.UUID) ([]models.ExternalIdentity, error)
+}
+
+var providerPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+const maxSubjectLength = 255
+
+// IdentityService links users to their accounts at external identity
+// providers and provisions users signing in through them for the first time.
+type IdentityService struct {
+	repo  IdentityStorage
+	users *UserService
+}
+
+func NewIdentityService(repo IdentityStorage, users *UserService) *IdentityService {
+	return &IdentityService{
+		repo:  repo,
+		users: users,
+	}
+}
+
+func (iSvc *IdentityService) LinkIdentity(ctx context.Context, userID uuid.UUID, provider string, subject string, claims map[string]any) error {
+	provider, subject, err := normalizeIdentity(provider, subject)
+	if err != nil {
+		return err
+	}
+
+	return iSvc.repo.InsertIdentity(ctx, models.ExternalIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  subject,
+		Claims:   claims,
+		LinkedAt: time.Now().UTC(),
+	})
+}
+
+func (iSvc *IdentityService) UnlinkIdentity(ctx context.Context, userID uuid.UUID, provider string, subject string) error {
+	provider, subject, err := normalizeIdentity(provider, subject)
+	if err != nil {
+		return err
+	}
+
+	return iSvc.repo.DeleteIdentity(ctx, userID, provider, subject)
+}
+
+func (iSvc *IdentityService) ListIdentities(ctx context.Context, userID uuid.UUID) ([]models.ExternalIdentity, error) {
+	exists, err := iSvc.users.repo.ExistsByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, models.ErrUserNotFound
+	}
+
+	return iSvc.repo.GetIdentities(ctx, userID)
+}
+
+// FindUserByExternalIdentity returns the user linked to the external account.
+// When none is and the lookup carries a profile to provision, a user without
+// password is created from it and linked to the account; provisioned reports
+// whether that happened.
+//
+// Users are never linked to an existing account by email, as the provider may
+// not have verified the address: provisioning fails with ErrEmailTaken instead.
+func (iSvc *IdentityService) FindUserByExternalIdentity(ctx context.Context, lookup models.ExternalIdentityLookup) (userID uuid.UUID, provisioned bool, err error) {
+	provider, subject, err := normalizeIdentity(lookup.Provider, lookup.Subject)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+
+	userID, err = iSvc.repo.GetUserIDByIdentity(ctx, provider, subject)
+	if !errors.Is(err, models.ErrIdentityNotFound) || lookup.Provision == nil {
+		return userID, false, err
+	}
+
+	nu := *lookup.Provision
+	nu.Type = models.UserTypeHuman
+	nu.Password = ""
+	nu.Passwordless = true
+
+	userID, err = iSvc.users.CreateUser(ctx, nu)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+
+	err = iSvc.repo.InsertIdentity(ctx, models.ExternalIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  subject,
+		Claims:   lookup.Claims,
+		LinkedAt: time.Now().UTC(),
+	})
+	if err == nil {
+		return userID, true, nil
+	}
+
+	// A concurrent lookup provisioned the account first: drop our user and
+	// return theirs.
+	if eraseErr := iSvc.users.EraseUser(ctx, userID); eraseErr != nil {
+		return uuid.Nil, false, fmt.Errorf("erasing provisioned user after %v: %w", err, eraseErr)
+	}
+
+	if !errors.Is(err, models.ErrIdentityLinked) {
+		return uuid.Nil, false, err
+	}
+
+	userID, err = iSvc.repo.GetUserIDByIdentity(ctx, provider, subject)
+	return userID, false, err
+}
+
+func normalizeIdentity(provider string, subject string) (string, string, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if !providerPattern.MatchString(provider) {
+		return "", "", fmt.Errorf("%w: invalid provider %q", models.ErrInvalidIdentity, provider)
+	}
+
+	if subject == "" || len(subject) > maxSubjectLength {
+		return "", "", fmt.Errorf("%w: subject must have 1 to %d characters", models.ErrInvalidIdentity, maxSubjectLength)
+	}
+
+	return provider, subject, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestNormalizeIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		subject  string
+		want     string
+		wantErr  bool
+	}{
+		{name: "valid", provider: " Google ", subject: "1234", want: "google"},
+		{name: "dotted", provider: "login.example-corp", subject: "1234", want: "login.example-corp"},
+		{name: "empty provider", provider: "", subject: "1234", wantErr: true},
+		{name: "invalid provider", provider: "git hub", subject: "1234", wantErr: true},
+		{name: "empty subject", provider: "github", subject: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, subject, err := normalizeIdentity(tt.provider, tt.subject)
+			if tt.wantErr {
+				require.ErrorIs(t, err, models.ErrInvalidIdentity)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, provider)
+			require.Equal(t, tt.subject, subject)
+		})
+	}
+}
+
+func TestIdentityService_FindUserByExternalIdentity(t *testing.T) {
+	linkedID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+	provision := &models.NewUser{Email: "bruce@wayne.com", FirstName: "bruce", Password: "ignored"}
+
+	tests := []struct {
+		name            string
+		provision       *models.NewUser
+		linked          bool
+		insertErr       error
+		wantProvisioned bool
+		wantErr         error
+		wantInserts     int
+		wantErases      int
+	}{
+		{name: "linked", linked: true},
+		{name: "not linked", wantErr: models.ErrIdentityNotFound},
+		{name: "provisioned", provision: provision, wantProvisioned: true, wantInserts: 1},
+		{name: "provisioned concurrently", provision: provision, insertErr: models.ErrIdentityLinked, wantInserts: 1, wantErases: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookups := 0
+			repoMock := IdentityStorageMock{
+				GetUserIDByIdentityFunc: func(ctx context.Context, provider string, subject string) (uuid.UUID, error) {
+					lookups++
+					if tt.linked || lookups > 1 {
+						return linkedID, nil
+					}
+					return uuid.Nil, models.ErrIdentityNotFound
+				},
+				InsertIdentityFunc: func(ctx context.Context, identity models.ExternalIdentity) error {
+					return tt.insertErr
+				},
+			}
+			usersMock := UserStorageMock{
+				InsertUserFunc: func(ctx context.Context, user models.User) (uuid.UUID, error) {
+					require.Nil(t, user.Password)
+					require.Equal(t, models.UserTypeHuman, user.Type)
+					return user.ID, nil
+				},
+				EraseUserFunc: func(ctx context.Context, userID uuid.UUID) error {
+					return nil
+				},
+			}
+			publisherMock := EventPublisherMock{
+				PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+					return nil
+				},
+				PublishTombstoneFunc: func(ctx context.Context, topic string, key string) error {
+					return nil
+				},
+			}
+
+			s := NewIdentityService(&repoMock, NewUserService(&usersMock, nil, &publisherMock))
+
+			userID, provisioned, err := s.FindUserByExternalIdentity(context.TODO(), models.ExternalIdentityLookup{
+				Provider:  "GitHub",
+				Subject:   "583231",
+				Provision: tt.provision,
+			})
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantProvisioned, provisioned)
+			require.Len(t, repoMock.InsertIdentityCalls(), tt.wantInserts)
+			require.Len(t, usersMock.EraseUserCalls(), tt.wantErases)
+
+			switch {
+			case tt.wantErr != nil:
+				require.Equal(t, uuid.Nil, userID)
+			case tt.wantProvisioned:
+				inserted := repoMock.InsertIdentityCalls()[0].Identity
+				require.Equal(t, usersMock.InsertUserCalls()[0].User.ID, userID)
+				require.Equal(t, userID, inserted.UserID)
+				require.Equal(t, "github", inserted.Provider)
+			default:
+				require.Equal(t, linkedID, userID)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+const (
+	// importBatchSize is the number of rows validated and written together.
+	importBatchSize = 500
+	// maxImportRows is the largest number of rows of an import, whose report
+	// is held in memory.
+	maxImportRows = 100000
+)
+
+// importWorkers is the number of passwords hashed concurrently. Hashing is
+// CPU bound, so more workers than CPUs would not help.
+var importWorkers = runtime.NumCPU()
+
+// ImportUsers creates the users read from next, until it returns io.EOF, and
+// returns the outcome of every row. Rows are processed in batches of
+// importBatchSize: their passwords are hashed by importWorkers workers and new
+// users are inserted with a single statement per table.
+//
+// Rows that are invalid, repeat the email of a previous row or hold a taken
+// email fail on their own; with opts.Upsert, rows holding the primary email of
+// a user update it instead. Any other error stops the import, leaving the
+// batches before it written.
+func (uSvc *UserService) ImportUsers(ctx context.Context, opts models.ImportUsersOptions, next func() (models.NewUser, error)) ([]models.ImportResult, error) {
+	imp := &importer{
+		svc:  uSvc,
+		opts: opts,
+		seen: make(map[string]int),
+	}
+
+	batch := make([]models.NewUser, 0, importBatchSize)
+	for {
+		nu, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(imp.results)+len(batch) == maxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", models.ErrImportTooLarge, maxImportRows)
+		}
+
+		batch = append(batch, nu)
+		if len(batch) == importBatchSize {
+			if err := imp.importBatch(ctx, batch); err != nil {
+				return nil, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if err := imp.importBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	return imp.results, nil
+}
+
+// importer holds the state of an import across batches.
+type importer struct {
+	svc  *UserService
+	opts models.ImportUsersOptions
+	// defs are the attribute definitions, loaded by the first row with attributes.
+	defs map[string]models.AttributeDefinition
+	// seen maps the emails of the rows imported so far to their row.
+	seen    map[string]int
+	results []models.ImportResult
+}
+
+func (imp *importer) importBatch(ctx context.Context, rows []models.NewUser) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	offset := len(imp.results)
+	results := make([]models.ImportResult, len(rows))
+	users := make([]models.User, len(rows))
+	emails := make([]string, 0, len(rows))
+
+	for i, nu := range rows {
+		results[i].Row = offset + i + 1
+
+		user, err := imp.prepare(ctx, nu)
+		if err != nil {
+			if !isRowError(err) {
+				return err
+			}
+			failRow(&results[i], err)
+			continue
+		}
+
+		if prev, ok := imp.seen[user.Email]; ok {
+			failRow(&results[i], fmt.Errorf("%w: email of row %d", models.ErrDuplicateImportRow, prev))
+			continue
+		}
+		imp.seen[user.Email] = results[i].Row
+
+		users[i] = user
+		emails = append(emails, user.Email)
+	}
+
+	owners, err := imp.svc.repo.GetEmailOwners(ctx, emails)
+	if err != nil {
+		return err
+	}
+
+	for i := range rows {
+		if results[i].Action == models.ImportActionFailed {
+			continue
+		}
+
+		owner, taken := owners[users[i].Email]
+		switch {
+		case !taken:
+			results[i].Action = models.ImportActionCreated
+			results[i].UserID = users[i].ID
+		case imp.opts.Upsert && owner.Primary:
+			results[i].Action = models.ImportActionUpdated
+			results[i].UserID = owner.UserID
+		default:
+			failRow(&results[i], models.ErrEmailTaken)
+		}
+	}
+
+	if imp.opts.DryRun {
+		for i := range results {
+			if results[i].Action == models.ImportActionCreated {
+				results[i].UserID = uuid.Nil
+			}
+		}
+		imp.results = append(imp.results, results...)
+		return nil
+	}
+
+	hashes, err := hashImportPasswords(ctx, rows, results)
+	if err != nil {
+		return err
+	}
+
+	var created []int
+	for i := range rows {
+		switch results[i].Action {
+		case models.ImportActionCreated:
+			users[i].Password = hashes[i]
+			created = append(created, i)
+		case models.ImportActionUpdated:
+			if err := imp.update(ctx, rows[i], hashes[i], &results[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := imp.create(ctx, users, created, results); err != nil {
+		return err
+	}
+
+	imp.results = append(imp.results, results...)
+	return nil
+}
+
+// prepare validates the row and returns the user it creates, without its
+// password hash.
+func (imp *importer) prepare(ctx context.Context, nu models.NewUser) (models.User, error) {
+	email, err := normalizeEmail(nu.Email)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	userType := nu.Type
+	switch userType {
+	case "":
+		userType = models.UserTypeHuman
+	case models.UserTypeHuman, models.UserTypeServiceAccount:
+	default:
+		return models.User{}, fmt.Errorf("%w: %q", models.ErrInvalidUserType, userType)
+	}
+
+	if len(nu.Attributes) > 0 && imp.defs == nil {
+		if imp.defs, err = attributeDefinitions(ctx, imp.svc.attributes); err != nil {
+			return models.User{}, err
+		}
+	}
+
+	attributes, err := applyAttributes(imp.defs, nil, nu.Attributes)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return models.User{
+		ID:         uuid.New(),
+		Type:       userType,
+		Status:     models.UserStatusActive,
+		Email:      email,
+		FirstName:  nu.FirstName,
+		LastName:   nu.LastName,
+		Nickname:   nu.Nickname,
+		Country:    nu.Country,
+		Attributes: attributes,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// create inserts the users of the created rows in one go. Should an email have
+// been taken in the meantime, they are inserted one by one to find out which.
+func (imp *importer) create(ctx context.Context, users []models.User, created []int, results []models.ImportResult) error {
+	batch := make([]models.User, len(created))
+	for j, i := range created {
+		batch[j] = users[i]
+	}
+
+	err := imp.svc.repo.InsertUsers(ctx, batch)
+	if err == nil {
+		for _, u := range batch {
+			imp.svc.publishUserCreated(u)
+		}
+		return nil
+	}
+	if !errors.Is(err, models.ErrEmailTaken) {
+		return err
+	}
+
+	for _, i := range created {
+		if _, err := imp.svc.repo.InsertUser(ctx, users[i]); err != nil {
+			if !errors.Is(err, models.ErrEmailTaken) {
+				return err
+			}
+			failRow(&results[i], err)
+			continue
+		}
+		imp.svc.publishUserCreated(users[i])
+	}
+
+	return nil
+}
+
+// update applies the row to the user holding its email.
+func (imp *importer) update(ctx context.Context, nu models.NewUser, hash []byte, result *models.ImportResult) error {
+	uu := models.UpdateUser{
+		FirstName:  nu.FirstName,
+		LastName:   nu.LastName,
+		Nickname:   nu.Nickname,
+		Country:    nu.Country,
+		Attributes: nu.Attributes,
+	}
+
+	user, err := imp.svc.updateUser(ctx, result.UserID, uu, hash, imp.defs)
+	if err != nil {
+		if isRowError(err) {
+			failRow(result, err)
+			return nil
+		}
+		return err
+	}
+
+	imp.svc.publishUserUpdated(user)
+	return nil
+}
+
+// hashImportPasswords hashes the passwords of the rows to write, in parallel.
+// Rows creating users hash them as CreateUser does; rows updating users only
+// when they have one.
+func hashImportPasswords(ctx context.Context, rows []models.NewUser, results []models.ImportResult) ([][]byte, error) {
+	hashes := make([][]byte, len(rows))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(importWorkers)
+
+	for i, nu := range rows {
+		i, nu := i, nu
+
+		var hash bool
+		switch results[i].Action {
+		case models.ImportActionCreated:
+			hash = (nu.Type != models.UserTypeServiceAccount && !nu.Passwordless) || nu.Password != ""
+		case models.ImportActionUpdated:
+			hash = nu.Password != ""
+		}
+		if !hash {
+			continue
+		}
+
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+
+			var err error
+			hashes[i], err = bcryptPassword(nu.Password)
+			return err
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// failRow marks the row as failed with the error.
+func failRow(result *models.ImportResult, err error) {
+	result.Action = models.ImportActionFailed
+	result.UserID = uuid.Nil
+	result.Err = err
+}
+
+// isRowError reports whether the error is about the row itself, and so only
+// fails that row.
+func isRowError(err error) bool {
+	for _, rowErr := range []error{
+		models.ErrInvalidEmail,
+		models.ErrInvalidUserType,
+		models.ErrInvalidAttribute,
+		models.ErrEmailTaken,
+		models.ErrUserNotFound,
+	} {
+		if errors.Is(err, rowErr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestUserService_ImportUsers(t *testing.T) {
+	existingID := uuid.New()
+	secondaryID := uuid.New()
+
+	rows := []models.NewUser{
+		{Email: "antonis+1@mail.com", FirstName: "Antonis", Password: "pass"},
+		{Email: "not-an-email"},
+		{Email: "antonis+1@mail.com", Password: "pass"},
+		{Email: "existing@mail.com", Nickname: "existing"},
+		{Email: "secondary@mail.com", Password: "pass"},
+		{Email: "bot@mail.com", Type: models.UserTypeServiceAccount},
+		{Email: "robot@mail.com", Type: "robot"},
+	}
+
+	newRepo := func() *UserStorageMock {
+		return &UserStorageMock{
+			GetEmailOwnersFunc: func(ctx context.Context, emails []string) (map[string]models.EmailOwner, error) {
+				require.Equal(t, []string{"antonis+1@mail.com", "existing@mail.com", "secondary@mail.com", "bot@mail.com"}, emails)
+				return map[string]models.EmailOwner{
+					"existing@mail.com":  {UserID: existingID, Primary: true},
+					"secondary@mail.com": {UserID: secondaryID},
+				}, nil
+			},
+			InsertUsersFunc: func(ctx context.Context, users []models.User) error {
+				return nil
+			},
+			GetUserByIDFunc: func(ctx context.Context, userID uuid.UUID) (models.User, error) {
+				return models.User{ID: userID, Email: "existing@mail.com"}, nil
+			},
+			UpdateUserFunc: func(ctx context.Context, userID uuid.UUID, user models.User) error {
+				return nil
+			},
+		}
+	}
+
+	next := func() func() (models.NewUser, error) {
+		i := 0
+		return func() (models.NewUser, error) {
+			if i == len(rows) {
+				return models.NewUser{}, io.EOF
+			}
+			i++
+			return rows[i-1], nil
+		}
+	}
+
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			return nil
+		},
+	}
+
+	t.Log("upsert")
+	{
+		repoMock := newRepo()
+		s := NewUserService(repoMock, nil, &publisherMock)
+
+		results, err := s.ImportUsers(context.TODO(), models.ImportUsersOptions{Upsert: true}, next())
+		require.NoError(t, err)
+		require.Len(t, results, len(rows))
+
+		for i, r := range results {
+			require.Equal(t, i+1, r.Row)
+		}
+
+		require.Equal(t, models.ImportActionCreated, results[0].Action)
+		require.NotEqual(t, uuid.Nil, results[0].UserID)
+		require.ErrorIs(t, results[1].Err, models.ErrInvalidEmail)
+		require.ErrorIs(t, results[2].Err, models.ErrDuplicateImportRow)
+		require.Equal(t, models.ImportActionUpdated, results[3].Action)
+		require.Equal(t, existingID, results[3].UserID)
+		require.ErrorIs(t, results[4].Err, models.ErrEmailTaken)
+		require.Equal(t, models.ImportActionCreated, results[5].Action)
+		require.ErrorIs(t, results[6].Err, models.ErrInvalidUserType)
+		for _, i := range []int{1, 2, 4, 6} {
+			require.Equal(t, models.ImportActionFailed, results[i].Action)
+			require.Equal(t, uuid.Nil, results[i].UserID)
+		}
+
+		require.Len(t, repoMock.InsertUsersCalls(), 1)
+		inserted := repoMock.InsertUsersCalls()[0].Users
+		require.Len(t, inserted, 2)
+		require.Equal(t, results[0].UserID, inserted[0].ID)
+		require.NotEmpty(t, inserted[0].Password)
+		require.Equal(t, models.UserTypeServiceAccount, inserted[1].Type)
+		require.Empty(t, inserted[1].Password)
+
+		require.Len(t, repoMock.UpdateUserCalls(), 1)
+		require.Equal(t, "existing", repoMock.UpdateUserCalls()[0].User.Nickname)
+		require.Empty(t, repoMock.UpdateUserCalls()[0].User.Password)
+	}
+
+	t.Log("without upsert, existing emails fail")
+	{
+		repoMock := newRepo()
+		s := NewUserService(repoMock, nil, &publisherMock)
+
+		results, err := s.ImportUsers(context.TODO(), models.ImportUsersOptions{}, next())
+		require.NoError(t, err)
+		require.ErrorIs(t, results[3].Err, models.ErrEmailTaken)
+		require.Len(t, repoMock.UpdateUserCalls(), 0)
+	}
+
+	t.Log("dry run")
+	{
+		repoMock := newRepo()
+		s := NewUserService(repoMock, nil, &publisherMock)
+
+		results, err := s.ImportUsers(context.TODO(), models.ImportUsersOptions{DryRun: true, Upsert: true}, next())
+		require.NoError(t, err)
+		require.Equal(t, models.ImportActionCreated, results[0].Action)
+		require.Equal(t, uuid.Nil, results[0].UserID)
+		require.Equal(t, models.ImportActionUpdated, results[3].Action)
+		require.Equal(t, existingID, results[3].UserID)
+		require.Len(t, repoMock.InsertUsersCalls(), 0)
+		require.Len(t, repoMock.UpdateUserCalls(), 0)
+	}
+
+	t.Log("email taken while importing")
+	{
+		repoMock := newRepo()
+		repoMock.InsertUsersFunc = func(ctx context.Context, users []models.User) error {
+			return models.ErrEmailTaken
+		}
+		repoMock.InsertUserFunc = func(ctx context.Context, user models.User) (uuid.UUID, error) {
+			if user.Email == "bot@mail.com" {
+				return uuid.Nil, models.ErrEmailTaken
+			}
+			return user.ID, nil
+		}
+		s := NewUserService(repoMock, nil, &publisherMock)
+
+		results, err := s.ImportUsers(context.TODO(), models.ImportUsersOptions{}, next())
+		require.NoError(t, err)
+		require.Equal(t, models.ImportActionCreated, results[0].Action)
+		require.ErrorIs(t, results[5].Err, models.ErrEmailTaken)
+		require.Len(t, repoMock.InsertUserCalls(), 2)
+	}
+}
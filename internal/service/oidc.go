@@ -0,0 +1,439 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/oidc"
+)
+
+const (
+	oauthSecretBytes   = 32
+	responseTypeCode   = "code"
+	grantTypeAuthCode  = "authorization_code"
+	maxRedirectURIs    = 10
+	maxClientNameBytes = 255
+)
+
+//go:generate moq -out oauth_storage_mock_test.go . OAuthStorage
+type OAuthStorage interface {
+	InsertClient(ctx context.Context, client models.OAuthClient) error
+	GetClient(ctx context.Context, clientID uuid.UUID) (models.OAuthClient, error)
+	GetClients(ctx context.Context) ([]models.OAuthClient, error)
+	DeleteClient(ctx context.Context, clientID uuid.UUID) error
+	InsertAuthorizationCode(ctx context.Context, code models.AuthorizationCode) error
+	ConsumeAuthorizationCode(ctx context.Context, hash []byte) (models.AuthorizationCode, error)
+}
+
+type OIDCConfig struct {
+	// Issuer is the URL the provider is reached at, without trailing slash.
+	Issuer   string
+	CodeTTL  time.Duration
+	TokenTTL time.Duration
+}
+
+// OIDCService implements the authorization code flow of an OpenID Connect
+// provider, signing users in with their email and password.
+type OIDCService struct {
+	repo   OAuthStorage
+	users  UserStorage
+	signer *oidc.Signer
+	cfg    OIDCConfig
+}
+
+func NewOIDCService(repo OAuthStorage, users UserStorage, signer *oidc.Signer, cfg OIDCConfig) *OIDCService {
+	return &OIDCService{
+		repo:   repo,
+		users:  users,
+		signer: signer,
+		cfg:    cfg,
+	}
+}
+
+// RegisterClient registers a client. The returned plaintext secret, empty for
+// public clients, is not stored anywhere and cannot be recovered later.
+func (oSvc *OIDCService) RegisterClient(ctx context.Context, nc models.NewOAuthClient) (models.OAuthClient, string, error) {
+	if len(nc.Name) > maxClientNameBytes {
+		return models.OAuthClient{}, "", fmt.Errorf("%w: name is longer than %d bytes", models.ErrInvalidOAuthClient, maxClientNameBytes)
+	}
+
+	if len(nc.RedirectURIs) == 0 || len(nc.RedirectURIs) > maxRedirectURIs {
+		return models.OAuthClient{}, "", fmt.Errorf("%w: a client has 1 to %d redirect uris", models.ErrInvalidOAuthClient, maxRedirectURIs)
+	}
+
+	for _, uri := range nc.RedirectURIs {
+		if err := validateRedirectURI(uri); err != nil {
+			return models.OAuthClient{}, "", err
+		}
+	}
+
+	client := models.OAuthClient{
+		ID:           uuid.New(),
+		Name:         nc.Name,
+		RedirectURIs: nc.RedirectURIs,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	var secret string
+	if !nc.Public {
+		var err error
+		if secret, err = randomToken(); err != nil {
+			return models.OAuthClient{}, "", fmt.Errorf("generating client secret: %w", err)
+		}
+		client.SecretHash = hashToken(secret)
+	}
+
+	if err := oSvc.repo.InsertClient(ctx, client); err != nil {
+		return models.OAuthClient{}, "", err
+	}
+
+	return client, secret, nil
+}
+
+func (oSvc *OIDCService) ListClients(ctx context.Context) ([]models.OAuthClient, error) {
+	return oSvc.repo.GetClients(ctx)
+}
+
+func (oSvc *OIDCService) DeleteClient(ctx context.Context, clientID uuid.UUID) error {
+	return oSvc.repo.DeleteClient(ctx, clientID)
+}
+
+// ValidateAuthorizationRequest checks the request against the registered
+// client. models.ErrOAuthClientNotFound and models.ErrInvalidRedirectURI mean
+// the user must not be redirected back to the client; other errors are
+// reported to the client through its redirect uri.
+func (oSvc *OIDCService) ValidateAuthorizationRequest(ctx context.Context, req models.AuthorizationRequest) (models.OAuthClient, error) {
+	clientID, err := uuid.Parse(req.ClientID)
+	if err != nil {
+		return models.OAuthClient{}, models.ErrOAuthClientNotFound
+	}
+
+	client, err := oSvc.repo.GetClient(ctx, clientID)
+	if err != nil {
+		return models.OAuthClient{}, err
+	}
+
+	if !containsString(client.RedirectURIs, req.RedirectURI) {
+		return models.OAuthClient{}, models.ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != responseTypeCode {
+		return client, models.ErrUnsupportedResponseType
+	}
+
+	if !containsString(req.Scopes, models.OIDCScopeOpenID) {
+		return client, fmt.Errorf("%w: %s is required", models.ErrInvalidScope, models.OIDCScopeOpenID)
+	}
+
+	for _, s := range req.Scopes {
+		if !containsString(models.OIDCScopes, s) {
+			return client, fmt.Errorf("%w: %s", models.ErrInvalidScope, s)
+		}
+	}
+
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != oidc.CodeChallengeMethod {
+		return client, fmt.Errorf("%w: a %s code challenge is required", models.ErrInvalidAuthRequest, oidc.CodeChallengeMethod)
+	}
+
+	return client, nil
+}
+
+// Authorize signs the user in and grants the client an authorization code,
+// once the user consented to the request.
+func (oSvc *OIDCService) Authorize(ctx context.Context, req models.AuthorizationRequest, email string, password string) (string, error) {
+	client, err := oSvc.ValidateAuthorizationRequest(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := oSvc.authenticate(ctx, email, password)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := randomToken()
+	if err != nil {
+		return "", fmt.Errorf("generating authorization code: %w", err)
+	}
+
+	now := time.Now().UTC()
+	err = oSvc.repo.InsertAuthorizationCode(ctx, models.AuthorizationCode{
+		Hash:          hashToken(code),
+		ClientID:      client.ID,
+		UserID:        user.ID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        req.Scopes,
+		Nonce:         req.Nonce,
+		CodeChallenge: req.CodeChallenge,
+		AuthTime:      now,
+		ExpiresAt:     now.Add(oSvc.cfg.CodeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// authenticate checks the credentials of an active human user. Every failure
+// is reported as models.ErrInvalidCredentials.
+func (oSvc *OIDCService) authenticate(ctx context.Context, email string, password string) (models.User, error) {
+	opts := models.GetUsersOptions{
+		PageNumber: 1,
+		PageSize:   1,
+	}
+	opts.Filter.Email = strings.TrimSpace(email)
+
+	users, err := oSvc.users.GetUsersByFilter(ctx, opts)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if len(users) == 0 {
+		// Hash anyway, so that unknown emails take as long as wrong passwords.
+		_, _ = bcryptPassword(password)
+		return models.User{}, models.ErrInvalidCredentials
+	}
+
+	user := users[0]
+	if user.Password == nil || bcrypt.CompareHashAndPassword(user.Password, []byte(password)) != nil {
+		return models.User{}, models.ErrInvalidCredentials
+	}
+
+	if user.Type != models.UserTypeHuman || user.Status != models.UserStatusActive {
+		return models.User{}, models.ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+// ExchangeCode redeems an authorization code for an ID token and an access
+// token to the userinfo endpoint.
+func (oSvc *OIDCService) ExchangeCode(ctx context.Context, tr models.TokenRequest) (models.Tokens, error) {
+	if tr.GrantType != grantTypeAuthCode {
+		return models.Tokens{}, models.ErrUnsupportedGrantType
+	}
+
+	client, err := oSvc.authenticateClient(ctx, tr.ClientID, tr.ClientSecret)
+	if err != nil {
+		return models.Tokens{}, err
+	}
+
+	code, err := oSvc.repo.ConsumeAuthorizationCode(ctx, hashToken(tr.Code))
+	if err != nil {
+		return models.Tokens{}, err
+	}
+
+	now := time.Now().UTC()
+	if code.ClientID != client.ID || code.RedirectURI != tr.RedirectURI || !now.Before(code.ExpiresAt) {
+		return models.Tokens{}, models.ErrInvalidGrant
+	}
+
+	if !oidc.VerifyCodeChallenge(tr.CodeVerifier, code.CodeChallenge) {
+		return models.Tokens{}, fmt.Errorf("%w: code verifier does not match", models.ErrInvalidGrant)
+	}
+
+	user, err := oSvc.users.GetUserByID(ctx, code.UserID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return models.Tokens{}, models.ErrInvalidGrant
+		}
+		return models.Tokens{}, err
+	}
+
+	if user.Status != models.UserStatusActive {
+		return models.Tokens{}, models.ErrInvalidGrant
+	}
+
+	expiresAt := now.Add(oSvc.cfg.TokenTTL)
+
+	idClaims := userClaims(user, code.Scopes)
+	idClaims["iss"] = oSvc.cfg.Issuer
+	idClaims["aud"] = client.ID.String()
+	idClaims["iat"] = now.Unix()
+	idClaims["exp"] = expiresAt.Unix()
+	idClaims["auth_time"] = code.AuthTime.Unix()
+	if code.Nonce != "" {
+		idClaims["nonce"] = code.Nonce
+	}
+
+	idToken, err := oSvc.signer.Sign(idClaims)
+	if err != nil {
+		return models.Tokens{}, fmt.Errorf("signing id token: %w", err)
+	}
+
+	accessToken, err := oSvc.signer.Sign(accessTokenClaims{
+		Issuer:    oSvc.cfg.Issuer,
+		Subject:   user.ID.String(),
+		Audience:  oSvc.cfg.Issuer,
+		ClientID:  client.ID.String(),
+		Scope:     strings.Join(code.Scopes, " "),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		ID:        uuid.NewString(),
+	})
+	if err != nil {
+		return models.Tokens{}, fmt.Errorf("signing access token: %w", err)
+	}
+
+	return models.Tokens{
+		AccessToken: accessToken,
+		IDToken:     idToken,
+		ExpiresIn:   oSvc.cfg.TokenTTL,
+		Scopes:      code.Scopes,
+	}, nil
+}
+
+// authenticateClient checks the secret of confidential clients; public
+// clients are identified by their id alone.
+func (oSvc *OIDCService) authenticateClient(ctx context.Context, rawClientID string, secret string) (models.OAuthClient, error) {
+	clientID, err := uuid.Parse(rawClientID)
+	if err != nil {
+		return models.OAuthClient{}, models.ErrInvalidClient
+	}
+
+	client, err := oSvc.repo.GetClient(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, models.ErrOAuthClientNotFound) {
+			return models.OAuthClient{}, models.ErrInvalidClient
+		}
+		return models.OAuthClient{}, err
+	}
+
+	if !client.Public() && subtle.ConstantTimeCompare(client.SecretHash, hashToken(secret)) != 1 {
+		return models.OAuthClient{}, models.ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+// accessTokenClaims follow the JWT profile of access tokens (RFC 9068). Their
+// audience is the issuer, which tells them apart from ID tokens.
+type accessTokenClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud"`
+	ClientID  string `json:"client_id"`
+	Scope     string `json:"scope"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	ID        string `json:"jti"`
+}
+
+// UserInfo returns the claims of the user the access token was issued for,
+// limited to the scopes granted to it.
+func (oSvc *OIDCService) UserInfo(ctx context.Context, accessToken string) (map[string]any, error) {
+	var claims accessTokenClaims
+	if err := oSvc.signer.Verify(accessToken, &claims); err != nil {
+		return nil, models.ErrInvalidAccessToken
+	}
+
+	if claims.Issuer != oSvc.cfg.Issuer || claims.Audience != oSvc.cfg.Issuer || time.Now().Unix() >= claims.ExpiresAt {
+		return nil, models.ErrInvalidAccessToken
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, models.ErrInvalidAccessToken
+	}
+
+	user, err := oSvc.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, models.ErrInvalidAccessToken
+		}
+		return nil, err
+	}
+
+	if user.Status != models.UserStatusActive {
+		return nil, models.ErrInvalidAccessToken
+	}
+
+	return userClaims(user, strings.Fields(claims.Scope)), nil
+}
+
+// JWKS returns the keys verifying the tokens issued by the provider.
+func (oSvc *OIDCService) JWKS() oidc.JSONWebKeySet {
+	return oSvc.signer.JWKS()
+}
+
+// userClaims returns the standard claims of the user released by the scopes.
+func userClaims(user models.User, scopes []string) map[string]any {
+	claims := map[string]any{
+		"sub": user.ID.String(),
+	}
+
+	if containsString(scopes, models.OIDCScopeProfile) {
+		claims["name"] = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		claims["given_name"] = user.FirstName
+		claims["family_name"] = user.LastName
+		claims["nickname"] = user.Nickname
+		updatedAt := user.CreatedAt
+		if user.UpdateAt != nil {
+			updatedAt = *user.UpdateAt
+		}
+		claims["updated_at"] = updatedAt.Unix()
+	}
+
+	if containsString(scopes, models.OIDCScopeEmail) {
+		claims["email"] = user.Email
+	}
+
+	return claims
+}
+
+// validateRedirectURI accepts absolute uris without fragment. Plain http is
+// only accepted for loopback addresses, used by native apps; custom schemes
+// of native apps are accepted as they are.
+func validateRedirectURI(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil || !u.IsAbs() || u.Fragment != "" {
+		return fmt.Errorf("%w: %q", models.ErrInvalidOAuthClient, uri)
+	}
+
+	if u.Scheme == "http" {
+		switch u.Hostname() {
+		case "localhost", "127.0.0.1", "::1":
+		default:
+			return fmt.Errorf("%w: %q must use https", models.ErrInvalidOAuthClient, uri)
+		}
+	}
+
+	return nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, oauthSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/oidc"
+)
+
+const (
+	testIssuer        = "https://id.example.com"
+	testCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func newTestOIDCService(t *testing.T, repo OAuthStorage, users UserStorage) *OIDCService {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return NewOIDCService(repo, users, oidc.NewSigner(key), OIDCConfig{
+		Issuer:   testIssuer,
+		CodeTTL:  time.Minute,
+		TokenTTL: time.Hour,
+	})
+}
+
+func TestValidateRedirectURI(t *testing.T) {
+	tests := []struct {
+		uri     string
+		wantErr bool
+	}{
+		{uri: "https://app.example.com/callback"},
+		{uri: "http://127.0.0.1:8400/callback"},
+		{uri: "http://localhost/callback"},
+		{uri: "com.example.app:/callback"},
+		{uri: "http://app.example.com/callback", wantErr: true},
+		{uri: "https://app.example.com/callback#token", wantErr: true},
+		{uri: "/callback", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			err := validateRedirectURI(tt.uri)
+			if tt.wantErr {
+				require.ErrorIs(t, err, models.ErrInvalidOAuthClient)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOIDCService_ValidateAuthorizationRequest(t *testing.T) {
+	client := models.OAuthClient{ID: uuid.New(), RedirectURIs: []string{"https://app.example.com/callback"}}
+
+	valid := models.AuthorizationRequest{
+		ClientID:            client.ID.String(),
+		RedirectURI:         "https://app.example.com/callback",
+		ResponseType:        "code",
+		Scopes:              []string{"openid", "email"},
+		CodeChallenge:       testCodeChallenge,
+		CodeChallengeMethod: "S256",
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(req *models.AuthorizationRequest)
+		wantErr error
+	}{
+		{name: "valid", modify: func(req *models.AuthorizationRequest) {}},
+		{name: "unknown client", modify: func(req *models.AuthorizationRequest) { req.ClientID = uuid.NewString() }, wantErr: models.ErrOAuthClientNotFound},
+		{name: "malformed client", modify: func(req *models.AuthorizationRequest) { req.ClientID = "app" }, wantErr: models.ErrOAuthClientNotFound},
+		{name: "unregistered redirect uri", modify: func(req *models.AuthorizationRequest) { req.RedirectURI = "https://evil.example.com" }, wantErr: models.ErrInvalidRedirectURI},
+		{name: "implicit flow", modify: func(req *models.AuthorizationRequest) { req.ResponseType = "token" }, wantErr: models.ErrUnsupportedResponseType},
+		{name: "missing openid", modify: func(req *models.AuthorizationRequest) { req.Scopes = []string{"email"} }, wantErr: models.ErrInvalidScope},
+		{name: "unknown scope", modify: func(req *models.AuthorizationRequest) { req.Scopes = []string{"openid", "admin"} }, wantErr: models.ErrInvalidScope},
+		{name: "missing challenge", modify: func(req *models.AuthorizationRequest) { req.CodeChallenge = "" }, wantErr: models.ErrInvalidAuthRequest},
+		{name: "plain challenge", modify: func(req *models.AuthorizationRequest) { req.CodeChallengeMethod = "plain" }, wantErr: models.ErrInvalidAuthRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := OAuthStorageMock{
+				GetClientFunc: func(ctx context.Context, clientID uuid.UUID) (models.OAuthClient, error) {
+					if clientID != client.ID {
+						return models.OAuthClient{}, models.ErrOAuthClientNotFound
+					}
+					return client, nil
+				},
+			}
+
+			s := newTestOIDCService(t, &repoMock, &UserStorageMock{})
+
+			req := valid
+			tt.modify(&req)
+
+			_, err := s.ValidateAuthorizationRequest(context.TODO(), req)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestOIDCService_ExchangeCode(t *testing.T) {
+	user := models.User{
+		ID:        uuid.New(),
+		Type:      models.UserTypeHuman,
+		Status:    models.UserStatusActive,
+		Email:     "bruce@wayne.com",
+		FirstName: "bruce",
+		LastName:  "wayne",
+	}
+	client := models.OAuthClient{ID: uuid.New(), SecretHash: hashToken("s3cret"), RedirectURIs: []string{"https://app.example.com/callback"}}
+
+	valid := models.TokenRequest{
+		GrantType:    "authorization_code",
+		Code:         "code",
+		RedirectURI:  "https://app.example.com/callback",
+		ClientID:     client.ID.String(),
+		ClientSecret: "s3cret",
+		CodeVerifier: testCodeVerifier,
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(req *models.TokenRequest)
+		expiresIn time.Duration
+		wantErr   error
+	}{
+		{name: "valid", modify: func(req *models.TokenRequest) {}},
+		{name: "refresh token grant", modify: func(req *models.TokenRequest) { req.GrantType = "refresh_token" }, wantErr: models.ErrUnsupportedGrantType},
+		{name: "wrong secret", modify: func(req *models.TokenRequest) { req.ClientSecret = "guess" }, wantErr: models.ErrInvalidClient},
+		{name: "unknown code", modify: func(req *models.TokenRequest) { req.Code = "other" }, wantErr: models.ErrInvalidGrant},
+		{name: "other redirect uri", modify: func(req *models.TokenRequest) { req.RedirectURI = "https://app.example.com/other" }, wantErr: models.ErrInvalidGrant},
+		{name: "wrong verifier", modify: func(req *models.TokenRequest) { req.CodeVerifier = testCodeVerifier + "x" }, wantErr: models.ErrInvalidGrant},
+		{name: "expired code", modify: func(req *models.TokenRequest) {}, expiresIn: -time.Second, wantErr: models.ErrInvalidGrant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiresIn := time.Minute
+			if tt.expiresIn != 0 {
+				expiresIn = tt.expiresIn
+			}
+
+			repoMock := OAuthStorageMock{
+				GetClientFunc: func(ctx context.Context, clientID uuid.UUID) (models.OAuthClient, error) {
+					return client, nil
+				},
+				ConsumeAuthorizationCodeFunc: func(ctx context.Context, hash []byte) (models.AuthorizationCode, error) {
+					if string(hash) != string(hashToken("code")) {
+						return models.AuthorizationCode{}, models.ErrInvalidGrant
+					}
+					return models.AuthorizationCode{
+						ClientID:      client.ID,
+						UserID:        user.ID,
+						RedirectURI:   "https://app.example.com/callback",
+						Scopes:        []string{"openid", "email"},
+						Nonce:         "n-0S6_WzA2Mj",
+						CodeChallenge: testCodeChallenge,
+						AuthTime:      time.Now().UTC(),
+						ExpiresAt:     time.Now().UTC().Add(expiresIn),
+					}, nil
+				},
+			}
+			usersMock := UserStorageMock{
+				GetUserByIDFunc: func(ctx context.Context, userID uuid.UUID) (models.User, error) {
+					return user, nil
+				},
+			}
+
+			s := newTestOIDCService(t, &repoMock, &usersMock)
+
+			req := valid
+			tt.modify(&req)
+
+			tokens, err := s.ExchangeCode(context.TODO(), req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var idClaims map[string]any
+			require.NoError(t, oidc.Verify(tokens.IDToken, s.JWKS(), &idClaims))
+			require.Equal(t, testIssuer, idClaims["iss"])
+			require.Equal(t, client.ID.String(), idClaims["aud"])
+			require.Equal(t, user.ID.String(), idClaims["sub"])
+			require.Equal(t, "n-0S6_WzA2Mj", idClaims["nonce"])
+			require.Equal(t, "bruce@wayne.com", idClaims["email"])
+			require.NotContains(t, idClaims, "given_name")
+
+			info, err := s.UserInfo(context.TODO(), tokens.AccessToken)
+			require.NoError(t, err)
+			require.Equal(t, map[string]any{"sub": user.ID.String(), "email": "bruce@wayne.com"}, info)
+
+			_, err = s.UserInfo(context.TODO(), tokens.IDToken)
+			require.ErrorIs(t, err, models.ErrInvalidAccessToken)
+		})
+	}
+}
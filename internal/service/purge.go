@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	// 3rd party
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	pbevents "github.com/TonyPath/user-mng-grpc-service/proto/events/user"
+)
+
+// PurgeConfig controls how soft deleted users are permanently removed.
+type PurgeConfig struct {
+	// Retention is how long a deleted user can still be restored.
+	Retention time.Duration
+	// Interval is the time between two purge runs.
+	Interval time.Duration
+	// BatchSize caps the number of users removed per transaction.
+	BatchSize uint64
+}
+
+// UserPurger periodically hard deletes users whose retention window has expired.
+type UserPurger struct {
+	repo           UserStorage
+	eventPublisher EventPublisher
+	cfg            PurgeConfig
+	logger         *zap.SugaredLogger
+}
+
+func NewUserPurger(repo UserStorage, publisher EventPublisher, cfg PurgeConfig, logger *zap.SugaredLogger) (*UserPurger, error) {
+	if cfg.BatchSize == 0 {
+		return nil, errors.New("purge batch size must be positive")
+	}
+	if cfg.Interval <= 0 {
+		return nil, errors.New("purge interval must be positive")
+	}
+
+	return &UserPurger{
+		repo:           repo,
+		eventPublisher: publisher,
+		cfg:            cfg,
+		logger:         logger,
+	}, nil
+}
+
+// Run purges users every configured interval until ctx is cancelled.
+func (p *UserPurger) Run(ctx context.Context) error {
+	p.logger.Infow("startup", "status", "user purger started", "retention", p.cfg.Retention, "interval", p.cfg.Interval)
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Infow("shutdown", "status", "user purger stopped")
+			return nil
+		case <-ticker.C:
+			n, err := p.Purge(ctx)
+			if err != nil {
+				p.logger.Errorw("purge deleted users", "error", err)
+				continue
+			}
+			if n > 0 {
+				p.logger.Infow("purge deleted users", "purged", n)
+			}
+		}
+	}
+}
+
+// Purge removes, batch by batch, every user deleted before the retention window
+// and publishes a UserPurged event for each of them. It stops between batches
+// when ctx is cancelled.
+func (p *UserPurger) Purge(ctx context.Context) (int, error) {
+	deletedBefore := time.Now().UTC().Add(-p.cfg.Retention)
+
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		ids, err := p.repo.PurgeDeletedUsers(ctx, deletedBefore, p.cfg.BatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		now := time.Now().UTC()
+		for _, id := range ids {
+			evt := pbevents.UserPurged{
+				UserId:   id.String(),
+				PurgedAt: timestamppb.New(now),
+			}
+			// The user is gone already; the event can only be reported.
+			if err := p.eventPublisher.Publish(context.Background(), "UserPurged", id.String(), &evt); err != nil {
+				p.logger.Errorw("publish UserPurged", "user_id", id, "error", err)
+			}
+		}
+
+		total += len(ids)
+		if uint64(len(ids)) < p.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestUserPurger_Purge(t *testing.T) {
+	batches := [][]uuid.UUID{
+		{uuid.New(), uuid.New()},
+		{uuid.New()},
+	}
+
+	repoMock := UserStorageMock{
+		PurgeDeletedUsersFunc: func(ctx context.Context, deletedBefore time.Time, limit uint64) ([]uuid.UUID, error) {
+			require.Equal(t, uint64(2), limit)
+			require.WithinDuration(t, time.Now().Add(-24*time.Hour), deletedBefore, time.Minute)
+
+			batch := batches[0]
+			batches = batches[1:]
+			return batch, nil
+		},
+	}
+
+	var mu sync.Mutex
+	var topics []string
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			mu.Lock()
+			defer mu.Unlock()
+			topics = append(topics, topic)
+			return nil
+		},
+	}
+
+	p, err := NewUserPurger(&repoMock, &publisherMock, PurgeConfig{
+		Retention: 24 * time.Hour,
+		Interval:  time.Hour,
+		BatchSize: 2,
+	}, zap.NewNop().Sugar())
+	require.NoError(t, err)
+
+	n, err := p.Purge(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Len(t, repoMock.PurgeDeletedUsersCalls(), 2)
+	require.Equal(t, []string{"UserPurged", "UserPurged", "UserPurged"}, topics)
+}
+
+func TestUserPurger_Purge_Fail(t *testing.T) {
+	repoMock := UserStorageMock{
+		PurgeDeletedUsersFunc: func(ctx context.Context, deletedBefore time.Time, limit uint64) ([]uuid.UUID, error) {
+			return nil, errors.New("internal error")
+		},
+	}
+
+	publisherMock := EventPublisherMock{}
+
+	p, err := NewUserPurger(&repoMock, &publisherMock, PurgeConfig{
+		Retention: time.Hour,
+		Interval:  time.Hour,
+		BatchSize: 10,
+	}, zap.NewNop().Sugar())
+	require.NoError(t, err)
+
+	n, err := p.Purge(context.TODO())
+	require.Error(t, err)
+	require.Equal(t, 0, n)
+	require.Len(t, publisherMock.PublishCalls(), 0)
+}
+
+func TestUserPurger_Purge_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Every batch is full, so that only the cancellation stops the purge.
+	batches := 0
+	repoMock := UserStorageMock{
+		PurgeDeletedUsersFunc: func(ctx context.Context, deletedBefore time.Time, limit uint64) ([]uuid.UUID, error) {
+			batches++
+			if batches == 2 {
+				cancel()
+			}
+			return []uuid.UUID{uuid.New()}, nil
+		},
+	}
+
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			return errors.New("broker down")
+		},
+	}
+
+	p, err := NewUserPurger(&repoMock, &publisherMock, PurgeConfig{
+		Retention: time.Hour,
+		Interval:  time.Hour,
+		BatchSize: 1,
+	}, zap.NewNop().Sugar())
+	require.NoError(t, err)
+
+	n, err := p.Purge(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 2, n)
+	require.Len(t, publisherMock.PublishCalls(), 2)
+}
+
+func TestNewUserPurger_Fail(t *testing.T) {
+	_, err := NewUserPurger(&UserStorageMock{}, &EventPublisherMock{}, PurgeConfig{
+		Retention: time.Hour,
+		Interval:  time.Hour,
+	}, zap.NewNop().Sugar())
+	require.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/search"
+)
+
+// SearchUsers ranks the users matching the query by email, first name, last
+// name or nickname, tolerating typos and partial words, and returns a page of
+// them along with the number of matches.
+func (uSvc *UserService) SearchUsers(ctx context.Context, opts models.SearchUsersOptions) ([]models.UserMatch, int, error) {
+	opts.Query = strings.TrimSpace(opts.Query)
+	if len(search.Trigrams(opts.Query)) == 0 {
+		return nil, 0, models.ErrInvalidSearchQuery
+	}
+
+	candidates, err := uSvc.repo.SearchUsers(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matches []models.UserMatch
+	for _, u := range candidates {
+		if m, ok := matchUser(opts.Query, u); ok {
+			matches = append(matches, m)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return bytes.Compare(matches[i].User.ID[:], matches[j].User.ID[:]) < 0
+	})
+
+	total := len(matches)
+	if opts.Offset >= uint64(total) {
+		return nil, total, nil
+	}
+
+	end := opts.Offset + opts.Limit
+	if end > uint64(total) {
+		end = uint64(total)
+	}
+
+	return matches[opts.Offset:end], total, nil
+}
+
+// matchUser scores the user by its best matching field and highlights every
+// matching field.
+func matchUser(query string, u models.User) (models.UserMatch, bool) {
+	m := models.UserMatch{
+		User:       u,
+		Highlights: make(map[string]string),
+	}
+
+	for field, value := range map[string]string{
+		"email":      u.Email,
+		"first_name": u.FirstName,
+		"last_name":  u.LastName,
+		"nickname":   u.Nickname,
+	} {
+		score := search.Score(query, value)
+		if score < search.MinScore {
+			continue
+		}
+
+		m.Highlights[field] = search.Highlight(query, value)
+		if score > m.Score {
+			m.Score = score
+		}
+	}
+
+	return m, m.Score > 0
+}
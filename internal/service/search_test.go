@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestUserService_SearchUsers(t *testing.T) {
+	antonis := models.User{ID: uuid.New(), Email: "antonis@mail.com", FirstName: "Antonis", LastName: "Papath", Nickname: "TonyPath"}
+	antonia := models.User{ID: uuid.New(), Email: "antonia@mail.com", FirstName: "Antonia", LastName: "Smith", Nickname: "toni"}
+	maria := models.User{ID: uuid.New(), Email: "maria@mail.com", FirstName: "Maria", LastName: "Jones", Nickname: "mj"}
+
+	repoMock := UserStorageMock{
+		SearchUsersFunc: func(ctx context.Context, opts models.SearchUsersOptions) ([]models.User, error) {
+			require.Equal(t, "antonis", opts.Query)
+			require.Equal(t, models.UserStatusActive, opts.Status)
+			return []models.User{maria, antonia, antonis}, nil
+		},
+	}
+
+	s := NewUserService(&repoMock, nil, nil)
+
+	opts := models.SearchUsersOptions{Query: " antonis ", Status: models.UserStatusActive, Limit: 10}
+	matches, total, err := s.SearchUsers(context.TODO(), opts)
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Len(t, matches, 2)
+
+	require.Equal(t, antonis.ID, matches[0].User.ID)
+	require.Equal(t, antonia.ID, matches[1].User.ID)
+	require.Greater(t, matches[0].Score, matches[1].Score)
+	require.Equal(t, map[string]string{
+		"email":      "<em>antonis</em>@mail.com",
+		"first_name": "<em>Antonis</em>",
+	}, matches[0].Highlights)
+	require.Equal(t, "<em>antonia</em>@mail.com", matches[1].Highlights["email"])
+
+	t.Log("second page")
+	{
+		opts.Offset = 1
+		matches, total, err := s.SearchUsers(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Equal(t, 2, total)
+		require.Len(t, matches, 1)
+		require.Equal(t, antonia.ID, matches[0].User.ID)
+	}
+
+	t.Log("query without words")
+	{
+		_, _, err := s.SearchUsers(context.TODO(), models.SearchUsersOptions{Query: " @. "})
+		require.ErrorIs(t, err, models.ErrInvalidSearchQuery)
+	}
+}
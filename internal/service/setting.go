@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/settings"
+)
+
+//go:generate moq -out settings_storage_mock_test.go . SettingsStorage
+type SettingsStorage interface {
+	UpsertSettings(ctx context.Context, userID uuid.UUID, namespace string, values map[string]json.RawMessage, updatedAt time.Time, maxKeys int) error
+	GetSettings(ctx context.Context, userID uuid.UUID, namespace string, keys []string) ([]models.Setting, error)
+	DeleteSetting(ctx context.Context, userID uuid.UUID, namespace string, key string) error
+}
+
+// SettingsConfig limits the size of the settings of a user.
+type SettingsConfig struct {
+	// MaxValueBytes caps the size of the JSON encoding of a single value.
+	MaxValueBytes int
+	// MaxKeys caps the number of keys a user may hold in a namespace.
+	MaxKeys int
+}
+
+// SettingsService stores per-user settings, validated against the schema
+// registered for their namespace.
+type SettingsService struct {
+	repo     SettingsStorage
+	users    UserStorage
+	registry *settings.Registry
+	cfg      SettingsConfig
+}
+
+func NewSettingsService(repo SettingsStorage, users UserStorage, registry *settings.Registry, cfg SettingsConfig) *SettingsService {
+	return &SettingsService{
+		repo:     repo,
+		users:    users,
+		registry: registry,
+		cfg:      cfg,
+	}
+}
+
+// GetSettings returns the settings of the namespace, ordered by key. Keys the
+// user has not set are returned with their default value, if any. When keys
+// are given only those are returned.
+func (sSvc *SettingsService) GetSettings(ctx context.Context, userID uuid.UUID, namespace string, keys []string) ([]models.Setting, error) {
+	if err := settings.ValidateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if err := settings.ValidateKey(namespace, key); err != nil {
+			return nil, err
+		}
+	}
+
+	exists, err := sSvc.users.ExistsByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, models.ErrUserNotFound
+	}
+
+	stored, err := sSvc.repo.GetSettings(ctx, userID, namespace, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Setting, 0, len(stored))
+	set := make(map[string]bool, len(stored))
+	for _, s := range stored {
+		result = append(result, s)
+		set[s.Key] = true
+	}
+
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+	}
+
+	for key, value := range sSvc.registry.Defaults(namespace) {
+		if set[key] || (len(keys) > 0 && !wanted[key]) {
+			continue
+		}
+
+		result = append(result, models.Setting{
+			UserID:    userID,
+			Namespace: namespace,
+			Key:       key,
+			Value:     value,
+			IsDefault: true,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result, nil
+}
+
+// SetSettings validates and stores the values of the keys of the namespace.
+// Either every value is stored or none is.
+func (sSvc *SettingsService) SetSettings(ctx context.Context, userID uuid.UUID, namespace string, values map[string]json.RawMessage) error {
+	if len(values) == 0 {
+		return fmt.Errorf("%w: no values given", models.ErrInvalidSettingValue)
+	}
+
+	for key, value := range values {
+		if len(value) > sSvc.cfg.MaxValueBytes {
+			return fmt.Errorf("%w: value of %s.%s is %d bytes, at most %d are allowed", models.ErrSettingsLimitExceeded, namespace, key, len(value), sSvc.cfg.MaxValueBytes)
+		}
+
+		if err := sSvc.registry.Validate(namespace, key, value); err != nil {
+			return err
+		}
+	}
+
+	if len(values) > sSvc.cfg.MaxKeys {
+		return fmt.Errorf("%w: %d keys given, at most %d are allowed", models.ErrSettingsLimitExceeded, len(values), sSvc.cfg.MaxKeys)
+	}
+
+	return sSvc.repo.UpsertSettings(ctx, userID, namespace, values, time.Now().UTC(), sSvc.cfg.MaxKeys)
+}
+
+func (sSvc *SettingsService) DeleteSetting(ctx context.Context, userID uuid.UUID, namespace string, key string) error {
+	if err := settings.ValidateKey(namespace, key); err != nil {
+		return err
+	}
+
+	return sSvc.repo.DeleteSetting(ctx, userID, namespace, key)
+}
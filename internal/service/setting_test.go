@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/settings"
+)
+
+func newSettingsRegistry(t *testing.T) *settings.Registry {
+	schema, err := settings.ParseSchema([]byte(`{
+		"properties": {
+			"theme": {"type": "string", "enum": ["light", "dark"], "default": "light"},
+			"sidebar": {"type": "boolean", "default": true}
+		},
+		"additionalProperties": false
+	}`))
+	require.NoError(t, err)
+
+	registry := settings.NewRegistry()
+	require.NoError(t, registry.Register("ui", schema))
+	return registry
+}
+
+func TestSettingsService_GetSettings(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+	updatedAt := time.Now()
+
+	repoMock := &SettingsStorageMock{
+		GetSettingsFunc: func(ctx context.Context, uID uuid.UUID, namespace string, keys []string) ([]models.Setting, error) {
+			return []models.Setting{
+				{UserID: uID, Namespace: namespace, Key: "theme", Value: json.RawMessage(`"dark"`), UpdatedAt: &updatedAt},
+			}, nil
+		},
+	}
+
+	users := &UserStorageMock{
+		ExistsByIDFunc: func(ctx context.Context, uID uuid.UUID) (bool, error) {
+			return uID == userID, nil
+		},
+	}
+
+	s := NewSettingsService(repoMock, users, newSettingsRegistry(t), SettingsConfig{MaxValueBytes: 1024, MaxKeys: 10})
+
+	got, err := s.GetSettings(context.TODO(), userID, "ui", nil)
+	require.NoError(t, err)
+	require.Equal(t, []models.Setting{
+		{UserID: userID, Namespace: "ui", Key: "sidebar", Value: json.RawMessage(`true`), IsDefault: true},
+		{UserID: userID, Namespace: "ui", Key: "theme", Value: json.RawMessage(`"dark"`), UpdatedAt: &updatedAt},
+	}, got)
+
+	got, err = s.GetSettings(context.TODO(), userID, "ui", []string{"theme"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	_, err = s.GetSettings(context.TODO(), uuid.New(), "ui", nil)
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+
+	_, err = s.GetSettings(context.TODO(), userID, "", nil)
+	require.ErrorIs(t, err, models.ErrInvalidSettingKey)
+}
+
+func TestSettingsService_SetSettings(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	tests := []struct {
+		name        string
+		namespace   string
+		values      map[string]json.RawMessage
+		err         error
+		upsertCalls int
+	}{
+		{
+			name:        "valid",
+			namespace:   "ui",
+			values:      map[string]json.RawMessage{"theme": json.RawMessage(`"dark"`), "sidebar": json.RawMessage(`false`)},
+			upsertCalls: 1,
+		},
+		{
+			name:      "schema violation",
+			namespace: "ui",
+			values:    map[string]json.RawMessage{"theme": json.RawMessage(`"blue"`)},
+			err:       models.ErrInvalidSettingValue,
+		},
+		{
+			name:      "unknown key",
+			namespace: "ui",
+			values:    map[string]json.RawMessage{"colour": json.RawMessage(`"red"`)},
+			err:       models.ErrInvalidSettingKey,
+		},
+		{
+			name:      "value too large",
+			namespace: "mobile",
+			values:    map[string]json.RawMessage{"notes": json.RawMessage(`"` + strings.Repeat("a", 64) + `"`)},
+			err:       models.ErrSettingsLimitExceeded,
+		},
+		{
+			name:      "too many keys",
+			namespace: "mobile",
+			values:    map[string]json.RawMessage{"a": json.RawMessage(`1`), "b": json.RawMessage(`2`), "c": json.RawMessage(`3`)},
+			err:       models.ErrSettingsLimitExceeded,
+		},
+		{
+			name:      "no values",
+			namespace: "mobile",
+			err:       models.ErrInvalidSettingValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := &SettingsStorageMock{
+				UpsertSettingsFunc: func(ctx context.Context, uID uuid.UUID, namespace string, values map[string]json.RawMessage, updatedAt time.Time, maxKeys int) error {
+					require.Equal(t, 2, maxKeys)
+					return nil
+				},
+			}
+
+			s := NewSettingsService(repoMock, &UserStorageMock{}, newSettingsRegistry(t), SettingsConfig{MaxValueBytes: 32, MaxKeys: 2})
+
+			err := s.SetSettings(context.TODO(), userID, tt.namespace, tt.values)
+			if tt.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.err)
+			}
+			require.Len(t, repoMock.UpsertSettingsCalls(), tt.upsertCalls)
+		})
+	}
+}
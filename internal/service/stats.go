@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	// 3rd party
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+//go:generate moq -out stats_storage_mock_test.go . StatsStorage
+type StatsStorage interface {
+	GetUserStats(ctx context.Context, opts models.UserStatsOptions, limit uint64) ([]models.UserStatsGroup, error)
+	GetUserStatsRollup(ctx context.Context, opts models.UserStatsOptions, limit uint64) ([]models.UserStatsGroup, time.Time, error)
+	RefreshUserStatsRollup(ctx context.Context) (bool, error)
+}
+
+// StatsConfig controls how user statistics are computed.
+type StatsConfig struct {
+	// Rollup counts users from the daily rollup whenever the query allows it,
+	// rather than from the users themselves.
+	Rollup bool
+	// RollupInterval is the time between two refreshes of the rollup.
+	RollupInterval time.Duration
+	// CacheTTL is how long results are served from memory.
+	CacheTTL time.Duration
+}
+
+const (
+	// maxStatsGroups caps the number of groups of a result.
+	maxStatsGroups = 10000
+	// maxCachedStats caps the number of results held in memory.
+	maxCachedStats = 1000
+	// statsComputeTimeout bounds computing a result, which is shared by its
+	// callers and so outlives the one that started it.
+	statsComputeTimeout = time.Minute
+)
+
+var statsDimensions = map[models.StatsDimension]bool{
+	models.StatsByCountry:  true,
+	models.StatsByStatus:   true,
+	models.StatsByType:     true,
+	models.StatsByVerified: true,
+}
+
+// StatsService counts users for dashboards.
+type StatsService struct {
+	stats  StatsStorage
+	cfg    StatsConfig
+	logger *zap.SugaredLogger
+
+	// computing shares a result between the callers asking for it at once.
+	computing singleflight.Group
+
+	mu    sync.Mutex
+	cache map[string]cachedStats
+}
+
+type cachedStats struct {
+	stats     models.UserStats
+	expiresAt time.Time
+}
+
+func NewStatsService(stats StatsStorage, cfg StatsConfig, logger *zap.SugaredLogger) *StatsService {
+	return &StatsService{
+		stats:  stats,
+		cfg:    cfg,
+		logger: logger,
+		cache:  make(map[string]cachedStats),
+	}
+}
+
+// GetUserStats counts the live users by the dimensions and signup period of
+// opts. Results may be as old as the cache TTL, or as the last refresh of the
+// rollup when it is used.
+func (sSvc *StatsService) GetUserStats(ctx context.Context, opts models.UserStatsOptions) (models.UserStats, error) {
+	key, err := statsKey(opts)
+	if err != nil {
+		return models.UserStats{}, err
+	}
+
+	if stats, ok := sSvc.cached(key); ok {
+		return stats, nil
+	}
+
+	// Callers giving up leave the computation to the others.
+	ch := sSvc.computing.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), statsComputeTimeout)
+		defer cancel()
+
+		stats, err := sSvc.computeStats(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		sSvc.store(key, stats)
+		return stats, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return models.UserStats{}, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return models.UserStats{}, res.Err
+		}
+		return res.Val.(models.UserStats), nil
+	}
+}
+
+func (sSvc *StatsService) computeStats(ctx context.Context, opts models.UserStatsOptions) (models.UserStats, error) {
+	if sSvc.cfg.Rollup && rollupCovers(opts) {
+		groups, refreshedAt, err := sSvc.stats.GetUserStatsRollup(ctx, opts, maxStatsGroups+1)
+		if err != nil {
+			return models.UserStats{}, err
+		}
+		// Until its first refresh, users are counted live.
+		if !refreshedAt.IsZero() {
+			return newUserStats(groups, refreshedAt)
+		}
+	}
+
+	computedAt := time.Now().UTC()
+	groups, err := sSvc.stats.GetUserStats(ctx, opts, maxStatsGroups+1)
+	if err != nil {
+		return models.UserStats{}, err
+	}
+
+	return newUserStats(groups, computedAt)
+}
+
+func newUserStats(groups []models.UserStatsGroup, computedAt time.Time) (models.UserStats, error) {
+	if len(groups) > maxStatsGroups {
+		return models.UserStats{}, models.ErrTooManyStatsGroups
+	}
+
+	return models.UserStats{Groups: groups, ComputedAt: computedAt}, nil
+}
+
+// rollupCovers reports whether the daily rollup can answer the query, that is
+// whether its bounds fall on UTC days.
+func rollupCovers(opts models.UserStatsOptions) bool {
+	for _, t := range []*time.Time{opts.CreatedAfter, opts.CreatedBefore} {
+		if t != nil && !t.Equal(t.Truncate(24*time.Hour)) {
+			return false
+		}
+	}
+	return true
+}
+
+// statsKey validates opts and returns the key of its results in the cache.
+func statsKey(opts models.UserStatsOptions) (string, error) {
+	var key strings.Builder
+
+	seen := make(map[models.StatsDimension]bool, len(opts.GroupBy))
+	for _, d := range opts.GroupBy {
+		if !statsDimensions[d] {
+			return "", fmt.Errorf("%w: unknown dimension %q", models.ErrInvalidStatsQuery, d)
+		}
+		if seen[d] {
+			return "", fmt.Errorf("%w: dimension %q repeated", models.ErrInvalidStatsQuery, d)
+		}
+		seen[d] = true
+	}
+	// Dimensions are keyed in a fixed order, as it does not change results.
+	for _, d := range []models.StatsDimension{models.StatsByCountry, models.StatsByStatus, models.StatsByType, models.StatsByVerified} {
+		if seen[d] {
+			key.WriteString(string(d) + ",")
+		}
+	}
+
+	switch opts.Bucket {
+	case models.StatsBucketNone, models.StatsBucketDay, models.StatsBucketWeek, models.StatsBucketMonth:
+	default:
+		return "", fmt.Errorf("%w: unknown bucket %q", models.ErrInvalidStatsQuery, opts.Bucket)
+	}
+	key.WriteString("|" + string(opts.Bucket))
+
+	if opts.CreatedAfter != nil && opts.CreatedBefore != nil && !opts.CreatedAfter.Before(*opts.CreatedBefore) {
+		return "", fmt.Errorf("%w: created_after must be before created_before", models.ErrInvalidStatsQuery)
+	}
+	for _, t := range []*time.Time{opts.CreatedAfter, opts.CreatedBefore} {
+		key.WriteString("|")
+		if t != nil {
+			key.WriteString(t.UTC().Format(time.RFC3339Nano))
+		}
+	}
+
+	return key.String(), nil
+}
+
+func (sSvc *StatsService) cached(key string) (models.UserStats, bool) {
+	sSvc.mu.Lock()
+	defer sSvc.mu.Unlock()
+
+	c, ok := sSvc.cache[key]
+	if !ok || time.Now().After(c.expiresAt) {
+		return models.UserStats{}, false
+	}
+	return c.stats, true
+}
+
+func (sSvc *StatsService) store(key string, stats models.UserStats) {
+	if sSvc.cfg.CacheTTL <= 0 {
+		return
+	}
+
+	sSvc.mu.Lock()
+	defer sSvc.mu.Unlock()
+
+	now := time.Now()
+	for k, c := range sSvc.cache {
+		if now.After(c.expiresAt) {
+			delete(sSvc.cache, k)
+		}
+	}
+
+	if len(sSvc.cache) < maxCachedStats {
+		sSvc.cache[key] = cachedStats{stats: stats, expiresAt: now.Add(sSvc.cfg.CacheTTL)}
+	}
+}
+
+// RefreshRollup refreshes the daily rollup at start and then every
+// RollupInterval, until ctx is cancelled. It does nothing when rollups are
+// disabled.
+func (sSvc *StatsService) RefreshRollup(ctx context.Context) error {
+	if !sSvc.cfg.Rollup {
+		return nil
+	}
+
+	sSvc.logger.Infow("startup", "status", "user stats rollup started", "interval", sSvc.cfg.RollupInterval)
+
+	ticker := time.NewTicker(sSvc.cfg.RollupInterval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		refreshed, err := sSvc.stats.RefreshUserStatsRollup(ctx)
+		switch {
+		case err != nil:
+			sSvc.logger.Errorw("refresh user stats rollup", "error", err)
+		case refreshed:
+			sSvc.logger.Infow("refresh user stats rollup", "took", time.Since(start))
+		}
+
+		select {
+		case <-ctx.Done():
+			sSvc.logger.Infow("shutdown", "status", "user stats rollup stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
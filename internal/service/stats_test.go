@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestStatsService_GetUserStats(t *testing.T) {
+	refreshedAt := time.Now().UTC().Add(-time.Hour)
+	day := time.Date(2023, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	newStorage := func() *StatsStorageMock {
+		return &StatsStorageMock{
+			GetUserStatsFunc: func(ctx context.Context, opts models.UserStatsOptions, limit uint64) ([]models.UserStatsGroup, error) {
+				return []models.UserStatsGroup{{Country: "GR", Users: 3}}, nil
+			},
+			GetUserStatsRollupFunc: func(ctx context.Context, opts models.UserStatsOptions, limit uint64) ([]models.UserStatsGroup, time.Time, error) {
+				return []models.UserStatsGroup{{Country: "GR", Users: 2}}, refreshedAt, nil
+			},
+		}
+	}
+
+	opts := models.UserStatsOptions{
+		GroupBy:      []models.StatsDimension{models.StatsByCountry, models.StatsByStatus},
+		Bucket:       models.StatsBucketDay,
+		CreatedAfter: &day,
+	}
+
+	t.Log("live and cached")
+	{
+		storage := newStorage()
+		s := NewStatsService(storage, StatsConfig{CacheTTL: time.Minute}, zap.NewNop().Sugar())
+
+		stats, err := s.GetUserStats(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), stats.Groups[0].Users)
+		require.WithinDuration(t, time.Now(), stats.ComputedAt, time.Minute)
+
+		// Dimensions in another order share the result.
+		opts := opts
+		opts.GroupBy = []models.StatsDimension{models.StatsByStatus, models.StatsByCountry}
+		_, err = s.GetUserStats(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Len(t, storage.GetUserStatsCalls(), 1)
+		require.Empty(t, storage.GetUserStatsRollupCalls())
+	}
+
+	t.Log("computed to the end when the caller gives up")
+	{
+		computing, release := make(chan struct{}), make(chan struct{})
+		storage := newStorage()
+		storage.GetUserStatsFunc = func(ctx context.Context, opts models.UserStatsOptions, limit uint64) ([]models.UserStatsGroup, error) {
+			close(computing)
+			<-release
+			return []models.UserStatsGroup{{Country: "GR", Users: 3}}, ctx.Err()
+		}
+		s := NewStatsService(storage, StatsConfig{CacheTTL: time.Minute}, zap.NewNop().Sugar())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		first := make(chan error, 1)
+		go func() {
+			_, err := s.GetUserStats(ctx, opts)
+			first <- err
+		}()
+
+		<-computing
+		cancel()
+		require.ErrorIs(t, <-first, context.Canceled)
+		close(release)
+
+		// Served by the computation the first caller started, in flight or cached.
+		stats, err := s.GetUserStats(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), stats.Groups[0].Users)
+		require.Len(t, storage.GetUserStatsCalls(), 1)
+	}
+
+	t.Log("rollup")
+	{
+		storage := newStorage()
+		s := NewStatsService(storage, StatsConfig{Rollup: true}, zap.NewNop().Sugar())
+
+		stats, err := s.GetUserStats(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), stats.Groups[0].Users)
+		require.Equal(t, refreshedAt, stats.ComputedAt)
+		require.Empty(t, storage.GetUserStatsCalls())
+
+		// Bounds within a day, and no cache.
+		after := day.Add(time.Hour)
+		_, err = s.GetUserStats(context.TODO(), models.UserStatsOptions{CreatedAfter: &after})
+		require.NoError(t, err)
+		_, err = s.GetUserStats(context.TODO(), models.UserStatsOptions{CreatedAfter: &after})
+		require.NoError(t, err)
+		require.Len(t, storage.GetUserStatsCalls(), 2)
+		require.Len(t, storage.GetUserStatsRollupCalls(), 1)
+	}
+
+	t.Log("rollup never refreshed")
+	{
+		storage := newStorage()
+		storage.GetUserStatsRollupFunc = func(ctx context.Context, opts models.UserStatsOptions, limit uint64) ([]models.UserStatsGroup, time.Time, error) {
+			return nil, time.Time{}, nil
+		}
+		s := NewStatsService(storage, StatsConfig{Rollup: true}, zap.NewNop().Sugar())
+
+		stats, err := s.GetUserStats(context.TODO(), opts)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), stats.Groups[0].Users)
+	}
+
+	t.Log("too many groups")
+	{
+		storage := newStorage()
+		storage.GetUserStatsFunc = func(ctx context.Context, opts models.UserStatsOptions, limit uint64) ([]models.UserStatsGroup, error) {
+			return make([]models.UserStatsGroup, limit), nil
+		}
+		s := NewStatsService(storage, StatsConfig{}, zap.NewNop().Sugar())
+
+		_, err := s.GetUserStats(context.TODO(), opts)
+		require.ErrorIs(t, err, models.ErrTooManyStatsGroups)
+	}
+
+	t.Log("invalid")
+	{
+		before := day.Add(-time.Hour)
+		s := NewStatsService(newStorage(), StatsConfig{}, zap.NewNop().Sugar())
+
+		for _, opts := range []models.UserStatsOptions{
+			{GroupBy: []models.StatsDimension{"password"}},
+			{GroupBy: []models.StatsDimension{models.StatsByStatus, models.StatsByStatus}},
+			{Bucket: "year"},
+			{CreatedAfter: &day, CreatedBefore: &before},
+		} {
+			_, err := s.GetUserStats(context.TODO(), opts)
+			require.ErrorIs(t, err, models.ErrInvalidStatsQuery)
+		}
+	}
+}
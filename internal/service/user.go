@@ -20,72 +20,183 @@ import (
 type UserStorage interface {
 	InsertUser(ctx context.Context, user models.User) (uuid.UUID, error)
 	UpdateUser(ctx context.Context, userID uuid.UUID, user models.User) error
+	UpdateUserStatus(ctx context.Context, userID uuid.UUID, from models.UserStatus, status models.UserStatus, reason string, changedAt time.Time) error
 	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	RestoreUser(ctx context.Context, userID uuid.UUID, restoredAt time.Time) error
+	EraseUser(ctx context.Context, userID uuid.UUID) error
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time, limit uint64) ([]uuid.UUID, error)
 	GetUsersByFilter(ctx context.Context, opts models.GetUsersOptions) ([]models.User, error)
+	CountUsers(ctx context.Context, opts models.GetUsersOptions) (models.UserCount, error)
+	SearchUsers(ctx context.Context, opts models.SearchUsersOptions) ([]models.User, error)
+	StreamUsers(ctx context.Context, opts models.GetUsersOptions, fn func(models.User) error) error
+	InsertUsers(ctx context.Context, users []models.User) error
+	GetEmailOwners(ctx context.Context, emails []string) (map[string]models.EmailOwner, error)
 	GetUserByID(ctx context.Context, userID uuid.UUID) (models.User, error)
 	ExistsByID(ctx context.Context, userID uuid.UUID) (bool, error)
+	// RunInTx runs fn in a single transaction, which the methods called with
+	// the context passed to fn take part in.
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// userKeyedTopics are the compacted topics whose messages are keyed by user id.
+// They receive a tombstone when a user is erased.
+var userKeyedTopics = []string{
+	"UserCreated",
+	"UserUpdated",
+	"UserDeleted",
+	"UserRestored",
+	"UserStatusChanged",
+	"UserPurged",
+	"ConsentChanged",
+}
+
+// statusTransitions lists, for every status, the statuses a user may move to.
+var statusTransitions = map[models.UserStatus][]models.UserStatus{
+	models.UserStatusPending:     {models.UserStatusActive, models.UserStatusDeactivated},
+	models.UserStatusActive:      {models.UserStatusSuspended, models.UserStatusDeactivated},
+	models.UserStatusSuspended:   {models.UserStatusActive, models.UserStatusDeactivated},
+	models.UserStatusDeactivated: {models.UserStatusActive},
 }
 
 //go:generate moq -out event_publisher_mock_test.go . EventPublisher
 type EventPublisher interface {
 	Publish(ctx context.Context, topic string, key string, pbMessage proto.Message) error
+	PublishTombstone(ctx context.Context, topic string, key string) error
 }
 
 type UserService struct {
 	repo           UserStorage
+	attributes     AttributeStorage
 	eventPublisher EventPublisher
 }
 
-func NewUserService(repo UserStorage, publisher EventPublisher) *UserService {
+func NewUserService(repo UserStorage, attributes AttributeStorage, publisher EventPublisher) *UserService {
 	return &UserService{
 		repo:           repo,
+		attributes:     attributes,
 		eventPublisher: publisher,
 	}
 }
 
 func (uSvc *UserService) CreateUser(ctx context.Context, nu models.NewUser) (uuid.UUID, error) {
+	hash, err := newUserPassword(nu)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	user, err := uSvc.createUser(ctx, nu, hash)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	uSvc.publishUserCreated(user)
+
+	return user.ID, nil
+}
+
+// newUserPassword returns the hash of the password of the new user, nil when
+// it has none.
+func newUserPassword(nu models.NewUser) ([]byte, error) {
+	// Service accounts authenticate with API keys and may be created without a
+	// password, as may users signing in through an external identity provider.
+	human := nu.Type == "" || nu.Type == models.UserTypeHuman
+	if (human && !nu.Passwordless) || nu.Password != "" {
+		return bcryptPassword(nu.Password)
+	}
+	return nil, nil
+}
+
+// createUser inserts the new user with the hash of its password, without
+// publishing its event.
+func (uSvc *UserService) createUser(ctx context.Context, nu models.NewUser, hash []byte) (models.User, error) {
 	now := time.Now().UTC()
 
-	hash, err := bcryptPassword(nu.Password)
+	userType := nu.Type
+	if userType == "" {
+		userType = models.UserTypeHuman
+	}
+
+	defs, err := uSvc.attributeDefinitions(ctx, nu.Attributes)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	attributes, err := applyAttributes(defs, nil, nu.Attributes)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("generating password hash: %w", err)
+		return models.User{}, err
+	}
+
+	status := models.UserStatusActive
+	if nu.Pending {
+		status = models.UserStatusPending
 	}
 
 	user := models.User{
-		ID:        uuid.New(),
-		Email:     nu.Email,
-		FirstName: nu.FirstName,
-		LastName:  nu.LastName,
-		Nickname:  nu.Nickname,
-		Country:   nu.Country,
-		Password:  hash,
-		CreatedAt: now,
-		UpdateAt:  nil,
+		ID:         uuid.New(),
+		Type:       userType,
+		Status:     status,
+		Email:      nu.Email,
+		FirstName:  nu.FirstName,
+		LastName:   nu.LastName,
+		Nickname:   nu.Nickname,
+		Country:    nu.Country,
+		Password:   hash,
+		Attributes: attributes,
+		CreatedAt:  now,
+		UpdateAt:   nil,
 	}
 
-	userID, err := uSvc.repo.InsertUser(ctx, user)
-	if err != nil {
-		return uuid.Nil, err
+	if user.ID, err = uSvc.repo.InsertUser(ctx, user); err != nil {
+		return models.User{}, err
 	}
 
+	return user, nil
+}
+
+func (uSvc *UserService) publishUserCreated(user models.User) {
 	go func() {
 		ctx := context.Background()
 		evt := pbevents.UserCreated{
 			UserId:    user.ID.String(),
-			CreatedAt: timestamppb.New(now),
+			CreatedAt: timestamppb.New(user.CreatedAt),
 		}
-		_ = uSvc.eventPublisher.Publish(ctx, "UserCreated", userID.String(), &evt)
+		_ = uSvc.eventPublisher.Publish(ctx, "UserCreated", user.ID.String(), &evt)
 	}()
-
-	return userID, nil
 }
 
 func (uSvc *UserService) UpdateUser(ctx context.Context, userID uuid.UUID, updateUser models.UpdateUser) error {
-	user, err := uSvc.repo.GetUserByID(ctx, userID)
+	var hash []byte
+	if len(updateUser.Password) != 0 {
+		var err error
+		if hash, err = bcryptPassword(updateUser.Password); err != nil {
+			return err
+		}
+	}
+
+	defs, err := uSvc.attributeDefinitions(ctx, updateUser.Attributes)
+	if err != nil {
+		return err
+	}
+
+	user, err := uSvc.updateUser(ctx, userID, updateUser, hash, defs)
 	if err != nil {
 		return err
 	}
 
+	uSvc.publishUserUpdated(user)
+
+	return nil
+}
+
+// updateUser applies the update to the user, replacing its password with
+// hash unless it is nil, and returns the updated user without publishing its
+// event. The attributes are checked against defs.
+func (uSvc *UserService) updateUser(ctx context.Context, userID uuid.UUID, updateUser models.UpdateUser, hash []byte, defs map[string]models.AttributeDefinition) (models.User, error) {
+	user, err := uSvc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.User{}, err
+	}
+
 	if updateUser.Email != "" {
 		user.Email = updateUser.Email
 	}
@@ -106,54 +217,301 @@ func (uSvc *UserService) UpdateUser(ctx context.Context, userID uuid.UUID, updat
 		user.Country = updateUser.Country
 	}
 
-	if len(updateUser.Password) != 0 {
-		hash, err := bcryptPassword(updateUser.Password)
-		if err != nil {
-			return err
-		}
+	if hash != nil {
 		user.Password = hash
 	}
 
+	user.Attributes, err = applyAttributes(defs, user.Attributes, updateUser.Attributes)
+	if err != nil {
+		return models.User{}, err
+	}
+
 	now := time.Now().UTC()
 	user.UpdateAt = &now
 
 	if err := uSvc.repo.UpdateUser(ctx, userID, user); err != nil {
-		return err
+		return models.User{}, err
 	}
 
+	return user, nil
+}
+
+func (uSvc *UserService) publishUserUpdated(user models.User) {
 	go func() {
 		ctx := context.Background()
 		evt := pbevents.UserUpdated{
 			UserId:    user.ID.String(),
-			UpdatedAt: timestamppb.New(now),
+			UpdatedAt: timestamppb.New(*user.UpdateAt),
 		}
-		_ = uSvc.eventPublisher.Publish(ctx, "UserUpdated", userID.String(), &evt)
+		_ = uSvc.eventPublisher.Publish(ctx, "UserUpdated", user.ID.String(), &evt)
 	}()
+}
+
+func (uSvc *UserService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	deletedAt, err := uSvc.deleteUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	uSvc.publishUserDeleted(userID, deletedAt)
 
 	return nil
 }
 
-func (uSvc *UserService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+// deleteUser soft deletes the user, without publishing its event, and returns
+// when it was deleted.
+func (uSvc *UserService) deleteUser(ctx context.Context, userID uuid.UUID) (time.Time, error) {
 	exists, err := uSvc.repo.ExistsByID(ctx, userID)
 	if err != nil {
-		return err
+		return time.Time{}, err
 	}
 
 	if !exists {
-		return models.ErrUserNotFound
+		return time.Time{}, models.ErrUserNotFound
 	}
 
 	if err := uSvc.repo.DeleteUser(ctx, userID); err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Now().UTC(), nil
+}
+
+func (uSvc *UserService) publishUserDeleted(userID uuid.UUID, deletedAt time.Time) {
+	go func() {
+		ctx := context.Background()
+		evt := pbevents.UserDeleted{
+			UserId:    userID.String(),
+			DeletedAt: timestamppb.New(deletedAt),
+		}
+		_ = uSvc.eventPublisher.Publish(ctx, "UserDeleted", userID.String(), &evt)
+	}()
+}
+
+func (uSvc *UserService) RestoreUser(ctx context.Context, userID uuid.UUID) error {
+	now := time.Now().UTC()
+
+	if err := uSvc.repo.RestoreUser(ctx, userID, now); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx := context.Background()
+		evt := pbevents.UserRestored{
+			UserId:     userID.String(),
+			RestoredAt: timestamppb.New(now),
+		}
+		_ = uSvc.eventPublisher.Publish(ctx, "UserRestored", userID.String(), &evt)
+	}()
+
+	return nil
+}
+
+// EraseUser permanently removes all data held about the user and notifies
+// consumers to do the same.
+func (uSvc *UserService) EraseUser(ctx context.Context, userID uuid.UUID) error {
+	if err := uSvc.repo.EraseUser(ctx, userID); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	go func() {
+		ctx := context.Background()
+		evt := pbevents.UserErased{
+			UserId:   userID.String(),
+			ErasedAt: timestamppb.New(now),
+		}
+		_ = uSvc.eventPublisher.Publish(ctx, "UserErased", userID.String(), &evt)
+
+		for _, topic := range userKeyedTopics {
+			_ = uSvc.eventPublisher.PublishTombstone(ctx, topic, userID.String())
+		}
+	}()
+
+	return nil
+}
+
+func (uSvc *UserService) SuspendUser(ctx context.Context, userID uuid.UUID, reason string) error {
+	return uSvc.changeStatus(ctx, userID, models.UserStatusSuspended, reason)
+}
+
+func (uSvc *UserService) ReactivateUser(ctx context.Context, userID uuid.UUID, reason string) error {
+	return uSvc.changeStatus(ctx, userID, models.UserStatusActive, reason)
+}
+
+func (uSvc *UserService) DeactivateUser(ctx context.Context, userID uuid.UUID, reason string) error {
+	return uSvc.changeStatus(ctx, userID, models.UserStatusDeactivated, reason)
+}
+
+func (uSvc *UserService) changeStatus(ctx context.Context, userID uuid.UUID, status models.UserStatus, reason string) error {
+	user, err := uSvc.repo.GetUserByID(ctx, userID)
+	if err != nil {
 		return err
 	}
 
+	if !canTransition(user.Status, status) {
+		return fmt.Errorf("%w: %s -> %s", models.ErrInvalidStatusTransition, user.Status, status)
+	}
+
+	now := time.Now().UTC()
+
+	// The status is checked again under the row lock, in case it was changed
+	// in between.
+	if err := uSvc.repo.UpdateUserStatus(ctx, userID, user.Status, status, reason, now); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx := context.Background()
+		evt := pbevents.UserStatusChanged{
+			UserId:         userID.String(),
+			PreviousStatus: string(user.Status),
+			Status:         string(status),
+			Reason:         reason,
+			ChangedAt:      timestamppb.New(now),
+		}
+		_ = uSvc.eventPublisher.Publish(ctx, "UserStatusChanged", userID.String(), &evt)
+	}()
+
 	return nil
 }
 
+func canTransition(from models.UserStatus, to models.UserStatus) bool {
+	for _, s := range statusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (uSvc *UserService) GetUser(ctx context.Context, userID uuid.UUID) (models.User, error) {
+	return uSvc.repo.GetUserByID(ctx, userID)
+}
+
 func (uSvc *UserService) GetUsers(ctx context.Context, qu models.GetUsersOptions) ([]models.User, error) {
+	qu, err := uSvc.normalizeQuery(ctx, qu)
+	if err != nil {
+		return nil, err
+	}
+
 	return uSvc.repo.GetUsersByFilter(ctx, qu)
 }
 
+// ListUsers returns a page of users, along with the cursor of the next page;
+// the cursor is nil on the last page.
+func (uSvc *UserService) ListUsers(ctx context.Context, qu models.GetUsersOptions) ([]models.User, *models.UserCursor, error) {
+	pageSize := qu.PageSize
+
+	// Ask for one more user than requested to find out whether another page follows.
+	qu.PageSize++
+	users, err := uSvc.GetUsers(ctx, qu)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if uint64(len(users)) <= pageSize {
+		return users, nil, nil
+	}
+
+	users = users[:pageSize]
+	last := users[len(users)-1]
+	return users, &models.UserCursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
+
+// CountUsers counts the users matching the filter of qu; large counts are
+// estimated.
+func (uSvc *UserService) CountUsers(ctx context.Context, qu models.GetUsersOptions) (models.UserCount, error) {
+	qu, err := uSvc.normalizeQuery(ctx, qu)
+	if err != nil {
+		return models.UserCount{}, err
+	}
+
+	return uSvc.repo.CountUsers(ctx, qu)
+}
+
+// ExportUsers calls fn with every user matching the filter of qu, in order of
+// creation, without holding them all in memory.
+func (uSvc *UserService) ExportUsers(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error {
+	qu, err := uSvc.normalizeQuery(ctx, qu)
+	if err != nil {
+		return err
+	}
+
+	return uSvc.repo.StreamUsers(ctx, qu, fn)
+}
+
+// normalizeQuery checks that the filtered attributes are indexed and
+// normalizes their values.
+func (uSvc *UserService) normalizeQuery(ctx context.Context, qu models.GetUsersOptions) (models.GetUsersOptions, error) {
+	if len(qu.Filter.Attributes) == 0 {
+		return qu, nil
+	}
+
+	defs, err := attributeDefinitions(ctx, uSvc.attributes)
+	if err != nil {
+		return qu, err
+	}
+
+	filter := make(map[string]any, len(qu.Filter.Attributes))
+	for name, value := range qu.Filter.Attributes {
+		def, ok := defs[name]
+		if !ok || !def.Indexed {
+			return qu, fmt.Errorf("%w: %s", models.ErrAttributeNotFilterable, name)
+		}
+		if filter[name], err = normalizeAttribute(def, value); err != nil {
+			return qu, err
+		}
+	}
+	qu.Filter.Attributes = filter
+
+	return qu, nil
+}
+
+// attributeDefinitions returns the attribute definitions by name, or nil
+// without querying them when there are no attribute changes to check.
+func (uSvc *UserService) attributeDefinitions(ctx context.Context, changes map[string]any) (map[string]models.AttributeDefinition, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	return attributeDefinitions(ctx, uSvc.attributes)
+}
+
+// applyAttributes validates the changes against the attribute definitions and
+// returns the current attributes with the changes applied. A nil value removes
+// the attribute.
+func applyAttributes(defs map[string]models.AttributeDefinition, current map[string]any, changes map[string]any) (map[string]any, error) {
+	if len(changes) == 0 {
+		return current, nil
+	}
+
+	attributes := make(map[string]any, len(current)+len(changes))
+	for name, value := range current {
+		attributes[name] = value
+	}
+
+	for name, value := range changes {
+		def, ok := defs[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown attribute %s", models.ErrInvalidAttribute, name)
+		}
+
+		if value == nil {
+			delete(attributes, name)
+			continue
+		}
+
+		var err error
+		if attributes[name], err = normalizeAttribute(def, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return attributes, nil
+}
+
 func bcryptPassword(password string) ([]byte, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
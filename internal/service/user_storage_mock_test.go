@@ -8,6 +8,7 @@ import (
 	"github.com/TonyPath/user-mng-grpc-service/internal/models"
 	"github.com/google/uuid"
 	"sync"
+	"time"
 )
 
 // Ensure, that UserStorageMock does implement UserStorage.
@@ -20,12 +21,21 @@ var _ UserStorage = &UserStorageMock{}
 //
 // 		// make and configure a mocked UserStorage
 // 		mockedUserStorage := &UserStorageMock{
+// 			CountUsersFunc: func(ctx context.Context, opts models.GetUsersOptions) (models.UserCount, error) {
+// 				panic("mock out the CountUsers method")
+// 			},
 // 			DeleteUserFunc: func(ctx context.Context, userID uuid.UUID) error {
 // 				panic("mock out the DeleteUser method")
 // 			},
+// 			EraseUserFunc: func(ctx context.Context, userID uuid.UUID) error {
+// 				panic("mock out the EraseUser method")
+// 			},
 // 			ExistsByIDFunc: func(ctx context.Context, userID uuid.UUID) (bool, error) {
 // 				panic("mock out the ExistsByID method")
 // 			},
+// 			GetEmailOwnersFunc: func(ctx context.Context, emails []string) (map[string]models.EmailOwner, error) {
+// 				panic("mock out the GetEmailOwners method")
+// 			},
 // 			GetUserByIDFunc: func(ctx context.Context, userID uuid.UUID) (models.User, error) {
 // 				panic("mock out the GetUserByID method")
 // 			},
@@ -35,9 +45,30 @@ var _ UserStorage = &UserStorageMock{}
 // 			InsertUserFunc: func(ctx context.Context, user models.User) (uuid.UUID, error) {
 // 				panic("mock out the InsertUser method")
 // 			},
+// 			InsertUsersFunc: func(ctx context.Context, users []models.User) error {
+// 				panic("mock out the InsertUsers method")
+// 			},
+// 			PurgeDeletedUsersFunc: func(ctx context.Context, deletedBefore time.Time, limit uint64) ([]uuid.UUID, error) {
+// 				panic("mock out the PurgeDeletedUsers method")
+// 			},
+// 			RestoreUserFunc: func(ctx context.Context, userID uuid.UUID, restoredAt time.Time) error {
+// 				panic("mock out the RestoreUser method")
+// 			},
+// 			RunInTxFunc: func(ctx context.Context, fn func(ctx context.Context) error) error {
+// 				panic("mock out the RunInTx method")
+// 			},
+// 			SearchUsersFunc: func(ctx context.Context, opts models.SearchUsersOptions) ([]models.User, error) {
+// 				panic("mock out the SearchUsers method")
+// 			},
+// 			StreamUsersFunc: func(ctx context.Context, opts models.GetUsersOptions, fn func(models.User) error) error {
+// 				panic("mock out the StreamUsers method")
+// 			},
 // 			UpdateUserFunc: func(ctx context.Context, userID uuid.UUID, user models.User) error {
 // 				panic("mock out the UpdateUser method")
 // 			},
+// 			UpdateUserStatusFunc: func(ctx context.Context, userID uuid.UUID, from models.UserStatus, status models.UserStatus, reason string, changedAt time.Time) error {
+// 				panic("mock out the UpdateUserStatus method")
+// 			},
 // 		}
 //
 // 		// use mockedUserStorage in code that requires UserStorage
@@ -45,12 +76,21 @@ var _ UserStorage = &UserStorageMock{}
 //
 // 	}
 type UserStorageMock struct {
+	// CountUsersFunc mocks the CountUsers method.
+	CountUsersFunc func(ctx context.Context, opts models.GetUsersOptions) (models.UserCount, error)
+
 	// DeleteUserFunc mocks the DeleteUser method.
 	DeleteUserFunc func(ctx context.Context, userID uuid.UUID) error
 
+	// EraseUserFunc mocks the EraseUser method.
+	EraseUserFunc func(ctx context.Context, userID uuid.UUID) error
+
 	// ExistsByIDFunc mocks the ExistsByID method.
 	ExistsByIDFunc func(ctx context.Context, userID uuid.UUID) (bool, error)
 
+	// GetEmailOwnersFunc mocks the GetEmailOwners method.
+	GetEmailOwnersFunc func(ctx context.Context, emails []string) (map[string]models.EmailOwner, error)
+
 	// GetUserByIDFunc mocks the GetUserByID method.
 	GetUserByIDFunc func(ctx context.Context, userID uuid.UUID) (models.User, error)
 
@@ -60,11 +100,39 @@ type UserStorageMock struct {
 	// InsertUserFunc mocks the InsertUser method.
 	InsertUserFunc func(ctx context.Context, user models.User) (uuid.UUID, error)
 
+	// InsertUsersFunc mocks the InsertUsers method.
+	InsertUsersFunc func(ctx context.Context, users []models.User) error
+
+	// PurgeDeletedUsersFunc mocks the PurgeDeletedUsers method.
+	PurgeDeletedUsersFunc func(ctx context.Context, deletedBefore time.Time, limit uint64) ([]uuid.UUID, error)
+
+	// RestoreUserFunc mocks the RestoreUser method.
+	RestoreUserFunc func(ctx context.Context, userID uuid.UUID, restoredAt time.Time) error
+
+	// RunInTxFunc mocks the RunInTx method.
+	RunInTxFunc func(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// SearchUsersFunc mocks the SearchUsers method.
+	SearchUsersFunc func(ctx context.Context, opts models.SearchUsersOptions) ([]models.User, error)
+
+	// StreamUsersFunc mocks the StreamUsers method.
+	StreamUsersFunc func(ctx context.Context, opts models.GetUsersOptions, fn func(models.User) error) error
+
 	// UpdateUserFunc mocks the UpdateUser method.
 	UpdateUserFunc func(ctx context.Context, userID uuid.UUID, user models.User) error
 
+	// UpdateUserStatusFunc mocks the UpdateUserStatus method.
+	UpdateUserStatusFunc func(ctx context.Context, userID uuid.UUID, from models.UserStatus, status models.UserStatus, reason string, changedAt time.Time) error
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// CountUsers holds details about calls to the CountUsers method.
+		CountUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Opts is the opts argument value.
+			Opts models.GetUsersOptions
+		}
 		// DeleteUser holds details about calls to the DeleteUser method.
 		DeleteUser []struct {
 			// Ctx is the ctx argument value.
@@ -72,6 +140,13 @@ type UserStorageMock struct {
 			// UserID is the userID argument value.
 			UserID uuid.UUID
 		}
+		// EraseUser holds details about calls to the EraseUser method.
+		EraseUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID uuid.UUID
+		}
 		// ExistsByID holds details about calls to the ExistsByID method.
 		ExistsByID []struct {
 			// Ctx is the ctx argument value.
@@ -79,6 +154,13 @@ type UserStorageMock struct {
 			// UserID is the userID argument value.
 			UserID uuid.UUID
 		}
+		// GetEmailOwners holds details about calls to the GetEmailOwners method.
+		GetEmailOwners []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Emails is the emails argument value.
+			Emails []string
+		}
 		// GetUserByID holds details about calls to the GetUserByID method.
 		GetUserByID []struct {
 			// Ctx is the ctx argument value.
@@ -100,6 +182,54 @@ type UserStorageMock struct {
 			// User is the user argument value.
 			User models.User
 		}
+		// InsertUsers holds details about calls to the InsertUsers method.
+		InsertUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Users is the users argument value.
+			Users []models.User
+		}
+		// PurgeDeletedUsers holds details about calls to the PurgeDeletedUsers method.
+		PurgeDeletedUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeletedBefore is the deletedBefore argument value.
+			DeletedBefore time.Time
+			// Limit is the limit argument value.
+			Limit uint64
+		}
+		// RestoreUser holds details about calls to the RestoreUser method.
+		RestoreUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID uuid.UUID
+			// RestoredAt is the restoredAt argument value.
+			RestoredAt time.Time
+		}
+		// RunInTx holds details about calls to the RunInTx method.
+		RunInTx []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fn is the fn argument value.
+			Fn func(ctx context.Context) error
+		}
+		// SearchUsers holds details about calls to the SearchUsers method.
+		SearchUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Opts is the opts argument value.
+			Opts models.SearchUsersOptions
+		}
+		// StreamUsers holds details about calls to the StreamUsers method.
+		StreamUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Opts is the opts argument value.
+			Opts models.GetUsersOptions
+			// Fn is the fn argument value.
+			Fn func(models.User) error
+		}
 		// UpdateUser holds details about calls to the UpdateUser method.
 		UpdateUser []struct {
 			// Ctx is the ctx argument value.
@@ -109,13 +239,73 @@ type UserStorageMock struct {
 			// User is the user argument value.
 			User models.User
 		}
+		// UpdateUserStatus holds details about calls to the UpdateUserStatus method.
+		UpdateUserStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID uuid.UUID
+			// From is the from argument value.
+			From models.UserStatus
+			// Status is the status argument value.
+			Status models.UserStatus
+			// Reason is the reason argument value.
+			Reason string
+			// ChangedAt is the changedAt argument value.
+			ChangedAt time.Time
+		}
+	}
+	lockCountUsers        sync.RWMutex
+	lockDeleteUser        sync.RWMutex
+	lockEraseUser         sync.RWMutex
+	lockExistsByID        sync.RWMutex
+	lockGetEmailOwners    sync.RWMutex
+	lockGetUserByID       sync.RWMutex
+	lockGetUsersByFilter  sync.RWMutex
+	lockInsertUser        sync.RWMutex
+	lockInsertUsers       sync.RWMutex
+	lockPurgeDeletedUsers sync.RWMutex
+	lockRestoreUser       sync.RWMutex
+	lockRunInTx           sync.RWMutex
+	lockSearchUsers       sync.RWMutex
+	lockStreamUsers       sync.RWMutex
+	lockUpdateUser        sync.RWMutex
+	lockUpdateUserStatus  sync.RWMutex
+}
+
+// CountUsers calls CountUsersFunc.
+func (mock *UserStorageMock) CountUsers(ctx context.Context, opts models.GetUsersOptions) (models.UserCount, error) {
+	if mock.CountUsersFunc == nil {
+		panic("UserStorageMock.CountUsersFunc: method is nil but UserStorage.CountUsers was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Opts models.GetUsersOptions
+	}{
+		Ctx:  ctx,
+		Opts: opts,
+	}
+	mock.lockCountUsers.Lock()
+	mock.calls.CountUsers = append(mock.calls.CountUsers, callInfo)
+	mock.lockCountUsers.Unlock()
+	return mock.CountUsersFunc(ctx, opts)
+}
+
+// CountUsersCalls gets all the calls that were made to CountUsers.
+// Check the length with:
+//     len(mockedUserStorage.CountUsersCalls())
+func (mock *UserStorageMock) CountUsersCalls() []struct {
+	Ctx  context.Context
+	Opts models.GetUsersOptions
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Opts models.GetUsersOptions
 	}
-	lockDeleteUser       sync.RWMutex
-	lockExistsByID       sync.RWMutex
-	lockGetUserByID      sync.RWMutex
-	lockGetUsersByFilter sync.RWMutex
-	lockInsertUser       sync.RWMutex
-	lockUpdateUser       sync.RWMutex
+	mock.lockCountUsers.RLock()
+	calls = mock.calls.CountUsers
+	mock.lockCountUsers.RUnlock()
+	return calls
 }
 
 // DeleteUser calls DeleteUserFunc.
@@ -153,6 +343,41 @@ func (mock *UserStorageMock) DeleteUserCalls() []struct {
 	return calls
 }
 
+// EraseUser calls EraseUserFunc.
+func (mock *UserStorageMock) EraseUser(ctx context.Context, userID uuid.UUID) error {
+	if mock.EraseUserFunc == nil {
+		panic("UserStorageMock.EraseUserFunc: method is nil but UserStorage.EraseUser was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockEraseUser.Lock()
+	mock.calls.EraseUser = append(mock.calls.EraseUser, callInfo)
+	mock.lockEraseUser.Unlock()
+	return mock.EraseUserFunc(ctx, userID)
+}
+
+// EraseUserCalls gets all the calls that were made to EraseUser.
+// Check the length with:
+//     len(mockedUserStorage.EraseUserCalls())
+func (mock *UserStorageMock) EraseUserCalls() []struct {
+	Ctx    context.Context
+	UserID uuid.UUID
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+	}
+	mock.lockEraseUser.RLock()
+	calls = mock.calls.EraseUser
+	mock.lockEraseUser.RUnlock()
+	return calls
+}
+
 // ExistsByID calls ExistsByIDFunc.
 func (mock *UserStorageMock) ExistsByID(ctx context.Context, userID uuid.UUID) (bool, error) {
 	if mock.ExistsByIDFunc == nil {
@@ -188,6 +413,41 @@ func (mock *UserStorageMock) ExistsByIDCalls() []struct {
 	return calls
 }
 
+// GetEmailOwners calls GetEmailOwnersFunc.
+func (mock *UserStorageMock) GetEmailOwners(ctx context.Context, emails []string) (map[string]models.EmailOwner, error) {
+	if mock.GetEmailOwnersFunc == nil {
+		panic("UserStorageMock.GetEmailOwnersFunc: method is nil but UserStorage.GetEmailOwners was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Emails []string
+	}{
+		Ctx:    ctx,
+		Emails: emails,
+	}
+	mock.lockGetEmailOwners.Lock()
+	mock.calls.GetEmailOwners = append(mock.calls.GetEmailOwners, callInfo)
+	mock.lockGetEmailOwners.Unlock()
+	return mock.GetEmailOwnersFunc(ctx, emails)
+}
+
+// GetEmailOwnersCalls gets all the calls that were made to GetEmailOwners.
+// Check the length with:
+//     len(mockedUserStorage.GetEmailOwnersCalls())
+func (mock *UserStorageMock) GetEmailOwnersCalls() []struct {
+	Ctx    context.Context
+	Emails []string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Emails []string
+	}
+	mock.lockGetEmailOwners.RLock()
+	calls = mock.calls.GetEmailOwners
+	mock.lockGetEmailOwners.RUnlock()
+	return calls
+}
+
 // GetUserByID calls GetUserByIDFunc.
 func (mock *UserStorageMock) GetUserByID(ctx context.Context, userID uuid.UUID) (models.User, error) {
 	if mock.GetUserByIDFunc == nil {
@@ -293,6 +553,228 @@ func (mock *UserStorageMock) InsertUserCalls() []struct {
 	return calls
 }
 
+// InsertUsers calls InsertUsersFunc.
+func (mock *UserStorageMock) InsertUsers(ctx context.Context, users []models.User) error {
+	if mock.InsertUsersFunc == nil {
+		panic("UserStorageMock.InsertUsersFunc: method is nil but UserStorage.InsertUsers was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Users []models.User
+	}{
+		Ctx:   ctx,
+		Users: users,
+	}
+	mock.lockInsertUsers.Lock()
+	mock.calls.InsertUsers = append(mock.calls.InsertUsers, callInfo)
+	mock.lockInsertUsers.Unlock()
+	return mock.InsertUsersFunc(ctx, users)
+}
+
+// InsertUsersCalls gets all the calls that were made to InsertUsers.
+// Check the length with:
+//     len(mockedUserStorage.InsertUsersCalls())
+func (mock *UserStorageMock) InsertUsersCalls() []struct {
+	Ctx   context.Context
+	Users []models.User
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Users []models.User
+	}
+	mock.lockInsertUsers.RLock()
+	calls = mock.calls.InsertUsers
+	mock.lockInsertUsers.RUnlock()
+	return calls
+}
+
+// PurgeDeletedUsers calls PurgeDeletedUsersFunc.
+func (mock *UserStorageMock) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time, limit uint64) ([]uuid.UUID, error) {
+	if mock.PurgeDeletedUsersFunc == nil {
+		panic("UserStorageMock.PurgeDeletedUsersFunc: method is nil but UserStorage.PurgeDeletedUsers was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		DeletedBefore time.Time
+		Limit         uint64
+	}{
+		Ctx:           ctx,
+		DeletedBefore: deletedBefore,
+		Limit:         limit,
+	}
+	mock.lockPurgeDeletedUsers.Lock()
+	mock.calls.PurgeDeletedUsers = append(mock.calls.PurgeDeletedUsers, callInfo)
+	mock.lockPurgeDeletedUsers.Unlock()
+	return mock.PurgeDeletedUsersFunc(ctx, deletedBefore, limit)
+}
+
+// PurgeDeletedUsersCalls gets all the calls that were made to PurgeDeletedUsers.
+// Check the length with:
+//     len(mockedUserStorage.PurgeDeletedUsersCalls())
+func (mock *UserStorageMock) PurgeDeletedUsersCalls() []struct {
+	Ctx           context.Context
+	DeletedBefore time.Time
+	Limit         uint64
+} {
+	var calls []struct {
+		Ctx           context.Context
+		DeletedBefore time.Time
+		Limit         uint64
+	}
+	mock.lockPurgeDeletedUsers.RLock()
+	calls = mock.calls.PurgeDeletedUsers
+	mock.lockPurgeDeletedUsers.RUnlock()
+	return calls
+}
+
+// RestoreUser calls RestoreUserFunc.
+func (mock *UserStorageMock) RestoreUser(ctx context.Context, userID uuid.UUID, restoredAt time.Time) error {
+	if mock.RestoreUserFunc == nil {
+		panic("UserStorageMock.RestoreUserFunc: method is nil but UserStorage.RestoreUser was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		UserID     uuid.UUID
+		RestoredAt time.Time
+	}{
+		Ctx:        ctx,
+		UserID:     userID,
+		RestoredAt: restoredAt,
+	}
+	mock.lockRestoreUser.Lock()
+	mock.calls.RestoreUser = append(mock.calls.RestoreUser, callInfo)
+	mock.lockRestoreUser.Unlock()
+	return mock.RestoreUserFunc(ctx, userID, restoredAt)
+}
+
+// RestoreUserCalls gets all the calls that were made to RestoreUser.
+// Check the length with:
+//     len(mockedUserStorage.RestoreUserCalls())
+func (mock *UserStorageMock) RestoreUserCalls() []struct {
+	Ctx        context.Context
+	UserID     uuid.UUID
+	RestoredAt time.Time
+} {
+	var calls []struct {
+		Ctx        context.Context
+		UserID     uuid.UUID
+		RestoredAt time.Time
+	}
+	mock.lockRestoreUser.RLock()
+	calls = mock.calls.RestoreUser
+	mock.lockRestoreUser.RUnlock()
+	return calls
+}
+
+// RunInTx calls RunInTxFunc.
+func (mock *UserStorageMock) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mock.RunInTxFunc == nil {
+		panic("UserStorageMock.RunInTxFunc: method is nil but UserStorage.RunInTx was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Fn  func(ctx context.Context) error
+	}{
+		Ctx: ctx,
+		Fn:  fn,
+	}
+	mock.lockRunInTx.Lock()
+	mock.calls.RunInTx = append(mock.calls.RunInTx, callInfo)
+	mock.lockRunInTx.Unlock()
+	return mock.RunInTxFunc(ctx, fn)
+}
+
+// RunInTxCalls gets all the calls that were made to RunInTx.
+// Check the length with:
+//     len(mockedUserStorage.RunInTxCalls())
+func (mock *UserStorageMock) RunInTxCalls() []struct {
+	Ctx context.Context
+	Fn  func(ctx context.Context) error
+} {
+	var calls []struct {
+		Ctx context.Context
+		Fn  func(ctx context.Context) error
+	}
+	mock.lockRunInTx.RLock()
+	calls = mock.calls.RunInTx
+	mock.lockRunInTx.RUnlock()
+	return calls
+}
+
+// SearchUsers calls SearchUsersFunc.
+func (mock *UserStorageMock) SearchUsers(ctx context.Context, opts models.SearchUsersOptions) ([]models.User, error) {
+	if mock.SearchUsersFunc == nil {
+		panic("UserStorageMock.SearchUsersFunc: method is nil but UserStorage.SearchUsers was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Opts models.SearchUsersOptions
+	}{
+		Ctx:  ctx,
+		Opts: opts,
+	}
+	mock.lockSearchUsers.Lock()
+	mock.calls.SearchUsers = append(mock.calls.SearchUsers, callInfo)
+	mock.lockSearchUsers.Unlock()
+	return mock.SearchUsersFunc(ctx, opts)
+}
+
+// SearchUsersCalls gets all the calls that were made to SearchUsers.
+// Check the length with:
+//     len(mockedUserStorage.SearchUsersCalls())
+func (mock *UserStorageMock) SearchUsersCalls() []struct {
+	Ctx  context.Context
+	Opts models.SearchUsersOptions
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Opts models.SearchUsersOptions
+	}
+	mock.lockSearchUsers.RLock()
+	calls = mock.calls.SearchUsers
+	mock.lockSearchUsers.RUnlock()
+	return calls
+}
+
+// StreamUsers calls StreamUsersFunc.
+func (mock *UserStorageMock) StreamUsers(ctx context.Context, opts models.GetUsersOptions, fn func(models.User) error) error {
+	if mock.StreamUsersFunc == nil {
+		panic("UserStorageMock.StreamUsersFunc: method is nil but UserStorage.StreamUsers was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Opts models.GetUsersOptions
+		Fn   func(models.User) error
+	}{
+		Ctx:  ctx,
+		Opts: opts,
+		Fn:   fn,
+	}
+	mock.lockStreamUsers.Lock()
+	mock.calls.StreamUsers = append(mock.calls.StreamUsers, callInfo)
+	mock.lockStreamUsers.Unlock()
+	return mock.StreamUsersFunc(ctx, opts, fn)
+}
+
+// StreamUsersCalls gets all the calls that were made to StreamUsers.
+// Check the length with:
+//     len(mockedUserStorage.StreamUsersCalls())
+func (mock *UserStorageMock) StreamUsersCalls() []struct {
+	Ctx  context.Context
+	Opts models.GetUsersOptions
+	Fn   func(models.User) error
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Opts models.GetUsersOptions
+		Fn   func(models.User) error
+	}
+	mock.lockStreamUsers.RLock()
+	calls = mock.calls.StreamUsers
+	mock.lockStreamUsers.RUnlock()
+	return calls
+}
+
 // UpdateUser calls UpdateUserFunc.
 func (mock *UserStorageMock) UpdateUser(ctx context.Context, userID uuid.UUID, user models.User) error {
 	if mock.UpdateUserFunc == nil {
@@ -331,3 +813,54 @@ func (mock *UserStorageMock) UpdateUserCalls() []struct {
 	mock.lockUpdateUser.RUnlock()
 	return calls
 }
+
+// UpdateUserStatus calls UpdateUserStatusFunc.
+func (mock *UserStorageMock) UpdateUserStatus(ctx context.Context, userID uuid.UUID, from models.UserStatus, status models.UserStatus, reason string, changedAt time.Time) error {
+	if mock.UpdateUserStatusFunc == nil {
+		panic("UserStorageMock.UpdateUserStatusFunc: method is nil but UserStorage.UpdateUserStatus was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		UserID    uuid.UUID
+		From      models.UserStatus
+		Status    models.UserStatus
+		Reason    string
+		ChangedAt time.Time
+	}{
+		Ctx:       ctx,
+		UserID:    userID,
+		From:      from,
+		Status:    status,
+		Reason:    reason,
+		ChangedAt: changedAt,
+	}
+	mock.lockUpdateUserStatus.Lock()
+	mock.calls.UpdateUserStatus = append(mock.calls.UpdateUserStatus, callInfo)
+	mock.lockUpdateUserStatus.Unlock()
+	return mock.UpdateUserStatusFunc(ctx, userID, from, status, reason, changedAt)
+}
+
+// UpdateUserStatusCalls gets all the calls that were made to UpdateUserStatus.
+// Check the length with:
+//     len(mockedUserStorage.UpdateUserStatusCalls())
+func (mock *UserStorageMock) UpdateUserStatusCalls() []struct {
+	Ctx       context.Context
+	UserID    uuid.UUID
+	From      models.UserStatus
+	Status    models.UserStatus
+	Reason    string
+	ChangedAt time.Time
+} {
+	var calls []struct {
+		Ctx       context.Context
+		UserID    uuid.UUID
+		From      models.UserStatus
+		Status    models.UserStatus
+		Reason    string
+		ChangedAt time.Time
+	}
+	mock.lockUpdateUserStatus.RLock()
+	calls = mock.calls.UpdateUserStatus
+	mock.lockUpdateUserStatus.RUnlock()
+	return calls
+}
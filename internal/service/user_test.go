@@ -41,7 +41,7 @@ func TestUserService_CreateUser_Success(t *testing.T) {
 		},
 	}
 
-	s := NewUserService(&repoMock, &publisherMock)
+	s := NewUserService(&repoMock, nil, &publisherMock)
 
 	uID, err := s.CreateUser(context.TODO(), newUser)
 
@@ -50,7 +50,19 @@ func TestUserService_CreateUser_Success(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, uuidMock, uID)
 	require.Len(t, repoMock.InsertUserCalls(), 1)
+	require.Equal(t, models.UserStatusActive, repoMock.InsertUserCalls()[0].User.Status)
 	require.Len(t, publisherMock.PublishCalls(), 1)
+
+	t.Log("pending user")
+	{
+		newUser.Pending = true
+		_, err := s.CreateUser(context.TODO(), newUser)
+
+		<-guard
+
+		require.NoError(t, err)
+		require.Equal(t, models.UserStatusPending, repoMock.InsertUserCalls()[1].User.Status)
+	}
 }
 
 func TestUserService_CreateUser_Fail(t *testing.T) {
@@ -120,7 +132,7 @@ func TestUserService_CreateUser_Fail(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			publisherMock := EventPublisherMock{}
 
-			s := NewUserService(tt.deps.repo, &publisherMock)
+			s := NewUserService(tt.deps.repo, nil, &publisherMock)
 
 			uID, err := s.CreateUser(context.TODO(), tt.args.newUser)
 			tt.checkFn(t, uID, err)
@@ -169,7 +181,7 @@ func TestUserService_UpdateUser_Success(t *testing.T) {
 		},
 	}
 
-	s := NewUserService(&repoMock, &publisherMock)
+	s := NewUserService(&repoMock, nil, &publisherMock)
 
 	err := s.UpdateUser(context.TODO(), uuidMock, updateUser)
 
@@ -265,7 +277,7 @@ func TestUserService_UpdateUser_Fail(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			publisherMock := EventPublisherMock{}
 
-			s := NewUserService(tt.deps.repo, &publisherMock)
+			s := NewUserService(tt.deps.repo, nil, &publisherMock)
 
 			err := s.UpdateUser(context.TODO(), uuidMock, tt.args.updateUser)
 			tt.checkFn(t, err)
@@ -275,3 +287,289 @@ func TestUserService_UpdateUser_Fail(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_CreateUser_ServiceAccount(t *testing.T) {
+	guard := make(chan struct{})
+
+	repoMock := UserStorageMock{
+		InsertUserFunc: func(ctx context.Context, user models.User) (uuid.UUID, error) {
+			return user.ID, nil
+		},
+	}
+
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			guard <- struct{}{}
+			return nil
+		},
+	}
+
+	s := NewUserService(&repoMock, nil, &publisherMock)
+
+	_, err := s.CreateUser(context.TODO(), models.NewUser{
+		Type:    models.UserTypeServiceAccount,
+		Email:   "ci@mail.com",
+		Country: "GR",
+	})
+
+	<-guard
+
+	require.NoError(t, err)
+	require.Len(t, repoMock.InsertUserCalls(), 1)
+	require.Equal(t, models.UserTypeServiceAccount, repoMock.InsertUserCalls()[0].User.Type)
+	require.Nil(t, repoMock.InsertUserCalls()[0].User.Password)
+}
+
+func TestUserService_ChangeStatus(t *testing.T) {
+	uuidMock := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	tests := []struct {
+		name         string
+		current      models.UserStatus
+		changeFn     func(s *UserService) error
+		wantStatus   models.UserStatus
+		wantErr      error
+		publishCalls int
+	}{
+		{
+			name:    "suspend active user",
+			current: models.UserStatusActive,
+			changeFn: func(s *UserService) error {
+				return s.SuspendUser(context.TODO(), uuidMock, "spam")
+			},
+			wantStatus:   models.UserStatusSuspended,
+			publishCalls: 1,
+		},
+		{
+			name:    "reactivate suspended user",
+			current: models.UserStatusSuspended,
+			changeFn: func(s *UserService) error {
+				return s.ReactivateUser(context.TODO(), uuidMock, "appeal accepted")
+			},
+			wantStatus:   models.UserStatusActive,
+			publishCalls: 1,
+		},
+		{
+			name:    "activate pending user",
+			current: models.UserStatusPending,
+			changeFn: func(s *UserService) error {
+				return s.ReactivateUser(context.TODO(), uuidMock, "")
+			},
+			wantStatus:   models.UserStatusActive,
+			publishCalls: 1,
+		},
+		{
+			name:    "deactivate suspended user",
+			current: models.UserStatusSuspended,
+			changeFn: func(s *UserService) error {
+				return s.DeactivateUser(context.TODO(), uuidMock, "closed")
+			},
+			wantStatus:   models.UserStatusDeactivated,
+			publishCalls: 1,
+		},
+		{
+			name:    "suspend deactivated user",
+			current: models.UserStatusDeactivated,
+			changeFn: func(s *UserService) error {
+				return s.SuspendUser(context.TODO(), uuidMock, "spam")
+			},
+			wantErr: models.ErrInvalidStatusTransition,
+		},
+		{
+			name:    "reactivate active user",
+			current: models.UserStatusActive,
+			changeFn: func(s *UserService) error {
+				return s.ReactivateUser(context.TODO(), uuidMock, "")
+			},
+			wantErr: models.ErrInvalidStatusTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := make(chan struct{}, 1)
+
+			repoMock := UserStorageMock{
+				GetUserByIDFunc: func(ctx context.Context, userID uuid.UUID) (models.User, error) {
+					return models.User{ID: userID, Status: tt.current}, nil
+				},
+				UpdateUserStatusFunc: func(ctx context.Context, userID uuid.UUID, from models.UserStatus, status models.UserStatus, reason string, changedAt time.Time) error {
+					return nil
+				},
+			}
+
+			publisherMock := EventPublisherMock{
+				PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+					require.Equal(t, "UserStatusChanged", topic)
+					guard <- struct{}{}
+					return nil
+				},
+			}
+
+			s := NewUserService(&repoMock, nil, &publisherMock)
+
+			err := tt.changeFn(s)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Len(t, repoMock.UpdateUserStatusCalls(), 0)
+				return
+			}
+
+			<-guard
+
+			require.NoError(t, err)
+			require.Len(t, repoMock.UpdateUserStatusCalls(), 1)
+			require.Equal(t, tt.current, repoMock.UpdateUserStatusCalls()[0].From)
+			require.Equal(t, tt.wantStatus, repoMock.UpdateUserStatusCalls()[0].Status)
+			require.Len(t, publisherMock.PublishCalls(), tt.publishCalls)
+		})
+	}
+}
+
+func TestUserService_EraseUser(t *testing.T) {
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	repoMock := UserStorageMock{
+		EraseUserFunc: func(ctx context.Context, uID uuid.UUID) error {
+			return nil
+		},
+	}
+
+	done := make(chan struct{}, len(userKeyedTopics))
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			return nil
+		},
+		PublishTombstoneFunc: func(ctx context.Context, topic string, key string) error {
+			done <- struct{}{}
+			return nil
+		},
+	}
+
+	s := NewUserService(&repoMock, nil, &publisherMock)
+
+	err := s.EraseUser(context.TODO(), userID)
+	require.NoError(t, err)
+
+	for range userKeyedTopics {
+		<-done
+	}
+
+	require.Len(t, publisherMock.PublishCalls(), 1)
+	require.Equal(t, "UserErased", publisherMock.PublishCalls()[0].Topic)
+
+	tombstones := publisherMock.PublishTombstoneCalls()
+	require.Len(t, tombstones, len(userKeyedTopics))
+	for i, c := range tombstones {
+		require.Equal(t, userKeyedTopics[i], c.Topic)
+		require.Equal(t, userID.String(), c.Key)
+	}
+
+	t.Log("user not found")
+	{
+		repoMock.EraseUserFunc = func(ctx context.Context, uID uuid.UUID) error {
+			return models.ErrUserNotFound
+		}
+
+		err := s.EraseUser(context.TODO(), userID)
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+		require.Len(t, publisherMock.PublishCalls(), 1)
+	}
+}
+
+func TestUserService_RestoreUser(t *testing.T) {
+	userID := uuid.MustParse("d79b55a7-0ab9-4a54-b5f7-33f56f9f16f5")
+
+	repoMock := UserStorageMock{
+		RestoreUserFunc: func(ctx context.Context, uID uuid.UUID, restoredAt time.Time) error {
+			return nil
+		},
+	}
+
+	guard := make(chan struct{}, 1)
+	publisherMock := EventPublisherMock{
+		PublishFunc: func(ctx context.Context, topic string, key string, pbMessage protoreflect.ProtoMessage) error {
+			guard <- struct{}{}
+			return nil
+		},
+	}
+
+	s := NewUserService(&repoMock, nil, &publisherMock)
+
+	err := s.RestoreUser(context.TODO(), userID)
+	require.NoError(t, err)
+
+	<-guard
+
+	require.Len(t, publisherMock.PublishCalls(), 1)
+	require.Equal(t, "UserRestored", publisherMock.PublishCalls()[0].Topic)
+	require.Equal(t, userID.String(), publisherMock.PublishCalls()[0].Key)
+
+	t.Log("user not found")
+	{
+		repoMock.RestoreUserFunc = func(ctx context.Context, uID uuid.UUID, restoredAt time.Time) error {
+			return models.ErrUserNotFound
+		}
+
+		err := s.RestoreUser(context.TODO(), userID)
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+		require.Len(t, publisherMock.PublishCalls(), 1)
+	}
+}
+
+func TestUserService_ListUsers(t *testing.T) {
+	now := time.Now().UTC()
+	stored := []models.User{
+		{ID: uuid.New(), CreatedAt: now},
+		{ID: uuid.New(), CreatedAt: now.Add(time.Second)},
+		{ID: uuid.New(), CreatedAt: now.Add(2 * time.Second)},
+	}
+
+	tests := []struct {
+		name       string
+		pageSize   uint64
+		after      *models.UserCursor
+		wantUsers  int
+		wantCursor *models.UserCursor
+	}{
+		{
+			name:       "more pages",
+			pageSize:   2,
+			wantUsers:  2,
+			wantCursor: &models.UserCursor{CreatedAt: stored[1].CreatedAt, ID: stored[1].ID},
+		},
+		{
+			name:       "more pages after a cursor",
+			pageSize:   1,
+			after:      &models.UserCursor{CreatedAt: now.Add(-time.Second), ID: uuid.New()},
+			wantUsers:  1,
+			wantCursor: &models.UserCursor{CreatedAt: stored[0].CreatedAt, ID: stored[0].ID},
+		},
+		{
+			name:      "last page",
+			pageSize:  3,
+			wantUsers: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := UserStorageMock{
+				GetUsersByFilterFunc: func(ctx context.Context, opts models.GetUsersOptions) ([]models.User, error) {
+					require.Equal(t, tt.pageSize+1, opts.PageSize)
+					if opts.PageSize > uint64(len(stored)) {
+						return stored, nil
+					}
+					return stored[:opts.PageSize], nil
+				},
+			}
+
+			s := NewUserService(&repoMock, nil, nil)
+
+			users, cursor, err := s.ListUsers(context.TODO(), models.GetUsersOptions{PageNumber: 1, PageSize: tt.pageSize, After: tt.after})
+			require.NoError(t, err)
+			require.Len(t, users, tt.wantUsers)
+			require.Equal(t, tt.wantCursor, cursor)
+		})
+	}
+}
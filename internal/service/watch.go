@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	// 3rd party
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+//go:generate moq -out change_storage_mock_test.go . ChangeStorage
+type ChangeStorage interface {
+	LatestUserChange(ctx context.Context) (models.ChangeCursor, error)
+	GetUserChanges(ctx context.Context, opts models.GetUsersOptions, after models.ChangeCursor, limit uint64) ([]models.UserChange, models.ChangeCursor, error)
+	CountUserChanges(ctx context.Context, after models.ChangeCursor, limit uint64) (int64, error)
+	PurgeUserChanges(ctx context.Context, changedBefore time.Time) (int64, error)
+}
+
+// ChangeNotifier wakes subscribers up when users change.
+//
+//go:generate moq -out change_notifier_mock_test.go . ChangeNotifier
+type ChangeNotifier interface {
+	// Subscribe returns a channel receiving a value whenever users changed,
+	// and a function ending the subscription.
+	Subscribe() (<-chan struct{}, func())
+}
+
+// WatchConfig controls the streams of changes to users.
+type WatchConfig struct {
+	// Retention is how long changes are kept, and so how long a watch can be
+	// resumed for.
+	Retention time.Duration
+	// MaxLag is the number of changes a watch may fall behind before it is
+	// ended; it can then be resumed.
+	MaxLag int64
+	// PollInterval is the time after which changes are read even without
+	// notification, in case one was missed.
+	PollInterval time.Duration
+	// CheckpointInterval is the time between two checkpoints sent to a watch
+	// whose filter skips changes.
+	CheckpointInterval time.Duration
+}
+
+// watchBatchSize is the number of changes read at a time.
+const watchBatchSize = 100
+
+// WatchService streams the changes to users, as they are committed.
+type WatchService struct {
+	changes  ChangeStorage
+	notifier ChangeNotifier
+	users    *UserService
+	cfg      WatchConfig
+	logger   *zap.SugaredLogger
+}
+
+func NewWatchService(changes ChangeStorage, notifier ChangeNotifier, users *UserService, cfg WatchConfig, logger *zap.SugaredLogger) *WatchService {
+	return &WatchService{
+		changes:  changes,
+		notifier: notifier,
+		users:    users,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// WatchUsers calls fn with every change to users matching the filter of qu,
+// in order, until ctx is cancelled or fn fails. Changes follow the cursor
+// after, or are those to come when it is nil. When the filter skipped changes
+// for a while, fn receives a UserChangeCheckpoint to resume from.
+//
+// Changes are read from storage at the pace of fn, so a slow subscriber holds
+// no more than a batch of them; one falling more than MaxLag changes behind
+// is ended with ErrWatchLagging.
+func (wSvc *WatchService) WatchUsers(ctx context.Context, qu models.GetUsersOptions, after *models.ChangeCursor, fn func(models.UserChange) error) error {
+	qu, err := wSvc.users.normalizeQuery(ctx, qu)
+	if err != nil {
+		return err
+	}
+
+	// Subscribe before reading, so that no change falls in between.
+	wake, unsubscribe := wSvc.notifier.Subscribe()
+	defer unsubscribe()
+
+	var cursor models.ChangeCursor
+	if after != nil {
+		if after.ChangedAt.Before(time.Now().Add(-wSvc.cfg.Retention)) {
+			return models.ErrResumeTokenExpired
+		}
+		cursor = *after
+	} else if cursor, err = wSvc.changes.LatestUserChange(ctx); err != nil {
+		return err
+	}
+
+	poll := time.NewTicker(wSvc.cfg.PollInterval)
+	defer poll.Stop()
+
+	sent := cursor
+	lastSent := time.Now()
+	for {
+		for {
+			changes, last, err := wSvc.changes.GetUserChanges(ctx, qu, cursor, watchBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, c := range changes {
+				if err := fn(c); err != nil {
+					return err
+				}
+				sent, lastSent = c.Cursor, time.Now()
+			}
+
+			if last.ID == cursor.ID {
+				break
+			}
+			cursor = last
+
+			// Change ids do not follow the order changes are read in, so
+			// the changes left to read are counted.
+			unread, err := wSvc.changes.CountUserChanges(ctx, cursor, uint64(wSvc.cfg.MaxLag)+1)
+			if err != nil {
+				return err
+			}
+			if unread > wSvc.cfg.MaxLag {
+				return models.ErrWatchLagging
+			}
+		}
+
+		if cursor.ID != sent.ID && time.Since(lastSent) >= wSvc.cfg.CheckpointInterval {
+			if err := fn(models.UserChange{Cursor: cursor, Kind: models.UserChangeCheckpoint}); err != nil {
+				return err
+			}
+			sent, lastSent = cursor, time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		case <-poll.C:
+		}
+	}
+}
+
+// PurgeChanges removes the changes past the retention, every interval, until
+// ctx is cancelled.
+func (wSvc *WatchService) PurgeChanges(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			n, err := wSvc.changes.PurgeUserChanges(ctx, time.Now().UTC().Add(-wSvc.cfg.Retention))
+			if err != nil {
+				wSvc.logger.Errorw("purge user changes", "error", err)
+				continue
+			}
+			if n > 0 {
+				wSvc.logger.Infow("purge user changes", "purged", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestWatchService_WatchUsers(t *testing.T) {
+	start := time.Now().UTC()
+	userID := uuid.New()
+
+	cfg := WatchConfig{
+		Retention:          time.Hour,
+		MaxLag:             1000,
+		PollInterval:       time.Hour,
+		CheckpointInterval: 0,
+	}
+
+	// The storage holds changes 1 to 5; only the third matches the filter.
+	newStorage := func() *ChangeStorageMock {
+		return &ChangeStorageMock{
+			LatestUserChangeFunc: func(ctx context.Context) (models.ChangeCursor, error) {
+				return models.ChangeCursor{ID: 2, ChangedAt: start}, nil
+			},
+			GetUserChangesFunc: func(ctx context.Context, opts models.GetUsersOptions, after models.ChangeCursor, limit uint64) ([]models.UserChange, models.ChangeCursor, error) {
+				require.Equal(t, "GR", opts.Filter.Country)
+				if after.ID >= 5 {
+					return nil, after, nil
+				}
+
+				id := after.ID + 1
+				cursor := models.ChangeCursor{ID: id, ChangedAt: start}
+				if id != 3 {
+					return nil, cursor, nil
+				}
+				return []models.UserChange{{Cursor: cursor, Kind: models.UserChangeUpdated, User: models.User{ID: userID}}}, cursor, nil
+			},
+			CountUserChangesFunc: func(ctx context.Context, after models.ChangeCursor, limit uint64) (int64, error) {
+				require.Equal(t, uint64(cfg.MaxLag)+1, limit)
+				return 5 - after.ID, nil
+			},
+		}
+	}
+
+	wake := make(chan struct{}, 1)
+	notifier := &ChangeNotifierMock{
+		SubscribeFunc: func() (<-chan struct{}, func()) {
+			return wake, func() {}
+		},
+	}
+
+	qu := models.GetUsersOptions{}
+	qu.Filter.Country = "GR"
+
+	stop := errors.New("stop")
+
+	t.Log("from now on")
+	{
+		s := NewWatchService(newStorage(), notifier, NewUserService(nil, nil, nil), cfg, zap.NewNop().Sugar())
+
+		var got []models.UserChange
+		err := s.WatchUsers(context.TODO(), qu, nil, func(c models.UserChange) error {
+			got = append(got, c)
+			if len(got) == 2 {
+				return stop
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, stop)
+
+		require.Equal(t, int64(3), got[0].Cursor.ID)
+		require.Equal(t, models.UserChangeUpdated, got[0].Kind)
+		require.Equal(t, userID, got[0].User.ID)
+		require.Equal(t, models.UserChangeCheckpoint, got[1].Kind)
+		require.Equal(t, int64(5), got[1].Cursor.ID)
+	}
+
+	t.Log("resumed")
+	{
+		storage := newStorage()
+		s := NewWatchService(storage, notifier, NewUserService(nil, nil, nil), cfg, zap.NewNop().Sugar())
+
+		err := s.WatchUsers(context.TODO(), qu, &models.ChangeCursor{ID: 1, ChangedAt: start}, func(c models.UserChange) error {
+			require.Equal(t, int64(3), c.Cursor.ID)
+			return stop
+		})
+		require.ErrorIs(t, err, stop)
+		require.Len(t, storage.LatestUserChangeCalls(), 0)
+	}
+
+	t.Log("resume token past the retention")
+	{
+		s := NewWatchService(newStorage(), notifier, NewUserService(nil, nil, nil), cfg, zap.NewNop().Sugar())
+
+		err := s.WatchUsers(context.TODO(), qu, &models.ChangeCursor{ID: 1, ChangedAt: start.Add(-2 * time.Hour)}, func(c models.UserChange) error {
+			return nil
+		})
+		require.ErrorIs(t, err, models.ErrResumeTokenExpired)
+	}
+
+	t.Log("lagging")
+	{
+		storage := newStorage()
+		storage.CountUserChangesFunc = func(ctx context.Context, after models.ChangeCursor, limit uint64) (int64, error) {
+			return int64(limit), nil
+		}
+		s := NewWatchService(storage, notifier, NewUserService(nil, nil, nil), cfg, zap.NewNop().Sugar())
+
+		err := s.WatchUsers(context.TODO(), qu, nil, func(c models.UserChange) error {
+			return nil
+		})
+		require.ErrorIs(t, err, models.ErrWatchLagging)
+	}
+
+	t.Log("woken up by notifications")
+	{
+		storage := newStorage()
+		storage.LatestUserChangeFunc = func(ctx context.Context) (models.ChangeCursor, error) {
+			return models.ChangeCursor{ID: 5, ChangedAt: start}, nil
+		}
+		s := NewWatchService(storage, notifier, NewUserService(nil, nil, nil), cfg, zap.NewNop().Sugar())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		wake <- struct{}{}
+		go func() {
+			for len(storage.GetUserChangesCalls()) < 2 {
+				time.Sleep(time.Millisecond)
+			}
+			cancel()
+		}()
+
+		err := s.WatchUsers(ctx, qu, nil, func(c models.UserChange) error {
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	}
+}
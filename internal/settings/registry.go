@@ -0,0 +1,152 @@
+// Package settings validates user settings against the schemas registered for
+// their namespace.
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+// namePattern is the format of namespaces and keys.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// Registry holds the schemas of the namespaces that are validated. The schema
+// of a namespace describes an object whose properties are the setting keys;
+// the default of a property is the default value of the key. Namespaces
+// without a schema accept any key and any JSON value.
+type Registry struct {
+	namespaces map[string]*Schema
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		namespaces: make(map[string]*Schema),
+	}
+}
+
+// LoadDir registers every <namespace>.json schema file of the directory.
+func LoadDir(dir string) (*Registry, error) {
+	r := NewRegistry()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		schema, err := ParseSchema(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		namespace := strings.TrimSuffix(filepath.Base(file), ".json")
+		if err := r.Register(namespace, schema); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return r, nil
+}
+
+// Register sets the schema of the namespace. Defaults must match the schema
+// of their key.
+func (r *Registry) Register(namespace string, schema *Schema) error {
+	if !namePattern.MatchString(namespace) {
+		return fmt.Errorf("invalid namespace %q", namespace)
+	}
+
+	for key, prop := range schema.Properties {
+		if prop == nil || len(prop.Default) == 0 {
+			continue
+		}
+		if err := prop.Validate(prop.Default); err != nil {
+			return fmt.Errorf("default of %q: %w", key, err)
+		}
+	}
+
+	r.namespaces[namespace] = schema
+	return nil
+}
+
+// ValidateNamespace checks the format of the namespace.
+func ValidateNamespace(namespace string) error {
+	if !namePattern.MatchString(namespace) {
+		return fmt.Errorf("%w: namespace %q", models.ErrInvalidSettingKey, namespace)
+	}
+	return nil
+}
+
+// ValidateKey checks the format of the namespace and the key.
+func ValidateKey(namespace string, key string) error {
+	if err := ValidateNamespace(namespace); err != nil {
+		return err
+	}
+
+	if !namePattern.MatchString(key) {
+		return fmt.Errorf("%w: key %q", models.ErrInvalidSettingKey, key)
+	}
+
+	return nil
+}
+
+// Validate checks that the key is allowed in the namespace and that the JSON
+// encoded value matches its schema.
+func (r *Registry) Validate(namespace string, key string, value []byte) error {
+	if err := ValidateKey(namespace, key); err != nil {
+		return err
+	}
+
+	if !json.Valid(value) {
+		return fmt.Errorf("%w: %s.%s: not a JSON value", models.ErrInvalidSettingValue, namespace, key)
+	}
+
+	schema, ok := r.namespaces[namespace]
+	if !ok {
+		return nil
+	}
+
+	prop, ok := schema.Property(key)
+	if !ok {
+		return fmt.Errorf("%w: unknown key %s.%s", models.ErrInvalidSettingKey, namespace, key)
+	}
+
+	if prop == nil {
+		return nil
+	}
+
+	if err := prop.Validate(value); err != nil {
+		return fmt.Errorf("%w: %s.%s: %v", models.ErrInvalidSettingValue, namespace, key, err)
+	}
+
+	return nil
+}
+
+// Defaults returns the default values of the keys of the namespace.
+func (r *Registry) Defaults(namespace string) map[string]json.RawMessage {
+	defaults := make(map[string]json.RawMessage)
+
+	schema, ok := r.namespaces[namespace]
+	if !ok {
+		return defaults
+	}
+
+	for key, prop := range schema.Properties {
+		if prop != nil && len(prop.Default) > 0 {
+			defaults[key] = prop.Default
+		}
+	}
+
+	return defaults
+}
@@ -0,0 +1,68 @@
+package settings
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+const uiSchema = `{
+	"type": "object",
+	"properties": {
+		"theme": {"type": "string", "enum": ["light", "dark"], "default": "light"},
+		"sidebar": {"type": "boolean"}
+	},
+	"additionalProperties": false
+}`
+
+func TestRegistry_Validate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ui.json"), []byte(uiSchema), 0o600))
+
+	r, err := LoadDir(dir)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		namespace string
+		key       string
+		value     string
+		err       error
+	}{
+		{name: "valid", namespace: "ui", key: "theme", value: `"dark"`},
+		{name: "invalid value", namespace: "ui", key: "theme", value: `"blue"`, err: models.ErrInvalidSettingValue},
+		{name: "unknown key", namespace: "ui", key: "colour", value: `"red"`, err: models.ErrInvalidSettingKey},
+		{name: "unregistered namespace", namespace: "mobile", key: "anything", value: `{"a":[1,2]}`},
+		{name: "not json", namespace: "mobile", key: "anything", value: `{`, err: models.ErrInvalidSettingValue},
+		{name: "invalid namespace", namespace: "UI", key: "theme", value: `"dark"`, err: models.ErrInvalidSettingKey},
+		{name: "invalid key", namespace: "ui", key: "", value: `"dark"`, err: models.ErrInvalidSettingKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Validate(tt.namespace, tt.key, []byte(tt.value))
+			if tt.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+
+	require.Equal(t, map[string]json.RawMessage{"theme": json.RawMessage(`"light"`)}, r.Defaults("ui"))
+	require.Empty(t, r.Defaults("mobile"))
+}
+
+func TestRegistry_Register_InvalidDefault(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{"properties":{"theme":{"enum":["light","dark"],"default":"blue"}}}`))
+	require.NoError(t, err)
+
+	require.Error(t, NewRegistry().Register("ui", schema))
+}
@@ -0,0 +1,302 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Schema is the subset of JSON Schema used to describe settings: type, enum,
+// const, numeric and length bounds, pattern, items, properties, required and
+// additionalProperties. Other keywords are accepted and ignored.
+type Schema struct {
+	Type                 schemaTypes        `json:"type"`
+	Enum                 []json.RawMessage  `json:"enum"`
+	Const                json.RawMessage    `json:"const"`
+	Default              json.RawMessage    `json:"default"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	Items                *Schema            `json:"items"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *additional        `json:"additionalProperties"`
+
+	pattern *regexp.Regexp
+}
+
+// ParseSchema parses and compiles a schema.
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing schema: %w", err)
+	}
+
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (s *Schema) compile() error {
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("compiling pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+
+	children := make([]*Schema, 0, len(s.Properties)+2)
+	children = append(children, s.Items)
+	for _, p := range s.Properties {
+		children = append(children, p)
+	}
+	if s.AdditionalProperties != nil {
+		children = append(children, s.AdditionalProperties.schema)
+	}
+
+	for _, c := range children {
+		if c == nil {
+			continue
+		}
+		if err := c.compile(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the JSON encoded value against the schema.
+func (s *Schema) Validate(data []byte) error {
+	v, err := decode(data)
+	if err != nil {
+		return err
+	}
+
+	return s.validate("", v)
+}
+
+func (s *Schema) validate(path string, v any) error {
+	if len(s.Type) > 0 && !s.Type.matches(v) {
+		return fmt.Errorf("%s: must be of type %s", pathOrRoot(path), s.Type)
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		return fmt.Errorf("%s: must be one of the allowed values", pathOrRoot(path))
+	}
+
+	if len(s.Const) > 0 && !inEnum([]json.RawMessage{s.Const}, v) {
+		return fmt.Errorf("%s: must be equal to the constant", pathOrRoot(path))
+	}
+
+	switch val := v.(type) {
+	case json.Number:
+		f, err := val.Float64()
+		if err != nil {
+			return fmt.Errorf("%s: %v", pathOrRoot(path), err)
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return fmt.Errorf("%s: must be at least %v", pathOrRoot(path), *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return fmt.Errorf("%s: must be at most %v", pathOrRoot(path), *s.Maximum)
+		}
+
+	case string:
+		n := utf8.RuneCountInString(val)
+		if s.MinLength != nil && n < *s.MinLength {
+			return fmt.Errorf("%s: must be at least %d characters", pathOrRoot(path), *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return fmt.Errorf("%s: must be at most %d characters", pathOrRoot(path), *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(val) {
+			return fmt.Errorf("%s: must match %q", pathOrRoot(path), s.Pattern)
+		}
+
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			return fmt.Errorf("%s: must have at least %d items", pathOrRoot(path), *s.MinItems)
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			return fmt.Errorf("%s: must have at most %d items", pathOrRoot(path), *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range val {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				return fmt.Errorf("%s: missing property %q", pathOrRoot(path), name)
+			}
+		}
+		for name, prop := range val {
+			child, ok := s.Property(name)
+			if !ok {
+				return fmt.Errorf("%s: unknown property %q", pathOrRoot(path), name)
+			}
+			if child == nil {
+				continue
+			}
+			if err := child.validate(joinPath(path, name), prop); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Property returns the schema of the named property of an object. It reports
+// false when the property is not allowed; a nil schema allows any value.
+func (s *Schema) Property(name string) (*Schema, bool) {
+	if p, ok := s.Properties[name]; ok {
+		return p, true
+	}
+
+	if s.AdditionalProperties == nil {
+		return nil, true
+	}
+
+	return s.AdditionalProperties.schema, s.AdditionalProperties.allowed
+}
+
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if dec.More() {
+		return nil, errors.New("trailing data after value")
+	}
+
+	return v, nil
+}
+
+func inEnum(enum []json.RawMessage, v any) bool {
+	for _, e := range enum {
+		ev, err := decode(e)
+		if err != nil {
+			continue
+		}
+		if reflect.DeepEqual(ev, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "value"
+	}
+	return path
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// schemaTypes holds the "type" keyword, given either as a string or a list.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = schemaTypes{one}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type must be a string or a list of strings")
+	}
+	*t = many
+	return nil
+}
+
+func (t schemaTypes) String() string {
+	if len(t) == 1 {
+		return t[0]
+	}
+	return fmt.Sprint([]string(t))
+}
+
+func (t schemaTypes) matches(v any) bool {
+	for _, name := range t {
+		if typeMatches(name, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeMatches(name string, v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return name == "null"
+	case bool:
+		return name == "boolean"
+	case string:
+		return name == "string"
+	case []any:
+		return name == "array"
+	case map[string]any:
+		return name == "object"
+	case json.Number:
+		if name == "number" {
+			return true
+		}
+		if name == "integer" {
+			_, err := val.Int64()
+			return err == nil
+		}
+	}
+	return false
+}
+
+// additional holds the "additionalProperties" keyword, given either as a
+// boolean or a schema.
+type additional struct {
+	allowed bool
+	schema  *Schema
+}
+
+func (a *additional) UnmarshalJSON(data []byte) error {
+	var allowed bool
+	if err := json.Unmarshal(data, &allowed); err == nil {
+		a.allowed = allowed
+		return nil
+	}
+
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	a.allowed = true
+	a.schema = &s
+	return nil
+}
@@ -0,0 +1,59 @@
+package settings
+
+import (
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"theme": {"type": "string", "enum": ["light", "dark"]},
+			"font_size": {"type": "integer", "minimum": 8, "maximum": 32},
+			"nickname": {"type": ["string", "null"], "maxLength": 5, "pattern": "^[a-z]*$"},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2}
+		},
+		"required": ["theme"],
+		"additionalProperties": false
+	}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "valid", value: `{"theme":"dark","font_size":12,"nickname":null,"tags":["a"]}`, valid: true},
+		{name: "wrong type", value: `"dark"`},
+		{name: "not in enum", value: `{"theme":"blue"}`},
+		{name: "missing required", value: `{"font_size":12}`},
+		{name: "not an integer", value: `{"theme":"dark","font_size":12.5}`},
+		{name: "below minimum", value: `{"theme":"dark","font_size":4}`},
+		{name: "too long", value: `{"theme":"dark","nickname":"batman"}`},
+		{name: "pattern mismatch", value: `{"theme":"dark","nickname":"Bat"}`},
+		{name: "too many items", value: `{"theme":"dark","tags":["a","b","c"]}`},
+		{name: "wrong item type", value: `{"theme":"dark","tags":[1]}`},
+		{name: "unknown property", value: `{"theme":"dark","colour":"red"}`},
+		{name: "malformed", value: `{"theme":`},
+		{name: "trailing data", value: `{"theme":"dark"} {}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.value))
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestParseSchema_InvalidPattern(t *testing.T) {
+	_, err := ParseSchema([]byte(`{"properties":{"a":{"pattern":"("}}}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS "groups" (
+    id                  UUID PRIMARY KEY,
+    name                VARCHAR(255) NOT NULL UNIQUE,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS "group_members" (
+    group_id            UUID NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    user_id             UUID NOT NULL REFERENCES users (id),
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS group_members_user_id_idx ON group_members (user_id);
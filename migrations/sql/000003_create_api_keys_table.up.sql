@@ -0,0 +1,16 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS type VARCHAR(32) NOT NULL DEFAULT 'human';
+
+CREATE TABLE IF NOT EXISTS "api_keys" (
+    id                  UUID PRIMARY KEY,
+    user_id             UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name                VARCHAR(255) NOT NULL,
+    prefix              VARCHAR(32) NOT NULL UNIQUE,
+    hash                BYTEA NOT NULL,
+    scopes              TEXT[] NOT NULL DEFAULT '{}',
+    expires_at          TIMESTAMPTZ,
+    last_used_at        TIMESTAMPTZ,
+    revoked_at          TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys (user_id);
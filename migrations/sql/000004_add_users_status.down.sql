@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS users_status_idx;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status;
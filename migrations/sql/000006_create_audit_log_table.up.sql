@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS "audit_log" (
+    id                  BIGSERIAL PRIMARY KEY,
+    user_id             UUID NOT NULL,
+    actor               VARCHAR(255) NOT NULL,
+    action              VARCHAR(64) NOT NULL,
+    old_values          JSONB,
+    new_values          JSONB,
+    request_id          VARCHAR(255) NOT NULL DEFAULT '',
+    client_ip           VARCHAR(64) NOT NULL DEFAULT '',
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_user_id_idx ON audit_log (user_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor, id);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+
+-- The audit log is append-only: entries outlive the users they refer to and are never changed.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
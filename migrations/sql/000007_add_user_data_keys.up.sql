@@ -0,0 +1,26 @@
+-- Every user has a data key, stored wrapped by the master key. PII that cannot be deleted,
+-- such as audit history, is encrypted with it so that destroying the key erases the data.
+CREATE TABLE IF NOT EXISTS "user_data_keys" (
+    user_id             UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    wrapped_key         BYTEA NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS encrypted_values BYTEA;
+
+-- Entries stay append-only, except that the plaintext values of an erased user may be cleared.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND NEW.old_values IS NULL
+        AND NEW.new_values IS NULL
+        AND (NEW.id, NEW.user_id, NEW.actor, NEW.action, NEW.request_id, NEW.client_ip, NEW.created_at, NEW.encrypted_values)
+            IS NOT DISTINCT FROM
+            (OLD.id, OLD.user_id, OLD.actor, OLD.action, OLD.request_id, OLD.client_ip, OLD.created_at, OLD.encrypted_values)
+    THEN
+        RETURN NEW;
+    END IF;
+
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
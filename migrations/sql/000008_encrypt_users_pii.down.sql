@@ -0,0 +1,30 @@
+-- Encrypted values cannot be decrypted here, so refuse to drop them.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users WHERE email IS NULL) THEN
+        RAISE EXCEPTION 'users hold encrypted PII that would be lost';
+    END IF;
+END;
+$$;
+
+DROP INDEX IF EXISTS users_country_index_idx;
+DROP INDEX IF EXISTS users_email_index_live_idx;
+
+ALTER TABLE users
+    ALTER COLUMN email SET NOT NULL,
+    ALTER COLUMN first_name SET NOT NULL,
+    ALTER COLUMN last_name SET NOT NULL,
+    ALTER COLUMN country SET NOT NULL;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email_ciphertext,
+    DROP COLUMN IF EXISTS first_name_ciphertext,
+    DROP COLUMN IF EXISTS last_name_ciphertext,
+    DROP COLUMN IF EXISTS country_ciphertext,
+    DROP COLUMN IF EXISTS email_index,
+    DROP COLUMN IF EXISTS country_index;
+
+ALTER TABLE user_data_keys ALTER CONSTRAINT user_data_keys_user_id_fkey NOT DEFERRABLE;
+
+DROP INDEX IF EXISTS user_data_keys_master_key_id_idx;
+ALTER TABLE user_data_keys DROP COLUMN IF EXISTS master_key_id;
@@ -0,0 +1,27 @@
+-- Data keys record the master key that wrapped them, so that the master key can be rotated.
+-- Keys wrapped before ids were recorded have an empty id.
+ALTER TABLE user_data_keys ADD COLUMN IF NOT EXISTS master_key_id VARCHAR(32) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS user_data_keys_master_key_id_idx ON user_data_keys (master_key_id);
+
+-- The data key is created before the user row in the same transaction.
+ALTER TABLE user_data_keys ALTER CONSTRAINT user_data_keys_user_id_fkey DEFERRABLE INITIALLY DEFERRED;
+
+-- PII is encrypted with the data key of the user. The plaintext columns are kept for rows
+-- written before encryption, until they are encrypted by the rotate-keys command.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email_ciphertext BYTEA,
+    ADD COLUMN IF NOT EXISTS first_name_ciphertext BYTEA,
+    ADD COLUMN IF NOT EXISTS last_name_ciphertext BYTEA,
+    ADD COLUMN IF NOT EXISTS country_ciphertext BYTEA,
+    ADD COLUMN IF NOT EXISTS email_index BYTEA,
+    ADD COLUMN IF NOT EXISTS country_index BYTEA;
+
+ALTER TABLE users
+    ALTER COLUMN email DROP NOT NULL,
+    ALTER COLUMN first_name DROP NOT NULL,
+    ALTER COLUMN last_name DROP NOT NULL,
+    ALTER COLUMN country DROP NOT NULL;
+
+-- Blind indexes keep equality lookups and the uniqueness of live emails working.
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_index_live_idx ON users (email_index) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS users_country_index_idx ON users (country_index);
@@ -0,0 +1,14 @@
+-- Consents are never updated; every grant and withdrawal is a new row, and the
+-- latest row of a purpose holds its current status.
+CREATE TABLE IF NOT EXISTS "consents" (
+    id                  BIGSERIAL PRIMARY KEY,
+    user_id             UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose             VARCHAR(255) NOT NULL,
+    status              VARCHAR(32) NOT NULL,
+    source              VARCHAR(255) NOT NULL DEFAULT '',
+    policy_version      VARCHAR(64) NOT NULL DEFAULT '',
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS consents_user_id_purpose_idx ON consents (user_id, purpose, id DESC);
+CREATE INDEX IF NOT EXISTS consents_purpose_idx ON consents (purpose);
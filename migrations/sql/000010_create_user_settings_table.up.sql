@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS "user_settings" (
+    user_id             UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    namespace           VARCHAR(64) NOT NULL,
+    key                 VARCHAR(64) NOT NULL,
+    value               JSONB NOT NULL,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, namespace, key)
+);
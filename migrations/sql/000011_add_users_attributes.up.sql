@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS "attribute_definitions" (
+    name                VARCHAR(64) PRIMARY KEY,
+    type                VARCHAR(16) NOT NULL,
+    enum_values         TEXT[] NOT NULL DEFAULT '{}',
+    description         TEXT NOT NULL DEFAULT '',
+    indexed             BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+-- Serves the containment (@>) lookups of filters on indexed attributes.
+CREATE INDEX IF NOT EXISTS users_attributes_idx ON users USING GIN (attributes jsonb_path_ops);
@@ -0,0 +1,39 @@
+-- Email addresses and phone numbers are encrypted like the PII of users, with a blind index
+-- for lookups and uniqueness. deleted_at follows the user, so that the addresses of soft
+-- deleted users can be taken by others.
+CREATE TABLE IF NOT EXISTS "user_emails" (
+    user_id             UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email_ciphertext    BYTEA NOT NULL,
+    email_index         BYTEA NOT NULL,
+    is_primary          BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_at         TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at          TIMESTAMPTZ,
+    PRIMARY KEY (user_id, email_index)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_emails_email_index_live_idx ON user_emails (email_index) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS user_emails_primary_idx ON user_emails (user_id) WHERE is_primary;
+
+CREATE TABLE IF NOT EXISTS "user_phones" (
+    user_id             UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    phone_ciphertext    BYTEA NOT NULL,
+    phone_index         BYTEA NOT NULL,
+    is_primary          BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_at         TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at          TIMESTAMPTZ,
+    PRIMARY KEY (user_id, phone_index)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_phones_phone_index_live_idx ON user_phones (phone_index) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS user_phones_primary_idx ON user_phones (user_id) WHERE is_primary;
+
+-- The primary address of encrypted users is the email of the user; both use the same
+-- associated data, so the ciphertext is copied as is. Users still stored in plaintext
+-- get theirs when the rotate-keys command encrypts them.
+INSERT INTO user_emails (user_id, email_ciphertext, email_index, is_primary, created_at, deleted_at)
+SELECT id, email_ciphertext, email_index, TRUE, created_at, deleted_at
+FROM users
+WHERE email_ciphertext IS NOT NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+-- Claims are encrypted with the data key of the user.
+CREATE TABLE IF NOT EXISTS "external_identities" (
+    provider            VARCHAR(64) NOT NULL,
+    subject             VARCHAR(255) NOT NULL,
+    user_id             UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    claims_ciphertext   BYTEA,
+    linked_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS external_identities_user_id_idx ON external_identities (user_id);
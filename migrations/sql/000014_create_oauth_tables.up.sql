@@ -0,0 +1,23 @@
+-- Public clients have no secret.
+CREATE TABLE IF NOT EXISTS "oauth_clients" (
+    id                  UUID PRIMARY KEY,
+    name                VARCHAR(255) NOT NULL,
+    secret_hash         BYTEA,
+    redirect_uris       TEXT[] NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Codes are single use: they are deleted when exchanged for tokens.
+CREATE TABLE IF NOT EXISTS "oauth_authorization_codes" (
+    code_hash           BYTEA PRIMARY KEY,
+    client_id           UUID NOT NULL REFERENCES oauth_clients (id) ON DELETE CASCADE,
+    user_id             UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    redirect_uri        TEXT NOT NULL,
+    scopes              TEXT[] NOT NULL DEFAULT '{}',
+    nonce               TEXT NOT NULL DEFAULT '',
+    code_challenge      VARCHAR(128) NOT NULL,
+    auth_time           TIMESTAMPTZ NOT NULL,
+    expires_at          TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS oauth_authorization_codes_expires_at_idx ON oauth_authorization_codes (expires_at);
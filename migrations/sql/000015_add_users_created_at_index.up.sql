@@ -0,0 +1,2 @@
+-- Users are listed in (created_at, id) order, which keyset pagination relies on.
+CREATE INDEX IF NOT EXISTS users_created_at_id_idx ON users (created_at, id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS users_search_tokens_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS search_tokens;
+DROP INDEX IF EXISTS users_nickname_trgm_idx;
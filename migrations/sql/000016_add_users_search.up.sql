@@ -0,0 +1,10 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Nicknames are stored in plaintext and matched by trigram similarity.
+CREATE INDEX IF NOT EXISTS users_nickname_trgm_idx ON users USING GIN (nickname gin_trgm_ops);
+
+-- Encrypted PII is searched through blind tokens: HMACs of the words and trigrams of the
+-- email (weight A) and of the first and last name (weight B). Rows written before the
+-- tokens are indexed by the rotate-keys command.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_tokens TSVECTOR;
+CREATE INDEX IF NOT EXISTS users_search_tokens_idx ON users USING GIN (search_tokens);
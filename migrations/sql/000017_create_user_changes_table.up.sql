@@ -0,0 +1,11 @@
+-- Outbox of the changes to users, read by WatchUsers. Rows are written in the transaction
+-- of the change, which notifies the user_changes channel on commit, and are removed once
+-- past the watch retention.
+CREATE TABLE IF NOT EXISTS "user_changes" (
+    id                  BIGSERIAL PRIMARY KEY,
+    user_id             UUID NOT NULL,
+    kind                VARCHAR(16) NOT NULL,
+    changed_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS user_changes_changed_at_idx ON user_changes (changed_at);
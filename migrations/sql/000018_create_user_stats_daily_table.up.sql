@@ -0,0 +1,15 @@
+-- Daily rollup of the live users by signup day and every GetUserStats dimension, rewritten
+-- as a whole by the stats refresh job. Countries are kept as their blind index, or as
+-- plaintext for users written before encryption, and are decrypted when read.
+CREATE TABLE IF NOT EXISTS "user_stats_daily" (
+    day                 DATE NOT NULL,
+    country_index       BYTEA,
+    country             VARCHAR(2),
+    status              VARCHAR(32) NOT NULL,
+    type                VARCHAR(32) NOT NULL,
+    verified            BOOLEAN NOT NULL,
+    users               BIGINT NOT NULL,
+    refreshed_at        TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS user_stats_daily_day_idx ON user_stats_daily (day);
@@ -0,0 +1,17 @@
+-- Calls made with an idempotency-key header, keyed by caller and key, whose responses are
+-- replayed to retries. A row without response is a call in progress, which a retry may take
+-- over once locked_until has passed. Rows are removed once expired.
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+    caller              VARCHAR(255) NOT NULL,
+    idempotency_key     VARCHAR(255) NOT NULL,
+    method              VARCHAR(255) NOT NULL,
+    request_hash        BYTEA NOT NULL,
+    response_type       VARCHAR(255),
+    response            BYTEA,
+    locked_until        TIMESTAMPTZ NOT NULL,
+    expires_at          TIMESTAMPTZ NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (caller, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS user_emails_email_index_unnormalized_idx;
+ALTER TABLE user_emails DROP COLUMN IF EXISTS email_index_normalized;
+
+DROP INDEX IF EXISTS users_email_index_unnormalized_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS email_index_normalized;
@@ -0,0 +1,9 @@
+-- Email blind indexes are computed on the lowercased, trimmed address. The rows indexed
+-- before are flagged until rotate-keys computes their index again; new rows are not.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_index_normalized BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ALTER COLUMN email_index_normalized SET DEFAULT TRUE;
+CREATE INDEX IF NOT EXISTS users_email_index_unnormalized_idx ON users (id) WHERE NOT email_index_normalized;
+
+ALTER TABLE user_emails ADD COLUMN IF NOT EXISTS email_index_normalized BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_emails ALTER COLUMN email_index_normalized SET DEFAULT TRUE;
+CREATE INDEX IF NOT EXISTS user_emails_email_index_unnormalized_idx ON user_emails (user_id) WHERE NOT email_index_normalized;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS user_changes_tx_id_id_idx;
+ALTER TABLE user_changes DROP COLUMN IF EXISTS tx_id;
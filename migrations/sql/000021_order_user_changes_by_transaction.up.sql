@@ -0,0 +1,6 @@
+-- Changes are read in the order of the transactions that recorded them, and only once no
+-- older transaction is in progress, so that transactions need not take a lock to commit in
+-- order. The rows recorded before are given transaction 0 and keep the order of their ids.
+ALTER TABLE user_changes ADD COLUMN IF NOT EXISTS tx_id XID8 NOT NULL DEFAULT '0';
+ALTER TABLE user_changes ALTER COLUMN tx_id SET DEFAULT pg_current_xact_id();
+CREATE INDEX IF NOT EXISTS user_changes_tx_id_id_idx ON user_changes (tx_id, id);
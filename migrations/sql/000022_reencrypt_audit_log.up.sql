@@ -0,0 +1,27 @@
+-- Entries stay append-only, except that the plaintext values of an erased user may be cleared
+-- and that the encrypted values may be encrypted again, under a new data key of the user.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND NEW.old_values IS NULL
+        AND NEW.new_values IS NULL
+        AND (NEW.id, NEW.user_id, NEW.actor, NEW.action, NEW.request_id, NEW.client_ip, NEW.created_at, NEW.encrypted_values)
+            IS NOT DISTINCT FROM
+            (OLD.id, OLD.user_id, OLD.actor, OLD.action, OLD.request_id, OLD.client_ip, OLD.created_at, OLD.encrypted_values)
+    THEN
+        RETURN NEW;
+    END IF;
+
+    IF TG_OP = 'UPDATE'
+        AND OLD.encrypted_values IS NOT NULL
+        AND NEW.encrypted_values IS NOT NULL
+        AND (NEW.id, NEW.user_id, NEW.actor, NEW.action, NEW.old_values, NEW.new_values, NEW.request_id, NEW.client_ip, NEW.created_at)
+            IS NOT DISTINCT FROM
+            (OLD.id, OLD.user_id, OLD.actor, OLD.action, OLD.old_values, OLD.new_values, OLD.request_id, OLD.client_ip, OLD.created_at)
+    THEN
+        RETURN NEW;
+    END IF;
+
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
@@ -43,6 +43,22 @@ func (ep *EventPublisher) Publish(ctx context.Context, topic topicName, key stri
 	return nil
 }
 
+// PublishTombstone publishes a message without value for the key. Once the
+// topic is compacted, every earlier message with the same key is removed.
+func (ep *EventPublisher) PublishTombstone(ctx context.Context, topic topicName, key string) error {
+	pr, err := ep.getProducer(ctx, topic)
+	if err != nil {
+		return err
+	}
+
+	pr.Input() <- &sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder(key),
+	}
+
+	return nil
+}
+
 func (ep *EventPublisher) getProducer(_ context.Context, topic topicName) (sarama.AsyncProducer, error) {
 	ep.RLock()
 	if p, ok := ep.producers[topic]; ok {
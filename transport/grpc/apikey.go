@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out apikey_service_mock_test.go . apiKeyService
+type apiKeyService interface {
+	CreateAPIKey(ctx context.Context, nk models.NewAPIKey) (models.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID uuid.UUID) error
+	Authenticate(ctx context.Context, plaintext string) (models.Principal, error)
+}
+
+func (g *GRPC) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	newKey := models.NewAPIKey{
+		UserID: userID,
+		Name:   req.GetName(),
+		Scopes: req.GetScopes(),
+	}
+
+	if req.GetExpiresAt() != nil {
+		expiresAt := req.GetExpiresAt().AsTime()
+		newKey.ExpiresAt = &expiresAt
+	}
+
+	key, plaintext, err := g.apiKeySvc.CreateAPIKey(ctx, newKey)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.CreateAPIKeyResponse{
+		Key:  plaintext,
+		Info: mapAPIKeyInfo(key),
+	}, nil
+}
+
+func (g *GRPC) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	keys, err := g.apiKeySvc.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	items := make([]*pb.APIKeyInfo, len(keys))
+	for i, k := range keys {
+		items[i] = mapAPIKeyInfo(k)
+	}
+
+	return &pb.ListAPIKeysResponse{
+		Keys: items,
+	}, nil
+}
+
+func (g *GRPC) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyRequest) (*pb.RevokeAPIKeyResponse, error) {
+	keyID, err := uuid.Parse(req.GetKeyId())
+	if err != nil {
+		return nil, errInvalidAPIKeyID
+	}
+
+	if err := g.apiKeySvc.RevokeAPIKey(ctx, keyID); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.RevokeAPIKeyResponse{
+		Success: true,
+	}, nil
+}
+
+func mapAPIKeyInfo(key models.APIKey) *pb.APIKeyInfo {
+	return &pb.APIKeyInfo{
+		Id:         key.ID.String(),
+		UserId:     key.UserID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  optionalTimestamp(key.ExpiresAt),
+		LastUsedAt: optionalTimestamp(key.LastUsedAt),
+		RevokedAt:  optionalTimestamp(key.RevokedAt),
+		CreatedAt:  timestamppb.New(key.CreatedAt),
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+
+	// 3rd party
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out attribute_service_mock_test.go . attributeService
+type attributeService interface {
+	DefineAttribute(ctx context.Context, def models.AttributeDefinition) (models.AttributeDefinition, error)
+	ListAttributeDefinitions(ctx context.Context) ([]models.AttributeDefinition, error)
+	DeleteAttributeDefinition(ctx context.Context, name string) error
+}
+
+func (g *GRPC) DefineAttribute(ctx context.Context, req *pb.DefineAttributeRequest) (*pb.DefineAttributeResponse, error) {
+	def := models.AttributeDefinition{
+		Name:        req.GetName(),
+		Type:        mapAttributeTypeFromPB(req.GetType()),
+		EnumValues:  req.GetEnumValues(),
+		Description: req.GetDescription(),
+		Indexed:     req.GetIndexed(),
+	}
+
+	def, err := g.attrSvc.DefineAttribute(ctx, def)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.DefineAttributeResponse{
+		Attribute: mapAttributeDefinition(def),
+	}, nil
+}
+
+func (g *GRPC) ListAttributeDefinitions(ctx context.Context, _ *pb.ListAttributeDefinitionsRequest) (*pb.ListAttributeDefinitionsResponse, error) {
+	defs, err := g.attrSvc.ListAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	items := make([]*pb.AttributeDefinition, len(defs))
+	for i, def := range defs {
+		items[i] = mapAttributeDefinition(def)
+	}
+
+	return &pb.ListAttributeDefinitionsResponse{
+		Attributes: items,
+	}, nil
+}
+
+func (g *GRPC) DeleteAttributeDefinition(ctx context.Context, req *pb.DeleteAttributeDefinitionRequest) (*pb.DeleteAttributeDefinitionResponse, error) {
+	if err := g.attrSvc.DeleteAttributeDefinition(ctx, req.GetName()); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.DeleteAttributeDefinitionResponse{
+		Success: true,
+	}, nil
+}
+
+func mapAttributeDefinition(def models.AttributeDefinition) *pb.AttributeDefinition {
+	return &pb.AttributeDefinition{
+		Name:        def.Name,
+		Type:        mapAttributeTypeToPB(def.Type),
+		EnumValues:  def.EnumValues,
+		Description: def.Description,
+		Indexed:     def.Indexed,
+		CreatedAt:   timestamppb.New(def.CreatedAt),
+	}
+}
+
+func mapAttributeTypeFromPB(t pb.AttributeType) models.AttributeType {
+	switch t {
+	case pb.AttributeType_ATTRIBUTE_TYPE_STRING:
+		return models.AttributeTypeString
+	case pb.AttributeType_ATTRIBUTE_TYPE_INT:
+		return models.AttributeTypeInt
+	case pb.AttributeType_ATTRIBUTE_TYPE_BOOL:
+		return models.AttributeTypeBool
+	case pb.AttributeType_ATTRIBUTE_TYPE_DATE:
+		return models.AttributeTypeDate
+	case pb.AttributeType_ATTRIBUTE_TYPE_ENUM:
+		return models.AttributeTypeEnum
+	default:
+		return ""
+	}
+}
+
+func mapAttributeTypeToPB(t models.AttributeType) pb.AttributeType {
+	switch t {
+	case models.AttributeTypeString:
+		return pb.AttributeType_ATTRIBUTE_TYPE_STRING
+	case models.AttributeTypeInt:
+		return pb.AttributeType_ATTRIBUTE_TYPE_INT
+	case models.AttributeTypeBool:
+		return pb.AttributeType_ATTRIBUTE_TYPE_BOOL
+	case models.AttributeTypeDate:
+		return pb.AttributeType_ATTRIBUTE_TYPE_DATE
+	case models.AttributeTypeEnum:
+		return pb.AttributeType_ATTRIBUTE_TYPE_ENUM
+	default:
+		return pb.AttributeType_ATTRIBUTE_TYPE_UNSPECIFIED
+	}
+}
+
+// mapAttributesFromPB converts attribute values to Go values. Numbers become
+// float64 and null becomes nil; the user service checks them against the
+// attribute definitions.
+func mapAttributesFromPB(values map[string]*structpb.Value) map[string]any {
+	if len(values) == 0 {
+		return nil
+	}
+
+	attributes := make(map[string]any, len(values))
+	for name, v := range values {
+		attributes[name] = v.AsInterface()
+	}
+
+	return attributes
+}
+
+func mapAttributesToPB(attributes map[string]any) (map[string]*structpb.Value, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]*structpb.Value, len(attributes))
+	for name, a := range attributes {
+		v, err := structpb.NewValue(a)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = v
+	}
+
+	return values, nil
+}
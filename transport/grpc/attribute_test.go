@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_DefineAttribute(t *testing.T) {
+	tests := []struct {
+		name    string
+		svcErr  error
+		checkFn func(t *testing.T, resp *user.DefineAttributeResponse, err error)
+	}{
+		{
+			name: "happy path",
+			checkFn: func(t *testing.T, resp *user.DefineAttributeResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "tier", resp.GetAttribute().GetName())
+				require.Equal(t, user.AttributeType_ATTRIBUTE_TYPE_ENUM, resp.GetAttribute().GetType())
+				require.Equal(t, []string{"gold", "silver"}, resp.GetAttribute().GetEnumValues())
+			},
+		},
+		{
+			name:   "invalid attribute",
+			svcErr: fmt.Errorf("%w: invalid name", models.ErrInvalidAttribute),
+			checkFn: func(t *testing.T, resp *user.DefineAttributeResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:   "already defined",
+			svcErr: models.ErrAttributeExists,
+			checkFn: func(t *testing.T, resp *user.DefineAttributeResponse, err error) {
+				require.ErrorIs(t, err, errAttributeExists)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &AttributeServiceMock{
+				DefineAttributeFunc: func(ctx context.Context, def models.AttributeDefinition) (models.AttributeDefinition, error) {
+					def.CreatedAt = time.Now()
+					return def, tt.svcErr
+				},
+			}
+
+			g := &GRPC{
+				attrSvc: svc,
+				logger:  zap.NewNop().Sugar(),
+			}
+
+			resp, err := g.DefineAttribute(context.Background(), &user.DefineAttributeRequest{
+				Name:       "tier",
+				Type:       user.AttributeType_ATTRIBUTE_TYPE_ENUM,
+				EnumValues: []string{"gold", "silver"},
+				Indexed:    true,
+			})
+			tt.checkFn(t, resp, err)
+			require.Len(t, svc.DefineAttributeCalls(), 1)
+			require.Equal(t, models.AttributeTypeEnum, svc.DefineAttributeCalls()[0].Def.Type)
+		})
+	}
+}
+
+func TestGRPC_DeleteAttributeDefinition_NotFound(t *testing.T) {
+	svc := &AttributeServiceMock{
+		DeleteAttributeDefinitionFunc: func(ctx context.Context, name string) error {
+			return models.ErrAttributeNotFound
+		},
+	}
+
+	g := &GRPC{
+		attrSvc: svc,
+		logger:  zap.NewNop().Sugar(),
+	}
+
+	_, err := g.DeleteAttributeDefinition(context.Background(), &user.DeleteAttributeDefinitionRequest{Name: "tier"})
+	require.ErrorIs(t, err, errAttributeNotFound)
+}
+
+func TestGRPC_QueryUsers_Attributes(t *testing.T) {
+	svc := &UserServiceMock{
+		ListUsersFunc: func(ctx context.Context, qu models.GetUsersOptions) ([]models.User, *models.UserCursor, error) {
+			return []models.User{
+				{
+					ID:         uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c"),
+					Attributes: map[string]any{"tier": "gold", "level": int64(3), "vip": true},
+				},
+			}, nil, nil
+		},
+	}
+
+	g := &GRPC{
+		svc:    svc,
+		logger: zap.NewNop().Sugar(),
+	}
+
+	resp, err := g.QueryUsers(context.Background(), &user.QueryUsersRequest{
+		Filter: &user.QueryUsersRequest_Filter{
+			Attributes: map[string]*structpb.Value{"tier": structpb.NewStringValue("gold")},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"tier": "gold"}, svc.ListUsersCalls()[0].Qu.Filter.Attributes)
+
+	attributes := resp.GetUsers()[0].GetAttributes()
+	require.Equal(t, "gold", attributes["tier"].GetStringValue())
+	require.Equal(t, float64(3), attributes["level"].GetNumberValue())
+	require.True(t, attributes["vip"].GetBoolValue())
+}
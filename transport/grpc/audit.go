@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out audit_service_mock_test.go . auditService
+type auditService interface {
+	ListAuditEntries(ctx context.Context, opts models.GetAuditEntriesOptions) ([]models.AuditEntry, int64, error)
+}
+
+func (g *GRPC) ListAuditEntries(ctx context.Context, req *pb.ListAuditEntriesRequest) (*pb.ListAuditEntriesResponse, error) {
+	opts := models.GetAuditEntriesOptions{
+		Actor:  req.GetActor(),
+		Action: req.GetAction(),
+		Limit:  req.GetPageSize(),
+	}
+
+	if req.GetUserId() != "" {
+		userID, err := uuid.Parse(req.GetUserId())
+		if err != nil {
+			return nil, errInvalidUserID
+		}
+		opts.UserID = userID
+	}
+
+	if req.GetFrom() != nil {
+		from := req.GetFrom().AsTime()
+		opts.From = &from
+	}
+
+	if req.GetTo() != nil {
+		to := req.GetTo().AsTime()
+		opts.To = &to
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = defaultPageSize
+	}
+
+	if req.GetPageToken() != "" {
+		cursor, err := decodePageToken(req.GetPageToken())
+		if err != nil {
+			return nil, errInvalidPageToken
+		}
+		opts.Cursor = cursor
+	}
+
+	entries, next, err := g.auditSvc.ListAuditEntries(ctx, opts)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	items := make([]*pb.AuditEntry, len(entries))
+	for i, e := range entries {
+		item, err := mapAuditEntry(e)
+		if err != nil {
+			return nil, g.mapError(err)
+		}
+		items[i] = item
+	}
+
+	resp := &pb.ListAuditEntriesResponse{
+		Entries: items,
+	}
+
+	if next != 0 {
+		resp.NextPageToken = encodePageToken(next)
+	}
+
+	return resp, nil
+}
+
+func mapAuditEntry(e models.AuditEntry) (*pb.AuditEntry, error) {
+	item := &pb.AuditEntry{
+		Id:        e.ID,
+		UserId:    e.UserID.String(),
+		Actor:     e.Actor,
+		Action:    e.Action,
+		RequestId: e.RequestID,
+		ClientIp:  e.ClientIP,
+		CreatedAt: timestamppb.New(e.CreatedAt),
+	}
+
+	if e.Before != nil {
+		before, err := structpb.NewStruct(e.Before)
+		if err != nil {
+			return nil, err
+		}
+		item.Before = before
+	}
+
+	if e.After != nil {
+		after, err := structpb.NewStruct(e.After)
+		if err != nil {
+			return nil, err
+		}
+		item.After = after
+	}
+
+	return item, nil
+}
+
+// Page tokens are opaque to clients; they wrap the id of the last entry returned.
+func encodePageToken(cursor int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(cursor, 10)))
+}
+
+func decodePageToken(token string) (int64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(b), 10, 64)
+}
@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_ListAuditEntries(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	svc := &AuditServiceMock{
+		ListAuditEntriesFunc: func(ctx context.Context, opts models.GetAuditEntriesOptions) ([]models.AuditEntry, int64, error) {
+			if opts.Cursor == 0 {
+				return []models.AuditEntry{
+					{
+						ID:        42,
+						UserID:    userID,
+						Action:    models.AuditActionUserUpdated,
+						Before:    map[string]any{"email": "old@example.com"},
+						After:     map[string]any{"email": "new@example.com"},
+						CreatedAt: time.Now(),
+					},
+				}, 42, nil
+			}
+			return nil, 0, nil
+		},
+	}
+
+	g := &GRPC{
+		auditSvc: svc,
+		logger:   zap.NewNop().Sugar(),
+	}
+
+	resp, err := g.ListAuditEntries(context.TODO(), &user.ListAuditEntriesRequest{UserId: userID.String()})
+	require.NoError(t, err)
+	require.Len(t, resp.GetEntries(), 1)
+	require.Equal(t, "new@example.com", resp.GetEntries()[0].GetAfter().AsMap()["email"])
+	require.NotEmpty(t, resp.GetNextPageToken())
+
+	opts := svc.ListAuditEntriesCalls()[0].Opts
+	require.Equal(t, userID, opts.UserID)
+	require.Equal(t, uint64(defaultPageSize), opts.Limit)
+
+	resp, err = g.ListAuditEntries(context.TODO(), &user.ListAuditEntriesRequest{PageToken: resp.GetNextPageToken()})
+	require.NoError(t, err)
+	require.Len(t, resp.GetEntries(), 0)
+	require.Empty(t, resp.GetNextPageToken())
+	require.Equal(t, int64(42), svc.ListAuditEntriesCalls()[1].Opts.Cursor)
+
+	t.Log("invalid page token")
+	{
+		_, err := g.ListAuditEntries(context.TODO(), &user.ListAuditEntriesRequest{PageToken: "%%%"})
+		require.ErrorIs(t, err, errInvalidPageToken)
+	}
+}
+
+func TestWithRequestMetadata(t *testing.T) {
+	principalID := uuid.MustParse("1c8f21c1-c8d0-401c-89b5-3f577c54679e")
+	proxy := &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 51234}
+	client := &net.TCPAddr{IP: net.ParseIP("198.51.100.4"), Port: 51234}
+
+	_, trusted, err := net.ParseCIDR("10.0.0.0/24")
+	require.NoError(t, err)
+	rm := &requestMetadata{trustedProxies: []*net.IPNet{trusted}}
+
+	tests := []struct {
+		name string
+		ctx  func() context.Context
+		want audit.Metadata
+	}{
+		{
+			name: "authenticated caller behind trusted proxies",
+			ctx: func() context.Context {
+				ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+					"x-request-id", "req-1",
+					"x-forwarded-for", "192.0.2.1, 203.0.113.9, 10.0.0.1",
+					"x-actor", "ignored",
+				))
+				ctx = peer.NewContext(ctx, &peer.Peer{Addr: proxy})
+				return auth.WithPrincipal(ctx, models.Principal{UserID: principalID})
+			},
+			want: audit.Metadata{
+				Actor:     principalID.String(),
+				RequestID: "req-1",
+				ClientIP:  "203.0.113.9",
+			},
+		},
+		{
+			name: "forwarded header of an untrusted client",
+			ctx: func() context.Context {
+				ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+					"x-request-id", "req-2",
+					"x-forwarded-for", "203.0.113.9",
+					"x-actor", "backoffice:alice",
+				))
+				return peer.NewContext(ctx, &peer.Peer{Addr: client})
+			},
+			want: audit.Metadata{
+				Actor:     anonymousActor,
+				RequestID: "req-2",
+				ClientIP:  "198.51.100.4",
+			},
+		},
+		{
+			name: "trusted proxy without forwarded header",
+			ctx: func() context.Context {
+				ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-3"))
+				return peer.NewContext(ctx, &peer.Peer{Addr: proxy})
+			},
+			want: audit.Metadata{
+				Actor:     anonymousActor,
+				RequestID: "req-3",
+				ClientIP:  "10.0.0.7",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := audit.MetadataFromContext(rm.withRequestMetadata(tt.ctx()))
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Log("anonymous caller gets a request id")
+	{
+		got := audit.MetadataFromContext(rm.withRequestMetadata(context.Background()))
+		require.Equal(t, anonymousActor, got.Actor)
+		require.NotEmpty(t, got.RequestID)
+	}
+}
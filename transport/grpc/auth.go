@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	// 3rd party
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+const (
+	userServicePrefix     = "/services.user.User/"
+	authorizationHeader   = "authorization"
+	apiKeyHeader          = "x-api-key"
+	apiKeyAuthScheme      = "apikey"
+	authSchemeSeparator   = " "
+	authSchemeHeaderParts = 2
+)
+
+// methodScopes maps every method of the User service to the scope an API key
+// needs in order to call it.
+var methodScopes = map[string]string{
+	"CreateUser":     models.ScopeUsersWrite,
+	"UpdateUser":     models.ScopeUsersWrite,
+	"DeleteUser":     models.ScopeUsersWrite,
+	"RestoreUser":    models.ScopeUsersWrite,
+	"EraseUser":      models.ScopeUsersAdmin,
+	"SuspendUser":    models.ScopeUsersWrite,
+	"ReactivateUser": models.ScopeUsersWrite,
+	"DeactivateUser": models.ScopeUsersWrite,
+	"QueryUsers":     models.ScopeUsersRead,
+	"SearchUsers":    models.ScopeUsersRead,
+	"ExportUserData": models.ScopeUsersAdmin,
+	"ExportUsers":    models.ScopeUsersRead,
+	"ImportUsers":    models.ScopeUsersWrite,
+	"WatchUsers":     models.ScopeUsersRead,
+	"GetUserStats":   models.ScopeUsersRead,
+
+	"BatchCreateUsers": models.ScopeUsersWrite,
+	"BatchUpdateUsers": models.ScopeUsersWrite,
+	"BatchDeleteUsers": models.ScopeUsersWrite,
+
+	"CreateGroup":       models.ScopeGroupsWrite,
+	"RenameGroup":       models.ScopeGroupsWrite,
+	"DeleteGroup":       models.ScopeGroupsWrite,
+	"AddGroupMember":    models.ScopeGroupsWrite,
+	"RemoveGroupMember": models.ScopeGroupsWrite,
+	"ListGroupMembers":  models.ScopeGroupsRead,
+	"ListUserGroups":    models.ScopeGroupsRead,
+
+	"CreateAPIKey": models.ScopeAPIKeysWrite,
+	"ListAPIKeys":  models.ScopeAPIKeysWrite,
+	"RevokeAPIKey": models.ScopeAPIKeysWrite,
+
+	"ListAuditEntries": models.ScopeAuditRead,
+
+	"RecordConsent":   models.ScopeConsentsWrite,
+	"WithdrawConsent": models.ScopeConsentsWrite,
+	"ListConsents":    models.ScopeConsentsRead,
+
+	"GetSettings":   models.ScopeSettingsRead,
+	"SetSettings":   models.ScopeSettingsWrite,
+	"DeleteSetting": models.ScopeSettingsWrite,
+
+	"AddEmail":        models.ScopeUsersWrite,
+	"RemoveEmail":     models.ScopeUsersWrite,
+	"SetPrimaryEmail": models.ScopeUsersWrite,
+	"VerifyEmail":     models.ScopeUsersWrite,
+	"ListEmails":      models.ScopeUsersRead,
+	"AddPhone":        models.ScopeUsersWrite,
+	"RemovePhone":     models.ScopeUsersWrite,
+	"SetPrimaryPhone": models.ScopeUsersWrite,
+	"VerifyPhone":     models.ScopeUsersWrite,
+	"ListPhones":      models.ScopeUsersRead,
+
+	"DefineAttribute":           models.ScopeUsersAdmin,
+	"ListAttributeDefinitions":  models.ScopeUsersAdmin,
+	"DeleteAttributeDefinition": models.ScopeUsersAdmin,
+
+	"LinkIdentity":   models.ScopeIdentitiesWrite,
+	"UnlinkIdentity": models.ScopeIdentitiesWrite,
+	"ListIdentities": models.ScopeIdentitiesRead,
+	// Provisioning a user also needs the users:write scope.
+	"FindUserByExternalIdentity": models.ScopeIdentitiesRead,
+
+	"RegisterOAuthClient": models.ScopeUsersAdmin,
+	"ListOAuthClients":    models.ScopeUsersAdmin,
+	"DeleteOAuthClient":   models.ScopeUsersAdmin,
+}
+
+type authenticator interface {
+	Authenticate(ctx context.Context, plaintext string) (models.Principal, error)
+}
+
+// authInterceptor authenticates callers presenting an API key and enforces the
+// scopes granted to it. Callers without credentials are let through unless
+// authentication is required.
+type authInterceptor struct {
+	authn    authenticator
+	required bool
+	logger   *zap.SugaredLogger
+}
+
+func (a *authInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *authInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (a *authInterceptor) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	key := apiKeyFromMetadata(ctx)
+	if key == "" {
+		if a.required && strings.HasPrefix(fullMethod, userServicePrefix) {
+			return nil, errUnauthenticated
+		}
+		return ctx, nil
+	}
+
+	principal, err := a.authn.Authenticate(ctx, key)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidAPIKey) {
+			return nil, errUnauthenticated
+		}
+		a.logger.Error(err)
+		return nil, errInternal
+	}
+
+	if method, ok := strings.CutPrefix(fullMethod, userServicePrefix); ok {
+		scope, known := methodScopes[method]
+		if !known || !principal.HasScope(scope) {
+			return nil, errPermissionDenied
+		}
+	}
+
+	return auth.WithPrincipal(ctx, principal), nil
+}
+
+// apiKeyFromMetadata extracts the key from either an "authorization: ApiKey <key>"
+// or an "x-api-key: <key>" header.
+func apiKeyFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(apiKeyHeader); len(values) > 0 {
+		return values[0]
+	}
+
+	for _, v := range md.Get(authorizationHeader) {
+		parts := strings.SplitN(v, authSchemeSeparator, authSchemeHeaderParts)
+		if len(parts) == authSchemeHeaderParts && strings.EqualFold(parts[0], apiKeyAuthScheme) {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+
+	return ""
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func TestAuthInterceptor_Unary(t *testing.T) {
+	principal := models.Principal{
+		UserID:   uuid.MustParse("1c8f21c1-c8d0-401c-89b5-3f577c54679e"),
+		APIKeyID: uuid.MustParse("5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11"),
+		Scopes:   []string{models.ScopeUsersRead},
+	}
+
+	authn := &ApiKeyServiceMock{
+		AuthenticateFunc: func(ctx context.Context, plaintext string) (models.Principal, error) {
+			switch plaintext {
+			case "valid":
+				return principal, nil
+			case "broken":
+				return models.Principal{}, errors.New("db down")
+			default:
+				return models.Principal{}, models.ErrInvalidAPIKey
+			}
+		},
+	}
+
+	tests := []struct {
+		name     string
+		required bool
+		md       metadata.MD
+		method   string
+		checkFn  func(t *testing.T, ctx context.Context, err error)
+	}{
+		{
+			name:   "anonymous call allowed",
+			method: "/services.user.User/QueryUsers",
+			checkFn: func(t *testing.T, ctx context.Context, err error) {
+				require.NoError(t, err)
+				_, ok := auth.PrincipalFromContext(ctx)
+				require.False(t, ok)
+			},
+		},
+		{
+			name:     "anonymous call rejected when auth is required",
+			required: true,
+			method:   "/services.user.User/QueryUsers",
+			checkFn: func(t *testing.T, ctx context.Context, err error) {
+				require.ErrorIs(t, err, errUnauthenticated)
+			},
+		},
+		{
+			name:   "authorization header",
+			md:     metadata.Pairs("authorization", "ApiKey valid"),
+			method: "/services.user.User/QueryUsers",
+			checkFn: func(t *testing.T, ctx context.Context, err error) {
+				require.NoError(t, err)
+				got, ok := auth.PrincipalFromContext(ctx)
+				require.True(t, ok)
+				require.Equal(t, principal, got)
+			},
+		},
+		{
+			name:   "x-api-key header",
+			md:     metadata.Pairs("x-api-key", "valid"),
+			method: "/services.user.User/QueryUsers",
+			checkFn: func(t *testing.T, ctx context.Context, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "invalid key",
+			md:     metadata.Pairs("x-api-key", "invalid"),
+			method: "/services.user.User/QueryUsers",
+			checkFn: func(t *testing.T, ctx context.Context, err error) {
+				require.ErrorIs(t, err, errUnauthenticated)
+			},
+		},
+		{
+			name:   "missing scope",
+			md:     metadata.Pairs("x-api-key", "valid"),
+			method: "/services.user.User/DeleteUser",
+			checkFn: func(t *testing.T, ctx context.Context, err error) {
+				require.ErrorIs(t, err, errPermissionDenied)
+			},
+		},
+		{
+			name:   "authenticator failure",
+			md:     metadata.Pairs("x-api-key", "broken"),
+			method: "/services.user.User/QueryUsers",
+			checkFn: func(t *testing.T, ctx context.Context, err error) {
+				require.ErrorIs(t, err, errInternal)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &authInterceptor{
+				authn:    authn,
+				required: tt.required,
+				logger:   zap.NewNop().Sugar(),
+			}
+
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var handlerCtx context.Context
+			handler := func(ctx context.Context, req any) (any, error) {
+				handlerCtx = ctx
+				return nil, nil
+			}
+
+			_, err := a.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			tt.checkFn(t, handlerCtx, err)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func (g *GRPC) BatchCreateUsers(ctx context.Context, req *pb.BatchCreateUsersRequest) (*pb.BatchUsersResponse, error) {
+	users := make([]models.NewUser, len(req.GetRequests()))
+	for i, r := range req.GetRequests() {
+		users[i] = mapNewUser(r)
+	}
+
+	results, err := g.svc.BatchCreateUsers(ctx, users, req.GetAtomic())
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return g.mapBatchResults(results), nil
+}
+
+func (g *GRPC) BatchUpdateUsers(ctx context.Context, req *pb.BatchUpdateUsersRequest) (*pb.BatchUsersResponse, error) {
+	updates := make([]models.BatchUpdate, len(req.GetRequests()))
+	for i, r := range req.GetRequests() {
+		userID, err := uuid.Parse(r.GetUserId())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid user id of item %d", i)
+		}
+
+		updates[i] = models.BatchUpdate{
+			UserID: userID,
+			Update: mapUpdateUser(r.GetFields()),
+		}
+	}
+
+	results, err := g.svc.BatchUpdateUsers(ctx, updates, req.GetAtomic())
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return g.mapBatchResults(results), nil
+}
+
+func (g *GRPC) BatchDeleteUsers(ctx context.Context, req *pb.BatchDeleteUsersRequest) (*pb.BatchUsersResponse, error) {
+	userIDs := make([]uuid.UUID, len(req.GetUserIds()))
+	for i, id := range req.GetUserIds() {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid user id of item %d", i)
+		}
+		userIDs[i] = userID
+	}
+
+	results, err := g.svc.BatchDeleteUsers(ctx, userIDs, req.GetAtomic())
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return g.mapBatchResults(results), nil
+}
+
+func (g *GRPC) mapBatchResults(results []models.BatchResult) *pb.BatchUsersResponse {
+	resp := &pb.BatchUsersResponse{
+		Results: make([]*pb.BatchUsersResponse_Result, len(results)),
+	}
+
+	for i, r := range results {
+		result := &pb.BatchUsersResponse_Result{}
+		if r.Err != nil {
+			st := status.Convert(g.mapError(r.Err))
+			result.Code = int32(st.Code())
+			result.Message = st.Message()
+		} else {
+			result.UserId = r.UserID.String()
+		}
+		resp.Results[i] = result
+	}
+
+	return resp
+}
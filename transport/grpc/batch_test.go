@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_BatchDeleteUsers(t *testing.T) {
+	deleted := uuid.New()
+	missing := uuid.New()
+
+	svc := &UserServiceMock{
+		BatchDeleteUsersFunc: func(ctx context.Context, userIDs []uuid.UUID, atomic bool) ([]models.BatchResult, error) {
+			require.Equal(t, []uuid.UUID{deleted, missing}, userIDs)
+			require.True(t, atomic)
+			return []models.BatchResult{
+				{Err: models.ErrBatchAborted},
+				{Err: models.ErrUserNotFound},
+			}, nil
+		},
+	}
+
+	g := &GRPC{
+		svc:    svc,
+		logger: zap.NewNop().Sugar(),
+	}
+
+	resp, err := g.BatchDeleteUsers(context.TODO(), &user.BatchDeleteUsersRequest{
+		UserIds: []string{deleted.String(), missing.String()},
+		Atomic:  true,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetResults(), 2)
+	require.Equal(t, int32(codes.Aborted), resp.GetResults()[0].GetCode())
+	require.Empty(t, resp.GetResults()[0].GetUserId())
+	require.Equal(t, int32(codes.NotFound), resp.GetResults()[1].GetCode())
+	require.Equal(t, "user not found", resp.GetResults()[1].GetMessage())
+
+	t.Log("invalid user id")
+	{
+		_, err := g.BatchDeleteUsers(context.TODO(), &user.BatchDeleteUsersRequest{UserIds: []string{deleted.String(), "nope"}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		require.Len(t, svc.BatchDeleteUsersCalls(), 1)
+	}
+
+	t.Log("too many items")
+	{
+		svc.BatchDeleteUsersFunc = func(ctx context.Context, userIDs []uuid.UUID, atomic bool) ([]models.BatchResult, error) {
+			return nil, models.ErrBatchTooLarge
+		}
+
+		_, err := g.BatchDeleteUsers(context.TODO(), &user.BatchDeleteUsersRequest{UserIds: []string{deleted.String()}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestGRPC_BatchUpdateUsers(t *testing.T) {
+	updated := uuid.New()
+
+	svc := &UserServiceMock{
+		BatchUpdateUsersFunc: func(ctx context.Context, updates []models.BatchUpdate, atomic bool) ([]models.BatchResult, error) {
+			require.Equal(t, []models.BatchUpdate{{UserID: updated, Update: models.UpdateUser{Nickname: "tony"}}}, updates)
+			require.False(t, atomic)
+			return []models.BatchResult{{UserID: updated}}, nil
+		},
+	}
+
+	g := &GRPC{
+		svc:    svc,
+		logger: zap.NewNop().Sugar(),
+	}
+
+	resp, err := g.BatchUpdateUsers(context.TODO(), &user.BatchUpdateUsersRequest{
+		Requests: []*user.UpdateUserRequest{
+			{UserId: updated.String(), Fields: &user.UpdateUserRequest_Fields{Nickname: "tony"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, updated.String(), resp.GetResults()[0].GetUserId())
+	require.Equal(t, int32(codes.OK), resp.GetResults()[0].GetCode())
+}
@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out consent_service_mock_test.go . consentService
+type consentService interface {
+	RecordConsent(ctx context.Context, change models.ConsentChange) (models.Consent, error)
+	WithdrawConsent(ctx context.Context, change models.ConsentChange) (models.Consent, error)
+	ListConsents(ctx context.Context, userID uuid.UUID, opts models.GetConsentsOptions) ([]models.Consent, error)
+}
+
+func (g *GRPC) RecordConsent(ctx context.Context, req *pb.RecordConsentRequest) (*pb.RecordConsentResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	consent, err := g.consentSvc.RecordConsent(ctx, models.ConsentChange{
+		UserID:        userID,
+		Purpose:       req.GetPurpose(),
+		Source:        req.GetSource(),
+		PolicyVersion: req.GetPolicyVersion(),
+	})
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.RecordConsentResponse{
+		Consent: mapConsentInfo(consent),
+	}, nil
+}
+
+func (g *GRPC) WithdrawConsent(ctx context.Context, req *pb.WithdrawConsentRequest) (*pb.WithdrawConsentResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	consent, err := g.consentSvc.WithdrawConsent(ctx, models.ConsentChange{
+		UserID:        userID,
+		Purpose:       req.GetPurpose(),
+		Source:        req.GetSource(),
+		PolicyVersion: req.GetPolicyVersion(),
+	})
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.WithdrawConsentResponse{
+		Consent: mapConsentInfo(consent),
+	}, nil
+}
+
+func (g *GRPC) ListConsents(ctx context.Context, req *pb.ListConsentsRequest) (*pb.ListConsentsResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	opts := models.GetConsentsOptions{
+		Purpose:        req.GetPurpose(),
+		IncludeHistory: req.GetIncludeHistory(),
+	}
+
+	consents, err := g.consentSvc.ListConsents(ctx, userID, opts)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	items := make([]*pb.ConsentInfo, len(consents))
+	for i, c := range consents {
+		items[i] = mapConsentInfo(c)
+	}
+
+	return &pb.ListConsentsResponse{
+		Consents: items,
+	}, nil
+}
+
+func mapConsentInfo(consent models.Consent) *pb.ConsentInfo {
+	return &pb.ConsentInfo{
+		UserId:        consent.UserID.String(),
+		Purpose:       consent.Purpose,
+		Status:        mapConsentStatusToPB(consent.Status),
+		Source:        consent.Source,
+		PolicyVersion: consent.PolicyVersion,
+		RecordedAt:    timestamppb.New(consent.RecordedAt),
+	}
+}
+
+func mapConsentStatusToPB(s models.ConsentStatus) pb.ConsentStatus {
+	switch s {
+	case models.ConsentStatusGranted:
+		return pb.ConsentStatus_CONSENT_STATUS_GRANTED
+	case models.ConsentStatusWithdrawn:
+		return pb.ConsentStatus_CONSENT_STATUS_WITHDRAWN
+	default:
+		return pb.ConsentStatus_CONSENT_STATUS_UNSPECIFIED
+	}
+}
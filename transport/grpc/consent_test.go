@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_RecordConsent(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	tests := []struct {
+		name    string
+		svc     consentService
+		req     *user.RecordConsentRequest
+		checkFn func(t *testing.T, resp *user.RecordConsentResponse, err error)
+	}{
+		{
+			name: "happy path",
+			svc: &ConsentServiceMock{
+				RecordConsentFunc: func(ctx context.Context, change models.ConsentChange) (models.Consent, error) {
+					return models.Consent{
+						ID:            1,
+						UserID:        change.UserID,
+						Purpose:       change.Purpose,
+						Status:        models.ConsentStatusGranted,
+						Source:        change.Source,
+						PolicyVersion: change.PolicyVersion,
+						RecordedAt:    time.Now(),
+					}, nil
+				},
+			},
+			req: &user.RecordConsentRequest{
+				UserId:        userID.String(),
+				Purpose:       "newsletter",
+				Source:        "signup_form",
+				PolicyVersion: "2023-01",
+			},
+			checkFn: func(t *testing.T, resp *user.RecordConsentResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, userID.String(), resp.GetConsent().GetUserId())
+				require.Equal(t, "newsletter", resp.GetConsent().GetPurpose())
+				require.Equal(t, user.ConsentStatus_CONSENT_STATUS_GRANTED, resp.GetConsent().GetStatus())
+				require.Equal(t, "signup_form", resp.GetConsent().GetSource())
+				require.Equal(t, "2023-01", resp.GetConsent().GetPolicyVersion())
+			},
+		},
+		{
+			name: "invalid user id",
+			svc:  &ConsentServiceMock{},
+			req:  &user.RecordConsentRequest{UserId: "1234", Purpose: "newsletter"},
+			checkFn: func(t *testing.T, resp *user.RecordConsentResponse, err error) {
+				require.ErrorIs(t, err, errInvalidUserID)
+			},
+		},
+		{
+			name: "invalid purpose",
+			svc: &ConsentServiceMock{
+				RecordConsentFunc: func(ctx context.Context, change models.ConsentChange) (models.Consent, error) {
+					return models.Consent{}, models.ErrInvalidConsentPurpose
+				},
+			},
+			req: &user.RecordConsentRequest{UserId: userID.String()},
+			checkFn: func(t *testing.T, resp *user.RecordConsentResponse, err error) {
+				require.ErrorIs(t, err, errInvalidPurpose)
+			},
+		},
+		{
+			name: "user not found",
+			svc: &ConsentServiceMock{
+				RecordConsentFunc: func(ctx context.Context, change models.ConsentChange) (models.Consent, error) {
+					return models.Consent{}, models.ErrUserNotFound
+				},
+			},
+			req: &user.RecordConsentRequest{UserId: userID.String(), Purpose: "newsletter"},
+			checkFn: func(t *testing.T, resp *user.RecordConsentResponse, err error) {
+				require.ErrorIs(t, err, errUserNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GRPC{
+				consentSvc: tt.svc,
+				logger:     zap.NewNop().Sugar(),
+			}
+			got, err := g.RecordConsent(context.Background(), tt.req)
+			tt.checkFn(t, got, err)
+		})
+	}
+}
+
+func TestGRPC_WithdrawConsent_NotGranted(t *testing.T) {
+	g := &GRPC{
+		consentSvc: &ConsentServiceMock{
+			WithdrawConsentFunc: func(ctx context.Context, change models.ConsentChange) (models.Consent, error) {
+				return models.Consent{}, models.ErrConsentNotGranted
+			},
+		},
+		logger: zap.NewNop().Sugar(),
+	}
+
+	_, err := g.WithdrawConsent(context.Background(), &user.WithdrawConsentRequest{
+		UserId:  uuid.New().String(),
+		Purpose: "newsletter",
+	})
+	require.ErrorIs(t, err, errConsentNotGranted)
+}
+
+func TestGRPC_ListConsents(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	svc := &ConsentServiceMock{
+		ListConsentsFunc: func(ctx context.Context, uID uuid.UUID, opts models.GetConsentsOptions) ([]models.Consent, error) {
+			return []models.Consent{
+				{UserID: uID, Purpose: "newsletter", Status: models.ConsentStatusWithdrawn},
+				{UserID: uID, Purpose: "newsletter", Status: models.ConsentStatusGranted},
+			}, nil
+		},
+	}
+
+	g := &GRPC{
+		consentSvc: svc,
+		logger:     zap.NewNop().Sugar(),
+	}
+
+	resp, err := g.ListConsents(context.Background(), &user.ListConsentsRequest{
+		UserId:         userID.String(),
+		Purpose:        "newsletter",
+		IncludeHistory: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetConsents(), 2)
+	require.Equal(t, user.ConsentStatus_CONSENT_STATUS_WITHDRAWN, resp.GetConsents()[0].GetStatus())
+
+	require.Len(t, svc.ListConsentsCalls(), 1)
+	require.Equal(t, models.GetConsentsOptions{Purpose: "newsletter", IncludeHistory: true}, svc.ListConsentsCalls()[0].Opts)
+}
@@ -0,0 +1,149 @@
+package grpc
+
+import (
+	"context"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out contact_service_mock_test.go . contactService
+type contactService interface {
+	AddEmail(ctx context.Context, userID uuid.UUID, email string) error
+	RemoveEmail(ctx context.Context, userID uuid.UUID, email string) error
+	SetPrimaryEmail(ctx context.Context, userID uuid.UUID, email string) error
+	VerifyEmail(ctx context.Context, userID uuid.UUID, email string) error
+	ListEmails(ctx context.Context, userID uuid.UUID) ([]models.UserEmail, error)
+	AddPhone(ctx context.Context, userID uuid.UUID, number string) error
+	RemovePhone(ctx context.Context, userID uuid.UUID, number string) error
+	SetPrimaryPhone(ctx context.Context, userID uuid.UUID, number string) error
+	VerifyPhone(ctx context.Context, userID uuid.UUID, number string) error
+	ListPhones(ctx context.Context, userID uuid.UUID) ([]models.UserPhone, error)
+}
+
+// changeContact runs fn for the user id of a request changing a contact point.
+func (g *GRPC) changeContact(ctx context.Context, rawUserID string, value string, fn func(ctx context.Context, userID uuid.UUID, value string) error) error {
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		return errInvalidUserID
+	}
+
+	if err := fn(ctx, userID, value); err != nil {
+		return g.mapError(err)
+	}
+
+	return nil
+}
+
+func (g *GRPC) AddEmail(ctx context.Context, req *pb.AddEmailRequest) (*pb.AddEmailResponse, error) {
+	if err := g.changeContact(ctx, req.GetUserId(), req.GetEmail(), g.contactSvc.AddEmail); err != nil {
+		return nil, err
+	}
+	return &pb.AddEmailResponse{Success: true}, nil
+}
+
+func (g *GRPC) RemoveEmail(ctx context.Context, req *pb.RemoveEmailRequest) (*pb.RemoveEmailResponse, error) {
+	if err := g.changeContact(ctx, req.GetUserId(), req.GetEmail(), g.contactSvc.RemoveEmail); err != nil {
+		return nil, err
+	}
+	return &pb.RemoveEmailResponse{Success: true}, nil
+}
+
+func (g *GRPC) SetPrimaryEmail(ctx context.Context, req *pb.SetPrimaryEmailRequest) (*pb.SetPrimaryEmailResponse, error) {
+	if err := g.changeContact(ctx, req.GetUserId(), req.GetEmail(), g.contactSvc.SetPrimaryEmail); err != nil {
+		return nil, err
+	}
+	return &pb.SetPrimaryEmailResponse{Success: true}, nil
+}
+
+func (g *GRPC) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	if err := g.changeContact(ctx, req.GetUserId(), req.GetEmail(), g.contactSvc.VerifyEmail); err != nil {
+		return nil, err
+	}
+	return &pb.VerifyEmailResponse{Success: true}, nil
+}
+
+func (g *GRPC) ListEmails(ctx context.Context, req *pb.ListEmailsRequest) (*pb.ListEmailsResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	emails, err := g.contactSvc.ListEmails(ctx, userID)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	items := make([]*pb.EmailInfo, len(emails))
+	for i, e := range emails {
+		items[i] = &pb.EmailInfo{
+			Email:      e.Email,
+			Primary:    e.Primary,
+			VerifiedAt: optionalTimestamp(e.VerifiedAt),
+			CreatedAt:  timestamppb.New(e.CreatedAt),
+		}
+	}
+
+	return &pb.ListEmailsResponse{
+		Emails: items,
+	}, nil
+}
+
+func (g *GRPC) AddPhone(ctx context.Context, req *pb.AddPhoneRequest) (*pb.AddPhoneResponse, error) {
+	if err := g.changeContact(ctx, req.GetUserId(), req.GetNumber(), g.contactSvc.AddPhone); err != nil {
+		return nil, err
+	}
+	return &pb.AddPhoneResponse{Success: true}, nil
+}
+
+func (g *GRPC) RemovePhone(ctx context.Context, req *pb.RemovePhoneRequest) (*pb.RemovePhoneResponse, error) {
+	if err := g.changeContact(ctx, req.GetUserId(), req.GetNumber(), g.contactSvc.RemovePhone); err != nil {
+		return nil, err
+	}
+	return &pb.RemovePhoneResponse{Success: true}, nil
+}
+
+func (g *GRPC) SetPrimaryPhone(ctx context.Context, req *pb.SetPrimaryPhoneRequest) (*pb.SetPrimaryPhoneResponse, error) {
+	if err := g.changeContact(ctx, req.GetUserId(), req.GetNumber(), g.contactSvc.SetPrimaryPhone); err != nil {
+		return nil, err
+	}
+	return &pb.SetPrimaryPhoneResponse{Success: true}, nil
+}
+
+func (g *GRPC) VerifyPhone(ctx context.Context, req *pb.VerifyPhoneRequest) (*pb.VerifyPhoneResponse, error) {
+	if err := g.changeContact(ctx, req.GetUserId(), req.GetNumber(), g.contactSvc.VerifyPhone); err != nil {
+		return nil, err
+	}
+	return &pb.VerifyPhoneResponse{Success: true}, nil
+}
+
+func (g *GRPC) ListPhones(ctx context.Context, req *pb.ListPhonesRequest) (*pb.ListPhonesResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	phones, err := g.contactSvc.ListPhones(ctx, userID)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	items := make([]*pb.PhoneInfo, len(phones))
+	for i, p := range phones {
+		items[i] = &pb.PhoneInfo{
+			Number:     p.Number,
+			Primary:    p.Primary,
+			VerifiedAt: optionalTimestamp(p.VerifiedAt),
+			CreatedAt:  timestamppb.New(p.CreatedAt),
+		}
+	}
+
+	return &pb.ListPhonesResponse{
+		Phones: items,
+	}, nil
+}
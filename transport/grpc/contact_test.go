@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_AddPhone(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	tests := []struct {
+		name    string
+		svcErr  error
+		req     *user.AddPhoneRequest
+		checkFn func(t *testing.T, svc *ContactServiceMock, err error)
+	}{
+		{
+			name: "happy path",
+			req:  &user.AddPhoneRequest{UserId: userID.String(), Number: "+30 210 123 4567"},
+			checkFn: func(t *testing.T, svc *ContactServiceMock, err error) {
+				require.NoError(t, err)
+				require.Len(t, svc.AddPhoneCalls(), 1)
+				require.Equal(t, userID, svc.AddPhoneCalls()[0].UserID)
+				require.Equal(t, "+30 210 123 4567", svc.AddPhoneCalls()[0].Number)
+			},
+		},
+		{
+			name: "invalid user id",
+			req:  &user.AddPhoneRequest{UserId: "1234"},
+			checkFn: func(t *testing.T, svc *ContactServiceMock, err error) {
+				require.ErrorIs(t, err, errInvalidUserID)
+				require.Len(t, svc.AddPhoneCalls(), 0)
+			},
+		},
+		{
+			name:   "invalid phone",
+			svcErr: fmt.Errorf("%w: %q", models.ErrInvalidPhone, "210"),
+			req:    &user.AddPhoneRequest{UserId: userID.String(), Number: "210"},
+			checkFn: func(t *testing.T, svc *ContactServiceMock, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:   "phone taken",
+			svcErr: models.ErrPhoneTaken,
+			req:    &user.AddPhoneRequest{UserId: userID.String(), Number: "+302101234567"},
+			checkFn: func(t *testing.T, svc *ContactServiceMock, err error) {
+				require.ErrorIs(t, err, errPhoneTaken)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &ContactServiceMock{
+				AddPhoneFunc: func(ctx context.Context, userID uuid.UUID, number string) error {
+					return tt.svcErr
+				},
+			}
+
+			g := &GRPC{
+				contactSvc: svc,
+				logger:     zap.NewNop().Sugar(),
+			}
+
+			_, err := g.AddPhone(context.Background(), tt.req)
+			tt.checkFn(t, svc, err)
+		})
+	}
+}
+
+func TestGRPC_RemoveEmail_Primary(t *testing.T) {
+	svc := &ContactServiceMock{
+		RemoveEmailFunc: func(ctx context.Context, userID uuid.UUID, email string) error {
+			return fmt.Errorf("%w: the primary email cannot be removed", models.ErrPrimaryContact)
+		},
+	}
+
+	g := &GRPC{
+		contactSvc: svc,
+		logger:     zap.NewNop().Sugar(),
+	}
+
+	_, err := g.RemoveEmail(context.Background(), &user.RemoveEmailRequest{
+		UserId: uuid.NewString(),
+		Email:  "bruce@wayne.com",
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestGRPC_ListEmails(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+	verifiedAt := time.Now()
+
+	svc := &ContactServiceMock{
+		ListEmailsFunc: func(ctx context.Context, uID uuid.UUID) ([]models.UserEmail, error) {
+			return []models.UserEmail{
+				{UserID: uID, Email: "bruce@wayne.com", Primary: true, VerifiedAt: &verifiedAt},
+				{UserID: uID, Email: "batman@gotham.com"},
+			}, nil
+		},
+	}
+
+	g := &GRPC{
+		contactSvc: svc,
+		logger:     zap.NewNop().Sugar(),
+	}
+
+	resp, err := g.ListEmails(context.Background(), &user.ListEmailsRequest{UserId: userID.String()})
+	require.NoError(t, err)
+	require.Len(t, resp.GetEmails(), 2)
+	require.True(t, resp.GetEmails()[0].GetPrimary())
+	require.NotNil(t, resp.GetEmails()[0].GetVerifiedAt())
+	require.Nil(t, resp.GetEmails()[1].GetVerifiedAt())
+}
@@ -12,10 +12,55 @@ import (
 )
 
 var (
-	errInvalidUserID = status.Errorf(codes.InvalidArgument, "invalid user id")
-	errUserNotFound  = status.Errorf(codes.NotFound, "user not found")
-	errEmailTaken    = status.Errorf(codes.AlreadyExists, "email is already used")
-	errInternal      = status.Errorf(codes.Internal, "internal server error")
+	errInvalidUserID       = status.Errorf(codes.InvalidArgument, "invalid user id")
+	errUserNotFound        = status.Errorf(codes.NotFound, "user not found")
+	errEmailTaken          = status.Errorf(codes.AlreadyExists, "email is already used")
+	errInvalidGroupID      = status.Errorf(codes.InvalidArgument, "invalid group id")
+	errInvalidGroupName    = status.Errorf(codes.InvalidArgument, "invalid group name")
+	errGroupNotFound       = status.Errorf(codes.NotFound, "group not found")
+	errGroupNameTaken      = status.Errorf(codes.AlreadyExists, "group name is already used")
+	errAlreadyGroupMember  = status.Errorf(codes.AlreadyExists, "user is already a member of the group")
+	errNotGroupMember      = status.Errorf(codes.NotFound, "user is not a member of the group")
+	errInvalidAPIKeyID     = status.Errorf(codes.InvalidArgument, "invalid api key id")
+	errAPIKeyNotFound      = status.Errorf(codes.NotFound, "api key not found")
+	errInvalidScope        = status.Errorf(codes.InvalidArgument, "invalid scope")
+	errNotServiceAccount   = status.Errorf(codes.FailedPrecondition, "user is not a service account")
+	errUnauthenticated     = status.Errorf(codes.Unauthenticated, "invalid or missing credentials")
+	errPermissionDenied    = status.Errorf(codes.PermissionDenied, "permission denied")
+	errInvalidTransition   = status.Errorf(codes.FailedPrecondition, "status transition is not allowed")
+	errInvalidExportFormat = status.Errorf(codes.InvalidArgument, "invalid export format")
+	errInvalidPageToken    = status.Errorf(codes.InvalidArgument, "invalid page token")
+	errPageNumberAndToken  = status.Errorf(codes.InvalidArgument, "page_number and page_token cannot be combined")
+	errPageOffsetTooLarge  = status.Errorf(codes.InvalidArgument, "page_number is too large, page with page_token instead")
+	errInvalidOrderBy      = status.Errorf(codes.InvalidArgument, "invalid order_by, sort by email, created_at, last_name or country, each at most once")
+	errInvalidSearchQuery  = status.Errorf(codes.InvalidArgument, "search query has no words")
+	errInvalidPurpose      = status.Errorf(codes.InvalidArgument, "invalid consent purpose")
+	errConsentNotGranted   = status.Errorf(codes.FailedPrecondition, "consent is not granted")
+	errSettingNotFound     = status.Errorf(codes.NotFound, "setting not found")
+	errInvalidSettingValue = status.Errorf(codes.InvalidArgument, "invalid setting value")
+	errAttributeNotFound   = status.Errorf(codes.NotFound, "attribute not found")
+	errAttributeExists     = status.Errorf(codes.AlreadyExists, "attribute is already defined")
+	errEmailNotFound       = status.Errorf(codes.NotFound, "email not found")
+	errPhoneNotFound       = status.Errorf(codes.NotFound, "phone not found")
+	errPhoneTaken          = status.Errorf(codes.AlreadyExists, "phone is already used")
+	errIdentityNotFound    = status.Errorf(codes.NotFound, "identity not found")
+	errIdentityLinked      = status.Errorf(codes.AlreadyExists, "identity is already linked to a user")
+	errInvalidClientID     = status.Errorf(codes.InvalidArgument, "invalid client id")
+	errOAuthClientNotFound = status.Errorf(codes.NotFound, "oauth client not found")
+	errBatchAborted        = status.Errorf(codes.Aborted, "not applied, another item of the batch failed")
+	errInvalidResumeToken  = status.Errorf(codes.InvalidArgument, "invalid resume token")
+	errResumeTokenExpired  = status.Errorf(codes.OutOfRange, "resume token has expired, watch again without it")
+	errWatchLagging        = status.Errorf(codes.ResourceExhausted, "too far behind the changes, resume with the last resume token")
+	errTooManyStatsGroups  = status.Errorf(codes.InvalidArgument, "too many groups, narrow the range or use a coarser bucket")
+	errInternal            = status.Errorf(codes.Internal, "internal server error")
+)
+
+// Errors of the idempotency interceptor.
+var (
+	errInvalidIdempotencyKey      = status.Errorf(codes.InvalidArgument, "invalid idempotency key")
+	errIdempotencyKeyReused       = status.Errorf(codes.FailedPrecondition, "idempotency key was used for another request")
+	errIdempotencyKeyInUse        = status.Errorf(codes.Aborted, "a request with this idempotency key is in progress")
+	errIdempotencyKeyNotSupported = status.Errorf(codes.InvalidArgument, "idempotency keys are not supported by methods returning secrets")
 )
 
 func (g *GRPC) mapError(err error) error {
@@ -24,6 +69,88 @@ func (g *GRPC) mapError(err error) error {
 		return errUserNotFound
 	case errors.Is(err, models.ErrEmailTaken):
 		return errEmailTaken
+	case errors.Is(err, models.ErrGroupNotFound):
+		return errGroupNotFound
+	case errors.Is(err, models.ErrGroupNameTaken):
+		return errGroupNameTaken
+	case errors.Is(err, models.ErrAlreadyGroupMember):
+		return errAlreadyGroupMember
+	case errors.Is(err, models.ErrNotGroupMember):
+		return errNotGroupMember
+	case errors.Is(err, models.ErrAPIKeyNotFound):
+		return errAPIKeyNotFound
+	case errors.Is(err, models.ErrInvalidScope):
+		return errInvalidScope
+	case errors.Is(err, models.ErrScopeNotGranted), errors.Is(err, models.ErrNotAPIKeyOwner):
+		return errPermissionDenied
+	case errors.Is(err, models.ErrNotServiceAccount):
+		return errNotServiceAccount
+	case errors.Is(err, models.ErrInvalidStatusTransition):
+		return errInvalidTransition
+	case errors.Is(err, models.ErrInvalidExportFormat):
+		return errInvalidExportFormat
+	case errors.Is(err, models.ErrInvalidConsentPurpose):
+		return errInvalidPurpose
+	case errors.Is(err, models.ErrConsentNotGranted):
+		return errConsentNotGranted
+	case errors.Is(err, models.ErrInvalidSettingKey),
+		errors.Is(err, models.ErrInvalidSettingValue):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrSettingsLimitExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, models.ErrSettingNotFound):
+		return errSettingNotFound
+	case errors.Is(err, models.ErrInvalidAttribute),
+		errors.Is(err, models.ErrAttributeNotFilterable):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrAttributeNotFound):
+		return errAttributeNotFound
+	case errors.Is(err, models.ErrAttributeExists):
+		return errAttributeExists
+	case errors.Is(err, models.ErrInvalidEmail),
+		errors.Is(err, models.ErrInvalidPhone):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrEmailNotFound):
+		return errEmailNotFound
+	case errors.Is(err, models.ErrPhoneNotFound):
+		return errPhoneNotFound
+	case errors.Is(err, models.ErrPhoneTaken):
+		return errPhoneTaken
+	case errors.Is(err, models.ErrPrimaryContact):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, models.ErrInvalidIdentity):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrIdentityNotFound):
+		return errIdentityNotFound
+	case errors.Is(err, models.ErrIdentityLinked):
+		return errIdentityLinked
+	case errors.Is(err, models.ErrInvalidOAuthClient):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrOAuthClientNotFound):
+		return errOAuthClientNotFound
+	case errors.Is(err, models.ErrCursorUserNotFound):
+		return errInvalidPageToken
+	case errors.Is(err, models.ErrInvalidFilter):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrInvalidSearchQuery):
+		return errInvalidSearchQuery
+	case errors.Is(err, models.ErrInvalidUserType),
+		errors.Is(err, models.ErrDuplicateImportRow):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrImportTooLarge):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, models.ErrBatchTooLarge):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrBatchAborted):
+		return errBatchAborted
+	case errors.Is(err, models.ErrResumeTokenExpired):
+		return errResumeTokenExpired
+	case errors.Is(err, models.ErrWatchLagging):
+		return errWatchLagging
+	case errors.Is(err, models.ErrInvalidStatsQuery):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrTooManyStatsGroups):
+		return errTooManyStatsGroups
 	default:
 		g.logger.Error(err)
 		return errInternal
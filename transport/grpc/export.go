@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	// 3rd party
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+const exportChunkSize = 64 << 10
+
+//go:generate moq -out export_service_mock_test.go . exportService
+type exportService interface {
+	ExportUserData(ctx context.Context, userID uuid.UUID, format models.ExportFormat, w io.Writer) error
+}
+
+func (g *GRPC) ExportUserData(req *pb.ExportUserDataRequest, stream pb.User_ExportUserDataServer) error {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return errInvalidUserID
+	}
+
+	format, contentType := models.ExportFormatJSON, "application/json"
+	if req.GetFormat() == pb.ExportFormat_EXPORT_FORMAT_ZIP {
+		format, contentType = models.ExportFormatZIP, "application/zip"
+	}
+
+	header := &pb.ExportUserDataResponse{
+		ContentType: contentType,
+		Filename:    fmt.Sprintf("user-%s.%s", userID, format),
+	}
+	cw := &chunkWriter{send: func(chunk []byte) error {
+		msg := &pb.ExportUserDataResponse{}
+		if header != nil {
+			msg, header = header, nil
+		}
+		msg.Chunk = chunk
+		return stream.Send(msg)
+	}}
+
+	w := bufio.NewWriterSize(cw, exportChunkSize)
+	if err := g.exportSvc.ExportUserData(stream.Context(), userID, format, w); err != nil {
+		return g.mapError(err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return g.mapError(err)
+	}
+
+	return nil
+}
+
+// chunkWriter sends writes in chunks of at most exportChunkSize bytes.
+type chunkWriter struct {
+	send func(chunk []byte) error
+}
+
+func (cw *chunkWriter) Write(p []byte) (int, error) {
+	var n int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > exportChunkSize {
+			chunk = chunk[:exportChunkSize]
+		}
+
+		if err := cw.send(chunk); err != nil {
+			return n, err
+		}
+
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
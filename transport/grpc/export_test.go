@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+type exportStream struct {
+	grpc.ServerStream
+	msgs []*user.ExportUserDataResponse
+}
+
+func (s *exportStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *exportStream) Send(msg *user.ExportUserDataResponse) error {
+	s.msgs = append(s.msgs, msg)
+	return nil
+}
+
+func TestGRPC_ExportUserData(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+	payload := bytes.Repeat([]byte("x"), exportChunkSize+10)
+
+	svc := &ExportServiceMock{
+		ExportUserDataFunc: func(ctx context.Context, uID uuid.UUID, format models.ExportFormat, w io.Writer) error {
+			if uID != userID {
+				return models.ErrUserNotFound
+			}
+			_, err := w.Write(payload)
+			return err
+		},
+	}
+
+	g := &GRPC{
+		exportSvc: svc,
+		logger:    zap.NewNop().Sugar(),
+	}
+
+	stream := &exportStream{}
+	err := g.ExportUserData(&user.ExportUserDataRequest{
+		UserId: userID.String(),
+		Format: user.ExportFormat_EXPORT_FORMAT_ZIP,
+	}, stream)
+	require.NoError(t, err)
+	require.Equal(t, models.ExportFormatZIP, svc.ExportUserDataCalls()[0].Format)
+
+	require.Len(t, stream.msgs, 2)
+	require.Equal(t, "application/zip", stream.msgs[0].GetContentType())
+	require.Equal(t, "user-b3ce8fed-d5e8-4583-8783-b95969b5bc0c.zip", stream.msgs[0].GetFilename())
+	require.Empty(t, stream.msgs[1].GetContentType())
+
+	var got []byte
+	for _, msg := range stream.msgs {
+		got = append(got, msg.GetChunk()...)
+	}
+	require.Equal(t, payload, got)
+
+	t.Log("user not found")
+	{
+		err := g.ExportUserData(&user.ExportUserDataRequest{UserId: uuid.NewString()}, &exportStream{})
+		require.ErrorIs(t, err, errUserNotFound)
+		require.Equal(t, models.ExportFormatJSON, svc.ExportUserDataCalls()[1].Format)
+	}
+}
@@ -0,0 +1,185 @@
+package grpc
+
+import (
+	"context"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out group_service_mock_test.go . groupService
+type groupService interface {
+	CreateGroup(ctx context.Context, name string) (uuid.UUID, error)
+	RenameGroup(ctx context.Context, groupID uuid.UUID, name string) error
+	DeleteGroup(ctx context.Context, groupID uuid.UUID) error
+	AddMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error
+	RemoveMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error
+	GetMembers(ctx context.Context, groupID uuid.UUID, opts models.GetGroupMembersOptions) ([]models.GroupMember, error)
+	GetUserGroups(ctx context.Context, userID uuid.UUID) ([]models.Group, error)
+}
+
+func (g *GRPC) CreateGroup(ctx context.Context, req *pb.CreateGroupRequest) (*pb.CreateGroupResponse, error) {
+	if req.GetName() == "" {
+		return nil, errInvalidGroupName
+	}
+
+	groupID, err := g.groupSvc.CreateGroup(ctx, req.GetName())
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.CreateGroupResponse{
+		GroupId: groupID.String(),
+	}, nil
+}
+
+func (g *GRPC) RenameGroup(ctx context.Context, req *pb.RenameGroupRequest) (*pb.RenameGroupResponse, error) {
+	groupID, err := uuid.Parse(req.GetGroupId())
+	if err != nil {
+		return nil, errInvalidGroupID
+	}
+
+	if req.GetName() == "" {
+		return nil, errInvalidGroupName
+	}
+
+	if err := g.groupSvc.RenameGroup(ctx, groupID, req.GetName()); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.RenameGroupResponse{
+		Success: true,
+	}, nil
+}
+
+func (g *GRPC) DeleteGroup(ctx context.Context, req *pb.DeleteGroupRequest) (*pb.DeleteGroupResponse, error) {
+	groupID, err := uuid.Parse(req.GetGroupId())
+	if err != nil {
+		return nil, errInvalidGroupID
+	}
+
+	if err := g.groupSvc.DeleteGroup(ctx, groupID); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.DeleteGroupResponse{
+		Success: true,
+	}, nil
+}
+
+func (g *GRPC) AddGroupMember(ctx context.Context, req *pb.AddGroupMemberRequest) (*pb.AddGroupMemberResponse, error) {
+	groupID, err := uuid.Parse(req.GetGroupId())
+	if err != nil {
+		return nil, errInvalidGroupID
+	}
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	if err := g.groupSvc.AddMember(ctx, groupID, userID); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.AddGroupMemberResponse{
+		Success: true,
+	}, nil
+}
+
+func (g *GRPC) RemoveGroupMember(ctx context.Context, req *pb.RemoveGroupMemberRequest) (*pb.RemoveGroupMemberResponse, error) {
+	groupID, err := uuid.Parse(req.GetGroupId())
+	if err != nil {
+		return nil, errInvalidGroupID
+	}
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	if err := g.groupSvc.RemoveMember(ctx, groupID, userID); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.RemoveGroupMemberResponse{
+		Success: true,
+	}, nil
+}
+
+func (g *GRPC) ListGroupMembers(ctx context.Context, req *pb.ListGroupMembersRequest) (*pb.ListGroupMembersResponse, error) {
+	groupID, err := uuid.Parse(req.GetGroupId())
+	if err != nil {
+		return nil, errInvalidGroupID
+	}
+
+	opts := models.GetGroupMembersOptions{
+		PageNumber: req.GetPageNumber(),
+		PageSize:   req.GetPageSize(),
+	}
+
+	if opts.PageNumber == 0 {
+		opts.PageNumber = 1
+	}
+
+	if opts.PageSize == 0 {
+		opts.PageSize = defaultPageSize
+	}
+
+	members, err := g.groupSvc.GetMembers(ctx, groupID, opts)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	items := make([]*pb.GroupMemberInfo, len(members))
+	for i, m := range members {
+		items[i] = &pb.GroupMemberInfo{
+			UserId:   m.UserID.String(),
+			JoinedAt: timestamppb.New(m.JoinedAt),
+		}
+	}
+
+	return &pb.ListGroupMembersResponse{
+		Members: items,
+	}, nil
+}
+
+func (g *GRPC) ListUserGroups(ctx context.Context, req *pb.ListUserGroupsRequest) (*pb.ListUserGroupsResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	groups, err := g.groupSvc.GetUserGroups(ctx, userID)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	items := make([]*pb.GroupInfo, len(groups))
+	for i, gr := range groups {
+		items[i] = mapGroupInfo(gr)
+	}
+
+	return &pb.ListUserGroupsResponse{
+		Groups: items,
+	}, nil
+}
+
+func mapGroupInfo(group models.Group) *pb.GroupInfo {
+	info := &pb.GroupInfo{
+		Id:        group.ID.String(),
+		Name:      group.Name,
+		CreatedAt: timestamppb.New(group.CreatedAt),
+	}
+
+	if group.UpdateAt != nil {
+		info.UpdatedAt = timestamppb.New(*group.UpdateAt)
+	}
+
+	return info
+}
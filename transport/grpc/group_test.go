@@ -0,0 +1,240 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_CreateGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		svc     groupService
+		req     *user.CreateGroupRequest
+		checkFn func(t *testing.T, resp *user.CreateGroupResponse, err error)
+	}{
+		{
+			name: "happy path",
+			svc: &GroupServiceMock{
+				CreateGroupFunc: func(ctx context.Context, name string) (uuid.UUID, error) {
+					return uuid.MustParse("5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11"), nil
+				},
+			},
+			req: &user.CreateGroupRequest{Name: "admins"},
+			checkFn: func(t *testing.T, resp *user.CreateGroupResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, &user.CreateGroupResponse{
+					GroupId: "5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11",
+				}, resp)
+			},
+		},
+		{
+			name: "empty name",
+			svc:  &GroupServiceMock{},
+			req:  &user.CreateGroupRequest{},
+			checkFn: func(t *testing.T, resp *user.CreateGroupResponse, err error) {
+				require.ErrorIs(t, err, errInvalidGroupName)
+			},
+		},
+		{
+			name: "name has been taken",
+			svc: &GroupServiceMock{
+				CreateGroupFunc: func(ctx context.Context, name string) (uuid.UUID, error) {
+					return uuid.Nil, models.ErrGroupNameTaken
+				},
+			},
+			req: &user.CreateGroupRequest{Name: "admins"},
+			checkFn: func(t *testing.T, resp *user.CreateGroupResponse, err error) {
+				require.ErrorIs(t, err, errGroupNameTaken)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GRPC{
+				groupSvc: tt.svc,
+				logger:   zap.NewNop().Sugar(),
+			}
+			got, err := g.CreateGroup(context.Background(), tt.req)
+			tt.checkFn(t, got, err)
+		})
+	}
+}
+
+func TestGRPC_AddGroupMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		svc     groupService
+		req     *user.AddGroupMemberRequest
+		checkFn func(t *testing.T, resp *user.AddGroupMemberResponse, err error)
+	}{
+		{
+			name: "happy path",
+			svc: &GroupServiceMock{
+				AddMemberFunc: func(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+					return nil
+				},
+			},
+			req: &user.AddGroupMemberRequest{
+				GroupId: "5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11",
+				UserId:  "1c8f21c1-c8d0-401c-89b5-3f577c54679e",
+			},
+			checkFn: func(t *testing.T, resp *user.AddGroupMemberResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, &user.AddGroupMemberResponse{Success: true}, resp)
+			},
+		},
+		{
+			name: "invalid group id",
+			svc:  &GroupServiceMock{},
+			req: &user.AddGroupMemberRequest{
+				GroupId: "invalid uuid",
+				UserId:  "1c8f21c1-c8d0-401c-89b5-3f577c54679e",
+			},
+			checkFn: func(t *testing.T, resp *user.AddGroupMemberResponse, err error) {
+				require.ErrorIs(t, err, errInvalidGroupID)
+			},
+		},
+		{
+			name: "invalid user id",
+			svc:  &GroupServiceMock{},
+			req: &user.AddGroupMemberRequest{
+				GroupId: "5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11",
+				UserId:  "invalid uuid",
+			},
+			checkFn: func(t *testing.T, resp *user.AddGroupMemberResponse, err error) {
+				require.ErrorIs(t, err, errInvalidUserID)
+			},
+		},
+		{
+			name: "user not found",
+			svc: &GroupServiceMock{
+				AddMemberFunc: func(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+					return models.ErrUserNotFound
+				},
+			},
+			req: &user.AddGroupMemberRequest{
+				GroupId: "5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11",
+				UserId:  "1c8f21c1-c8d0-401c-89b5-3f577c54679e",
+			},
+			checkFn: func(t *testing.T, resp *user.AddGroupMemberResponse, err error) {
+				require.ErrorIs(t, err, errUserNotFound)
+			},
+		},
+		{
+			name: "already a member",
+			svc: &GroupServiceMock{
+				AddMemberFunc: func(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+					return models.ErrAlreadyGroupMember
+				},
+			},
+			req: &user.AddGroupMemberRequest{
+				GroupId: "5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11",
+				UserId:  "1c8f21c1-c8d0-401c-89b5-3f577c54679e",
+			},
+			checkFn: func(t *testing.T, resp *user.AddGroupMemberResponse, err error) {
+				require.ErrorIs(t, err, errAlreadyGroupMember)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GRPC{
+				groupSvc: tt.svc,
+				logger:   zap.NewNop().Sugar(),
+			}
+			got, err := g.AddGroupMember(context.Background(), tt.req)
+			tt.checkFn(t, got, err)
+		})
+	}
+}
+
+func TestGRPC_ListGroupMembers(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		svc     groupService
+		req     *user.ListGroupMembersRequest
+		checkFn func(t *testing.T, resp *user.ListGroupMembersResponse, err error)
+	}{
+		{
+			name: "happy path",
+			svc: &GroupServiceMock{
+				GetMembersFunc: func(ctx context.Context, groupID uuid.UUID, opts models.GetGroupMembersOptions) ([]models.GroupMember, error) {
+					require.Equal(t, uint64(1), opts.PageNumber)
+					require.Equal(t, uint64(defaultPageSize), opts.PageSize)
+
+					return []models.GroupMember{
+						{
+							GroupID:  groupID,
+							UserID:   uuid.MustParse("1c8f21c1-c8d0-401c-89b5-3f577c54679e"),
+							JoinedAt: now,
+						},
+					}, nil
+				},
+			},
+			req: &user.ListGroupMembersRequest{
+				GroupId: "5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11",
+			},
+			checkFn: func(t *testing.T, resp *user.ListGroupMembersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, resp.GetMembers(), 1)
+				require.Equal(t, "1c8f21c1-c8d0-401c-89b5-3f577c54679e", resp.GetMembers()[0].GetUserId())
+				require.True(t, timestamppb.New(now).AsTime().Equal(resp.GetMembers()[0].GetJoinedAt().AsTime()))
+			},
+		},
+		{
+			name: "group not found",
+			svc: &GroupServiceMock{
+				GetMembersFunc: func(ctx context.Context, groupID uuid.UUID, opts models.GetGroupMembersOptions) ([]models.GroupMember, error) {
+					return nil, models.ErrGroupNotFound
+				},
+			},
+			req: &user.ListGroupMembersRequest{
+				GroupId: "5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11",
+			},
+			checkFn: func(t *testing.T, resp *user.ListGroupMembersResponse, err error) {
+				require.ErrorIs(t, err, errGroupNotFound)
+			},
+		},
+		{
+			name: "internal server error",
+			svc: &GroupServiceMock{
+				GetMembersFunc: func(ctx context.Context, groupID uuid.UUID, opts models.GetGroupMembersOptions) ([]models.GroupMember, error) {
+					return nil, errors.New("internal error")
+				},
+			},
+			req: &user.ListGroupMembersRequest{
+				GroupId: "5e0b4ef4-6d0e-4f43-a3c3-2a4f7f4e2d11",
+			},
+			checkFn: func(t *testing.T, resp *user.ListGroupMembersResponse, err error) {
+				require.ErrorIs(t, err, errInternal)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GRPC{
+				groupSvc: tt.svc,
+				logger:   zap.NewNop().Sugar(),
+			}
+			got, err := g.ListGroupMembers(context.Background(), tt.req)
+			tt.checkFn(t, got, err)
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"time"
+
+	// 3rd party
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+const (
+	idempotencyKeyHeader = "idempotency-key"
+	// idempotentReplayedHeader is set on the responses replayed to retries.
+	idempotentReplayedHeader = "idempotent-replayed"
+	// idempotencyStoreTimeout bounds recording the outcome of a call, which
+	// outlives the call so that a client giving up does not lose it.
+	idempotencyStoreTimeout = 5 * time.Second
+)
+
+// readOnlyMethodPrefixes are the prefixes of the methods of the User service
+// that change nothing, and so ignore idempotency keys.
+var readOnlyMethodPrefixes = []string{"Get", "List", "Query", "Search", "Export", "Find", "Watch"}
+
+// secretMethods are the methods of the User service whose responses carry
+// secrets, which are never stored, so they reject idempotency keys.
+var secretMethods = map[string]bool{
+	"CreateAPIKey":        true,
+	"RegisterOAuthClient": true,
+}
+
+//go:generate moq -out idempotency_service_mock_test.go . idempotencyService
+type idempotencyService interface {
+	Begin(ctx context.Context, call models.IdempotentCall) (models.IdempotentCall, bool, error)
+	Complete(ctx context.Context, call models.IdempotentCall) error
+	Release(ctx context.Context, call models.IdempotentCall) error
+}
+
+// idempotencyInterceptor makes the unary mutations of the User service called
+// with an idempotency-key header safe to retry: the response of the first call
+// is replayed to the retries carrying the same key, by the same authenticated
+// user. Failed calls are not recorded, so that they can be retried. The keys of
+// anonymous calls are ignored. The client-streaming ImportUsers is not covered,
+// as its request is only known once streamed. It must run after the
+// authentication interceptor.
+type idempotencyInterceptor struct {
+	calls  idempotencyService
+	logger *zap.SugaredLogger
+}
+
+func (i *idempotencyInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		key := firstValue(md, idempotencyKeyHeader)
+
+		msg, ok := req.(proto.Message)
+		if key == "" || !ok || !mutatingMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if secretMethods[strings.TrimPrefix(info.FullMethod, userServicePrefix)] {
+			return nil, errIdempotencyKeyNotSupported
+		}
+
+		// Responses are only replayed to the user who made the call, so the
+		// keys of anonymous callers are ignored rather than shared by all of
+		// them.
+		principal, ok := auth.PrincipalFromContext(ctx)
+		if !ok {
+			i.logger.Warnw("ignoring idempotency key of anonymous call", "method", info.FullMethod)
+			return handler(ctx, req)
+		}
+
+		hash, err := requestHash(msg)
+		if err != nil {
+			i.logger.Error(err)
+			return nil, errInternal
+		}
+
+		call := models.IdempotentCall{
+			Caller:      principal.UserID.String(),
+			Key:         key,
+			Method:      info.FullMethod,
+			RequestHash: hash,
+		}
+
+		held, replay, err := i.calls.Begin(ctx, call)
+		if err != nil {
+			return nil, i.mapError(err)
+		}
+		if replay {
+			return i.replay(ctx, held)
+		}
+
+		resp, err := handler(ctx, req)
+
+		storeCtx, cancel := context.WithTimeout(context.Background(), idempotencyStoreTimeout)
+		defer cancel()
+
+		if err != nil {
+			if err := i.calls.Release(storeCtx, call); err != nil {
+				i.logger.Errorw("release idempotency key", "method", info.FullMethod, "error", err)
+			}
+			return nil, err
+		}
+
+		if call.Response, err = proto.Marshal(resp.(proto.Message)); err == nil {
+			call.ResponseType = string(resp.(proto.Message).ProtoReflect().Descriptor().FullName())
+			err = i.calls.Complete(storeCtx, call)
+		}
+		// The call succeeded all the same; retries fail until the key can be
+		// taken over.
+		if err != nil {
+			i.logger.Errorw("complete idempotency key", "method", info.FullMethod, "error", err)
+		}
+
+		return resp, nil
+	}
+}
+
+// replay returns the response recorded for the call.
+func (i *idempotencyInterceptor) replay(ctx context.Context, call models.IdempotentCall) (any, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(call.ResponseType))
+	if err != nil {
+		i.logger.Errorw("replay idempotency key", "method", call.Method, "error", err)
+		return nil, errInternal
+	}
+
+	resp := mt.New().Interface()
+	if err := proto.Unmarshal(call.Response, resp); err != nil {
+		i.logger.Errorw("replay idempotency key", "method", call.Method, "error", err)
+		return nil, errInternal
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(idempotentReplayedHeader, "true"))
+
+	return resp, nil
+}
+
+func (i *idempotencyInterceptor) mapError(err error) error {
+	switch {
+	case errors.Is(err, models.ErrInvalidIdempotencyKey):
+		return errInvalidIdempotencyKey
+	case errors.Is(err, models.ErrIdempotencyKeyReused):
+		return errIdempotencyKeyReused
+	case errors.Is(err, models.ErrIdempotencyKeyInUse):
+		return errIdempotencyKeyInUse
+	default:
+		i.logger.Error(err)
+		return errInternal
+	}
+}
+
+// mutatingMethod reports whether the method is a User service method that may
+// change something.
+func mutatingMethod(fullMethod string) bool {
+	method, ok := strings.CutPrefix(fullMethod, userServicePrefix)
+	if !ok {
+		return false
+	}
+
+	for _, prefix := range readOnlyMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// requestHash identifies the request by its content, whatever the order its
+// fields were encoded in.
+func requestHash(req proto.Message) ([]byte, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
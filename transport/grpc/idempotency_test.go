@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestIdempotencyInterceptor_Unary(t *testing.T) {
+	// calls holds the calls by caller and key, like the storage does.
+	calls := map[string]models.IdempotentCall{}
+	svc := &IdempotencyServiceMock{
+		BeginFunc: func(ctx context.Context, call models.IdempotentCall) (models.IdempotentCall, bool, error) {
+			held, ok := calls[call.Caller+"/"+call.Key]
+			switch {
+			case !ok:
+				calls[call.Caller+"/"+call.Key] = call
+				return models.IdempotentCall{}, false, nil
+			case !bytes.Equal(held.RequestHash, call.RequestHash):
+				return models.IdempotentCall{}, false, models.ErrIdempotencyKeyReused
+			default:
+				return held, true, nil
+			}
+		},
+		CompleteFunc: func(ctx context.Context, call models.IdempotentCall) error {
+			calls[call.Caller+"/"+call.Key] = call
+			return nil
+		},
+		ReleaseFunc: func(ctx context.Context, call models.IdempotentCall) error {
+			delete(calls, call.Caller+"/"+call.Key)
+			return nil
+		},
+	}
+
+	interceptor := (&idempotencyInterceptor{calls: svc, logger: zap.NewNop().Sugar()}).Unary()
+	callerID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	var handled int
+	handler := func(ctx context.Context, req any) (any, error) {
+		handled++
+		if req.(*user.CreateUserRequest).GetEmail() == "" {
+			return nil, errInvalidUserID
+		}
+		return &user.CreateUserResponse{UserId: "0e1b7a6c-3f5d-4a8e-9b2c-6d4f1e8a7c3b"}, nil
+	}
+
+	call := func(key string, method string, req *user.CreateUserRequest) (any, error) {
+		ctx := auth.WithPrincipal(context.Background(), models.Principal{UserID: callerID})
+		if key != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(idempotencyKeyHeader, key))
+		}
+		return interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: userServicePrefix + method}, handler)
+	}
+
+	req := &user.CreateUserRequest{Email: "bruce@wayne.com"}
+
+	first, err := call("key-1", "CreateUser", req)
+	require.NoError(t, err)
+	require.Equal(t, 1, handled)
+	require.Equal(t, "services.user.CreateUserResponse", calls[callerID.String()+"/key-1"].ResponseType)
+
+	t.Log("retry replays the response")
+	{
+		resp, err := call("key-1", "CreateUser", proto.Clone(req).(*user.CreateUserRequest))
+		require.NoError(t, err)
+		require.Equal(t, 1, handled)
+		require.True(t, proto.Equal(first.(proto.Message), resp.(proto.Message)))
+	}
+
+	t.Log("key reused for another request")
+	{
+		_, err := call("key-1", "CreateUser", &user.CreateUserRequest{Email: "clark@kent.com"})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+		require.Equal(t, 1, handled)
+	}
+
+	t.Log("failed calls can be retried")
+	{
+		_, err := call("key-2", "CreateUser", &user.CreateUserRequest{})
+		require.ErrorIs(t, err, errInvalidUserID)
+		require.Len(t, svc.ReleaseCalls(), 1)
+		require.NotContains(t, calls, callerID.String()+"/key-2")
+	}
+
+	t.Log("ignored without key, and by read-only methods")
+	{
+		begun := len(svc.BeginCalls())
+
+		_, err := call("", "CreateUser", req)
+		require.NoError(t, err)
+		_, err = call("key-3", "QueryUsers", req)
+		require.NoError(t, err)
+		require.Len(t, svc.BeginCalls(), begun)
+	}
+
+	t.Log("rejected by methods returning secrets")
+	{
+		begun := len(svc.BeginCalls())
+
+		_, err := call("key-5", "CreateAPIKey", req)
+		require.ErrorIs(t, err, errIdempotencyKeyNotSupported)
+		_, err = call("key-5", "RegisterOAuthClient", req)
+		require.ErrorIs(t, err, errIdempotencyKeyNotSupported)
+		require.Len(t, svc.BeginCalls(), begun)
+	}
+
+	t.Log("keys of another user are not replayed")
+	{
+		before := handled
+
+		ctx := auth.WithPrincipal(context.Background(), models.Principal{UserID: uuid.New()})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(idempotencyKeyHeader, "key-1"))
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: userServicePrefix + "CreateUser"}, handler)
+		require.NoError(t, err)
+		require.Equal(t, before+1, handled)
+	}
+
+	t.Log("ignored without a principal")
+	{
+		begun := len(svc.BeginCalls())
+		before := handled
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, "key-6"))
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: userServicePrefix + "CreateUser"}, handler)
+		require.NoError(t, err)
+		require.Equal(t, before+1, handled)
+		require.Len(t, svc.BeginCalls(), begun)
+	}
+
+	t.Log("storage failure")
+	{
+		failing := &IdempotencyServiceMock{
+			BeginFunc: func(ctx context.Context, call models.IdempotentCall) (models.IdempotentCall, bool, error) {
+				return models.IdempotentCall{}, false, errors.New("db down")
+			},
+		}
+		interceptor := (&idempotencyInterceptor{calls: failing, logger: zap.NewNop().Sugar()}).Unary()
+
+		ctx := auth.WithPrincipal(context.Background(), models.Principal{UserID: callerID})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(idempotencyKeyHeader, "key-4"))
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: userServicePrefix + "CreateUser"}, handler)
+		require.ErrorIs(t, err, errInternal)
+	}
+}
@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out identity_service_mock_test.go . identityService
+type identityService interface {
+	LinkIdentity(ctx context.Context, userID uuid.UUID, provider string, subject string, claims map[string]any) error
+	UnlinkIdentity(ctx context.Context, userID uuid.UUID, provider string, subject string) error
+	ListIdentities(ctx context.Context, userID uuid.UUID) ([]models.ExternalIdentity, error)
+	FindUserByExternalIdentity(ctx context.Context, lookup models.ExternalIdentityLookup) (uuid.UUID, bool, error)
+}
+
+func (g *GRPC) LinkIdentity(ctx context.Context, req *pb.LinkIdentityRequest) (*pb.LinkIdentityResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	err = g.identitySvc.LinkIdentity(ctx, userID, req.GetProvider(), req.GetSubject(), req.GetClaims().AsMap())
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.LinkIdentityResponse{Success: true}, nil
+}
+
+func (g *GRPC) UnlinkIdentity(ctx context.Context, req *pb.UnlinkIdentityRequest) (*pb.UnlinkIdentityResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	if err := g.identitySvc.UnlinkIdentity(ctx, userID, req.GetProvider(), req.GetSubject()); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.UnlinkIdentityResponse{Success: true}, nil
+}
+
+func (g *GRPC) ListIdentities(ctx context.Context, req *pb.ListIdentitiesRequest) (*pb.ListIdentitiesResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	identities, err := g.identitySvc.ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	resp := &pb.ListIdentitiesResponse{
+		Identities: make([]*pb.ExternalIdentity, 0, len(identities)),
+	}
+	for _, identity := range identities {
+		claims, err := structpb.NewStruct(identity.Claims)
+		if err != nil {
+			return nil, g.mapError(err)
+		}
+
+		resp.Identities = append(resp.Identities, &pb.ExternalIdentity{
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+			Claims:   claims,
+			LinkedAt: timestamppb.New(identity.LinkedAt),
+		})
+	}
+
+	return resp, nil
+}
+
+func (g *GRPC) FindUserByExternalIdentity(ctx context.Context, req *pb.FindUserByExternalIdentityRequest) (*pb.FindUserByExternalIdentityResponse, error) {
+	lookup := models.ExternalIdentityLookup{
+		Provider: req.GetProvider(),
+		Subject:  req.GetSubject(),
+		Claims:   req.GetClaims().AsMap(),
+	}
+
+	if p := req.GetProvision(); p != nil {
+		// Provisioning creates a user.
+		if principal, ok := auth.PrincipalFromContext(ctx); ok && !principal.HasScope(models.ScopeUsersWrite) {
+			return nil, errPermissionDenied
+		}
+
+		lookup.Provision = &models.NewUser{
+			Email:     p.GetEmail(),
+			FirstName: p.GetFirstName(),
+			LastName:  p.GetLastName(),
+			Nickname:  p.GetNickname(),
+			Country:   p.GetCountry(),
+		}
+	}
+
+	userID, provisioned, err := g.identitySvc.FindUserByExternalIdentity(ctx, lookup)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.FindUserByExternalIdentityResponse{
+		UserId:      userID.String(),
+		Provisioned: provisioned,
+	}, nil
+}
@@ -0,0 +1,135 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_LinkIdentity(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+	claims, err := structpb.NewStruct(map[string]any{"email": "bruce@wayne.com"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		svcErr  error
+		req     *user.LinkIdentityRequest
+		checkFn func(t *testing.T, svc *IdentityServiceMock, err error)
+	}{
+		{
+			name: "happy path",
+			req:  &user.LinkIdentityRequest{UserId: userID.String(), Provider: "google", Subject: "1234", Claims: claims},
+			checkFn: func(t *testing.T, svc *IdentityServiceMock, err error) {
+				require.NoError(t, err)
+				require.Len(t, svc.LinkIdentityCalls(), 1)
+				require.Equal(t, userID, svc.LinkIdentityCalls()[0].UserID)
+				require.Equal(t, map[string]any{"email": "bruce@wayne.com"}, svc.LinkIdentityCalls()[0].Claims)
+			},
+		},
+		{
+			name: "invalid user id",
+			req:  &user.LinkIdentityRequest{UserId: "1234"},
+			checkFn: func(t *testing.T, svc *IdentityServiceMock, err error) {
+				require.ErrorIs(t, err, errInvalidUserID)
+				require.Len(t, svc.LinkIdentityCalls(), 0)
+			},
+		},
+		{
+			name:   "already linked",
+			svcErr: models.ErrIdentityLinked,
+			req:    &user.LinkIdentityRequest{UserId: userID.String(), Provider: "google", Subject: "1234"},
+			checkFn: func(t *testing.T, svc *IdentityServiceMock, err error) {
+				require.ErrorIs(t, err, errIdentityLinked)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &IdentityServiceMock{
+				LinkIdentityFunc: func(ctx context.Context, userID uuid.UUID, provider string, subject string, claims map[string]any) error {
+					return tt.svcErr
+				},
+			}
+
+			g := &GRPC{
+				identitySvc: svc,
+				logger:      zap.NewNop().Sugar(),
+			}
+
+			_, err := g.LinkIdentity(context.Background(), tt.req)
+			tt.checkFn(t, svc, err)
+		})
+	}
+}
+
+func TestGRPC_FindUserByExternalIdentity(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+	provision := &user.FindUserByExternalIdentityRequest_Provision{Email: "bruce@wayne.com"}
+
+	tests := []struct {
+		name    string
+		scopes  []string
+		req     *user.FindUserByExternalIdentityRequest
+		checkFn func(t *testing.T, svc *IdentityServiceMock, resp *user.FindUserByExternalIdentityResponse, err error)
+	}{
+		{
+			name:   "lookup",
+			scopes: []string{models.ScopeIdentitiesRead},
+			req:    &user.FindUserByExternalIdentityRequest{Provider: "google", Subject: "1234"},
+			checkFn: func(t *testing.T, svc *IdentityServiceMock, resp *user.FindUserByExternalIdentityResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, userID.String(), resp.GetUserId())
+				require.Nil(t, svc.FindUserByExternalIdentityCalls()[0].Lookup.Provision)
+			},
+		},
+		{
+			name:   "provision",
+			scopes: []string{models.ScopeIdentitiesRead, models.ScopeUsersWrite},
+			req:    &user.FindUserByExternalIdentityRequest{Provider: "google", Subject: "1234", Provision: provision},
+			checkFn: func(t *testing.T, svc *IdentityServiceMock, resp *user.FindUserByExternalIdentityResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "bruce@wayne.com", svc.FindUserByExternalIdentityCalls()[0].Lookup.Provision.Email)
+			},
+		},
+		{
+			name:   "provision without users:write",
+			scopes: []string{models.ScopeIdentitiesRead},
+			req:    &user.FindUserByExternalIdentityRequest{Provider: "google", Subject: "1234", Provision: provision},
+			checkFn: func(t *testing.T, svc *IdentityServiceMock, resp *user.FindUserByExternalIdentityResponse, err error) {
+				require.ErrorIs(t, err, errPermissionDenied)
+				require.Len(t, svc.FindUserByExternalIdentityCalls(), 0)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &IdentityServiceMock{
+				FindUserByExternalIdentityFunc: func(ctx context.Context, lookup models.ExternalIdentityLookup) (uuid.UUID, bool, error) {
+					return userID, lookup.Provision != nil, nil
+				},
+			}
+
+			g := &GRPC{
+				identitySvc: svc,
+				logger:      zap.NewNop().Sugar(),
+			}
+
+			ctx := auth.WithPrincipal(context.Background(), models.Principal{Scopes: tt.scopes})
+			resp, err := g.FindUserByExternalIdentity(ctx, tt.req)
+			tt.checkFn(t, svc, resp, err)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"errors"
+	"io"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/grpc/status"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+var importActions = map[models.ImportAction]pb.ImportAction{
+	models.ImportActionCreated: pb.ImportAction_IMPORT_ACTION_CREATED,
+	models.ImportActionUpdated: pb.ImportAction_IMPORT_ACTION_UPDATED,
+	models.ImportActionFailed:  pb.ImportAction_IMPORT_ACTION_FAILED,
+}
+
+// ImportUsers creates the users of the streamed requests, reading the options
+// of the import from the first one. Rows failing on their own are reported
+// in the response; other errors fail the call.
+func (g *GRPC) ImportUsers(stream pb.User_ImportUsersServer) error {
+	ctx := stream.Context()
+
+	req, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	opts := models.ImportUsersOptions{
+		DryRun: req.GetDryRun(),
+		Upsert: req.GetUpsert(),
+	}
+
+	// pending are the rows of the last request not yet passed to the service.
+	pending := req.GetUsers()
+	next := func() (models.NewUser, error) {
+		for len(pending) == 0 {
+			req, err := stream.Recv()
+			if err != nil {
+				return models.NewUser{}, err
+			}
+			pending = req.GetUsers()
+		}
+
+		nu := mapNewUser(pending[0])
+		pending = pending[1:]
+		return nu, nil
+	}
+
+	results, err := g.svc.ImportUsers(ctx, opts, next)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return g.mapError(err)
+	}
+
+	resp := &pb.ImportUsersResponse{
+		Results: make([]*pb.ImportUsersResponse_Result, 0, len(results)),
+		DryRun:  opts.DryRun,
+	}
+	for _, r := range results {
+		result := &pb.ImportUsersResponse_Result{
+			Row:    uint64(r.Row),
+			Action: importActions[r.Action],
+		}
+		if r.UserID != uuid.Nil {
+			result.UserId = r.UserID.String()
+		}
+
+		switch r.Action {
+		case models.ImportActionCreated:
+			resp.Created++
+		case models.ImportActionUpdated:
+			resp.Updated++
+		case models.ImportActionFailed:
+			resp.Failed++
+			result.Error = status.Convert(g.mapError(r.Err)).Message()
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	return stream.SendAndClose(resp)
+}
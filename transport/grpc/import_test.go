@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+type importUsersStream struct {
+	grpc.ServerStream
+	reqs []*user.ImportUsersRequest
+	resp *user.ImportUsersResponse
+}
+
+func (s *importUsersStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *importUsersStream) Recv() (*user.ImportUsersRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *importUsersStream) SendAndClose(resp *user.ImportUsersResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestGRPC_ImportUsers(t *testing.T) {
+	createdID := uuid.New()
+	updatedID := uuid.New()
+
+	svc := &UserServiceMock{
+		ImportUsersFunc: func(ctx context.Context, opts models.ImportUsersOptions, next func() (models.NewUser, error)) ([]models.ImportResult, error) {
+			var rows []models.NewUser
+			for {
+				nu, err := next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+				rows = append(rows, nu)
+			}
+
+			require.Equal(t, []models.NewUser{
+				{Type: models.UserTypeHuman, Email: "antonis+1@mail.com"},
+				{Type: models.UserTypeServiceAccount, Email: "antonis+2@mail.com"},
+				{Type: models.UserTypeHuman, Email: "antonis+3@mail.com"},
+			}, rows)
+
+			return []models.ImportResult{
+				{Row: 1, UserID: createdID, Action: models.ImportActionCreated},
+				{Row: 2, UserID: updatedID, Action: models.ImportActionUpdated},
+				{Row: 3, Action: models.ImportActionFailed, Err: models.ErrEmailTaken},
+			}, nil
+		},
+	}
+
+	g := &GRPC{
+		svc:    svc,
+		logger: zap.NewNop().Sugar(),
+	}
+
+	stream := &importUsersStream{
+		reqs: []*user.ImportUsersRequest{
+			{
+				Upsert: true,
+				Users: []*user.CreateUserRequest{
+					{Email: "antonis+1@mail.com"},
+					{Email: "antonis+2@mail.com", Type: user.UserType_USER_TYPE_SERVICE_ACCOUNT},
+				},
+			},
+			{},
+			{
+				DryRun: true,
+				Users:  []*user.CreateUserRequest{{Email: "antonis+3@mail.com"}},
+			},
+		},
+	}
+
+	err := g.ImportUsers(stream)
+	require.NoError(t, err)
+
+	require.Equal(t, models.ImportUsersOptions{Upsert: true}, svc.ImportUsersCalls()[0].Opts)
+
+	resp := stream.resp
+	require.Equal(t, uint64(1), resp.GetCreated())
+	require.Equal(t, uint64(1), resp.GetUpdated())
+	require.Equal(t, uint64(1), resp.GetFailed())
+	require.False(t, resp.GetDryRun())
+	require.Len(t, resp.GetResults(), 3)
+	require.Equal(t, createdID.String(), resp.GetResults()[0].GetUserId())
+	require.Equal(t, user.ImportAction_IMPORT_ACTION_UPDATED, resp.GetResults()[1].GetAction())
+	require.Equal(t, uint64(3), resp.GetResults()[2].GetRow())
+	require.Empty(t, resp.GetResults()[2].GetUserId())
+	require.Equal(t, "email is already used", resp.GetResults()[2].GetError())
+
+	t.Log("too many rows")
+	{
+		svc.ImportUsersFunc = func(ctx context.Context, opts models.ImportUsersOptions, next func() (models.NewUser, error)) ([]models.ImportResult, error) {
+			return nil, models.ErrImportTooLarge
+		}
+
+		err := g.ImportUsers(&importUsersStream{})
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	}
+}
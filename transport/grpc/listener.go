@@ -29,11 +29,27 @@ type Server struct {
 func NewServer(
 	logger *zap.SugaredLogger,
 	addr string,
-	svc userService) *Server {
+	opts Options,
+	svcs Services) *Server {
+	authn := &authInterceptor{
+		authn:    svcs.APIKey,
+		required: opts.AuthRequired,
+		logger:   logger,
+	}
+	requestMetadata := &requestMetadata{
+		trustedProxies: opts.TrustedProxies,
+	}
+	idempotency := &idempotencyInterceptor{
+		calls:  svcs.Idempotency,
+		logger: logger,
+	}
+
 	grpcServer := grpc.NewServer(
 		grpc.ConnectionTimeout(defaultConnectionTimeout),
+		grpc.ChainUnaryInterceptor(authn.Unary(), requestMetadata.Unary(), idempotency.Unary()),
+		grpc.ChainStreamInterceptor(authn.Stream(), requestMetadata.Stream()),
 	)
-	pb.RegisterUserServer(grpcServer, New(logger, svc))
+	pb.RegisterUserServer(grpcServer, New(logger, opts, svcs))
 
 	/*
 		Used mostly for testing under development.
@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/audit"
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+)
+
+const (
+	requestIDHeader     = "x-request-id"
+	forwardedForHeader  = "x-forwarded-for"
+	anonymousActor      = "anonymous"
+	forwardedForListSep = ","
+)
+
+// requestMetadata records who made the request and where it came from, so that
+// mutations can be attributed in the audit log. It must run after the auth
+// interceptor.
+type requestMetadata struct {
+	// trustedProxies are the networks of the proxies whose x-forwarded-for
+	// header is honored.
+	trustedProxies []*net.IPNet
+}
+
+func (rm *requestMetadata) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(rm.withRequestMetadata(ctx), req)
+	}
+}
+
+func (rm *requestMetadata) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: rm.withRequestMetadata(ss.Context())})
+	}
+}
+
+// withRequestMetadata attributes the request to the authenticated principal,
+// and to anonymousActor for calls without credentials.
+func (rm *requestMetadata) withRequestMetadata(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	m := audit.Metadata{
+		Actor:     anonymousActor,
+		RequestID: firstValue(md, requestIDHeader),
+		ClientIP:  rm.clientIP(ctx, md),
+	}
+
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		m.Actor = principal.UserID.String()
+	}
+
+	if m.RequestID == "" {
+		m.RequestID = uuid.NewString()
+	}
+
+	return audit.WithMetadata(ctx, m)
+}
+
+// clientIP is the address of the connection, unless it comes from a trusted
+// proxy. x-forwarded-for is then walked from the right, past the trusted
+// proxies, to the address they received the request from.
+func (rm *requestMetadata) clientIP(ctx context.Context, md metadata.MD) string {
+	ip := peerIP(ctx)
+	if !rm.trusted(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(md.Get(forwardedForHeader), forwardedForListSep), forwardedForListSep)
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+
+		ip = hop
+		if !rm.trusted(hop) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func (rm *requestMetadata) trusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, n := range rm.trustedProxies {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out oauth_client_service_mock_test.go . oauthClientService
+type oauthClientService interface {
+	RegisterClient(ctx context.Context, nc models.NewOAuthClient) (models.OAuthClient, string, error)
+	ListClients(ctx context.Context) ([]models.OAuthClient, error)
+	DeleteClient(ctx context.Context, clientID uuid.UUID) error
+}
+
+func (g *GRPC) RegisterOAuthClient(ctx context.Context, req *pb.RegisterOAuthClientRequest) (*pb.RegisterOAuthClientResponse, error) {
+	client, secret, err := g.oauthClientSvc.RegisterClient(ctx, models.NewOAuthClient{
+		Name:         req.GetName(),
+		RedirectURIs: req.GetRedirectUris(),
+		Public:       req.GetPublic(),
+	})
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.RegisterOAuthClientResponse{
+		ClientSecret: secret,
+		Client:       mapOAuthClientInfo(client),
+	}, nil
+}
+
+func (g *GRPC) ListOAuthClients(ctx context.Context, _ *pb.ListOAuthClientsRequest) (*pb.ListOAuthClientsResponse, error) {
+	clients, err := g.oauthClientSvc.ListClients(ctx)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	resp := &pb.ListOAuthClientsResponse{
+		Clients: make([]*pb.OAuthClientInfo, 0, len(clients)),
+	}
+	for _, c := range clients {
+		resp.Clients = append(resp.Clients, mapOAuthClientInfo(c))
+	}
+
+	return resp, nil
+}
+
+func (g *GRPC) DeleteOAuthClient(ctx context.Context, req *pb.DeleteOAuthClientRequest) (*pb.DeleteOAuthClientResponse, error) {
+	clientID, err := uuid.Parse(req.GetClientId())
+	if err != nil {
+		return nil, errInvalidClientID
+	}
+
+	if err := g.oauthClientSvc.DeleteClient(ctx, clientID); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.DeleteOAuthClientResponse{Success: true}, nil
+}
+
+func mapOAuthClientInfo(c models.OAuthClient) *pb.OAuthClientInfo {
+	return &pb.OAuthClientInfo{
+		ClientId:     c.ID.String(),
+		Name:         c.Name,
+		RedirectUris: c.RedirectURIs,
+		Public:       c.Public(),
+		CreatedAt:    timestamppb.New(c.CreatedAt),
+	}
+}
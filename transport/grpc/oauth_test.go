@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_RegisterOAuthClient(t *testing.T) {
+	clientID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	tests := []struct {
+		name    string
+		svcErr  error
+		req     *user.RegisterOAuthClientRequest
+		checkFn func(t *testing.T, svc *OauthClientServiceMock, resp *user.RegisterOAuthClientResponse, err error)
+	}{
+		{
+			name: "happy path",
+			req:  &user.RegisterOAuthClientRequest{Name: "partner", RedirectUris: []string{"https://app.example.com/callback"}},
+			checkFn: func(t *testing.T, svc *OauthClientServiceMock, resp *user.RegisterOAuthClientResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "s3cret", resp.GetClientSecret())
+				require.Equal(t, clientID.String(), resp.GetClient().GetClientId())
+				require.False(t, resp.GetClient().GetPublic())
+				require.Equal(t, []string{"https://app.example.com/callback"}, svc.RegisterClientCalls()[0].Nc.RedirectURIs)
+			},
+		},
+		{
+			name:   "invalid redirect uri",
+			svcErr: fmt.Errorf("%w: %q", models.ErrInvalidOAuthClient, "/callback"),
+			req:    &user.RegisterOAuthClientRequest{Name: "partner", RedirectUris: []string{"/callback"}},
+			checkFn: func(t *testing.T, svc *OauthClientServiceMock, resp *user.RegisterOAuthClientResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &OauthClientServiceMock{
+				RegisterClientFunc: func(ctx context.Context, nc models.NewOAuthClient) (models.OAuthClient, string, error) {
+					if tt.svcErr != nil {
+						return models.OAuthClient{}, "", tt.svcErr
+					}
+					return models.OAuthClient{ID: clientID, Name: nc.Name, SecretHash: []byte("hash"), RedirectURIs: nc.RedirectURIs}, "s3cret", nil
+				},
+			}
+
+			g := &GRPC{
+				oauthClientSvc: svc,
+				logger:         zap.NewNop().Sugar(),
+			}
+
+			resp, err := g.RegisterOAuthClient(context.Background(), tt.req)
+			tt.checkFn(t, svc, resp, err)
+		})
+	}
+}
+
+func TestGRPC_DeleteOAuthClient(t *testing.T) {
+	svc := &OauthClientServiceMock{
+		DeleteClientFunc: func(ctx context.Context, clientID uuid.UUID) error {
+			return models.ErrOAuthClientNotFound
+		},
+	}
+
+	g := &GRPC{
+		oauthClientSvc: svc,
+		logger:         zap.NewNop().Sugar(),
+	}
+
+	_, err := g.DeleteOAuthClient(context.Background(), &user.DeleteOAuthClientRequest{ClientId: "partner"})
+	require.ErrorIs(t, err, errInvalidClientID)
+	require.Len(t, svc.DeleteClientCalls(), 0)
+
+	_, err = g.DeleteOAuthClient(context.Background(), &user.DeleteOAuthClientRequest{ClientId: uuid.NewString()})
+	require.ErrorIs(t, err, errOAuthClientNotFound)
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+
+	// 3rd party
+	"google.golang.org/protobuf/proto"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+// searchCursor is the position of a page of search results.
+type searchCursor struct {
+	Offset uint64 `json:"o"`
+}
+
+func (g *GRPC) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	pageSize := req.GetPageSize()
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	opts := models.SearchUsersOptions{
+		Query:  req.GetQuery(),
+		Status: mapUserStatusFromPB(req.GetStatus()),
+		Limit:  pageSize,
+	}
+
+	query, err := searchFingerprint(req)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	if req.GetPageToken() != "" {
+		var cursor searchCursor
+		if err := g.pageTokens.Decode(req.GetPageToken(), query, &cursor); err != nil {
+			return nil, errInvalidPageToken
+		}
+		opts.Offset = cursor.Offset
+	}
+
+	matches, total, err := g.svc.SearchUsers(ctx, opts)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	users := make([]models.User, len(matches))
+	for i, m := range matches {
+		users[i] = m.User
+	}
+
+	infos, err := mapUsersInfo(users)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	resp := &pb.SearchUsersResponse{
+		Results:   make([]*pb.SearchUsersResponse_Result, len(matches)),
+		TotalSize: uint64(total),
+	}
+	for i, m := range matches {
+		resp.Results[i] = &pb.SearchUsersResponse_Result{
+			User:       infos.GetUsers()[i],
+			Score:      m.Score,
+			Highlights: m.Highlights,
+		}
+		// Password hashes are not returned.
+		resp.Results[i].User.Password = ""
+	}
+
+	if next := opts.Offset + uint64(len(matches)); len(matches) > 0 && next < uint64(total) {
+		if resp.NextPageToken, err = g.pageTokens.Encode(searchCursor{Offset: next}, query); err != nil {
+			return nil, g.mapError(err)
+		}
+	}
+
+	return resp, nil
+}
+
+// searchFingerprint returns the request without its paging fields, which page
+// tokens are bound to.
+func searchFingerprint(req *pb.SearchUsersRequest) ([]byte, error) {
+	query := proto.Clone(req).(*pb.SearchUsersRequest)
+	query.PageSize = 0
+	query.PageToken = ""
+
+	return proto.MarshalOptions{Deterministic: true}.Marshal(query)
+}
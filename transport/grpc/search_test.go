@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/pagetoken"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_SearchUsers(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	svc := &UserServiceMock{
+		SearchUsersFunc: func(ctx context.Context, opts models.SearchUsersOptions) ([]models.UserMatch, int, error) {
+			require.Equal(t, "antonsi", opts.Query)
+			require.Equal(t, models.UserStatusActive, opts.Status)
+			require.Equal(t, uint64(1), opts.Limit)
+
+			return []models.UserMatch{{
+				User:       models.User{ID: userID, FirstName: "Antonis", Password: []byte("$2a$10$hash")},
+				Score:      0.45,
+				Highlights: map[string]string{"first_name": "<em>Antonis</em>"},
+			}}, 2, nil
+		},
+	}
+
+	g := &GRPC{
+		svc:        svc,
+		logger:     zap.NewNop().Sugar(),
+		pageTokens: pagetoken.NewCodec([]byte("secret")),
+	}
+
+	req := &user.SearchUsersRequest{
+		Query:    "antonsi",
+		Status:   user.UserStatus_USER_STATUS_ACTIVE,
+		PageSize: 1,
+	}
+
+	resp, err := g.SearchUsers(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.GetResults(), 1)
+	require.Equal(t, userID.String(), resp.GetResults()[0].GetUser().GetId())
+	require.Empty(t, resp.GetResults()[0].GetUser().GetPassword())
+	require.Equal(t, 0.45, resp.GetResults()[0].GetScore())
+	require.Equal(t, "<em>Antonis</em>", resp.GetResults()[0].GetHighlights()["first_name"])
+	require.Equal(t, uint64(2), resp.GetTotalSize())
+	require.NotEmpty(t, resp.GetNextPageToken())
+
+	t.Log("next page")
+	{
+		req.PageToken = resp.GetNextPageToken()
+		resp, err := g.SearchUsers(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), svc.SearchUsersCalls()[1].Opts.Offset)
+		require.Empty(t, resp.GetNextPageToken())
+	}
+
+	t.Log("token used with another query")
+	{
+		_, err := g.SearchUsers(context.Background(), &user.SearchUsersRequest{
+			Query:     "maria",
+			PageToken: resp.GetNextPageToken(),
+		})
+		require.ErrorIs(t, err, errInvalidPageToken)
+	}
+}
@@ -2,51 +2,132 @@ package grpc
 
 import (
 	"context"
+	"net"
+
 	// 3rd party
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/filter"
 	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/pagetoken"
 	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
 )
 
-const defaultPageSize = 10
+const (
+	defaultPageSize = 10
+	maxPageSize     = 1000
+)
 
 //go:generate moq -out user_service_mock_test.go . UserService
 type userService interface {
 	CreateUser(ctx context.Context, nu models.NewUser) (uuid.UUID, error)
 	UpdateUser(ctx context.Context, userID uuid.UUID, uu models.UpdateUser) error
 	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	RestoreUser(ctx context.Context, userID uuid.UUID) error
+	EraseUser(ctx context.Context, userID uuid.UUID) error
+	SuspendUser(ctx context.Context, userID uuid.UUID, reason string) error
+	ReactivateUser(ctx context.Context, userID uuid.UUID, reason string) error
+	DeactivateUser(ctx context.Context, userID uuid.UUID, reason string) error
 	GetUsers(ctx context.Context, qu models.GetUsersOptions) ([]models.User, error)
+	ListUsers(ctx context.Context, qu models.GetUsersOptions) ([]models.User, *models.UserCursor, error)
+	CountUsers(ctx context.Context, qu models.GetUsersOptions) (models.UserCount, error)
+	SearchUsers(ctx context.Context, opts models.SearchUsersOptions) ([]models.UserMatch, int, error)
+	ExportUsers(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error
+	ImportUsers(ctx context.Context, opts models.ImportUsersOptions, next func() (models.NewUser, error)) ([]models.ImportResult, error)
+	BatchCreateUsers(ctx context.Context, users []models.NewUser, atomic bool) ([]models.BatchResult, error)
+	BatchUpdateUsers(ctx context.Context, updates []models.BatchUpdate, atomic bool) ([]models.BatchResult, error)
+	BatchDeleteUsers(ctx context.Context, userIDs []uuid.UUID, atomic bool) ([]models.BatchResult, error)
 }
 
 type GRPC struct {
 	pb.UnimplementedUserServer
 
-	logger *zap.SugaredLogger
-	svc    userService
+	logger         *zap.SugaredLogger
+	svc            userService
+	groupSvc       groupService
+	apiKeySvc      apiKeyService
+	auditSvc       auditService
+	exportSvc      exportService
+	consentSvc     consentService
+	settingsSvc    settingsService
+	attrSvc        attributeService
+	contactSvc     contactService
+	identitySvc    identityService
+	oauthClientSvc oauthClientService
+	watchSvc       watchService
+	statsSvc       statsService
+	pageTokens     *pagetoken.Codec
+	maxPageOffset  uint64
+}
+
+// Services groups the application services exposed through the gRPC API.
+type Services struct {
+	User     userService
+	Group    groupService
+	APIKey   apiKeyService
+	Audit    auditService
+	Export   exportService
+	Consent  consentService
+	Settings settingsService
+	// Attribute manages the definitions of custom user attributes.
+	Attribute attributeService
+	Contact   contactService
+	// Identity links users to accounts at external identity providers.
+	Identity identityService
+	// OAuthClient manages the clients of the OpenID Connect provider.
+	OAuthClient oauthClientService
+	// Watch streams the changes to users.
+	Watch watchService
+	// Stats counts users for dashboards.
+	Stats statsService
+	// Idempotency replays the responses of mutations to their retries.
+	Idempotency idempotencyService
+}
+
+// Options configures the gRPC API.
+type Options struct {
+	// AuthRequired rejects calls without an API key.
+	AuthRequired bool
+	// PageTokenKey signs the page tokens of QueryUsers.
+	PageTokenKey []byte
+	// MaxPageOffset is the number of users QueryUsers may skip when paging by
+	// page number.
+	MaxPageOffset uint64
+	// TrustedProxies are the networks of the proxies whose x-forwarded-for
+	// header gives the client IP recorded in the audit log.
+	TrustedProxies []*net.IPNet
 }
 
-func New(logger *zap.SugaredLogger, svc userService) *GRPC {
+func New(logger *zap.SugaredLogger, opts Options, svcs Services) *GRPC {
 	return &GRPC{
-		logger: logger,
-		svc:    svc,
+		logger:         logger,
+		svc:            svcs.User,
+		groupSvc:       svcs.Group,
+		apiKeySvc:      svcs.APIKey,
+		auditSvc:       svcs.Audit,
+		exportSvc:      svcs.Export,
+		consentSvc:     svcs.Consent,
+		settingsSvc:    svcs.Settings,
+		attrSvc:        svcs.Attribute,
+		contactSvc:     svcs.Contact,
+		identitySvc:    svcs.Identity,
+		oauthClientSvc: svcs.OAuthClient,
+		watchSvc:       svcs.Watch,
+		statsSvc:       svcs.Stats,
+		pageTokens:     pagetoken.NewCodec(opts.PageTokenKey),
+		maxPageOffset:  opts.MaxPageOffset,
 	}
 }
 
 func (g *GRPC) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	newUser := models.NewUser{
-		Email:     req.GetEmail(),
-		FirstName: req.GetFirstName(),
-		LastName:  req.GetLastName(),
-		Nickname:  req.GetNickname(),
-		Password:  req.GetPassword(),
-		Country:   req.GetCountry(),
-	}
-
-	userID, err := g.svc.CreateUser(ctx, newUser)
+	userID, err := g.svc.CreateUser(ctx, mapNewUser(req))
 	if err != nil {
 		return nil, g.mapError(err)
 	}
@@ -56,22 +137,27 @@ func (g *GRPC) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.C
 	}, nil
 }
 
+func mapNewUser(req *pb.CreateUserRequest) models.NewUser {
+	return models.NewUser{
+		Type:       mapUserTypeFromPB(req.GetType()),
+		Email:      req.GetEmail(),
+		FirstName:  req.GetFirstName(),
+		LastName:   req.GetLastName(),
+		Nickname:   req.GetNickname(),
+		Password:   req.GetPassword(),
+		Country:    req.GetCountry(),
+		Pending:    req.GetPending(),
+		Attributes: mapAttributesFromPB(req.GetAttributes()),
+	}
+}
+
 func (g *GRPC) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	userID, err := uuid.Parse(req.GetUserId())
 	if err != nil {
 		return nil, errInvalidUserID
 	}
 
-	updateUser := models.UpdateUser{
-		Email:     req.GetFields().GetEmail(),
-		FirstName: req.GetFields().GetFirstName(),
-		LastName:  req.GetFields().GetLastName(),
-		Nickname:  req.GetFields().GetNickname(),
-		Country:   req.GetFields().GetCountry(),
-		Password:  req.GetFields().GetPassword(),
-	}
-
-	if err = g.svc.UpdateUser(ctx, userID, updateUser); err != nil {
+	if err = g.svc.UpdateUser(ctx, userID, mapUpdateUser(req.GetFields())); err != nil {
 		return nil, g.mapError(err)
 	}
 
@@ -80,6 +166,18 @@ func (g *GRPC) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.U
 	}, nil
 }
 
+func mapUpdateUser(fields *pb.UpdateUserRequest_Fields) models.UpdateUser {
+	return models.UpdateUser{
+		Email:      fields.GetEmail(),
+		FirstName:  fields.GetFirstName(),
+		LastName:   fields.GetLastName(),
+		Nickname:   fields.GetNickname(),
+		Country:    fields.GetCountry(),
+		Password:   fields.GetPassword(),
+		Attributes: mapAttributesFromPB(fields.GetAttributes()),
+	}
+}
+
 func (g *GRPC) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
 	userID, err := uuid.Parse(req.GetUserId())
 	if err != nil {
@@ -95,31 +193,189 @@ func (g *GRPC) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.D
 	}, nil
 }
 
+func (g *GRPC) RestoreUser(ctx context.Context, req *pb.RestoreUserRequest) (*pb.RestoreUserResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	if err := g.svc.RestoreUser(ctx, userID); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.RestoreUserResponse{
+		Success: true,
+	}, nil
+}
+
+func (g *GRPC) EraseUser(ctx context.Context, req *pb.EraseUserRequest) (*pb.EraseUserResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	if err := g.svc.EraseUser(ctx, userID); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.EraseUserResponse{
+		Success: true,
+	}, nil
+}
+
+func (g *GRPC) SuspendUser(ctx context.Context, req *pb.SuspendUserRequest) (*pb.SuspendUserResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	if err := g.svc.SuspendUser(ctx, userID, req.GetReason()); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.SuspendUserResponse{
+		Success: true,
+	}, nil
+}
+
+func (g *GRPC) ReactivateUser(ctx context.Context, req *pb.ReactivateUserRequest) (*pb.ReactivateUserResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	if err := g.svc.ReactivateUser(ctx, userID, req.GetReason()); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.ReactivateUserResponse{
+		Success: true,
+	}, nil
+}
+
+func (g *GRPC) DeactivateUser(ctx context.Context, req *pb.DeactivateUserRequest) (*pb.DeactivateUserResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	if err := g.svc.DeactivateUser(ctx, userID, req.GetReason()); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.DeactivateUserResponse{
+		Success: true,
+	}, nil
+}
+
 func (g *GRPC) QueryUsers(ctx context.Context, req *pb.QueryUsersRequest) (*pb.QueryUsersResponse, error) {
-	quOpts := mapQueryOptions(req)
-	users, err := g.svc.GetUsers(ctx, quOpts)
+	if req.GetIncludeDeleted() {
+		if p, ok := auth.PrincipalFromContext(ctx); ok && !p.HasScope(models.ScopeUsersAdmin) {
+			return nil, errPermissionDenied
+		}
+	}
+
+	quOpts, err := mapQueryOptions(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.queryUsers(ctx, req, quOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetIncludeTotalSize() {
+		count, err := g.svc.CountUsers(ctx, quOpts)
+		if err != nil {
+			return nil, g.mapError(err)
+		}
+		resp.TotalSize = count.Total
+		resp.TotalSizeEstimated = count.Estimated
+	}
+
+	return resp, nil
+}
+
+// queryUsers returns the page of users requested by page token or, for
+// compatibility, by page number.
+func (g *GRPC) queryUsers(ctx context.Context, req *pb.QueryUsersRequest, quOpts models.GetUsersOptions) (*pb.QueryUsersResponse, error) {
+	// Paging by page number skips rows, which gets slower the deeper the page.
+	if req.GetPageNumber() > 0 {
+		if req.GetPageToken() != "" {
+			return nil, errPageNumberAndToken
+		}
+		// Dividing rather than multiplying, which could overflow.
+		if quOpts.PageSize > 0 && quOpts.PageNumber-1 > g.maxPageOffset/quOpts.PageSize {
+			return nil, errPageOffsetTooLarge
+		}
+
+		users, err := g.svc.GetUsers(ctx, quOpts)
+		if err != nil {
+			return nil, g.mapError(err)
+		}
+
+		resp, err := mapUsersInfo(users)
+		if err != nil {
+			return nil, g.mapError(err)
+		}
+
+		return resp, nil
+	}
+
+	query, err := queryFingerprint(req)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	if req.GetPageToken() != "" {
+		var cursor models.UserCursor
+		if err := g.pageTokens.Decode(req.GetPageToken(), query, &cursor); err != nil {
+			return nil, errInvalidPageToken
+		}
+		quOpts.After = &cursor
+	}
+
+	users, next, err := g.svc.ListUsers(ctx, quOpts)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	resp, err := mapUsersInfo(users)
 	if err != nil {
 		return nil, g.mapError(err)
 	}
 
-	return mapUsersInfo(users)
+	if next != nil {
+		if resp.NextPageToken, err = g.pageTokens.Encode(next, query); err != nil {
+			return nil, g.mapError(err)
+		}
+	}
+
+	return resp, nil
+}
+
+// queryFingerprint returns the request without its paging fields, which page
+// tokens are bound to.
+func queryFingerprint(req *pb.QueryUsersRequest) ([]byte, error) {
+	query := proto.Clone(req).(*pb.QueryUsersRequest)
+	query.PageNumber = 0
+	query.PageSize = 0
+	query.PageToken = ""
+	query.IncludeTotalSize = false
+
+	return proto.MarshalOptions{Deterministic: true}.Marshal(query)
 }
 
 func mapUsersInfo(users []models.User) (*pb.QueryUsersResponse, error) {
 	items := make([]*pb.UserInfo, len(users))
 
 	for i, u := range users {
-		items[i] = &pb.UserInfo{
-			Id:        u.ID.String(),
-			Email:     u.Email,
-			FirstName: u.FirstName,
-			LastName:  u.LastName,
-			Nickname:  u.Nickname,
-			Country:   u.Country,
-			Password:  string(u.Password),
-			CreatedAt: timestamppb.New(u.CreatedAt),
-			//UpdateAt:  timestamppb.New(u.UpdateAt),
+		info, err := mapUserInfo(u)
+		if err != nil {
+			return nil, err
 		}
+		items[i] = info
 	}
 
 	return &pb.QueryUsersResponse{
@@ -127,7 +383,88 @@ func mapUsersInfo(users []models.User) (*pb.QueryUsersResponse, error) {
 	}, nil
 }
 
-func mapQueryOptions(req *pb.QueryUsersRequest) models.GetUsersOptions {
+func mapUserInfo(u models.User) (*pb.UserInfo, error) {
+	attributes, err := mapAttributesToPB(u.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UserInfo{
+		Id:         u.ID.String(),
+		Type:       mapUserTypeToPB(u.Type),
+		Status:     mapUserStatusToPB(u.Status),
+		Email:      u.Email,
+		FirstName:  u.FirstName,
+		LastName:   u.LastName,
+		Nickname:   u.Nickname,
+		Country:    u.Country,
+		Password:   string(u.Password),
+		CreatedAt:  timestamppb.New(u.CreatedAt),
+		DeletedAt:  optionalTimestamp(u.DeletedAt),
+		Attributes: attributes,
+		//UpdateAt:  timestamppb.New(u.UpdateAt),
+	}, nil
+}
+
+func mapUserTypeFromPB(t pb.UserType) models.UserType {
+	switch t {
+	case pb.UserType_USER_TYPE_SERVICE_ACCOUNT:
+		return models.UserTypeServiceAccount
+	default:
+		return models.UserTypeHuman
+	}
+}
+
+func mapUserTypeToPB(t models.UserType) pb.UserType {
+	switch t {
+	case models.UserTypeHuman:
+		return pb.UserType_USER_TYPE_HUMAN
+	case models.UserTypeServiceAccount:
+		return pb.UserType_USER_TYPE_SERVICE_ACCOUNT
+	default:
+		return pb.UserType_USER_TYPE_UNSPECIFIED
+	}
+}
+
+func mapUserStatusFromPB(s pb.UserStatus) models.UserStatus {
+	switch s {
+	case pb.UserStatus_USER_STATUS_PENDING:
+		return models.UserStatusPending
+	case pb.UserStatus_USER_STATUS_ACTIVE:
+		return models.UserStatusActive
+	case pb.UserStatus_USER_STATUS_SUSPENDED:
+		return models.UserStatusSuspended
+	case pb.UserStatus_USER_STATUS_DEACTIVATED:
+		return models.UserStatusDeactivated
+	default:
+		return ""
+	}
+}
+
+func mapUserStatusToPB(s models.UserStatus) pb.UserStatus {
+	switch s {
+	case models.UserStatusPending:
+		return pb.UserStatus_USER_STATUS_PENDING
+	case models.UserStatusActive:
+		return pb.UserStatus_USER_STATUS_ACTIVE
+	case models.UserStatusSuspended:
+		return pb.UserStatus_USER_STATUS_SUSPENDED
+	case models.UserStatusDeactivated:
+		return pb.UserStatus_USER_STATUS_DEACTIVATED
+	default:
+		return pb.UserStatus_USER_STATUS_UNSPECIFIED
+	}
+}
+
+// userOrderFields are the fields users can be sorted by.
+var userOrderFields = map[string]models.UserOrderField{
+	"email":      models.UserOrderEmail,
+	"created_at": models.UserOrderCreatedAt,
+	"last_name":  models.UserOrderLastName,
+	"country":    models.UserOrderCountry,
+}
+
+func mapQueryOptions(req *pb.QueryUsersRequest) (models.GetUsersOptions, error) {
 	pageNumber := req.GetPageNumber()
 	if pageNumber == 0 {
 		pageNumber = 1
@@ -137,10 +474,28 @@ func mapQueryOptions(req *pb.QueryUsersRequest) models.GetUsersOptions {
 	if pageSize == 0 {
 		pageSize = defaultPageSize
 	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
 
 	quOpts := models.GetUsersOptions{
-		PageNumber: pageNumber,
-		PageSize:   pageSize,
+		PageNumber:     pageNumber,
+		PageSize:       pageSize,
+		IncludeDeleted: req.GetIncludeDeleted(),
+	}
+
+	seen := make(map[models.UserOrderField]bool, len(req.GetOrderBy()))
+	for _, o := range req.GetOrderBy() {
+		field, ok := userOrderFields[o.GetField()]
+		if !ok || seen[field] {
+			return models.GetUsersOptions{}, errInvalidOrderBy
+		}
+		seen[field] = true
+
+		quOpts.OrderBy = append(quOpts.OrderBy, models.UserOrder{
+			Field: field,
+			Desc:  o.GetDirection() == pb.SortDirection_SORT_DIRECTION_DESC,
+		})
 	}
 
 	if req.GetFilter() != nil {
@@ -155,7 +510,17 @@ func mapQueryOptions(req *pb.QueryUsersRequest) models.GetUsersOptions {
 		if nickname := req.GetFilter().GetNickname(); nickname != "" {
 			quOpts.Filter.Nickname = nickname
 		}
+
+		quOpts.Filter.Status = mapUserStatusFromPB(req.GetFilter().GetStatus())
+		quOpts.Filter.ConsentPurpose = req.GetFilter().GetConsentPurpose()
+		quOpts.Filter.Attributes = mapAttributesFromPB(req.GetFilter().GetAttributes())
+	}
+
+	expr, err := filter.Parse(req.GetFilterExpression())
+	if err != nil {
+		return models.GetUsersOptions{}, status.Error(codes.InvalidArgument, err.Error())
 	}
+	quOpts.Filter.Expression = expr
 
-	return quOpts
+	return quOpts, nil
 }
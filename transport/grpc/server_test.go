@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -11,10 +12,15 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/filter"
 	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/pagetoken"
 	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
 )
 
@@ -328,6 +334,60 @@ func TestGRPC_DeleteUser(t *testing.T) {
 	}
 }
 
+func TestGRPC_EraseUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		svc     *UserServiceMock
+		userID  string
+		checkFn func(t *testing.T, resp *user.EraseUserResponse, err error)
+	}{
+		{
+			name: "happy path",
+			svc: &UserServiceMock{
+				EraseUserFunc: func(ctx context.Context, userID uuid.UUID) error {
+					return nil
+				},
+			},
+			userID: "1c8f21c1-c8d0-401c-89b5-3f577c54679e",
+			checkFn: func(t *testing.T, resp *user.EraseUserResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, resp.GetSuccess())
+			},
+		},
+		{
+			name:   "invalid uuid",
+			svc:    &UserServiceMock{},
+			userID: "invalid uuid",
+			checkFn: func(t *testing.T, resp *user.EraseUserResponse, err error) {
+				require.ErrorIs(t, err, errInvalidUserID)
+			},
+		},
+		{
+			name: "user not found",
+			svc: &UserServiceMock{
+				EraseUserFunc: func(ctx context.Context, userID uuid.UUID) error {
+					return models.ErrUserNotFound
+				},
+			},
+			userID: "1c8f21c1-c8d0-401c-89b5-3f577c54679e",
+			checkFn: func(t *testing.T, resp *user.EraseUserResponse, err error) {
+				require.ErrorIs(t, err, errUserNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GRPC{
+				svc:    tt.svc,
+				logger: zap.NewNop().Sugar(),
+			}
+			got, err := g.EraseUser(context.Background(), &user.EraseUserRequest{UserId: tt.userID})
+			tt.checkFn(t, got, err)
+		})
+	}
+}
+
 func TestGRPC_QueryUsers(t *testing.T) {
 
 	now := time.Now()
@@ -409,3 +469,348 @@ func TestGRPC_QueryUsers(t *testing.T) {
 		})
 	}
 }
+
+func TestGRPC_SuspendUser(t *testing.T) {
+
+	type fields struct {
+		svc userService
+	}
+	type args struct {
+		req *user.SuspendUserRequest
+	}
+
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		checkFn func(t *testing.T, resp *user.SuspendUserResponse, err error)
+	}{
+		{
+			name: "happy path",
+			fields: fields{
+				svc: &UserServiceMock{
+					SuspendUserFunc: func(ctx context.Context, userID uuid.UUID, reason string) error {
+						require.Equal(t, "spam", reason)
+						return nil
+					},
+				},
+			},
+			args: args{
+				req: &user.SuspendUserRequest{
+					UserId: "1c8f21c1-c8d0-401c-89b5-3f577c54679e",
+					Reason: "spam",
+				},
+			},
+			checkFn: func(t *testing.T, resp *user.SuspendUserResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, &user.SuspendUserResponse{
+					Success: true,
+				}, resp)
+			},
+		},
+		{
+			name: "invalid uuid",
+			fields: fields{
+				svc: &UserServiceMock{},
+			},
+			args: args{
+				req: &user.SuspendUserRequest{
+					UserId: "invalid uuid",
+				},
+			},
+			checkFn: func(t *testing.T, resp *user.SuspendUserResponse, err error) {
+				require.ErrorIs(t, err, errInvalidUserID)
+			},
+		},
+		{
+			name: "transition not allowed",
+			fields: fields{
+				svc: &UserServiceMock{
+					SuspendUserFunc: func(ctx context.Context, userID uuid.UUID, reason string) error {
+						return fmt.Errorf("%w: deactivated -> suspended", models.ErrInvalidStatusTransition)
+					},
+				},
+			},
+			args: args{
+				req: &user.SuspendUserRequest{
+					UserId: "1c8f21c1-c8d0-401c-89b5-3f577c54679e",
+				},
+			},
+			checkFn: func(t *testing.T, resp *user.SuspendUserResponse, err error) {
+				require.ErrorIs(t, err, errInvalidTransition)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GRPC{
+				svc:    tt.fields.svc,
+				logger: zap.NewNop().Sugar(),
+			}
+			got, err := g.SuspendUser(context.Background(), tt.args.req)
+			tt.checkFn(t, got, err)
+		})
+	}
+}
+
+func TestMapQueryOptions_StatusFilter(t *testing.T) {
+	opts, err := mapQueryOptions(&user.QueryUsersRequest{
+		Filter: &user.QueryUsersRequest_Filter{
+			Status: user.UserStatus_USER_STATUS_SUSPENDED,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.UserStatusSuspended, opts.Filter.Status)
+
+	opts, err = mapQueryOptions(&user.QueryUsersRequest{})
+	require.NoError(t, err)
+	require.Equal(t, models.UserStatus(""), opts.Filter.Status)
+}
+
+func TestMapQueryOptions_ConsentFilter(t *testing.T) {
+	opts, err := mapQueryOptions(&user.QueryUsersRequest{
+		Filter: &user.QueryUsersRequest_Filter{
+			ConsentPurpose: "newsletter",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "newsletter", opts.Filter.ConsentPurpose)
+}
+
+func TestMapQueryOptions_OrderBy(t *testing.T) {
+	tt := map[string]struct {
+		orderBy []*user.QueryUsersRequest_OrderBy
+		want    []models.UserOrder
+		err     error
+	}{
+		"default": {},
+		"fields and directions": {
+			orderBy: []*user.QueryUsersRequest_OrderBy{
+				{Field: "country"},
+				{Field: "last_name", Direction: user.SortDirection_SORT_DIRECTION_DESC},
+				{Field: "created_at", Direction: user.SortDirection_SORT_DIRECTION_ASC},
+			},
+			want: []models.UserOrder{
+				{Field: models.UserOrderCountry},
+				{Field: models.UserOrderLastName, Desc: true},
+				{Field: models.UserOrderCreatedAt},
+			},
+		},
+		"unknown field": {
+			orderBy: []*user.QueryUsersRequest_OrderBy{{Field: "password"}},
+			err:     errInvalidOrderBy,
+		},
+		"repeated field": {
+			orderBy: []*user.QueryUsersRequest_OrderBy{{Field: "email"}, {Field: "email", Direction: user.SortDirection_SORT_DIRECTION_DESC}},
+			err:     errInvalidOrderBy,
+		},
+	}
+
+	for name, tc := range tt {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			opts, err := mapQueryOptions(&user.QueryUsersRequest{OrderBy: tc.orderBy})
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.want, opts.OrderBy)
+		})
+	}
+}
+
+func TestMapQueryOptions_FilterExpression(t *testing.T) {
+	opts, err := mapQueryOptions(&user.QueryUsersRequest{
+		FilterExpression: `country = "GR" AND nickname:"tony*"`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, filter.And{Exprs: []filter.Expr{
+		filter.Restriction{Field: "country", Op: filter.OpEq, Values: []filter.Value{{Kind: filter.String, Text: "GR"}}},
+		filter.Restriction{Field: "nickname", Op: filter.OpHas, Values: []filter.Value{{Kind: filter.String, Text: "tony*"}}},
+	}}, opts.Filter.Expression)
+
+	_, err = mapQueryOptions(&user.QueryUsersRequest{FilterExpression: `country =`})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPC_QueryUsers_IncludeDeleted(t *testing.T) {
+	svc := &UserServiceMock{
+		ListUsersFunc: func(ctx context.Context, qu models.GetUsersOptions) ([]models.User, *models.UserCursor, error) {
+			require.True(t, qu.IncludeDeleted)
+			return nil, nil, nil
+		},
+	}
+
+	g := &GRPC{
+		svc:    svc,
+		logger: zap.NewNop().Sugar(),
+	}
+
+	req := &user.QueryUsersRequest{IncludeDeleted: true}
+
+	t.Log("api key without admin scope")
+	{
+		ctx := auth.WithPrincipal(context.Background(), models.Principal{
+			Scopes: []string{models.ScopeUsersRead},
+		})
+		_, err := g.QueryUsers(ctx, req)
+		require.ErrorIs(t, err, errPermissionDenied)
+		require.Len(t, svc.ListUsersCalls(), 0)
+	}
+
+	t.Log("api key with admin scope")
+	{
+		ctx := auth.WithPrincipal(context.Background(), models.Principal{
+			Scopes: []string{models.ScopeUsersRead, models.ScopeUsersAdmin},
+		})
+		_, err := g.QueryUsers(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, svc.ListUsersCalls(), 1)
+	}
+}
+
+func TestGRPC_QueryUsers_PageToken(t *testing.T) {
+	cursor := models.UserCursor{
+		CreatedAt: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+		ID:        uuid.MustParse("1c8f21c1-c8d0-401c-89b5-3f577c54679e"),
+	}
+
+	svc := &UserServiceMock{
+		ListUsersFunc: func(ctx context.Context, qu models.GetUsersOptions) ([]models.User, *models.UserCursor, error) {
+			if qu.After == nil {
+				return []models.User{{ID: cursor.ID, CreatedAt: cursor.CreatedAt}}, &cursor, nil
+			}
+			return nil, nil, nil
+		},
+	}
+
+	g := &GRPC{
+		svc:        svc,
+		logger:     zap.NewNop().Sugar(),
+		pageTokens: pagetoken.NewCodec([]byte("secret")),
+	}
+
+	req := &user.QueryUsersRequest{
+		PageSize: 1,
+		Filter:   &user.QueryUsersRequest_Filter{Country: "GR"},
+	}
+
+	resp, err := g.QueryUsers(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.GetUsers(), 1)
+	require.NotEmpty(t, resp.GetNextPageToken())
+
+	t.Log("next page")
+	{
+		resp, err := g.QueryUsers(context.Background(), &user.QueryUsersRequest{
+			PageSize:  1,
+			PageToken: resp.GetNextPageToken(),
+			Filter:    &user.QueryUsersRequest_Filter{Country: "GR"},
+		})
+		require.NoError(t, err)
+		require.Empty(t, resp.GetNextPageToken())
+
+		after := svc.ListUsersCalls()[1].Qu.After
+		require.NotNil(t, after)
+		require.Equal(t, cursor.ID, after.ID)
+		require.True(t, cursor.CreatedAt.Equal(after.CreatedAt))
+	}
+
+	t.Log("token used with another filter")
+	{
+		_, err := g.QueryUsers(context.Background(), &user.QueryUsersRequest{
+			PageSize:  1,
+			PageToken: resp.GetNextPageToken(),
+			Filter:    &user.QueryUsersRequest_Filter{Country: "UK"},
+		})
+		require.ErrorIs(t, err, errInvalidPageToken)
+	}
+
+	t.Log("tampered token")
+	{
+		_, err := g.QueryUsers(context.Background(), &user.QueryUsersRequest{
+			PageToken: resp.GetNextPageToken() + "x",
+			Filter:    &user.QueryUsersRequest_Filter{Country: "GR"},
+		})
+		require.ErrorIs(t, err, errInvalidPageToken)
+	}
+
+	require.Len(t, svc.ListUsersCalls(), 2)
+}
+
+func TestGRPC_QueryUsers_PageNumber(t *testing.T) {
+	svc := &UserServiceMock{
+		GetUsersFunc: func(ctx context.Context, qu models.GetUsersOptions) ([]models.User, error) {
+			return nil, nil
+		},
+	}
+
+	g := &GRPC{
+		svc:           svc,
+		logger:        zap.NewNop().Sugar(),
+		pageTokens:    pagetoken.NewCodec([]byte("secret")),
+		maxPageOffset: 100,
+	}
+
+	t.Log("within the offset limit")
+	{
+		resp, err := g.QueryUsers(context.Background(), &user.QueryUsersRequest{PageNumber: 11, PageSize: 10})
+		require.NoError(t, err)
+		require.Empty(t, resp.GetNextPageToken())
+	}
+
+	t.Log("beyond the offset limit")
+	{
+		_, err := g.QueryUsers(context.Background(), &user.QueryUsersRequest{PageNumber: 12, PageSize: 10})
+		require.ErrorIs(t, err, errPageOffsetTooLarge)
+
+		// The offset overflows uint64.
+		_, err = g.QueryUsers(context.Background(), &user.QueryUsersRequest{PageNumber: 1<<63 + 1, PageSize: 2})
+		require.ErrorIs(t, err, errPageOffsetTooLarge)
+	}
+
+	t.Log("combined with a page token")
+	{
+		_, err := g.QueryUsers(context.Background(), &user.QueryUsersRequest{PageNumber: 1, PageToken: "token"})
+		require.ErrorIs(t, err, errPageNumberAndToken)
+	}
+
+	require.Len(t, svc.GetUsersCalls(), 1)
+}
+
+func TestGRPC_QueryUsers_TotalSize(t *testing.T) {
+	svc := &UserServiceMock{
+		ListUsersFunc: func(ctx context.Context, qu models.GetUsersOptions) ([]models.User, *models.UserCursor, error) {
+			return nil, nil, nil
+		},
+		CountUsersFunc: func(ctx context.Context, qu models.GetUsersOptions) (models.UserCount, error) {
+			require.Equal(t, "GR", qu.Filter.Country)
+			return models.UserCount{Total: 25000, Estimated: true}, nil
+		},
+	}
+
+	g := &GRPC{
+		svc:        svc,
+		logger:     zap.NewNop().Sugar(),
+		pageTokens: pagetoken.NewCodec([]byte("secret")),
+	}
+
+	t.Log("not requested")
+	{
+		resp, err := g.QueryUsers(context.Background(), &user.QueryUsersRequest{
+			Filter: &user.QueryUsersRequest_Filter{Country: "GR"},
+		})
+		require.NoError(t, err)
+		require.Zero(t, resp.GetTotalSize())
+		require.Len(t, svc.CountUsersCalls(), 0)
+	}
+
+	t.Log("requested")
+	{
+		resp, err := g.QueryUsers(context.Background(), &user.QueryUsersRequest{
+			Filter:           &user.QueryUsersRequest_Filter{Country: "GR"},
+			IncludeTotalSize: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(25000), resp.GetTotalSize())
+		require.True(t, resp.GetTotalSizeEstimated())
+	}
+}
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out settings_service_mock_test.go . settingsService
+type settingsService interface {
+	GetSettings(ctx context.Context, userID uuid.UUID, namespace string, keys []string) ([]models.Setting, error)
+	SetSettings(ctx context.Context, userID uuid.UUID, namespace string, values map[string]json.RawMessage) error
+	DeleteSetting(ctx context.Context, userID uuid.UUID, namespace string, key string) error
+}
+
+func (g *GRPC) GetSettings(ctx context.Context, req *pb.GetSettingsRequest) (*pb.GetSettingsResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	settings, err := g.settingsSvc.GetSettings(ctx, userID, req.GetNamespace(), req.GetKeys())
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	items := make([]*pb.Setting, len(settings))
+	for i, s := range settings {
+		item, err := mapSetting(s)
+		if err != nil {
+			return nil, g.mapError(err)
+		}
+		items[i] = item
+	}
+
+	return &pb.GetSettingsResponse{
+		Settings: items,
+	}, nil
+}
+
+func (g *GRPC) SetSettings(ctx context.Context, req *pb.SetSettingsRequest) (*pb.SetSettingsResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	values := make(map[string]json.RawMessage, len(req.GetValues()))
+	for key, v := range req.GetValues() {
+		value, err := v.MarshalJSON()
+		if err != nil {
+			return nil, errInvalidSettingValue
+		}
+		values[key] = value
+	}
+
+	if err := g.settingsSvc.SetSettings(ctx, userID, req.GetNamespace(), values); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.SetSettingsResponse{
+		Success: true,
+	}, nil
+}
+
+func (g *GRPC) DeleteSetting(ctx context.Context, req *pb.DeleteSettingRequest) (*pb.DeleteSettingResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, errInvalidUserID
+	}
+
+	if err := g.settingsSvc.DeleteSetting(ctx, userID, req.GetNamespace(), req.GetKey()); err != nil {
+		return nil, g.mapError(err)
+	}
+
+	return &pb.DeleteSettingResponse{
+		Success: true,
+	}, nil
+}
+
+func mapSetting(s models.Setting) (*pb.Setting, error) {
+	value := &structpb.Value{}
+	if err := value.UnmarshalJSON(s.Value); err != nil {
+		return nil, err
+	}
+
+	return &pb.Setting{
+		Namespace: s.Namespace,
+		Key:       s.Key,
+		Value:     value,
+		IsDefault: s.IsDefault,
+		UpdatedAt: optionalTimestamp(s.UpdatedAt),
+	}, nil
+}
@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_GetSettings(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	svc := &SettingsServiceMock{
+		GetSettingsFunc: func(ctx context.Context, uID uuid.UUID, namespace string, keys []string) ([]models.Setting, error) {
+			return []models.Setting{
+				{UserID: uID, Namespace: namespace, Key: "layout", Value: json.RawMessage(`{"columns":2,"compact":true}`)},
+				{UserID: uID, Namespace: namespace, Key: "theme", Value: json.RawMessage(`"light"`), IsDefault: true},
+			}, nil
+		},
+	}
+
+	g := &GRPC{
+		settingsSvc: svc,
+		logger:      zap.NewNop().Sugar(),
+	}
+
+	resp, err := g.GetSettings(context.Background(), &user.GetSettingsRequest{
+		UserId:    userID.String(),
+		Namespace: "ui",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetSettings(), 2)
+	require.Equal(t, float64(2), resp.GetSettings()[0].GetValue().GetStructValue().AsMap()["columns"])
+	require.Equal(t, "light", resp.GetSettings()[1].GetValue().GetStringValue())
+	require.True(t, resp.GetSettings()[1].GetIsDefault())
+}
+
+func TestGRPC_SetSettings(t *testing.T) {
+	userID := uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c")
+
+	tests := []struct {
+		name    string
+		svcErr  error
+		req     *user.SetSettingsRequest
+		checkFn func(t *testing.T, svc *SettingsServiceMock, err error)
+	}{
+		{
+			name: "happy path",
+			req: &user.SetSettingsRequest{
+				UserId:    userID.String(),
+				Namespace: "ui",
+				Values:    map[string]*structpb.Value{"theme": structpb.NewStringValue("dark")},
+			},
+			checkFn: func(t *testing.T, svc *SettingsServiceMock, err error) {
+				require.NoError(t, err)
+				require.Len(t, svc.SetSettingsCalls(), 1)
+				require.Equal(t, map[string]json.RawMessage{"theme": json.RawMessage(`"dark"`)}, svc.SetSettingsCalls()[0].Values)
+			},
+		},
+		{
+			name: "invalid user id",
+			req:  &user.SetSettingsRequest{UserId: "1234"},
+			checkFn: func(t *testing.T, svc *SettingsServiceMock, err error) {
+				require.ErrorIs(t, err, errInvalidUserID)
+				require.Len(t, svc.SetSettingsCalls(), 0)
+			},
+		},
+		{
+			name:   "schema violation",
+			svcErr: fmt.Errorf("%w: ui.theme: value: must be one of the allowed values", models.ErrInvalidSettingValue),
+			req:    &user.SetSettingsRequest{UserId: userID.String(), Namespace: "ui"},
+			checkFn: func(t *testing.T, svc *SettingsServiceMock, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+				require.Contains(t, err.Error(), "ui.theme")
+			},
+		},
+		{
+			name:   "limit exceeded",
+			svcErr: models.ErrSettingsLimitExceeded,
+			req:    &user.SetSettingsRequest{UserId: userID.String(), Namespace: "ui"},
+			checkFn: func(t *testing.T, svc *SettingsServiceMock, err error) {
+				require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &SettingsServiceMock{
+				SetSettingsFunc: func(ctx context.Context, uID uuid.UUID, namespace string, values map[string]json.RawMessage) error {
+					return tt.svcErr
+				},
+			}
+
+			g := &GRPC{
+				settingsSvc: svc,
+				logger:      zap.NewNop().Sugar(),
+			}
+			_, err := g.SetSettings(context.Background(), tt.req)
+			tt.checkFn(t, svc, err)
+		})
+	}
+}
+
+func TestGRPC_DeleteSetting_NotFound(t *testing.T) {
+	g := &GRPC{
+		settingsSvc: &SettingsServiceMock{
+			DeleteSettingFunc: func(ctx context.Context, uID uuid.UUID, namespace string, key string) error {
+				return models.ErrSettingNotFound
+			},
+		},
+		logger: zap.NewNop().Sugar(),
+	}
+
+	_, err := g.DeleteSetting(context.Background(), &user.DeleteSettingRequest{
+		UserId:    uuid.New().String(),
+		Namespace: "ui",
+		Key:       "theme",
+	})
+	require.ErrorIs(t, err, errSettingNotFound)
+}
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+
+	// 3rd party
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out stats_service_mock_test.go . statsService
+type statsService interface {
+	GetUserStats(ctx context.Context, opts models.UserStatsOptions) (models.UserStats, error)
+}
+
+var statsDimensions = map[pb.UserStatsDimension]models.StatsDimension{
+	pb.UserStatsDimension_USER_STATS_DIMENSION_COUNTRY:  models.StatsByCountry,
+	pb.UserStatsDimension_USER_STATS_DIMENSION_STATUS:   models.StatsByStatus,
+	pb.UserStatsDimension_USER_STATS_DIMENSION_TYPE:     models.StatsByType,
+	pb.UserStatsDimension_USER_STATS_DIMENSION_VERIFIED: models.StatsByVerified,
+}
+
+var statsBuckets = map[pb.UserStatsBucket]models.StatsBucket{
+	pb.UserStatsBucket_USER_STATS_BUCKET_UNSPECIFIED: models.StatsBucketNone,
+	pb.UserStatsBucket_USER_STATS_BUCKET_DAY:         models.StatsBucketDay,
+	pb.UserStatsBucket_USER_STATS_BUCKET_WEEK:        models.StatsBucketWeek,
+	pb.UserStatsBucket_USER_STATS_BUCKET_MONTH:       models.StatsBucketMonth,
+}
+
+func (g *GRPC) GetUserStats(ctx context.Context, req *pb.GetUserStatsRequest) (*pb.GetUserStatsResponse, error) {
+	var opts models.UserStatsOptions
+
+	for _, d := range req.GetGroupBy() {
+		dimension, ok := statsDimensions[d]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown dimension %s", d)
+		}
+		opts.GroupBy = append(opts.GroupBy, dimension)
+	}
+
+	bucket, ok := statsBuckets[req.GetBucket()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown bucket %s", req.GetBucket())
+	}
+	opts.Bucket = bucket
+
+	if req.CreatedAfter != nil {
+		t := req.GetCreatedAfter().AsTime()
+		opts.CreatedAfter = &t
+	}
+	if req.CreatedBefore != nil {
+		t := req.GetCreatedBefore().AsTime()
+		opts.CreatedBefore = &t
+	}
+
+	stats, err := g.statsSvc.GetUserStats(ctx, opts)
+	if err != nil {
+		return nil, g.mapError(err)
+	}
+
+	resp := &pb.GetUserStatsResponse{
+		Groups:     make([]*pb.GetUserStatsResponse_Group, len(stats.Groups)),
+		ComputedAt: timestamppb.New(stats.ComputedAt),
+	}
+
+	for i, group := range stats.Groups {
+		resp.Groups[i] = &pb.GetUserStatsResponse_Group{
+			Country:  group.Country,
+			Status:   mapUserStatusToPB(group.Status),
+			Type:     mapUserTypeToPB(group.Type),
+			Verified: group.Verified,
+			Users:    group.Users,
+		}
+		if !group.BucketStart.IsZero() {
+			resp.Groups[i].BucketStart = timestamppb.New(group.BucketStart)
+		}
+	}
+
+	return resp, nil
+}
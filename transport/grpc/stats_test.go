@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+func TestGRPC_GetUserStats(t *testing.T) {
+	day := time.Date(2023, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	svc := &StatsServiceMock{
+		GetUserStatsFunc: func(ctx context.Context, opts models.UserStatsOptions) (models.UserStats, error) {
+			return models.UserStats{
+				Groups: []models.UserStatsGroup{
+					{BucketStart: day, Country: "GR", Status: models.UserStatusActive, Users: 3},
+					{BucketStart: day, Country: "GR", Status: models.UserStatusSuspended, Users: 1},
+				},
+				ComputedAt: day,
+			}, nil
+		},
+	}
+
+	g := &GRPC{
+		statsSvc: svc,
+		logger:   zap.NewNop().Sugar(),
+	}
+
+	resp, err := g.GetUserStats(context.TODO(), &user.GetUserStatsRequest{
+		GroupBy: []user.UserStatsDimension{
+			user.UserStatsDimension_USER_STATS_DIMENSION_COUNTRY,
+			user.UserStatsDimension_USER_STATS_DIMENSION_STATUS,
+		},
+		Bucket:       user.UserStatsBucket_USER_STATS_BUCKET_WEEK,
+		CreatedAfter: timestamppb.New(day),
+	})
+	require.NoError(t, err)
+
+	opts := svc.GetUserStatsCalls()[0].Opts
+	require.Equal(t, []models.StatsDimension{models.StatsByCountry, models.StatsByStatus}, opts.GroupBy)
+	require.Equal(t, models.StatsBucketWeek, opts.Bucket)
+	require.Equal(t, day, *opts.CreatedAfter)
+	require.Nil(t, opts.CreatedBefore)
+
+	require.Len(t, resp.GetGroups(), 2)
+	require.Equal(t, day, resp.GetGroups()[0].GetBucketStart().AsTime())
+	require.Equal(t, "GR", resp.GetGroups()[0].GetCountry())
+	require.Equal(t, user.UserStatus_USER_STATUS_ACTIVE, resp.GetGroups()[0].GetStatus())
+	require.Equal(t, user.UserType_USER_TYPE_UNSPECIFIED, resp.GetGroups()[0].GetType())
+	require.Equal(t, int64(1), resp.GetGroups()[1].GetUsers())
+	require.Equal(t, day, resp.GetComputedAt().AsTime())
+
+	t.Log("unknown dimension")
+	{
+		_, err := g.GetUserStats(context.TODO(), &user.GetUserStatsRequest{
+			GroupBy: []user.UserStatsDimension{user.UserStatsDimension_USER_STATS_DIMENSION_UNSPECIFIED},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		require.Len(t, svc.GetUserStatsCalls(), 1)
+	}
+
+	t.Log("too many groups")
+	{
+		g := &GRPC{
+			statsSvc: &StatsServiceMock{
+				GetUserStatsFunc: func(ctx context.Context, opts models.UserStatsOptions) (models.UserStats, error) {
+					return models.UserStats{}, models.ErrTooManyStatsGroups
+				},
+			},
+			logger: zap.NewNop().Sugar(),
+		}
+
+		_, err := g.GetUserStats(context.TODO(), &user.GetUserStatsRequest{Bucket: user.UserStatsBucket_USER_STATS_BUCKET_DAY})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
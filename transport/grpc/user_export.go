@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"bufio"
+	"time"
+
+	// 3rd party
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+	"github.com/TonyPath/user-mng-grpc-service/transport/userexport"
+)
+
+// userExportFormats maps the export formats of the API to their encodings.
+var userExportFormats = map[pb.UserExportFormat]userexport.Format{
+	pb.UserExportFormat_USER_EXPORT_FORMAT_UNSPECIFIED: userexport.FormatCSV,
+	pb.UserExportFormat_USER_EXPORT_FORMAT_CSV:         userexport.FormatCSV,
+	pb.UserExportFormat_USER_EXPORT_FORMAT_JSONL:       userexport.FormatJSONL,
+	pb.UserExportFormat_USER_EXPORT_FORMAT_PROTOBUF:    userexport.FormatProtobuf,
+}
+
+// ExportUsers streams every user matching the filter, in order of creation.
+// The users are encoded as they are read from the database, so that exports
+// of any size use constant memory.
+func (g *GRPC) ExportUsers(req *pb.ExportUsersRequest, stream pb.User_ExportUsersServer) error {
+	ctx := stream.Context()
+
+	if req.GetIncludeDeleted() {
+		if p, ok := auth.PrincipalFromContext(ctx); ok && !p.HasScope(models.ScopeUsersAdmin) {
+			return errPermissionDenied
+		}
+	}
+
+	format, ok := userExportFormats[req.GetFormat()]
+	if !ok {
+		return errInvalidExportFormat
+	}
+
+	columns, err := userexport.ParseColumns(req.GetColumns())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	quOpts, err := mapQueryOptions(&pb.QueryUsersRequest{
+		Filter:           req.GetFilter(),
+		FilterExpression: req.GetFilterExpression(),
+		IncludeDeleted:   req.GetIncludeDeleted(),
+	})
+	if err != nil {
+		return err
+	}
+
+	header := &pb.ExportUsersResponse{
+		ContentType: format.ContentType(),
+		Filename:    userexport.Filename(format, time.Now()),
+	}
+	cw := &chunkWriter{send: func(chunk []byte) error {
+		msg := &pb.ExportUsersResponse{}
+		if header != nil {
+			msg, header = header, nil
+		}
+		msg.Chunk = chunk
+		return stream.Send(msg)
+	}}
+
+	w := bufio.NewWriterSize(cw, exportChunkSize)
+	enc, err := userexport.NewEncoder(w, format, columns)
+	if err != nil {
+		return g.mapError(err)
+	}
+
+	if err := g.svc.ExportUsers(ctx, quOpts, enc.Encode); err != nil {
+		return g.mapError(err)
+	}
+
+	if err := enc.Flush(); err != nil {
+		return g.mapError(err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return g.mapError(err)
+	}
+
+	// Empty exports still tell the client what they would have contained.
+	if header != nil {
+		if err := stream.Send(header); err != nil {
+			return g.mapError(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+type exportUsersStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	msgs []*user.ExportUsersResponse
+}
+
+func (s *exportUsersStream) Context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// Send keeps a copy of the message, as chunks reuse the buffer of the writer.
+func (s *exportUsersStream) Send(msg *user.ExportUsersResponse) error {
+	s.msgs = append(s.msgs, proto.Clone(msg).(*user.ExportUsersResponse))
+	return nil
+}
+
+func TestGRPC_ExportUsers(t *testing.T) {
+	users := make([]models.User, 3000)
+	for i := range users {
+		users[i] = models.User{ID: uuid.New(), Email: "antonis@mail.com"}
+	}
+
+	svc := &UserServiceMock{
+		ExportUsersFunc: func(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error {
+			for _, u := range users {
+				if err := fn(u); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	g := &GRPC{
+		svc:    svc,
+		logger: zap.NewNop().Sugar(),
+	}
+
+	stream := &exportUsersStream{}
+	err := g.ExportUsers(&user.ExportUsersRequest{
+		Filter:           &user.QueryUsersRequest_Filter{Country: "GR"},
+		FilterExpression: `nickname:"tony*"`,
+		Columns:          []string{"id", "email"},
+	}, stream)
+	require.NoError(t, err)
+
+	qu := svc.ExportUsersCalls()[0].Qu
+	require.Equal(t, "GR", qu.Filter.Country)
+	require.NotNil(t, qu.Filter.Expression)
+
+	require.Greater(t, len(stream.msgs), 1)
+	require.Equal(t, "text/csv; charset=utf-8", stream.msgs[0].GetContentType())
+	require.Regexp(t, `^users-\d{8}T\d{6}Z\.csv$`, stream.msgs[0].GetFilename())
+	require.Empty(t, stream.msgs[1].GetFilename())
+
+	var got []byte
+	for _, msg := range stream.msgs {
+		require.LessOrEqual(t, len(msg.GetChunk()), exportChunkSize)
+		got = append(got, msg.GetChunk()...)
+	}
+	require.True(t, strings.HasPrefix(string(got), "id,email\n"+users[0].ID.String()+",antonis@mail.com\n"))
+	require.True(t, strings.HasSuffix(string(got), users[len(users)-1].ID.String()+",antonis@mail.com\n"))
+	require.Equal(t, len(users)+1, strings.Count(string(got), "\n"))
+
+	t.Log("empty export sends the header")
+	{
+		g := &GRPC{
+			svc: &UserServiceMock{
+				ExportUsersFunc: func(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error {
+					return nil
+				},
+			},
+			logger: zap.NewNop().Sugar(),
+		}
+
+		stream := &exportUsersStream{}
+		err := g.ExportUsers(&user.ExportUsersRequest{Format: user.UserExportFormat_USER_EXPORT_FORMAT_JSONL}, stream)
+		require.NoError(t, err)
+		require.Len(t, stream.msgs, 1)
+		require.Equal(t, "application/jsonl", stream.msgs[0].GetContentType())
+		require.Empty(t, stream.msgs[0].GetChunk())
+	}
+}
+
+func TestGRPC_ExportUsers_Rejected(t *testing.T) {
+	g := &GRPC{
+		svc:    &UserServiceMock{},
+		logger: zap.NewNop().Sugar(),
+	}
+
+	tt := map[string]struct {
+		req  *user.ExportUsersRequest
+		code codes.Code
+	}{
+		"unknown column":    {req: &user.ExportUsersRequest{Columns: []string{"password"}}, code: codes.InvalidArgument},
+		"repeated column":   {req: &user.ExportUsersRequest{Columns: []string{"id", "id"}}, code: codes.InvalidArgument},
+		"unknown format":    {req: &user.ExportUsersRequest{Format: user.UserExportFormat(42)}, code: codes.InvalidArgument},
+		"invalid filter":    {req: &user.ExportUsersRequest{FilterExpression: "country ="}, code: codes.InvalidArgument},
+		"deleted, no admin": {req: &user.ExportUsersRequest{IncludeDeleted: true}, code: codes.PermissionDenied},
+	}
+
+	for name, tc := range tt {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ctx := auth.WithPrincipal(context.Background(), models.Principal{Scopes: []string{models.ScopeUsersRead}})
+			err := g.ExportUsers(tc.req, &exportUsersStream{ctx: ctx})
+			require.Equal(t, tc.code, status.Code(err))
+			require.Empty(t, g.svc.(*UserServiceMock).ExportUsersCalls())
+		})
+	}
+}
@@ -20,15 +20,54 @@ var _ userService = &UserServiceMock{}
 //
 // 		// make and configure a mocked userService
 // 		mockedUserService := &UserServiceMock{
+// 			BatchCreateUsersFunc: func(ctx context.Context, users []models.NewUser, atomic bool) ([]models.BatchResult, error) {
+// 				panic("mock out the BatchCreateUsers method")
+// 			},
+// 			BatchDeleteUsersFunc: func(ctx context.Context, userIDs []uuid.UUID, atomic bool) ([]models.BatchResult, error) {
+// 				panic("mock out the BatchDeleteUsers method")
+// 			},
+// 			BatchUpdateUsersFunc: func(ctx context.Context, updates []models.BatchUpdate, atomic bool) ([]models.BatchResult, error) {
+// 				panic("mock out the BatchUpdateUsers method")
+// 			},
+// 			CountUsersFunc: func(ctx context.Context, qu models.GetUsersOptions) (models.UserCount, error) {
+// 				panic("mock out the CountUsers method")
+// 			},
 // 			CreateUserFunc: func(ctx context.Context, nu models.NewUser) (uuid.UUID, error) {
 // 				panic("mock out the CreateUser method")
 // 			},
+// 			DeactivateUserFunc: func(ctx context.Context, userID uuid.UUID, reason string) error {
+// 				panic("mock out the DeactivateUser method")
+// 			},
 // 			DeleteUserFunc: func(ctx context.Context, userID uuid.UUID) error {
 // 				panic("mock out the DeleteUser method")
 // 			},
+// 			EraseUserFunc: func(ctx context.Context, userID uuid.UUID) error {
+// 				panic("mock out the EraseUser method")
+// 			},
+// 			ExportUsersFunc: func(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error {
+// 				panic("mock out the ExportUsers method")
+// 			},
 // 			GetUsersFunc: func(ctx context.Context, qu models.GetUsersOptions) ([]models.User, error) {
 // 				panic("mock out the GetUsers method")
 // 			},
+// 			ImportUsersFunc: func(ctx context.Context, opts models.ImportUsersOptions, next func() (models.NewUser, error)) ([]models.ImportResult, error) {
+// 				panic("mock out the ImportUsers method")
+// 			},
+// 			ListUsersFunc: func(ctx context.Context, qu models.GetUsersOptions) ([]models.User, *models.UserCursor, error) {
+// 				panic("mock out the ListUsers method")
+// 			},
+// 			ReactivateUserFunc: func(ctx context.Context, userID uuid.UUID, reason string) error {
+// 				panic("mock out the ReactivateUser method")
+// 			},
+// 			RestoreUserFunc: func(ctx context.Context, userID uuid.UUID) error {
+// 				panic("mock out the RestoreUser method")
+// 			},
+// 			SearchUsersFunc: func(ctx context.Context, opts models.SearchUsersOptions) ([]models.UserMatch, int, error) {
+// 				panic("mock out the SearchUsers method")
+// 			},
+// 			SuspendUserFunc: func(ctx context.Context, userID uuid.UUID, reason string) error {
+// 				panic("mock out the SuspendUser method")
+// 			},
 // 			UpdateUserFunc: func(ctx context.Context, userID uuid.UUID, uu models.UpdateUser) error {
 // 				panic("mock out the UpdateUser method")
 // 			},
@@ -39,20 +78,93 @@ var _ userService = &UserServiceMock{}
 //
 // 	}
 type UserServiceMock struct {
+	// BatchCreateUsersFunc mocks the BatchCreateUsers method.
+	BatchCreateUsersFunc func(ctx context.Context, users []models.NewUser, atomic bool) ([]models.BatchResult, error)
+
+	// BatchDeleteUsersFunc mocks the BatchDeleteUsers method.
+	BatchDeleteUsersFunc func(ctx context.Context, userIDs []uuid.UUID, atomic bool) ([]models.BatchResult, error)
+
+	// BatchUpdateUsersFunc mocks the BatchUpdateUsers method.
+	BatchUpdateUsersFunc func(ctx context.Context, updates []models.BatchUpdate, atomic bool) ([]models.BatchResult, error)
+
+	// CountUsersFunc mocks the CountUsers method.
+	CountUsersFunc func(ctx context.Context, qu models.GetUsersOptions) (models.UserCount, error)
+
 	// CreateUserFunc mocks the CreateUser method.
 	CreateUserFunc func(ctx context.Context, nu models.NewUser) (uuid.UUID, error)
 
+	// DeactivateUserFunc mocks the DeactivateUser method.
+	DeactivateUserFunc func(ctx context.Context, userID uuid.UUID, reason string) error
+
 	// DeleteUserFunc mocks the DeleteUser method.
 	DeleteUserFunc func(ctx context.Context, userID uuid.UUID) error
 
+	// EraseUserFunc mocks the EraseUser method.
+	EraseUserFunc func(ctx context.Context, userID uuid.UUID) error
+
+	// ExportUsersFunc mocks the ExportUsers method.
+	ExportUsersFunc func(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error
+
 	// GetUsersFunc mocks the GetUsers method.
 	GetUsersFunc func(ctx context.Context, qu models.GetUsersOptions) ([]models.User, error)
 
+	// ImportUsersFunc mocks the ImportUsers method.
+	ImportUsersFunc func(ctx context.Context, opts models.ImportUsersOptions, next func() (models.NewUser, error)) ([]models.ImportResult, error)
+
+	// ListUsersFunc mocks the ListUsers method.
+	ListUsersFunc func(ctx context.Context, qu models.GetUsersOptions) ([]models.User, *models.UserCursor, error)
+
+	// ReactivateUserFunc mocks the ReactivateUser method.
+	ReactivateUserFunc func(ctx context.Context, userID uuid.UUID, reason string) error
+
+	// RestoreUserFunc mocks the RestoreUser method.
+	RestoreUserFunc func(ctx context.Context, userID uuid.UUID) error
+
+	// SearchUsersFunc mocks the SearchUsers method.
+	SearchUsersFunc func(ctx context.Context, opts models.SearchUsersOptions) ([]models.UserMatch, int, error)
+
+	// SuspendUserFunc mocks the SuspendUser method.
+	SuspendUserFunc func(ctx context.Context, userID uuid.UUID, reason string) error
+
 	// UpdateUserFunc mocks the UpdateUser method.
 	UpdateUserFunc func(ctx context.Context, userID uuid.UUID, uu models.UpdateUser) error
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// BatchCreateUsers holds details about calls to the BatchCreateUsers method.
+		BatchCreateUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Users is the users argument value.
+			Users []models.NewUser
+			// Atomic is the atomic argument value.
+			Atomic bool
+		}
+		// BatchDeleteUsers holds details about calls to the BatchDeleteUsers method.
+		BatchDeleteUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserIDs is the userIDs argument value.
+			UserIDs []uuid.UUID
+			// Atomic is the atomic argument value.
+			Atomic bool
+		}
+		// BatchUpdateUsers holds details about calls to the BatchUpdateUsers method.
+		BatchUpdateUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Updates is the updates argument value.
+			Updates []models.BatchUpdate
+			// Atomic is the atomic argument value.
+			Atomic bool
+		}
+		// CountUsers holds details about calls to the CountUsers method.
+		CountUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Qu is the qu argument value.
+			Qu models.GetUsersOptions
+		}
 		// CreateUser holds details about calls to the CreateUser method.
 		CreateUser []struct {
 			// Ctx is the ctx argument value.
@@ -60,6 +172,15 @@ type UserServiceMock struct {
 			// Nu is the nu argument value.
 			Nu models.NewUser
 		}
+		// DeactivateUser holds details about calls to the DeactivateUser method.
+		DeactivateUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID uuid.UUID
+			// Reason is the reason argument value.
+			Reason string
+		}
 		// DeleteUser holds details about calls to the DeleteUser method.
 		DeleteUser []struct {
 			// Ctx is the ctx argument value.
@@ -67,6 +188,22 @@ type UserServiceMock struct {
 			// UserID is the userID argument value.
 			UserID uuid.UUID
 		}
+		// EraseUser holds details about calls to the EraseUser method.
+		EraseUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID uuid.UUID
+		}
+		// ExportUsers holds details about calls to the ExportUsers method.
+		ExportUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Qu is the qu argument value.
+			Qu models.GetUsersOptions
+			// Fn is the fn argument value.
+			Fn func(models.User) error
+		}
 		// GetUsers holds details about calls to the GetUsers method.
 		GetUsers []struct {
 			// Ctx is the ctx argument value.
@@ -74,6 +211,54 @@ type UserServiceMock struct {
 			// Qu is the qu argument value.
 			Qu models.GetUsersOptions
 		}
+		// ImportUsers holds details about calls to the ImportUsers method.
+		ImportUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Opts is the opts argument value.
+			Opts models.ImportUsersOptions
+			// Next is the next argument value.
+			Next func() (models.NewUser, error)
+		}
+		// ListUsers holds details about calls to the ListUsers method.
+		ListUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Qu is the qu argument value.
+			Qu models.GetUsersOptions
+		}
+		// ReactivateUser holds details about calls to the ReactivateUser method.
+		ReactivateUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID uuid.UUID
+			// Reason is the reason argument value.
+			Reason string
+		}
+		// RestoreUser holds details about calls to the RestoreUser method.
+		RestoreUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID uuid.UUID
+		}
+		// SearchUsers holds details about calls to the SearchUsers method.
+		SearchUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Opts is the opts argument value.
+			Opts models.SearchUsersOptions
+		}
+		// SuspendUser holds details about calls to the SuspendUser method.
+		SuspendUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID uuid.UUID
+			// Reason is the reason argument value.
+			Reason string
+		}
 		// UpdateUser holds details about calls to the UpdateUser method.
 		UpdateUser []struct {
 			// Ctx is the ctx argument value.
@@ -84,10 +269,175 @@ type UserServiceMock struct {
 			Uu models.UpdateUser
 		}
 	}
-	lockCreateUser sync.RWMutex
-	lockDeleteUser sync.RWMutex
-	lockGetUsers   sync.RWMutex
-	lockUpdateUser sync.RWMutex
+	lockBatchCreateUsers sync.RWMutex
+	lockBatchDeleteUsers sync.RWMutex
+	lockBatchUpdateUsers sync.RWMutex
+	lockCountUsers       sync.RWMutex
+	lockCreateUser       sync.RWMutex
+	lockDeactivateUser   sync.RWMutex
+	lockDeleteUser       sync.RWMutex
+	lockEraseUser        sync.RWMutex
+	lockExportUsers      sync.RWMutex
+	lockGetUsers         sync.RWMutex
+	lockImportUsers      sync.RWMutex
+	lockListUsers        sync.RWMutex
+	lockReactivateUser   sync.RWMutex
+	lockRestoreUser      sync.RWMutex
+	lockSearchUsers      sync.RWMutex
+	lockSuspendUser      sync.RWMutex
+	lockUpdateUser       sync.RWMutex
+}
+
+// BatchCreateUsers calls BatchCreateUsersFunc.
+func (mock *UserServiceMock) BatchCreateUsers(ctx context.Context, users []models.NewUser, atomic bool) ([]models.BatchResult, error) {
+	if mock.BatchCreateUsersFunc == nil {
+		panic("UserServiceMock.BatchCreateUsersFunc: method is nil but userService.BatchCreateUsers was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Users  []models.NewUser
+		Atomic bool
+	}{
+		Ctx:    ctx,
+		Users:  users,
+		Atomic: atomic,
+	}
+	mock.lockBatchCreateUsers.Lock()
+	mock.calls.BatchCreateUsers = append(mock.calls.BatchCreateUsers, callInfo)
+	mock.lockBatchCreateUsers.Unlock()
+	return mock.BatchCreateUsersFunc(ctx, users, atomic)
+}
+
+// BatchCreateUsersCalls gets all the calls that were made to BatchCreateUsers.
+// Check the length with:
+//     len(mockedUserService.BatchCreateUsersCalls())
+func (mock *UserServiceMock) BatchCreateUsersCalls() []struct {
+	Ctx    context.Context
+	Users  []models.NewUser
+	Atomic bool
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Users  []models.NewUser
+		Atomic bool
+	}
+	mock.lockBatchCreateUsers.RLock()
+	calls = mock.calls.BatchCreateUsers
+	mock.lockBatchCreateUsers.RUnlock()
+	return calls
+}
+
+// BatchDeleteUsers calls BatchDeleteUsersFunc.
+func (mock *UserServiceMock) BatchDeleteUsers(ctx context.Context, userIDs []uuid.UUID, atomic bool) ([]models.BatchResult, error) {
+	if mock.BatchDeleteUsersFunc == nil {
+		panic("UserServiceMock.BatchDeleteUsersFunc: method is nil but userService.BatchDeleteUsers was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserIDs []uuid.UUID
+		Atomic  bool
+	}{
+		Ctx:     ctx,
+		UserIDs: userIDs,
+		Atomic:  atomic,
+	}
+	mock.lockBatchDeleteUsers.Lock()
+	mock.calls.BatchDeleteUsers = append(mock.calls.BatchDeleteUsers, callInfo)
+	mock.lockBatchDeleteUsers.Unlock()
+	return mock.BatchDeleteUsersFunc(ctx, userIDs, atomic)
+}
+
+// BatchDeleteUsersCalls gets all the calls that were made to BatchDeleteUsers.
+// Check the length with:
+//     len(mockedUserService.BatchDeleteUsersCalls())
+func (mock *UserServiceMock) BatchDeleteUsersCalls() []struct {
+	Ctx     context.Context
+	UserIDs []uuid.UUID
+	Atomic  bool
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserIDs []uuid.UUID
+		Atomic  bool
+	}
+	mock.lockBatchDeleteUsers.RLock()
+	calls = mock.calls.BatchDeleteUsers
+	mock.lockBatchDeleteUsers.RUnlock()
+	return calls
+}
+
+// BatchUpdateUsers calls BatchUpdateUsersFunc.
+func (mock *UserServiceMock) BatchUpdateUsers(ctx context.Context, updates []models.BatchUpdate, atomic bool) ([]models.BatchResult, error) {
+	if mock.BatchUpdateUsersFunc == nil {
+		panic("UserServiceMock.BatchUpdateUsersFunc: method is nil but userService.BatchUpdateUsers was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Updates []models.BatchUpdate
+		Atomic  bool
+	}{
+		Ctx:     ctx,
+		Updates: updates,
+		Atomic:  atomic,
+	}
+	mock.lockBatchUpdateUsers.Lock()
+	mock.calls.BatchUpdateUsers = append(mock.calls.BatchUpdateUsers, callInfo)
+	mock.lockBatchUpdateUsers.Unlock()
+	return mock.BatchUpdateUsersFunc(ctx, updates, atomic)
+}
+
+// BatchUpdateUsersCalls gets all the calls that were made to BatchUpdateUsers.
+// Check the length with:
+//     len(mockedUserService.BatchUpdateUsersCalls())
+func (mock *UserServiceMock) BatchUpdateUsersCalls() []struct {
+	Ctx     context.Context
+	Updates []models.BatchUpdate
+	Atomic  bool
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Updates []models.BatchUpdate
+		Atomic  bool
+	}
+	mock.lockBatchUpdateUsers.RLock()
+	calls = mock.calls.BatchUpdateUsers
+	mock.lockBatchUpdateUsers.RUnlock()
+	return calls
+}
+
+// CountUsers calls CountUsersFunc.
+func (mock *UserServiceMock) CountUsers(ctx context.Context, qu models.GetUsersOptions) (models.UserCount, error) {
+	if mock.CountUsersFunc == nil {
+		panic("UserServiceMock.CountUsersFunc: method is nil but userService.CountUsers was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Qu  models.GetUsersOptions
+	}{
+		Ctx: ctx,
+		Qu:  qu,
+	}
+	mock.lockCountUsers.Lock()
+	mock.calls.CountUsers = append(mock.calls.CountUsers, callInfo)
+	mock.lockCountUsers.Unlock()
+	return mock.CountUsersFunc(ctx, qu)
+}
+
+// CountUsersCalls gets all the calls that were made to CountUsers.
+// Check the length with:
+//     len(mockedUserService.CountUsersCalls())
+func (mock *UserServiceMock) CountUsersCalls() []struct {
+	Ctx context.Context
+	Qu  models.GetUsersOptions
+} {
+	var calls []struct {
+		Ctx context.Context
+		Qu  models.GetUsersOptions
+	}
+	mock.lockCountUsers.RLock()
+	calls = mock.calls.CountUsers
+	mock.lockCountUsers.RUnlock()
+	return calls
 }
 
 // CreateUser calls CreateUserFunc.
@@ -125,6 +475,45 @@ func (mock *UserServiceMock) CreateUserCalls() []struct {
 	return calls
 }
 
+// DeactivateUser calls DeactivateUserFunc.
+func (mock *UserServiceMock) DeactivateUser(ctx context.Context, userID uuid.UUID, reason string) error {
+	if mock.DeactivateUserFunc == nil {
+		panic("UserServiceMock.DeactivateUserFunc: method is nil but userService.DeactivateUser was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+		Reason string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Reason: reason,
+	}
+	mock.lockDeactivateUser.Lock()
+	mock.calls.DeactivateUser = append(mock.calls.DeactivateUser, callInfo)
+	mock.lockDeactivateUser.Unlock()
+	return mock.DeactivateUserFunc(ctx, userID, reason)
+}
+
+// DeactivateUserCalls gets all the calls that were made to DeactivateUser.
+// Check the length with:
+//     len(mockedUserService.DeactivateUserCalls())
+func (mock *UserServiceMock) DeactivateUserCalls() []struct {
+	Ctx    context.Context
+	UserID uuid.UUID
+	Reason string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+		Reason string
+	}
+	mock.lockDeactivateUser.RLock()
+	calls = mock.calls.DeactivateUser
+	mock.lockDeactivateUser.RUnlock()
+	return calls
+}
+
 // DeleteUser calls DeleteUserFunc.
 func (mock *UserServiceMock) DeleteUser(ctx context.Context, userID uuid.UUID) error {
 	if mock.DeleteUserFunc == nil {
@@ -160,6 +549,80 @@ func (mock *UserServiceMock) DeleteUserCalls() []struct {
 	return calls
 }
 
+// EraseUser calls EraseUserFunc.
+func (mock *UserServiceMock) EraseUser(ctx context.Context, userID uuid.UUID) error {
+	if mock.EraseUserFunc == nil {
+		panic("UserServiceMock.EraseUserFunc: method is nil but userService.EraseUser was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockEraseUser.Lock()
+	mock.calls.EraseUser = append(mock.calls.EraseUser, callInfo)
+	mock.lockEraseUser.Unlock()
+	return mock.EraseUserFunc(ctx, userID)
+}
+
+// EraseUserCalls gets all the calls that were made to EraseUser.
+// Check the length with:
+//     len(mockedUserService.EraseUserCalls())
+func (mock *UserServiceMock) EraseUserCalls() []struct {
+	Ctx    context.Context
+	UserID uuid.UUID
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+	}
+	mock.lockEraseUser.RLock()
+	calls = mock.calls.EraseUser
+	mock.lockEraseUser.RUnlock()
+	return calls
+}
+
+// ExportUsers calls ExportUsersFunc.
+func (mock *UserServiceMock) ExportUsers(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error {
+	if mock.ExportUsersFunc == nil {
+		panic("UserServiceMock.ExportUsersFunc: method is nil but userService.ExportUsers was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Qu  models.GetUsersOptions
+		Fn  func(models.User) error
+	}{
+		Ctx: ctx,
+		Qu:  qu,
+		Fn:  fn,
+	}
+	mock.lockExportUsers.Lock()
+	mock.calls.ExportUsers = append(mock.calls.ExportUsers, callInfo)
+	mock.lockExportUsers.Unlock()
+	return mock.ExportUsersFunc(ctx, qu, fn)
+}
+
+// ExportUsersCalls gets all the calls that were made to ExportUsers.
+// Check the length with:
+//     len(mockedUserService.ExportUsersCalls())
+func (mock *UserServiceMock) ExportUsersCalls() []struct {
+	Ctx context.Context
+	Qu  models.GetUsersOptions
+	Fn  func(models.User) error
+} {
+	var calls []struct {
+		Ctx context.Context
+		Qu  models.GetUsersOptions
+		Fn  func(models.User) error
+	}
+	mock.lockExportUsers.RLock()
+	calls = mock.calls.ExportUsers
+	mock.lockExportUsers.RUnlock()
+	return calls
+}
+
 // GetUsers calls GetUsersFunc.
 func (mock *UserServiceMock) GetUsers(ctx context.Context, qu models.GetUsersOptions) ([]models.User, error) {
 	if mock.GetUsersFunc == nil {
@@ -195,6 +658,228 @@ func (mock *UserServiceMock) GetUsersCalls() []struct {
 	return calls
 }
 
+// ImportUsers calls ImportUsersFunc.
+func (mock *UserServiceMock) ImportUsers(ctx context.Context, opts models.ImportUsersOptions, next func() (models.NewUser, error)) ([]models.ImportResult, error) {
+	if mock.ImportUsersFunc == nil {
+		panic("UserServiceMock.ImportUsersFunc: method is nil but userService.ImportUsers was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Opts models.ImportUsersOptions
+		Next func() (models.NewUser, error)
+	}{
+		Ctx:  ctx,
+		Opts: opts,
+		Next: next,
+	}
+	mock.lockImportUsers.Lock()
+	mock.calls.ImportUsers = append(mock.calls.ImportUsers, callInfo)
+	mock.lockImportUsers.Unlock()
+	return mock.ImportUsersFunc(ctx, opts, next)
+}
+
+// ImportUsersCalls gets all the calls that were made to ImportUsers.
+// Check the length with:
+//     len(mockedUserService.ImportUsersCalls())
+func (mock *UserServiceMock) ImportUsersCalls() []struct {
+	Ctx  context.Context
+	Opts models.ImportUsersOptions
+	Next func() (models.NewUser, error)
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Opts models.ImportUsersOptions
+		Next func() (models.NewUser, error)
+	}
+	mock.lockImportUsers.RLock()
+	calls = mock.calls.ImportUsers
+	mock.lockImportUsers.RUnlock()
+	return calls
+}
+
+// ListUsers calls ListUsersFunc.
+func (mock *UserServiceMock) ListUsers(ctx context.Context, qu models.GetUsersOptions) ([]models.User, *models.UserCursor, error) {
+	if mock.ListUsersFunc == nil {
+		panic("UserServiceMock.ListUsersFunc: method is nil but userService.ListUsers was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Qu  models.GetUsersOptions
+	}{
+		Ctx: ctx,
+		Qu:  qu,
+	}
+	mock.lockListUsers.Lock()
+	mock.calls.ListUsers = append(mock.calls.ListUsers, callInfo)
+	mock.lockListUsers.Unlock()
+	return mock.ListUsersFunc(ctx, qu)
+}
+
+// ListUsersCalls gets all the calls that were made to ListUsers.
+// Check the length with:
+//     len(mockedUserService.ListUsersCalls())
+func (mock *UserServiceMock) ListUsersCalls() []struct {
+	Ctx context.Context
+	Qu  models.GetUsersOptions
+} {
+	var calls []struct {
+		Ctx context.Context
+		Qu  models.GetUsersOptions
+	}
+	mock.lockListUsers.RLock()
+	calls = mock.calls.ListUsers
+	mock.lockListUsers.RUnlock()
+	return calls
+}
+
+// ReactivateUser calls ReactivateUserFunc.
+func (mock *UserServiceMock) ReactivateUser(ctx context.Context, userID uuid.UUID, reason string) error {
+	if mock.ReactivateUserFunc == nil {
+		panic("UserServiceMock.ReactivateUserFunc: method is nil but userService.ReactivateUser was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+		Reason string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Reason: reason,
+	}
+	mock.lockReactivateUser.Lock()
+	mock.calls.ReactivateUser = append(mock.calls.ReactivateUser, callInfo)
+	mock.lockReactivateUser.Unlock()
+	return mock.ReactivateUserFunc(ctx, userID, reason)
+}
+
+// ReactivateUserCalls gets all the calls that were made to ReactivateUser.
+// Check the length with:
+//     len(mockedUserService.ReactivateUserCalls())
+func (mock *UserServiceMock) ReactivateUserCalls() []struct {
+	Ctx    context.Context
+	UserID uuid.UUID
+	Reason string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+		Reason string
+	}
+	mock.lockReactivateUser.RLock()
+	calls = mock.calls.ReactivateUser
+	mock.lockReactivateUser.RUnlock()
+	return calls
+}
+
+// RestoreUser calls RestoreUserFunc.
+func (mock *UserServiceMock) RestoreUser(ctx context.Context, userID uuid.UUID) error {
+	if mock.RestoreUserFunc == nil {
+		panic("UserServiceMock.RestoreUserFunc: method is nil but userService.RestoreUser was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockRestoreUser.Lock()
+	mock.calls.RestoreUser = append(mock.calls.RestoreUser, callInfo)
+	mock.lockRestoreUser.Unlock()
+	return mock.RestoreUserFunc(ctx, userID)
+}
+
+// RestoreUserCalls gets all the calls that were made to RestoreUser.
+// Check the length with:
+//     len(mockedUserService.RestoreUserCalls())
+func (mock *UserServiceMock) RestoreUserCalls() []struct {
+	Ctx    context.Context
+	UserID uuid.UUID
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+	}
+	mock.lockRestoreUser.RLock()
+	calls = mock.calls.RestoreUser
+	mock.lockRestoreUser.RUnlock()
+	return calls
+}
+
+// SearchUsers calls SearchUsersFunc.
+func (mock *UserServiceMock) SearchUsers(ctx context.Context, opts models.SearchUsersOptions) ([]models.UserMatch, int, error) {
+	if mock.SearchUsersFunc == nil {
+		panic("UserServiceMock.SearchUsersFunc: method is nil but userService.SearchUsers was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Opts models.SearchUsersOptions
+	}{
+		Ctx:  ctx,
+		Opts: opts,
+	}
+	mock.lockSearchUsers.Lock()
+	mock.calls.SearchUsers = append(mock.calls.SearchUsers, callInfo)
+	mock.lockSearchUsers.Unlock()
+	return mock.SearchUsersFunc(ctx, opts)
+}
+
+// SearchUsersCalls gets all the calls that were made to SearchUsers.
+// Check the length with:
+//     len(mockedUserService.SearchUsersCalls())
+func (mock *UserServiceMock) SearchUsersCalls() []struct {
+	Ctx  context.Context
+	Opts models.SearchUsersOptions
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Opts models.SearchUsersOptions
+	}
+	mock.lockSearchUsers.RLock()
+	calls = mock.calls.SearchUsers
+	mock.lockSearchUsers.RUnlock()
+	return calls
+}
+
+// SuspendUser calls SuspendUserFunc.
+func (mock *UserServiceMock) SuspendUser(ctx context.Context, userID uuid.UUID, reason string) error {
+	if mock.SuspendUserFunc == nil {
+		panic("UserServiceMock.SuspendUserFunc: method is nil but userService.SuspendUser was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+		Reason string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Reason: reason,
+	}
+	mock.lockSuspendUser.Lock()
+	mock.calls.SuspendUser = append(mock.calls.SuspendUser, callInfo)
+	mock.lockSuspendUser.Unlock()
+	return mock.SuspendUserFunc(ctx, userID, reason)
+}
+
+// SuspendUserCalls gets all the calls that were made to SuspendUser.
+// Check the length with:
+//     len(mockedUserService.SuspendUserCalls())
+func (mock *UserServiceMock) SuspendUserCalls() []struct {
+	Ctx    context.Context
+	UserID uuid.UUID
+	Reason string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID uuid.UUID
+		Reason string
+	}
+	mock.lockSuspendUser.RLock()
+	calls = mock.calls.SuspendUser
+	mock.lockSuspendUser.RUnlock()
+	return calls
+}
+
 // UpdateUser calls UpdateUserFunc.
 func (mock *UserServiceMock) UpdateUser(ctx context.Context, userID uuid.UUID, uu models.UpdateUser) error {
 	if mock.UpdateUserFunc == nil {
@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+
+	// 3rd party
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+//go:generate moq -out watch_service_mock_test.go . watchService
+type watchService interface {
+	WatchUsers(ctx context.Context, qu models.GetUsersOptions, after *models.ChangeCursor, fn func(models.UserChange) error) error
+}
+
+var userChangeTypes = map[models.UserChangeKind]pb.UserChangeType{
+	models.UserChangeCreated:    pb.UserChangeType_USER_CHANGE_TYPE_CREATED,
+	models.UserChangeUpdated:    pb.UserChangeType_USER_CHANGE_TYPE_UPDATED,
+	models.UserChangeDeleted:    pb.UserChangeType_USER_CHANGE_TYPE_DELETED,
+	models.UserChangeCheckpoint: pb.UserChangeType_USER_CHANGE_TYPE_CHECKPOINT,
+}
+
+// WatchUsers streams the changes to the users matching the filter until the
+// client goes away. Every message carries a token to resume the watch from.
+func (g *GRPC) WatchUsers(req *pb.WatchUsersRequest, stream pb.User_WatchUsersServer) error {
+	ctx := stream.Context()
+
+	quOpts, err := mapQueryOptions(&pb.QueryUsersRequest{
+		Filter:           req.GetFilter(),
+		FilterExpression: req.GetFilterExpression(),
+	})
+	if err != nil {
+		return err
+	}
+
+	query, err := watchFingerprint(req)
+	if err != nil {
+		return g.mapError(err)
+	}
+
+	var after *models.ChangeCursor
+	if req.GetResumeToken() != "" {
+		after = &models.ChangeCursor{}
+		if err := g.pageTokens.Decode(req.GetResumeToken(), query, after); err != nil {
+			return errInvalidResumeToken
+		}
+	}
+
+	err = g.watchSvc.WatchUsers(ctx, quOpts, after, func(c models.UserChange) error {
+		token, err := g.pageTokens.Encode(c.Cursor, query)
+		if err != nil {
+			return err
+		}
+
+		msg := &pb.WatchUsersResponse{
+			Type:        userChangeTypes[c.Kind],
+			ChangedAt:   timestamppb.New(c.Cursor.ChangedAt),
+			ResumeToken: token,
+		}
+
+		if c.Kind != models.UserChangeCheckpoint {
+			if msg.User, err = mapUserInfo(c.User); err != nil {
+				return err
+			}
+			// Password hashes are not streamed.
+			msg.User.Password = ""
+		}
+
+		return stream.Send(msg)
+	})
+
+	if err != nil {
+		// The client went away, or sending failed.
+		if _, ok := status.FromError(err); ok || ctx.Err() != nil {
+			return err
+		}
+		return g.mapError(err)
+	}
+
+	return nil
+}
+
+// watchFingerprint identifies the filter of a watch, which its resume tokens
+// are bound to.
+func watchFingerprint(req *pb.WatchUsersRequest) ([]byte, error) {
+	query := proto.Clone(req).(*pb.WatchUsersRequest)
+	query.ResumeToken = ""
+
+	return proto.MarshalOptions{Deterministic: true}.Marshal(query)
+}
@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/pagetoken"
+	"github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+type watchUsersStream struct {
+	grpc.ServerStream
+	msgs []*user.WatchUsersResponse
+}
+
+func (s *watchUsersStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *watchUsersStream) Send(msg *user.WatchUsersResponse) error {
+	s.msgs = append(s.msgs, msg)
+	return nil
+}
+
+func TestGRPC_WatchUsers(t *testing.T) {
+	changedAt := time.Now().UTC()
+	userID := uuid.New()
+
+	svc := &WatchServiceMock{
+		WatchUsersFunc: func(ctx context.Context, qu models.GetUsersOptions, after *models.ChangeCursor, fn func(models.UserChange) error) error {
+			if after != nil {
+				return models.ErrResumeTokenExpired
+			}
+
+			err := fn(models.UserChange{
+				Cursor: models.ChangeCursor{ID: 7, ChangedAt: changedAt},
+				Kind:   models.UserChangeUpdated,
+				User:   models.User{ID: userID, Email: "antonis@mail.com", Password: []byte("hash")},
+			})
+			if err != nil {
+				return err
+			}
+			return fn(models.UserChange{
+				Cursor: models.ChangeCursor{ID: 9, ChangedAt: changedAt},
+				Kind:   models.UserChangeCheckpoint,
+			})
+		},
+	}
+
+	g := &GRPC{
+		watchSvc:   svc,
+		logger:     zap.NewNop().Sugar(),
+		pageTokens: pagetoken.NewCodec([]byte("secret")),
+	}
+
+	req := &user.WatchUsersRequest{Filter: &user.QueryUsersRequest_Filter{Country: "GR"}}
+	stream := &watchUsersStream{}
+	err := g.WatchUsers(req, stream)
+	require.NoError(t, err)
+
+	require.Equal(t, "GR", svc.WatchUsersCalls()[0].Qu.Filter.Country)
+	require.Nil(t, svc.WatchUsersCalls()[0].After)
+
+	require.Len(t, stream.msgs, 2)
+	require.Equal(t, user.UserChangeType_USER_CHANGE_TYPE_UPDATED, stream.msgs[0].GetType())
+	require.Equal(t, userID.String(), stream.msgs[0].GetUser().GetId())
+	require.Empty(t, stream.msgs[0].GetUser().GetPassword())
+	require.Equal(t, user.UserChangeType_USER_CHANGE_TYPE_CHECKPOINT, stream.msgs[1].GetType())
+	require.Nil(t, stream.msgs[1].GetUser())
+	require.NotEmpty(t, stream.msgs[1].GetResumeToken())
+
+	t.Log("resumed")
+	{
+		req.ResumeToken = stream.msgs[1].GetResumeToken()
+		err := g.WatchUsers(req, &watchUsersStream{})
+		require.Equal(t, codes.OutOfRange, status.Code(err))
+		require.Equal(t, int64(9), svc.WatchUsersCalls()[1].After.ID)
+	}
+
+	t.Log("resume token of another filter")
+	{
+		req := &user.WatchUsersRequest{
+			Filter:      &user.QueryUsersRequest_Filter{Country: "CY"},
+			ResumeToken: stream.msgs[1].GetResumeToken(),
+		}
+		err := g.WatchUsers(req, &watchUsersStream{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		require.Len(t, svc.WatchUsersCalls(), 2)
+	}
+}
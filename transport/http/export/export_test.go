@@ -0,0 +1,161 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+const testToken = "umk_test"
+
+func newTestServer(t *testing.T, users userService, scopes ...string) *httptest.Server {
+	authn := &AuthenticatorMock{
+		AuthenticateFunc: func(ctx context.Context, plaintext string) (models.Principal, error) {
+			if plaintext != testToken {
+				return models.Principal{}, models.ErrInvalidAPIKey
+			}
+			return models.Principal{UserID: uuid.New(), APIKeyID: uuid.New(), Scopes: scopes}, nil
+		},
+	}
+
+	s := NewServer(zap.NewNop().Sugar(), "", users, authn)
+	ts := httptest.NewServer(s.httpServer.Handler)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func download(t *testing.T, ts *httptest.Server, query string) (*http.Response, string, error) {
+	req, err := http.NewRequest(http.MethodGet, ts.URL+usersExportPath+"?"+query, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	return resp, string(body), err
+}
+
+func exportUsers(users ...models.User) *UserServiceMock {
+	return &UserServiceMock{
+		ExportUsersFunc: func(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error {
+			for _, u := range users {
+				if err := fn(u); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestExportUsers(t *testing.T) {
+	u := models.User{ID: uuid.New(), Email: "antonis@mail.com", Status: models.UserStatusActive}
+	svc := exportUsers(u)
+	ts := newTestServer(t, svc, models.ScopeUsersRead)
+
+	resp, body, err := download(t, ts, `format=jsonl&columns=id,email&status=Active&filter=nickname:"tony*"`)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/jsonl", resp.Header.Get("Content-Type"))
+	require.Regexp(t, `^attachment; filename="users-\d{8}T\d{6}Z\.jsonl"$`, resp.Header.Get("Content-Disposition"))
+	require.Equal(t, `{"email":"antonis@mail.com","id":"`+u.ID.String()+`"}`+"\n", body)
+
+	qu := svc.ExportUsersCalls()[0].Qu
+	require.Equal(t, models.UserStatusActive, qu.Filter.Status)
+	require.NotNil(t, qu.Filter.Expression)
+	require.False(t, qu.IncludeDeleted)
+
+	t.Log("csv by default")
+	{
+		resp, body, err := download(t, ts, "columns=email")
+		require.NoError(t, err)
+		require.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		require.Equal(t, "email\nantonis@mail.com\n", body)
+	}
+}
+
+func TestExportUsers_Rejected(t *testing.T) {
+	ts := newTestServer(t, exportUsers(), models.ScopeUsersRead)
+
+	tt := map[string]struct {
+		query  string
+		status int
+	}{
+		"unknown format":    {query: "format=xml", status: http.StatusBadRequest},
+		"unknown column":    {query: "columns=id,password", status: http.StatusBadRequest},
+		"unknown status":    {query: "status=gone", status: http.StatusBadRequest},
+		"invalid filter":    {query: "filter=country%20%3D", status: http.StatusBadRequest},
+		"deleted, no admin": {query: "include_deleted=true", status: http.StatusForbidden},
+	}
+
+	for name, tc := range tt {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			resp, body, err := download(t, ts, tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.status, resp.StatusCode)
+			require.Empty(t, resp.Header.Get("Content-Disposition"))
+			require.Contains(t, body, `"error"`)
+		})
+	}
+
+	t.Log("missing scope")
+	{
+		ts := newTestServer(t, exportUsers(), models.ScopeGroupsRead)
+		resp, _, err := download(t, ts, "")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	}
+}
+
+func TestExportUsers_Failure(t *testing.T) {
+	t.Log("before the download started")
+	{
+		svc := &UserServiceMock{
+			ExportUsersFunc: func(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error {
+				return errors.New("db down")
+			},
+		}
+		ts := newTestServer(t, svc, models.ScopeUsersRead)
+
+		resp, body, err := download(t, ts, "")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		require.Contains(t, body, "internal server error")
+	}
+
+	t.Log("after the download started")
+	{
+		svc := &UserServiceMock{
+			ExportUsersFunc: func(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error {
+				u := models.User{ID: uuid.New(), Email: strings.Repeat("x", exportBufferSize)}
+				if err := fn(u); err != nil {
+					return err
+				}
+				return errors.New("db down")
+			},
+		}
+		ts := newTestServer(t, svc, models.ScopeUsersRead)
+
+		resp, _, err := download(t, ts, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	}
+}
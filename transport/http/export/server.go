@@ -0,0 +1,145 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	// 3rd party
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	thttp "github.com/TonyPath/user-mng-grpc-service/transport/http"
+)
+
+const (
+	httpReadTimeout = 30 * time.Second
+	// Exports are streamed for as long as they take, so writes have no timeout.
+	httpWriteTimeout    = 0
+	shutdownGracePeriod = 5 * time.Second
+)
+
+const usersExportPath = "/v1/users:export"
+
+//go:generate moq -out user_service_mock_test.go . userService
+type userService interface {
+	ExportUsers(ctx context.Context, qu models.GetUsersOptions, fn func(models.User) error) error
+}
+
+//go:generate moq -out authenticator_mock_test.go . authenticator
+type authenticator interface {
+	Authenticate(ctx context.Context, plaintext string) (models.Principal, error)
+}
+
+// Server serves downloads of user exports, the HTTP counterpart of the
+// ExportUsers RPC.
+type Server struct {
+	httpServer http.Server
+	users      userService
+	authn      authenticator
+	logger     *zap.SugaredLogger
+}
+
+func NewServer(logger *zap.SugaredLogger, addr string, users userService, authn authenticator) *Server {
+	s := &Server{
+		httpServer: http.Server{
+			Addr:         addr,
+			ReadTimeout:  httpReadTimeout,
+			WriteTimeout: httpWriteTimeout,
+		},
+		users:  users,
+		authn:  authn,
+		logger: logger,
+	}
+
+	s.addRoutes()
+
+	return s
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Infow("startup", "status", "http export server started", "host", s.httpServer.Addr)
+		errCh <- s.httpServer.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return s.shutdown()
+	case err := <-errCh:
+		return err
+	}
+}
+
+func (s *Server) addRoutes() {
+	mux := chi.NewRouter()
+
+	mux.Group(func(r chi.Router) {
+		r.Use(s.authenticate)
+		r.Get(usersExportPath, s.exportUsers)
+	})
+
+	s.httpServer.Handler = mux
+}
+
+func (s *Server) shutdown() error {
+	tctx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+	defer cancel()
+
+	if err := s.httpServer.Shutdown(tctx); err != nil {
+		closeErr := s.httpServer.Close()
+		if closeErr != nil {
+			return fmt.Errorf("cannot stop server gracefully: %w", err)
+		}
+	}
+	s.logger.Infow("shutdown", "status", "gracefully stopped http export server")
+
+	return nil
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func respondError(w http.ResponseWriter, statusCode int, msg string) {
+	thttp.Respond(w, errorResponse{Error: msg}, statusCode)
+}
+
+// authenticate requires an API key with the users:read scope, sent as a
+// bearer token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="export"`)
+			respondError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+
+		principal, err := s.authn.Authenticate(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidAPIKey) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="export", error="invalid_token"`)
+				respondError(w, http.StatusUnauthorized, "invalid token")
+				return
+			}
+			s.logger.Error(err)
+			respondError(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		if !principal.HasScope(models.ScopeUsersRead) {
+			respondError(w, http.StatusForbidden, "the token lacks the "+models.ScopeUsersRead+" scope")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
@@ -0,0 +1,156 @@
+package export
+
+import (
+	"bufio"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/filter"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/transport/userexport"
+)
+
+const exportBufferSize = 64 << 10
+
+var userStatuses = []models.UserStatus{
+	models.UserStatusPending,
+	models.UserStatusActive,
+	models.UserStatusSuspended,
+	models.UserStatusDeactivated,
+}
+
+// exportUsers streams every user matching the query parameters as an
+// attachment:
+//
+//	format           csv (default), jsonl or protobuf
+//	columns          comma separated exported fields, all by default
+//	filter           AIP-160 filter expression, as in QueryUsers
+//	country, email, nickname, status, consent_purpose
+//	include_deleted  true to export soft deleted users too (users:admin)
+//
+// Errors found once the export has started cannot change the status code, so
+// the response is cut short instead, which clients see as a truncated body.
+func (s *Server) exportUsers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	format, err := userexport.ParseFormat(q.Get("format"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var names []string
+	if v := q.Get("columns"); v != "" {
+		names = strings.Split(v, ",")
+	}
+	columns, err := userexport.ParseColumns(names)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quOpts, err := parseQuery(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if quOpts.IncludeDeleted {
+		if p, ok := auth.PrincipalFromContext(r.Context()); !ok || !p.HasScope(models.ScopeUsersAdmin) {
+			respondError(w, http.StatusForbidden, "include_deleted requires the "+models.ScopeUsersAdmin+" scope")
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+userexport.Filename(format, time.Now())+`"`)
+
+	rw := &responseWriter{ResponseWriter: w}
+	bw := bufio.NewWriterSize(rw, exportBufferSize)
+	enc, err := userexport.NewEncoder(bw, format, columns)
+	if err == nil {
+		err = s.users.ExportUsers(r.Context(), quOpts, enc.Encode)
+	}
+	if err == nil {
+		err = enc.Flush()
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+
+	if err == nil {
+		return
+	}
+
+	if rw.written {
+		s.logger.Errorw("export cut short", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	w.Header().Del("Content-Disposition")
+	s.handleError(w, err)
+}
+
+// parseQuery returns the filter of the export.
+func parseQuery(r *http.Request) (models.GetUsersOptions, error) {
+	q := r.URL.Query()
+
+	var quOpts models.GetUsersOptions
+	quOpts.Filter.Country = q.Get("country")
+	quOpts.Filter.Email = q.Get("email")
+	quOpts.Filter.Nickname = q.Get("nickname")
+	quOpts.Filter.ConsentPurpose = q.Get("consent_purpose")
+
+	if v := q.Get("status"); v != "" {
+		for _, st := range userStatuses {
+			if strings.EqualFold(v, string(st)) {
+				quOpts.Filter.Status = st
+			}
+		}
+		if quOpts.Filter.Status == "" {
+			return models.GetUsersOptions{}, errors.New("invalid status")
+		}
+	}
+
+	if v := q.Get("include_deleted"); v != "" {
+		includeDeleted, err := strconv.ParseBool(v)
+		if err != nil {
+			return models.GetUsersOptions{}, errors.New("include_deleted must be true or false")
+		}
+		quOpts.IncludeDeleted = includeDeleted
+	}
+
+	expr, err := filter.Parse(q.Get("filter"))
+	if err != nil {
+		return models.GetUsersOptions{}, err
+	}
+	quOpts.Filter.Expression = expr
+
+	return quOpts, nil
+}
+
+func (s *Server) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidFilter), errors.Is(err, models.ErrAttributeNotFilterable):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		s.logger.Error(err)
+		respondError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// responseWriter records whether the response has started.
+type responseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	rw.written = true
+	return rw.ResponseWriter.Write(p)
+}
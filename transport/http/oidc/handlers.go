@@ -0,0 +1,320 @@
+package oidc
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/oidc"
+	thttp "github.com/TonyPath/user-mng-grpc-service/transport/http"
+)
+
+// Error codes of OAuth 2.0 (RFC 6749) and bearer token usage (RFC 6750).
+const (
+	errCodeInvalidRequest          = "invalid_request"
+	errCodeInvalidClient           = "invalid_client"
+	errCodeInvalidGrant            = "invalid_grant"
+	errCodeInvalidScope            = "invalid_scope"
+	errCodeInvalidToken            = "invalid_token"
+	errCodeAccessDenied            = "access_denied"
+	errCodeUnsupportedGrantType    = "unsupported_grant_type"
+	errCodeUnsupportedResponseType = "unsupported_response_type"
+	errCodeServerError             = "server_error"
+)
+
+type discoveryResponse struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	IDToken     string `json:"id_token"`
+	Scope       string `json:"scope"`
+}
+
+type errorResponse struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+// authorizePage is rendered by the authorize template.
+type authorizePage struct {
+	ClientName string
+	Scopes     []string
+	Request    models.AuthorizationRequest
+	Scope      string
+	Email      string
+	Error      string
+}
+
+// errorPage is rendered by the error template, when the user cannot be sent
+// back to the client.
+type errorPage struct {
+	Message string
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	thttp.Respond(w, discoveryResponse{
+		Issuer:                            s.issuer,
+		AuthorizationEndpoint:             s.issuer + authorizePath,
+		TokenEndpoint:                     s.issuer + tokenPath,
+		UserInfoEndpoint:                  s.issuer + userInfoPath,
+		JWKSURI:                           s.issuer + jwksPath,
+		ScopesSupported:                   models.OIDCScopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{oidc.Algorithm},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{oidc.CodeChallengeMethod},
+		ClaimsSupported: []string{
+			"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce",
+			"name", "given_name", "family_name", "nickname", "updated_at", "email",
+		},
+	}, http.StatusOK)
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	thttp.Respond(w, s.svc.JWKS(), http.StatusOK)
+}
+
+// authorizeForm asks the user to sign in and consent to the request.
+func (s *Server) authorizeForm(w http.ResponseWriter, r *http.Request) {
+	req := authorizationRequest(r.URL.Query())
+
+	client, err := s.svc.ValidateAuthorizationRequest(r.Context(), req)
+	if err != nil {
+		s.authorizationError(w, r, req, err)
+		return
+	}
+
+	s.renderAuthorize(w, http.StatusOK, authorizePage{
+		ClientName: client.Name,
+		Scopes:     req.Scopes,
+		Request:    req,
+		Scope:      strings.Join(req.Scopes, " "),
+	})
+}
+
+// authorize handles the submitted sign in and consent form.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.renderError(w, http.StatusBadRequest, "The request is malformed.")
+		return
+	}
+
+	req := authorizationRequest(r.PostForm)
+
+	client, err := s.svc.ValidateAuthorizationRequest(r.Context(), req)
+	if err != nil {
+		s.authorizationError(w, r, req, err)
+		return
+	}
+
+	if r.PostForm.Get("action") != "allow" {
+		redirect(w, r, req.RedirectURI, url.Values{"error": {errCodeAccessDenied}, "state": {req.State}})
+		return
+	}
+
+	email := r.PostForm.Get("email")
+
+	code, err := s.svc.Authorize(r.Context(), req, email, r.PostForm.Get("password"))
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidCredentials) {
+			s.renderAuthorize(w, http.StatusUnauthorized, authorizePage{
+				ClientName: client.Name,
+				Scopes:     req.Scopes,
+				Request:    req,
+				Scope:      strings.Join(req.Scopes, " "),
+				Email:      email,
+				Error:      "Invalid email or password.",
+			})
+			return
+		}
+		s.authorizationError(w, r, req, err)
+		return
+	}
+
+	redirect(w, r, req.RedirectURI, url.Values{"code": {code}, "state": {req.State}})
+}
+
+// authorizationError reports the error to the client through its redirect
+// uri, unless the client or the redirect uri cannot be trusted.
+func (s *Server) authorizationError(w http.ResponseWriter, r *http.Request, req models.AuthorizationRequest, err error) {
+	switch {
+	case errors.Is(err, models.ErrOAuthClientNotFound):
+		s.renderError(w, http.StatusBadRequest, "The application is not registered.")
+		return
+	case errors.Is(err, models.ErrInvalidRedirectURI):
+		s.renderError(w, http.StatusBadRequest, "The redirect URI is not registered for the application.")
+		return
+	}
+
+	params := url.Values{"state": {req.State}}
+	switch {
+	case errors.Is(err, models.ErrUnsupportedResponseType):
+		params.Set("error", errCodeUnsupportedResponseType)
+	case errors.Is(err, models.ErrInvalidScope):
+		params.Set("error", errCodeInvalidScope)
+		params.Set("error_description", err.Error())
+	case errors.Is(err, models.ErrInvalidAuthRequest):
+		params.Set("error", errCodeInvalidRequest)
+		params.Set("error_description", err.Error())
+	default:
+		s.logger.Error(err)
+		params.Set("error", errCodeServerError)
+	}
+
+	redirect(w, r, req.RedirectURI, params)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	if err := r.ParseForm(); err != nil {
+		thttp.Respond(w, errorResponse{Error: errCodeInvalidRequest}, http.StatusBadRequest)
+		return
+	}
+
+	tr := models.TokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		ClientID:     r.PostForm.Get("client_id"),
+		ClientSecret: r.PostForm.Get("client_secret"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+	}
+
+	// client_secret_basic: both parts are form encoded (RFC 6749, section 2.3.1).
+	clientID, secret, basic := r.BasicAuth()
+	if basic {
+		var errID, errSecret error
+		tr.ClientID, errID = url.QueryUnescape(clientID)
+		tr.ClientSecret, errSecret = url.QueryUnescape(secret)
+		if errID != nil || errSecret != nil {
+			thttp.Respond(w, errorResponse{Error: errCodeInvalidRequest}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	tokens, err := s.svc.ExchangeCode(r.Context(), tr)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidClient):
+			if basic {
+				w.Header().Set("WWW-Authenticate", `Basic realm="token"`)
+			}
+			thttp.Respond(w, errorResponse{Error: errCodeInvalidClient}, http.StatusUnauthorized)
+		case errors.Is(err, models.ErrInvalidGrant):
+			thttp.Respond(w, errorResponse{Error: errCodeInvalidGrant, Description: err.Error()}, http.StatusBadRequest)
+		case errors.Is(err, models.ErrUnsupportedGrantType):
+			thttp.Respond(w, errorResponse{Error: errCodeUnsupportedGrantType}, http.StatusBadRequest)
+		default:
+			s.logger.Error(err)
+			thttp.Respond(w, errorResponse{Error: errCodeServerError}, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	thttp.Respond(w, tokenResponse{
+		AccessToken: tokens.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(tokens.ExpiresIn.Seconds()),
+		IDToken:     tokens.IDToken,
+		Scope:       strings.Join(tokens.Scopes, " "),
+	}, http.StatusOK)
+}
+
+func (s *Server) userInfo(w http.ResponseWriter, r *http.Request) {
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="userinfo"`)
+		thttp.Respond(w, errorResponse{Error: errCodeInvalidRequest}, http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := s.svc.UserInfo(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidAccessToken) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="userinfo", error="invalid_token"`)
+			thttp.Respond(w, errorResponse{Error: errCodeInvalidToken}, http.StatusUnauthorized)
+			return
+		}
+		s.logger.Error(err)
+		thttp.Respond(w, errorResponse{Error: errCodeServerError}, http.StatusInternalServerError)
+		return
+	}
+
+	thttp.Respond(w, claims, http.StatusOK)
+}
+
+func (s *Server) renderAuthorize(w http.ResponseWriter, statusCode int, page authorizePage) {
+	s.render(w, statusCode, "authorize.html", page)
+}
+
+func (s *Server) renderError(w http.ResponseWriter, statusCode int, message string) {
+	s.render(w, statusCode, "error.html", errorPage{Message: message})
+}
+
+func (s *Server) render(w http.ResponseWriter, statusCode int, name string, data any) {
+	// The pages take credentials: they must not be cached nor framed.
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	w.WriteHeader(statusCode)
+
+	if err := templates.ExecuteTemplate(w, name, data); err != nil {
+		s.logger.Errorw("render template", "template", name, "error", err)
+	}
+}
+
+func authorizationRequest(values url.Values) models.AuthorizationRequest {
+	return models.AuthorizationRequest{
+		ClientID:            values.Get("client_id"),
+		RedirectURI:         values.Get("redirect_uri"),
+		ResponseType:        values.Get("response_type"),
+		Scopes:              strings.Fields(values.Get("scope")),
+		State:               values.Get("state"),
+		Nonce:               values.Get("nonce"),
+		CodeChallenge:       values.Get("code_challenge"),
+		CodeChallengeMethod: values.Get("code_challenge_method"),
+	}
+}
+
+// redirect sends the user back to the client, adding the params to the query
+// of its redirect uri.
+func redirect(w http.ResponseWriter, r *http.Request, redirectURI string, params url.Values) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect uri", http.StatusBadRequest)
+		return
+	}
+
+	query := u.Query()
+	for k, v := range params {
+		if len(v) > 0 && v[0] != "" {
+			query.Set(k, v[0])
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
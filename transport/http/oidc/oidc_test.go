@@ -0,0 +1,382 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/oidc"
+	"github.com/TonyPath/user-mng-grpc-service/internal/service"
+)
+
+const testRedirectURI = "https://app.example.com/callback"
+
+// memOAuthStorage keeps clients and codes in memory.
+type memOAuthStorage struct {
+	mu      sync.Mutex
+	clients map[uuid.UUID]models.OAuthClient
+	codes   map[string]models.AuthorizationCode
+}
+
+func (m *memOAuthStorage) InsertClient(ctx context.Context, client models.OAuthClient) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clients[client.ID] = client
+	return nil
+}
+
+func (m *memOAuthStorage) GetClient(ctx context.Context, clientID uuid.UUID) (models.OAuthClient, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.clients[clientID]
+	if !ok {
+		return models.OAuthClient{}, models.ErrOAuthClientNotFound
+	}
+	return c, nil
+}
+
+func (m *memOAuthStorage) GetClients(ctx context.Context) ([]models.OAuthClient, error) {
+	return nil, nil
+}
+
+func (m *memOAuthStorage) DeleteClient(ctx context.Context, clientID uuid.UUID) error {
+	return nil
+}
+
+func (m *memOAuthStorage) InsertAuthorizationCode(ctx context.Context, code models.AuthorizationCode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.codes[string(code.Hash)] = code
+	return nil
+}
+
+func (m *memOAuthStorage) ConsumeAuthorizationCode(ctx context.Context, hash []byte) (models.AuthorizationCode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.codes[string(hash)]
+	if !ok {
+		return models.AuthorizationCode{}, models.ErrInvalidGrant
+	}
+	delete(m.codes, string(hash))
+	return c, nil
+}
+
+// memUsers serves a single user; the OIDC service needs no other method.
+type memUsers struct {
+	service.UserStorage
+	user models.User
+}
+
+func (m *memUsers) GetUsersByFilter(ctx context.Context, opts models.GetUsersOptions) ([]models.User, error) {
+	if opts.Filter.Email != m.user.Email {
+		return nil, nil
+	}
+	return []models.User{m.user}, nil
+}
+
+func (m *memUsers) GetUserByID(ctx context.Context, userID uuid.UUID) (models.User, error) {
+	if userID != m.user.ID {
+		return models.User{}, models.ErrUserNotFound
+	}
+	return m.user, nil
+}
+
+type testProvider struct {
+	server       *httptest.Server
+	client       models.OAuthClient
+	clientSecret string
+	user         models.User
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	user := models.User{
+		ID:        uuid.New(),
+		Type:      models.UserTypeHuman,
+		Status:    models.UserStatusActive,
+		Email:     "bruce@wayne.com",
+		FirstName: "bruce",
+		LastName:  "wayne",
+		Nickname:  "batman",
+		Password:  hash,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// The issuer is the URL of the test server, known once it started.
+	var handler http.Handler
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	repo := &memOAuthStorage{
+		clients: make(map[uuid.UUID]models.OAuthClient),
+		codes:   make(map[string]models.AuthorizationCode),
+	}
+	svc := service.NewOIDCService(repo, &memUsers{user: user}, oidc.NewSigner(key), service.OIDCConfig{
+		Issuer:   ts.URL,
+		CodeTTL:  time.Minute,
+		TokenTTL: time.Hour,
+	})
+
+	client, secret, err := svc.RegisterClient(context.Background(), models.NewOAuthClient{
+		Name:         "Wayne Enterprises",
+		RedirectURIs: []string{testRedirectURI},
+	})
+	require.NoError(t, err)
+
+	handler = NewServer(zap.NewNop().Sugar(), "", ts.URL, svc).httpServer.Handler
+
+	return &testProvider{
+		server:       ts,
+		client:       client,
+		clientSecret: secret,
+		user:         user,
+	}
+}
+
+// noRedirects stops at redirects to the client, which is not running.
+var noRedirects = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func getJSON(t *testing.T, rawURL string, v any) {
+	resp, err := http.Get(rawURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
+
+func (p *testProvider) authorizationParams(verifier string) url.Values {
+	sum := sha256.Sum256([]byte(verifier))
+	return url.Values{
+		"client_id":             {p.client.ID.String()},
+		"redirect_uri":          {testRedirectURI},
+		"response_type":         {"code"},
+		"scope":                 {"openid profile email"},
+		"state":                 {"af0ifjsldkj"},
+		"nonce":                 {"n-0S6_WzA2Mj"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(sum[:])},
+		"code_challenge_method": {"S256"},
+	}
+}
+
+// submit posts the sign in and consent form and returns the redirect to the
+// client.
+func submit(t *testing.T, endpoint string, params url.Values, password string, action string) *http.Response {
+	form := url.Values{}
+	for k, v := range params {
+		form[k] = v
+	}
+	form.Set("email", "bruce@wayne.com")
+	form.Set("password", password)
+	form.Set("action", action)
+
+	resp, err := noRedirects.PostForm(endpoint, form)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	p := newTestProvider(t)
+
+	var discovery discoveryResponse
+	getJSON(t, p.server.URL+discoveryPath, &discovery)
+	require.Equal(t, p.server.URL, discovery.Issuer)
+	require.Equal(t, []string{"S256"}, discovery.CodeChallengeMethodsSupported)
+
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	params := p.authorizationParams(verifier)
+
+	t.Log("the consent screen names the client")
+	{
+		resp, err := http.Get(discovery.AuthorizationEndpoint + "?" + params.Encode())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Contains(t, string(body), "Sign in to Wayne Enterprises")
+		require.Contains(t, string(body), "your email address")
+		require.Equal(t, "DENY", resp.Header.Get("X-Frame-Options"))
+	}
+
+	t.Log("a wrong password shows the form again")
+	{
+		resp := submit(t, discovery.AuthorizationEndpoint, params, "guess", "allow")
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	resp := submit(t, discovery.AuthorizationEndpoint, params, "password", "allow")
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, testRedirectURI, location.Scheme+"://"+location.Host+location.Path)
+	require.Equal(t, "af0ifjsldkj", location.Query().Get("state"))
+	code := location.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	exchange := func() *http.Response {
+		req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {code},
+			"redirect_uri":  {testRedirectURI},
+			"code_verifier": {verifier},
+		}.Encode()))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(url.QueryEscape(p.client.ID.String()), url.QueryEscape(p.clientSecret))
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	tokenResp := exchange()
+	require.Equal(t, http.StatusOK, tokenResp.StatusCode)
+
+	var tokens tokenResponse
+	require.NoError(t, json.NewDecoder(tokenResp.Body).Decode(&tokens))
+	require.Equal(t, "Bearer", tokens.TokenType)
+	require.Equal(t, int64(3600), tokens.ExpiresIn)
+
+	t.Log("the id token verifies against the published keys")
+	{
+		var keys oidc.JSONWebKeySet
+		getJSON(t, discovery.JWKSURI, &keys)
+
+		var claims struct {
+			Issuer   string `json:"iss"`
+			Subject  string `json:"sub"`
+			Audience string `json:"aud"`
+			Nonce    string `json:"nonce"`
+			Expiry   int64  `json:"exp"`
+			Email    string `json:"email"`
+		}
+		require.NoError(t, oidc.Verify(tokens.IDToken, keys, &claims))
+		require.Equal(t, p.server.URL, claims.Issuer)
+		require.Equal(t, p.user.ID.String(), claims.Subject)
+		require.Equal(t, p.client.ID.String(), claims.Audience)
+		require.Equal(t, "n-0S6_WzA2Mj", claims.Nonce)
+		require.Greater(t, claims.Expiry, time.Now().Unix())
+		require.Equal(t, "bruce@wayne.com", claims.Email)
+	}
+
+	t.Log("the access token gives the claims of the user")
+	{
+		req, err := http.NewRequest(http.MethodGet, discovery.UserInfoEndpoint, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var info map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+		require.Equal(t, p.user.ID.String(), info["sub"])
+		require.Equal(t, "bruce", info["given_name"])
+		require.Equal(t, "batman", info["nickname"])
+		require.Equal(t, "bruce@wayne.com", info["email"])
+	}
+
+	t.Log("a code is exchanged once")
+	{
+		resp := exchange()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var errResp errorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		require.Equal(t, "invalid_grant", errResp.Error)
+	}
+}
+
+func TestProvider_AuthorizationErrors(t *testing.T) {
+	p := newTestProvider(t)
+	endpoint := p.server.URL + authorizePath
+
+	t.Log("denied consent is reported to the client")
+	{
+		resp := submit(t, endpoint, p.authorizationParams("verifier"), "", "deny")
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+
+		location, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "access_denied", location.Query().Get("error"))
+		require.Equal(t, "af0ifjsldkj", location.Query().Get("state"))
+	}
+
+	t.Log("requests without PKCE are reported to the client")
+	{
+		params := p.authorizationParams("verifier")
+		params.Del("code_challenge")
+
+		resp, err := noRedirects.Get(endpoint + "?" + params.Encode())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+
+		location, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "invalid_request", location.Query().Get("error"))
+	}
+
+	t.Log("unregistered redirect uris are not redirected to")
+	{
+		params := p.authorizationParams("verifier")
+		params.Set("redirect_uri", "https://evil.example.com/callback")
+
+		resp, err := noRedirects.Get(endpoint + "?" + params.Encode())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Empty(t, resp.Header.Get("Location"))
+	}
+
+	t.Log("userinfo requires a valid access token")
+	{
+		req, err := http.NewRequest(http.MethodGet, p.server.URL+userInfoPath, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer forged")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		require.Contains(t, resp.Header.Get("WWW-Authenticate"), `error="invalid_token"`)
+	}
+}
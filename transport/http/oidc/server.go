@@ -0,0 +1,115 @@
+package oidc
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	// 3rd party
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	"github.com/TonyPath/user-mng-grpc-service/internal/oidc"
+)
+
+const (
+	httpReadTimeout     = 30 * time.Second
+	httpWriteTimeout    = 60 * time.Second
+	shutdownGracePeriod = 5 * time.Second
+)
+
+// Paths of the endpoints, relative to the issuer.
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	authorizePath = "/authorize"
+	tokenPath     = "/token"
+	userInfoPath  = "/userinfo"
+	jwksPath      = "/jwks"
+)
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+var templates = template.Must(template.ParseFS(templatesFS, "templates/*.html"))
+
+type oidcService interface {
+	ValidateAuthorizationRequest(ctx context.Context, req models.AuthorizationRequest) (models.OAuthClient, error)
+	Authorize(ctx context.Context, req models.AuthorizationRequest, email string, password string) (string, error)
+	ExchangeCode(ctx context.Context, tr models.TokenRequest) (models.Tokens, error)
+	UserInfo(ctx context.Context, accessToken string) (map[string]any, error)
+	JWKS() oidc.JSONWebKeySet
+}
+
+// Server is the public HTTP server of the OpenID Connect provider.
+type Server struct {
+	httpServer http.Server
+	issuer     string
+	svc        oidcService
+	logger     *zap.SugaredLogger
+}
+
+func NewServer(logger *zap.SugaredLogger, addr string, issuer string, svc oidcService) *Server {
+	s := &Server{
+		httpServer: http.Server{
+			Addr:         addr,
+			ReadTimeout:  httpReadTimeout,
+			WriteTimeout: httpWriteTimeout,
+		},
+		issuer: issuer,
+		svc:    svc,
+		logger: logger,
+	}
+
+	s.addRoutes()
+
+	return s
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Infow("startup", "status", "http oidc server started", "issuer", s.issuer)
+		errCh <- s.httpServer.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return s.shutdown()
+	case err := <-errCh:
+		return err
+	}
+}
+
+func (s *Server) addRoutes() {
+	mux := chi.NewRouter()
+
+	mux.Get(discoveryPath, s.discovery)
+	mux.Get(jwksPath, s.jwks)
+	mux.Get(authorizePath, s.authorizeForm)
+	mux.Post(authorizePath, s.authorize)
+	mux.Post(tokenPath, s.token)
+	mux.Get(userInfoPath, s.userInfo)
+	mux.Post(userInfoPath, s.userInfo)
+
+	s.httpServer.Handler = mux
+}
+
+func (s *Server) shutdown() error {
+	tctx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+	defer cancel()
+
+	if err := s.httpServer.Shutdown(tctx); err != nil {
+		closeErr := s.httpServer.Close()
+		if closeErr != nil {
+			return fmt.Errorf("cannot stop server gracefully: %w", err)
+		}
+	}
+	s.logger.Infow("shutdown", "status", "gracefully stopped http oidc server")
+
+	return nil
+}
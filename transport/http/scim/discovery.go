@@ -0,0 +1,265 @@
+package scim
+
+import (
+	"net/http"
+
+	// 3rd party
+	"github.com/go-chi/chi/v5"
+)
+
+type supported struct {
+	Supported bool `json:"supported"`
+}
+
+type filterSupported struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type bulkSupported struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type authenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+type serviceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	Patch                 supported              `json:"patch"`
+	Bulk                  bulkSupported          `json:"bulk"`
+	Filter                filterSupported        `json:"filter"`
+	ChangePassword        supported              `json:"changePassword"`
+	Sort                  supported              `json:"sort"`
+	ETag                  supported              `json:"etag"`
+	AuthenticationSchemes []authenticationScheme `json:"authenticationSchemes"`
+	Meta                  map[string]string      `json:"meta"`
+}
+
+type attribute struct {
+	Name          string      `json:"name"`
+	Type          string      `json:"type"`
+	MultiValued   bool        `json:"multiValued"`
+	Required      bool        `json:"required"`
+	CaseExact     bool        `json:"caseExact"`
+	Mutability    string      `json:"mutability"`
+	Returned      string      `json:"returned"`
+	Uniqueness    string      `json:"uniqueness"`
+	SubAttributes []attribute `json:"subAttributes,omitempty"`
+}
+
+type schema struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Attributes  []attribute       `json:"attributes"`
+	Meta        map[string]string `json:"meta"`
+}
+
+type resourceType struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Endpoint    string            `json:"endpoint"`
+	Description string            `json:"description"`
+	Schema      string            `json:"schema"`
+	Meta        map[string]string `json:"meta"`
+}
+
+// attr returns a single-valued, read-write string attribute.
+func attr(name string) attribute {
+	return attribute{
+		Name:       name,
+		Type:       "string",
+		Mutability: "readWrite",
+		Returned:   "default",
+		Uniqueness: "none",
+	}
+}
+
+func multiValuedAttr(name string, subAttributes ...attribute) attribute {
+	a := attr(name)
+	a.Type = "complex"
+	a.MultiValued = true
+	a.SubAttributes = subAttributes
+	return a
+}
+
+func complexAttr(name string, subAttributes ...attribute) attribute {
+	a := attr(name)
+	a.Type = "complex"
+	a.SubAttributes = subAttributes
+	return a
+}
+
+func primaryAttr() attribute {
+	a := attr("primary")
+	a.Type = "boolean"
+	return a
+}
+
+func userSchema() schema {
+	userName := attr("userName")
+	userName.Required = true
+	userName.Uniqueness = "server"
+
+	displayName := attr("displayName")
+	displayName.Mutability = "readOnly"
+
+	active := attr("active")
+	active.Type = "boolean"
+
+	password := attr("password")
+	password.Mutability = "writeOnly"
+	password.Returned = "never"
+
+	return schema{
+		ID:          schemaUser,
+		Name:        resourceTypeUser,
+		Description: "User Account; the userName is the email of the user",
+		Attributes: []attribute{
+			userName,
+			complexAttr("name", attr("formatted"), attr("givenName"), attr("familyName")),
+			displayName,
+			attr("nickName"),
+			active,
+			multiValuedAttr("emails", attr("value"), attr("type"), primaryAttr()),
+			multiValuedAttr("addresses", attr("country"), attr("type"), primaryAttr()),
+			password,
+		},
+	}
+}
+
+func groupSchema() schema {
+	displayName := attr("displayName")
+	displayName.Required = true
+	displayName.Uniqueness = "server"
+
+	value := attr("value")
+	value.Mutability = "immutable"
+
+	ref := attr("$ref")
+	ref.Type = "reference"
+	ref.Mutability = "immutable"
+
+	memberType := attr("type")
+	memberType.Mutability = "immutable"
+
+	return schema{
+		ID:          schemaGroup,
+		Name:        resourceTypeGroup,
+		Description: "Group",
+		Attributes: []attribute{
+			displayName,
+			multiValuedAttr("members", value, ref, memberType),
+		},
+	}
+}
+
+func (s *Server) serviceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	respond(w, serviceProviderConfig{
+		Schemas:        []string{schemaServiceProviderConfig},
+		Patch:          supported{Supported: true},
+		Filter:         filterSupported{Supported: true, MaxResults: maxResults},
+		ChangePassword: supported{Supported: true},
+		AuthenticationSchemes: []authenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Bearer Token",
+			Description: "Authentication with the API key of a service account, sent as a bearer token",
+			Primary:     true,
+		}},
+		Meta: map[string]string{
+			"resourceType": "ServiceProviderConfig",
+			"location":     s.baseURL + serviceProviderConfigPath,
+		},
+	}, http.StatusOK)
+}
+
+func (s *Server) schemaList() []schema {
+	list := []schema{userSchema(), groupSchema()}
+	for i := range list {
+		list[i].Schemas = []string{schemaSchema}
+		list[i].Meta = map[string]string{
+			"resourceType": "Schema",
+			"location":     s.baseURL + schemasPath + "/" + list[i].ID,
+		}
+	}
+	return list
+}
+
+func (s *Server) schemas(w http.ResponseWriter, r *http.Request) {
+	list := s.schemaList()
+
+	resp := listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: len(list),
+		StartIndex:   1,
+		ItemsPerPage: len(list),
+	}
+	for _, sch := range list {
+		resp.Resources = append(resp.Resources, sch)
+	}
+
+	respond(w, resp, http.StatusOK)
+}
+
+func (s *Server) schema(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	for _, sch := range s.schemaList() {
+		if sch.ID == id {
+			respond(w, sch, http.StatusOK)
+			return
+		}
+	}
+
+	respondError(w, http.StatusNotFound, "", "schema not found")
+}
+
+func (s *Server) resourceTypeList() []resourceType {
+	list := []resourceType{
+		{ID: resourceTypeUser, Name: resourceTypeUser, Endpoint: usersPath, Description: "User Account", Schema: schemaUser},
+		{ID: resourceTypeGroup, Name: resourceTypeGroup, Endpoint: groupsPath, Description: "Group", Schema: schemaGroup},
+	}
+	for i := range list {
+		list[i].Schemas = []string{schemaResourceType}
+		list[i].Meta = map[string]string{
+			"resourceType": "ResourceType",
+			"location":     s.baseURL + resourceTypesPath + "/" + list[i].ID,
+		}
+	}
+	return list
+}
+
+func (s *Server) resourceTypes(w http.ResponseWriter, r *http.Request) {
+	list := s.resourceTypeList()
+
+	resp := listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: len(list),
+		StartIndex:   1,
+		ItemsPerPage: len(list),
+	}
+	for _, rt := range list {
+		resp.Resources = append(resp.Resources, rt)
+	}
+
+	respond(w, resp, http.StatusOK)
+}
+
+func (s *Server) resourceType(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	for _, rt := range s.resourceTypeList() {
+		if rt.ID == id {
+			respond(w, rt, http.StatusOK)
+			return
+		}
+	}
+
+	respondError(w, http.StatusNotFound, "", "resource type not found")
+}
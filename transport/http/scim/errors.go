@@ -0,0 +1,84 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+// Detail error types of RFC 7644, section 3.12.
+const (
+	scimTypeInvalidFilter = "invalidFilter"
+	scimTypeInvalidSyntax = "invalidSyntax"
+	scimTypeInvalidPath   = "invalidPath"
+	scimTypeInvalidValue  = "invalidValue"
+	scimTypeNoTarget      = "noTarget"
+	scimTypeMutability    = "mutability"
+	scimTypeUniqueness    = "uniqueness"
+)
+
+const contentType = "application/scim+json"
+
+var (
+	errInvalidSyntax = errors.New("invalid syntax")
+	errInvalidValue  = errors.New("invalid value")
+	errMutability    = errors.New("attribute cannot be changed")
+)
+
+type errorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+func respond(w http.ResponseWriter, v any, statusCode int) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	b, _ := json.Marshal(v)
+	_, _ = w.Write(b)
+}
+
+func respondError(w http.ResponseWriter, statusCode int, scimType string, detail string) {
+	respond(w, errorResponse{
+		Schemas:  []string{schemaError},
+		Status:   strconv.Itoa(statusCode),
+		ScimType: scimType,
+		Detail:   detail,
+	}, statusCode)
+}
+
+// handleError responds with the SCIM error matching err.
+func (s *Server) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrUserNotFound):
+		respondError(w, http.StatusNotFound, "", "user not found")
+	case errors.Is(err, models.ErrGroupNotFound):
+		respondError(w, http.StatusNotFound, "", "group not found")
+	case errors.Is(err, models.ErrEmailTaken):
+		respondError(w, http.StatusConflict, scimTypeUniqueness, "userName is already taken")
+	case errors.Is(err, models.ErrGroupNameTaken):
+		respondError(w, http.StatusConflict, scimTypeUniqueness, "displayName is already taken")
+	case errors.Is(err, models.ErrInvalidStatusTransition):
+		respondError(w, http.StatusBadRequest, scimTypeInvalidValue, "active cannot be changed")
+	case errors.Is(err, errInvalidSyntax):
+		respondError(w, http.StatusBadRequest, scimTypeInvalidSyntax, err.Error())
+	case errors.Is(err, errInvalidFilter), errors.Is(err, models.ErrInvalidFilter):
+		respondError(w, http.StatusBadRequest, scimTypeInvalidFilter, err.Error())
+	case errors.Is(err, errInvalidPath):
+		respondError(w, http.StatusBadRequest, scimTypeInvalidPath, err.Error())
+	case errors.Is(err, errNoTarget):
+		respondError(w, http.StatusBadRequest, scimTypeNoTarget, err.Error())
+	case errors.Is(err, errInvalidOp), errors.Is(err, errInvalidValue):
+		respondError(w, http.StatusBadRequest, scimTypeInvalidValue, err.Error())
+	case errors.Is(err, errMutability):
+		respondError(w, http.StatusBadRequest, scimTypeMutability, err.Error())
+	default:
+		s.logger.Error(err)
+		respondError(w, http.StatusInternalServerError, "", "internal server error")
+	}
+}
@@ -0,0 +1,514 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// errInvalidFilter is returned for filters that do not follow the grammar of
+// RFC 7644, section 3.4.2.2.
+var errInvalidFilter = errors.New("invalid filter")
+
+// Comparison operators of the filter language.
+const (
+	opEqual          = "eq"
+	opNotEqual       = "ne"
+	opContains       = "co"
+	opStartsWith     = "sw"
+	opEndsWith       = "ew"
+	opGreater        = "gt"
+	opGreaterOrEqual = "ge"
+	opLess           = "lt"
+	opLessOrEqual    = "le"
+	opPresent        = "pr"
+)
+
+var compareOps = map[string]bool{
+	opEqual:          true,
+	opNotEqual:       true,
+	opContains:       true,
+	opStartsWith:     true,
+	opEndsWith:       true,
+	opGreater:        true,
+	opGreaterOrEqual: true,
+	opLess:           true,
+	opLessOrEqual:    true,
+}
+
+var attrNameRegex = regexp.MustCompile(`^[A-Za-z$][A-Za-z0-9_$-]*$`)
+
+// filter is a parsed filter expression, evaluated against the JSON
+// representation of a resource.
+type filter interface {
+	match(res map[string]any) bool
+}
+
+type logicalFilter struct {
+	and         bool
+	left, right filter
+}
+
+func (f logicalFilter) match(res map[string]any) bool {
+	if f.and {
+		return f.left.match(res) && f.right.match(res)
+	}
+	return f.left.match(res) || f.right.match(res)
+}
+
+type notFilter struct {
+	filter filter
+}
+
+func (f notFilter) match(res map[string]any) bool {
+	return !f.filter.match(res)
+}
+
+// attrFilter compares the values of an attribute; op is opPresent or one of
+// compareOps.
+type attrFilter struct {
+	path  []string
+	op    string
+	value any
+}
+
+func (f attrFilter) match(res map[string]any) bool {
+	values := attrValues(res, f.path)
+
+	switch {
+	case f.op == opPresent:
+		for _, v := range values {
+			if s, ok := v.(string); !ok || s != "" {
+				return true
+			}
+		}
+		return false
+	case f.value == nil:
+		// "eq null" matches unassigned attributes.
+		return (f.op == opEqual) == (len(values) == 0)
+	}
+
+	for _, v := range values {
+		if compare(v, f.op, f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// valuePathFilter matches resources with an element of the multi-valued
+// attribute that matches the filter, e.g. emails[type eq "work"].
+type valuePathFilter struct {
+	attr   string
+	filter filter
+}
+
+func (f valuePathFilter) match(res map[string]any) bool {
+	for _, elem := range elements(lookup(res, f.attr)) {
+		if m, ok := elem.(map[string]any); ok && f.filter.match(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFilter parses the filter expression. An empty expression yields a nil
+// filter.
+func parseFilter(expr string) (filter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	p := &filterParser{tokens: tokenize(expr)}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %q", errInvalidFilter, tok.text)
+	}
+
+	return f, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenInvalid
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expr string) []token {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")"})
+			i++
+		case c == '[':
+			tokens = append(tokens, token{kind: tokenLBracket, text: "["})
+			i++
+		case c == ']':
+			tokens = append(tokens, token{kind: tokenRBracket, text: "]"})
+			i++
+		case c == '"':
+			// Strings are JSON strings: find the closing quote, skipping
+			// escaped characters.
+			j := i + 1
+			for j < len(expr) && expr[j] != '"' {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expr) {
+				return append(tokens, token{kind: tokenInvalid, text: expr[i:]})
+			}
+			var s string
+			if err := json.Unmarshal([]byte(expr[i:j+1]), &s); err != nil {
+				return append(tokens, token{kind: tokenInvalid, text: expr[i : j+1]})
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s})
+			i = j + 1
+		default:
+			j := i
+			for j < len(expr) && !strings.ContainsRune(" \t\n\r()[]\"", rune(expr[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: expr[i:j]})
+			i = j
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF})
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *filterParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *filterParser) expect(kind tokenKind, text string) error {
+	if tok := p.next(); tok.kind != kind {
+		return fmt.Errorf("%w: expected %q", errInvalidFilter, text)
+	}
+	return nil
+}
+
+func (p *filterParser) keyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, word)
+}
+
+func (p *filterParser) parseOr() (filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalFilter{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalFilter{and: true, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filter, error) {
+	not := p.keyword("not") && p.tokens[p.pos+1].kind == tokenLParen
+	if not {
+		p.next()
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		if not {
+			return notFilter{filter: f}, nil
+		}
+		return f, nil
+	}
+
+	return p.parseAttrExpr()
+}
+
+func (p *filterParser) parseAttrExpr() (filter, error) {
+	tok := p.next()
+	if tok.kind != tokenWord {
+		return nil, fmt.Errorf("%w: expected attribute, got %q", errInvalidFilter, tok.text)
+	}
+
+	path, err := parseAttrPath(tok.text)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind == tokenLBracket {
+		if len(path) != 1 {
+			return nil, fmt.Errorf("%w: unexpected \"[\" after %q", errInvalidFilter, tok.text)
+		}
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRBracket, "]"); err != nil {
+			return nil, err
+		}
+		return valuePathFilter{attr: path[0], filter: f}, nil
+	}
+
+	opTok := p.next()
+	op := strings.ToLower(opTok.text)
+	if opTok.kind != tokenWord || (op != opPresent && !compareOps[op]) {
+		return nil, fmt.Errorf("%w: unknown operator %q", errInvalidFilter, opTok.text)
+	}
+
+	if op == opPresent {
+		return attrFilter{path: path, op: op}, nil
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return attrFilter{path: path, op: op, value: value}, nil
+}
+
+func (p *filterParser) parseValue() (any, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString:
+		return tok.text, nil
+	case tokenWord:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		if n, err := strconv.ParseFloat(tok.text, 64); err == nil {
+			return n, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: invalid value %q", errInvalidFilter, tok.text)
+}
+
+// parseAttrPath splits the attribute path into the lower-cased attribute and
+// optional sub-attribute. A schema URN prefix is dropped, as all attributes
+// are unique within the schemas of a resource type.
+func parseAttrPath(text string) ([]string, error) {
+	if strings.HasPrefix(strings.ToLower(text), "urn:") {
+		text = text[strings.LastIndex(text, ":")+1:]
+	}
+
+	path := strings.Split(strings.ToLower(text), ".")
+	if len(path) > 2 {
+		return nil, fmt.Errorf("%w: invalid attribute %q", errInvalidFilter, text)
+	}
+	for _, name := range path {
+		if !attrNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("%w: invalid attribute %q", errInvalidFilter, text)
+		}
+	}
+
+	return path, nil
+}
+
+// lookup returns the value of the attribute; attribute names are case
+// insensitive.
+func lookup(m map[string]any, name string) any {
+	if v, ok := m[name]; ok {
+		return v
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// elements returns the elements of a multi-valued attribute, or the value
+// itself for single-valued ones.
+func elements(v any) []any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	default:
+		return []any{v}
+	}
+}
+
+// attrValues returns the values of the attribute path. Comparing a complex
+// multi-valued attribute without a sub-attribute compares its "value"
+// sub-attribute.
+func attrValues(res map[string]any, path []string) []any {
+	var values []any
+	for _, elem := range elements(lookup(res, path[0])) {
+		m, complex := elem.(map[string]any)
+		switch {
+		case len(path) == 2 && complex:
+			values = append(values, elements(lookup(m, path[1]))...)
+		case len(path) == 1 && complex:
+			values = append(values, elements(lookup(m, "value"))...)
+		case len(path) == 1:
+			values = append(values, elem)
+		}
+	}
+	return values
+}
+
+// compare reports whether the actual value compares to the expected one.
+// Strings are compared case insensitively; values of different types never
+// match.
+func compare(actual any, op string, expected any) bool {
+	switch expected := expected.(type) {
+	case string:
+		a, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		a, b := strings.ToLower(a), strings.ToLower(expected)
+		switch op {
+		case opContains:
+			return strings.Contains(a, b)
+		case opStartsWith:
+			return strings.HasPrefix(a, b)
+		case opEndsWith:
+			return strings.HasSuffix(a, b)
+		}
+		return ordered(strings.Compare(a, b), op)
+	case float64:
+		a, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case a < expected:
+			return ordered(-1, op)
+		case a > expected:
+			return ordered(1, op)
+		}
+		return ordered(0, op)
+	case bool:
+		a, ok := actual.(bool)
+		if !ok {
+			return false
+		}
+		switch op {
+		case opEqual:
+			return a == expected
+		case opNotEqual:
+			return a != expected
+		}
+	}
+	return false
+}
+
+func ordered(cmp int, op string) bool {
+	switch op {
+	case opEqual:
+		return cmp == 0
+	case opNotEqual:
+		return cmp != 0
+	case opGreater:
+		return cmp > 0
+	case opGreaterOrEqual:
+		return cmp >= 0
+	case opLess:
+		return cmp < 0
+	case opLessOrEqual:
+		return cmp <= 0
+	}
+	return false
+}
+
+// equalities returns the attributes compared for equality with strings by
+// the top-level conjunctions of the filter; these are used to narrow down the
+// resources fetched before the filter is evaluated.
+func equalities(f filter) map[string]string {
+	eq := map[string]string{}
+	var walk func(f filter)
+	walk = func(f filter) {
+		switch f := f.(type) {
+		case logicalFilter:
+			if f.and {
+				walk(f.left)
+				walk(f.right)
+			}
+		case attrFilter:
+			if s, ok := f.value.(string); ok && f.op == opEqual {
+				eq[strings.Join(f.path, ".")] = s
+			}
+		}
+	}
+	walk(f)
+	return eq
+}
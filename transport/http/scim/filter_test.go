@@ -0,0 +1,146 @@
+package scim
+
+import (
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+)
+
+func testUserResource() map[string]any {
+	return map[string]any{
+		"schemas":  []any{schemaUser},
+		"id":       "2819c223-7f76-453a-919d-413861904646",
+		"userName": "Bjensen@example.com",
+		"name": map[string]any{
+			"givenName":  "Barbara",
+			"familyName": "Jensen",
+		},
+		"nickName": "Babs",
+		"active":   true,
+		"emails": []any{
+			map[string]any{"value": "bjensen@example.com", "type": "work", "primary": true},
+			map[string]any{"value": "babs@jensen.org", "type": "home"},
+		},
+		"meta": map[string]any{
+			"created":      "2011-08-01T18:29:49Z",
+			"lastModified": "2011-08-01T20:31:02Z",
+		},
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tt := map[string]struct {
+		filter string
+		match  bool
+	}{
+		"userName eq, case insensitive": {
+			filter: `userName eq "bjensen@example.com"`,
+			match:  true,
+		},
+		"userName eq with schema urn": {
+			filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "bjensen@example.com"`,
+			match:  true,
+		},
+		"userName ne": {
+			filter: `userName ne "bjensen@example.com"`,
+			match:  false,
+		},
+		"sub-attribute sw": {
+			filter: `name.familyName sw "jen"`,
+			match:  true,
+		},
+		"multi-valued value co": {
+			filter: `emails co "jensen.org"`,
+			match:  true,
+		},
+		"multi-valued sub-attribute ew": {
+			filter: `emails.value ew "@example.org"`,
+			match:  false,
+		},
+		"value path": {
+			filter: `emails[type eq "work"]`,
+			match:  true,
+		},
+		"value path with and": {
+			filter: `emails[type eq "home" and value co "example.com"]`,
+			match:  false,
+		},
+		"boolean": {
+			filter: `active eq true`,
+			match:  true,
+		},
+		"present": {
+			filter: `nickName pr`,
+			match:  true,
+		},
+		"not present": {
+			filter: `title pr`,
+			match:  false,
+		},
+		"eq null": {
+			filter: `title eq null`,
+			match:  true,
+		},
+		"dates": {
+			filter: `meta.lastModified gt "2011-05-13T04:42:34Z" and meta.created le "2011-08-01T18:29:49Z"`,
+			match:  true,
+		},
+		"and binds tighter than or": {
+			filter: `nickName eq "x" and active eq true or userName sw "bjensen"`,
+			match:  true,
+		},
+		"parentheses": {
+			filter: `nickName eq "x" and (active eq true or userName sw "bjensen")`,
+			match:  false,
+		},
+		"not": {
+			filter: `not (nickName eq "babs")`,
+			match:  false,
+		},
+		"type mismatch": {
+			filter: `active eq "true"`,
+			match:  false,
+		},
+	}
+
+	for name, tc := range tt {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			f, err := parseFilter(tc.filter)
+			require.NoError(t, err)
+			require.Equal(t, tc.match, f.match(testUserResource()))
+		})
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		`userName`,
+		`userName eq`,
+		`userName like "x"`,
+		`userName eq "x`,
+		`userName eq bjensen`,
+		`(userName eq "x"`,
+		`emails[type eq "work"`,
+		`name.familyName.first eq "x"`,
+		`userName eq "x" or`,
+		`userName eq "x" userName`,
+	} {
+		_, err := parseFilter(expr)
+		require.ErrorIs(t, err, errInvalidFilter, expr)
+	}
+
+	f, err := parseFilter("  ")
+	require.NoError(t, err)
+	require.Nil(t, f)
+}
+
+func TestEqualities(t *testing.T) {
+	f, err := parseFilter(`userName eq "a@mail.com" and (nickName eq "a" or nickName eq "b") and addresses.country eq "GR"`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"username":          "a@mail.com",
+		"addresses.country": "GR",
+	}, equalities(f))
+}
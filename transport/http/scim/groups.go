@@ -0,0 +1,329 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	// 3rd party
+	"github.com/google/uuid"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	f, err := parseFilter(q.Get("filter"))
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	win, err := parseWindow(q)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	// Identity providers exclude the members when looking groups up, which
+	// saves fetching them.
+	withMembers := !excluded(q, "members")
+
+	opts := models.GetGroupsOptions{
+		PageSize: scanPageSize,
+		Name:     equalities(f)["displayname"],
+	}
+
+	resp, err := list(f, win, func(pageNumber uint64) ([]any, error) {
+		opts.PageNumber = pageNumber
+		groups, err := s.groups.GetGroups(r.Context(), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]any, 0, len(groups))
+		for _, g := range groups {
+			var members []models.GroupMember
+			if withMembers {
+				if members, err = s.members(r.Context(), g.ID); err != nil {
+					return nil, err
+				}
+			}
+			resources = append(resources, s.toGroup(g, members))
+		}
+		return resources, nil
+	})
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	respond(w, resp, http.StatusOK)
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request) {
+	groupID, err := resourceID(r, models.ErrGroupNotFound)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	g, err := s.groups.GetGroup(r.Context(), groupID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	var members []models.GroupMember
+	if !excluded(r.URL.Query(), "members") {
+		if members, err = s.members(r.Context(), groupID); err != nil {
+			s.handleError(w, err)
+			return
+		}
+	}
+
+	respond(w, s.toGroup(g, members), http.StatusOK)
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
+	var req group
+	if err := decode(w, r, &req); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if req.DisplayName == "" {
+		s.handleError(w, fmt.Errorf("%w: displayName is required", errInvalidValue))
+		return
+	}
+
+	userIDs, err := parseIDs(req.Members)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	groupID, err := s.groups.CreateGroup(r.Context(), req.DisplayName)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := s.addMembers(r.Context(), groupID, userIDs); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	g, err := s.groups.GetGroup(r.Context(), groupID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	members, err := s.members(r.Context(), groupID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", s.location(groupsPath, groupID.String()))
+	respond(w, s.toGroup(g, members), http.StatusCreated)
+}
+
+func (s *Server) replaceGroup(w http.ResponseWriter, r *http.Request) {
+	groupID, err := resourceID(r, models.ErrGroupNotFound)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	var req group
+	if err := decode(w, r, &req); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	current, err := s.groups.GetGroup(r.Context(), groupID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	members, err := s.members(r.Context(), groupID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	s.updateGroup(r.Context(), w, current, members, req)
+}
+
+func (s *Server) patchGroup(w http.ResponseWriter, r *http.Request) {
+	groupID, err := resourceID(r, models.ErrGroupNotFound)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	var req patchRequest
+	if err := decode(w, r, &req); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	current, err := s.groups.GetGroup(r.Context(), groupID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	members, err := s.members(r.Context(), groupID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	res, err := toMap(s.toGroup(current, members))
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := applyPatch(res, req.Operations); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	var patched group
+	if err := fromMap(res, &patched); err != nil {
+		s.handleError(w, fmt.Errorf("%w: %v", errInvalidValue, err))
+		return
+	}
+
+	s.updateGroup(r.Context(), w, current, members, patched)
+}
+
+// updateGroup renames the group and adds or removes members so that it
+// matches the desired representation, then responds with the result.
+func (s *Server) updateGroup(ctx context.Context, w http.ResponseWriter, current models.Group, members []models.GroupMember, desired group) {
+	userIDs, err := parseIDs(desired.Members)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if desired.DisplayName != current.Name {
+		if desired.DisplayName == "" {
+			s.handleError(w, fmt.Errorf("%w: displayName cannot be removed", errMutability))
+			return
+		}
+		if err := s.groups.RenameGroup(ctx, current.ID, desired.DisplayName); err != nil {
+			s.handleError(w, err)
+			return
+		}
+	}
+
+	keep := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		keep[id] = true
+	}
+
+	var added []uuid.UUID
+	existing := make(map[uuid.UUID]bool, len(members))
+	for _, m := range members {
+		existing[m.UserID] = true
+		if !keep[m.UserID] {
+			if err := s.groups.RemoveMember(ctx, current.ID, m.UserID); err != nil && !errors.Is(err, models.ErrNotGroupMember) {
+				s.handleError(w, err)
+				return
+			}
+		}
+	}
+	for _, id := range userIDs {
+		if !existing[id] {
+			added = append(added, id)
+		}
+	}
+
+	if err := s.addMembers(ctx, current.ID, added); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	g, err := s.groups.GetGroup(ctx, current.ID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	members, err = s.members(ctx, current.ID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	respond(w, s.toGroup(g, members), http.StatusOK)
+}
+
+func (s *Server) addMembers(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error {
+	for _, userID := range userIDs {
+		err := s.groups.AddMember(ctx, groupID, userID)
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			return fmt.Errorf("%w: member %s does not exist", errInvalidValue, userID)
+		case err != nil && !errors.Is(err, models.ErrAlreadyGroupMember):
+			return err
+		}
+	}
+	return nil
+}
+
+// members returns every member of the group.
+func (s *Server) members(ctx context.Context, groupID uuid.UUID) ([]models.GroupMember, error) {
+	var members []models.GroupMember
+	for pageNumber := uint64(1); ; pageNumber++ {
+		page, err := s.groups.GetMembers(ctx, groupID, models.GetGroupMembersOptions{
+			PageNumber: pageNumber,
+			PageSize:   scanPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, page...)
+		if len(page) < scanPageSize {
+			return members, nil
+		}
+	}
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	groupID, err := resourceID(r, models.ErrGroupNotFound)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := s.groups.DeleteGroup(r.Context(), groupID); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseIDs parses the user ids of the members of a group.
+func parseIDs(members []member) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		id, err := uuid.Parse(m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: member %q is not a user id", errInvalidValue, m.Value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
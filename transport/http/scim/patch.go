@@ -0,0 +1,333 @@
+package scim
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Patch operations of RFC 7644, section 3.5.2.
+const (
+	patchOpAdd     = "add"
+	patchOpReplace = "replace"
+	patchOpRemove  = "remove"
+)
+
+var (
+	errInvalidPath = errors.New("invalid path")
+	errNoTarget    = errors.New("no target")
+	errInvalidOp   = errors.New("invalid operation")
+)
+
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// patchPath is the target of an operation: an attribute and optional
+// sub-attribute, with an optional filter selecting the elements of a
+// multi-valued attribute, e.g. emails[type eq "work"].value.
+type patchPath struct {
+	path   []string
+	filter filter
+}
+
+func parsePatchPath(text string) (patchPath, error) {
+	attr, sub := text, ""
+	var f filter
+
+	if i := strings.IndexByte(text, '['); i >= 0 {
+		j := strings.LastIndexByte(text, ']')
+		if j < i {
+			return patchPath{}, fmt.Errorf("%w: %q", errInvalidPath, text)
+		}
+
+		var err error
+		if f, err = parseFilter(text[i+1 : j]); err != nil || f == nil {
+			return patchPath{}, fmt.Errorf("%w: %q", errInvalidPath, text)
+		}
+
+		attr, sub = text[:i], text[j+1:]
+		if sub != "" {
+			if !strings.HasPrefix(sub, ".") || !attrNameRegex.MatchString(sub[1:]) {
+				return patchPath{}, fmt.Errorf("%w: %q", errInvalidPath, text)
+			}
+			sub = strings.ToLower(sub[1:])
+		}
+	}
+
+	path, err := parseAttrPath(attr)
+	if err != nil {
+		return patchPath{}, fmt.Errorf("%w: %q", errInvalidPath, text)
+	}
+
+	if f != nil {
+		if len(path) != 1 {
+			return patchPath{}, fmt.Errorf("%w: %q", errInvalidPath, text)
+		}
+		if sub != "" {
+			path = append(path, sub)
+		}
+	}
+
+	return patchPath{path: path, filter: f}, nil
+}
+
+// applyPatch applies the operations to the JSON representation of a
+// resource.
+func applyPatch(res map[string]any, ops []patchOperation) error {
+	for _, op := range ops {
+		name := strings.ToLower(op.Op)
+
+		switch name {
+		case patchOpAdd, patchOpReplace:
+			if op.Path == "" {
+				values, ok := op.Value.(map[string]any)
+				if !ok {
+					return fmt.Errorf("%w: %s without path requires an object value", errInvalidOp, op.Op)
+				}
+				for k, v := range values {
+					p, err := parsePatchPath(k)
+					if err != nil {
+						return err
+					}
+					if err := setValue(res, p, v, name == patchOpAdd); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			p, err := parsePatchPath(op.Path)
+			if err != nil {
+				return err
+			}
+			if err := setValue(res, p, op.Value, name == patchOpAdd); err != nil {
+				return err
+			}
+		case patchOpRemove:
+			if op.Path == "" {
+				return fmt.Errorf("%w: remove requires a path", errNoTarget)
+			}
+			p, err := parsePatchPath(op.Path)
+			if err != nil {
+				return err
+			}
+			removeValue(res, p, op.Value)
+		default:
+			return fmt.Errorf("%w: %q", errInvalidOp, op.Op)
+		}
+	}
+
+	return nil
+}
+
+func setValue(res map[string]any, p patchPath, value any, add bool) error {
+	if p.filter != nil {
+		return setFiltered(res, p, value, add)
+	}
+
+	if len(p.path) == 1 {
+		setKey(res, p.path[0], merge(lookup(res, p.path[0]), value, add))
+		return nil
+	}
+
+	switch parent := lookup(res, p.path[0]).(type) {
+	case map[string]any:
+		setKey(parent, p.path[1], merge(lookup(parent, p.path[1]), value, add))
+	case []any:
+		for _, elem := range parent {
+			if m, ok := elem.(map[string]any); ok {
+				setKey(m, p.path[1], value)
+			}
+		}
+	default:
+		setKey(res, p.path[0], map[string]any{p.path[1]: value})
+	}
+
+	return nil
+}
+
+// setFiltered sets the value on the elements matching the filter. When no
+// element matches a filter on a single attribute, e.g. [type eq "work"], the
+// element is added, as identity providers commonly rely on that to set the
+// first value of a type.
+func setFiltered(res map[string]any, p patchPath, value any, add bool) error {
+	elems, _ := lookup(res, p.path[0]).([]any)
+
+	matched := false
+	for i, elem := range elems {
+		m, ok := elem.(map[string]any)
+		if !ok || !p.filter.match(m) {
+			continue
+		}
+		matched = true
+
+		switch {
+		case len(p.path) == 2:
+			setKey(m, p.path[1], value)
+		case add:
+			elems[i] = merge(m, value, true)
+		default:
+			elems[i] = value
+		}
+	}
+
+	if matched {
+		return nil
+	}
+
+	f, ok := p.filter.(attrFilter)
+	if !ok || f.op != opEqual || len(f.path) != 1 {
+		return fmt.Errorf("%w: no value matches %q", errNoTarget, p.path[0])
+	}
+
+	elem := map[string]any{f.path[0]: f.value}
+	if len(p.path) == 2 {
+		elem[p.path[1]] = value
+	} else if m, ok := value.(map[string]any); ok {
+		for k, v := range m {
+			setKey(elem, k, v)
+		}
+	}
+
+	setKey(res, p.path[0], append(elems, elem))
+	return nil
+}
+
+func removeValue(res map[string]any, p patchPath, value any) {
+	current := lookup(res, p.path[0])
+
+	if p.filter == nil {
+		if len(p.path) == 2 {
+			for _, elem := range elements(current) {
+				if m, ok := elem.(map[string]any); ok {
+					deleteKey(m, p.path[1])
+				}
+			}
+			return
+		}
+
+		// Some identity providers remove values of a multi-valued attribute
+		// by listing them, rather than with a filter.
+		elems, multi := current.([]any)
+		values, listed := value.([]any)
+		if multi && listed {
+			setKey(res, p.path[0], without(elems, func(elem any) bool {
+				for _, v := range values {
+					if sameValue(elem, v) {
+						return true
+					}
+				}
+				return false
+			}))
+			return
+		}
+
+		deleteKey(res, p.path[0])
+		return
+	}
+
+	elems, _ := current.([]any)
+	if len(p.path) == 2 {
+		for _, elem := range elems {
+			if m, ok := elem.(map[string]any); ok && p.filter.match(m) {
+				deleteKey(m, p.path[1])
+			}
+		}
+		return
+	}
+
+	setKey(res, p.path[0], without(elems, func(elem any) bool {
+		m, ok := elem.(map[string]any)
+		return ok && p.filter.match(m)
+	}))
+}
+
+// merge returns the value to set on an attribute. Adding to a multi-valued
+// attribute appends the new values, and adding to a complex attribute sets
+// its sub-attributes; anything else replaces the current value.
+func merge(current any, value any, add bool) any {
+	if !add {
+		return value
+	}
+
+	switch current := current.(type) {
+	case []any:
+		merged := current
+		for _, v := range elements(value) {
+			dup := false
+			for _, elem := range current {
+				if sameValue(elem, v) {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				merged = append(merged, v)
+			}
+		}
+		return merged
+	case map[string]any:
+		values, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		for k, v := range values {
+			setKey(current, k, v)
+		}
+		return current
+	}
+
+	return value
+}
+
+// sameValue reports whether two values of a multi-valued attribute are the
+// same, comparing the "value" sub-attribute of complex values.
+func sameValue(a, b any) bool {
+	ma, okA := a.(map[string]any)
+	mb, okB := b.(map[string]any)
+	if okA && okB {
+		va, vb := lookup(ma, "value"), lookup(mb, "value")
+		if va != nil || vb != nil {
+			return reflect.DeepEqual(va, vb)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func without(elems []any, drop func(any) bool) []any {
+	kept := []any{}
+	for _, elem := range elems {
+		if !drop(elem) {
+			kept = append(kept, elem)
+		}
+	}
+	return kept
+}
+
+// setKey sets the attribute, keeping the case of an existing key.
+func setKey(m map[string]any, name string, value any) {
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			m[k] = value
+			return
+		}
+	}
+	m[name] = value
+}
+
+func deleteKey(m map[string]any, name string) {
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			delete(m, k)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package scim
+
+import (
+	"testing"
+
+	// 3rd party
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatch(t *testing.T) {
+	tt := map[string]struct {
+		ops    []patchOperation
+		assert func(t *testing.T, res map[string]any)
+	}{
+		"replace attribute": {
+			ops: []patchOperation{{Op: "replace", Path: "nickName", Value: "Barb"}},
+			assert: func(t *testing.T, res map[string]any) {
+				require.Equal(t, "Barb", res["nickName"])
+			},
+		},
+		"replace sub-attribute, op is case insensitive": {
+			ops: []patchOperation{{Op: "Replace", Path: "name.givenName", Value: "Babs"}},
+			assert: func(t *testing.T, res map[string]any) {
+				require.Equal(t, map[string]any{"givenName": "Babs", "familyName": "Jensen"}, res["name"])
+			},
+		},
+		"replace without path": {
+			ops: []patchOperation{{Op: "replace", Value: map[string]any{"active": false, "name.familyName": "Doe"}}},
+			assert: func(t *testing.T, res map[string]any) {
+				require.Equal(t, false, res["active"])
+				require.Equal(t, "Doe", res["name"].(map[string]any)["familyName"])
+			},
+		},
+		"replace filtered sub-attribute": {
+			ops: []patchOperation{{Op: "replace", Path: `emails[type eq "work"].value`, Value: "barbara@example.com"}},
+			assert: func(t *testing.T, res map[string]any) {
+				emails := res["emails"].([]any)
+				require.Equal(t, "barbara@example.com", emails[0].(map[string]any)["value"])
+				require.Equal(t, "babs@jensen.org", emails[1].(map[string]any)["value"])
+			},
+		},
+		"add filtered sub-attribute creates the value": {
+			ops: []patchOperation{{Op: "add", Path: `addresses[type eq "work"].country`, Value: "GR"}},
+			assert: func(t *testing.T, res map[string]any) {
+				require.Equal(t, []any{map[string]any{"type": "work", "country": "GR"}}, res["addresses"])
+			},
+		},
+		"add to multi-valued appends": {
+			ops: []patchOperation{{Op: "add", Path: "emails", Value: []any{
+				map[string]any{"value": "babs@jensen.org"},
+				map[string]any{"value": "b@jensen.org", "type": "other"},
+			}}},
+			assert: func(t *testing.T, res map[string]any) {
+				require.Len(t, res["emails"], 3)
+			},
+		},
+		"remove attribute": {
+			ops: []patchOperation{{Op: "remove", Path: "nickName"}},
+			assert: func(t *testing.T, res map[string]any) {
+				require.NotContains(t, res, "nickName")
+			},
+		},
+		"remove filtered values": {
+			ops: []patchOperation{{Op: "remove", Path: `emails[type eq "home"]`}},
+			assert: func(t *testing.T, res map[string]any) {
+				require.Len(t, res["emails"], 1)
+			},
+		},
+		"remove listed values": {
+			ops: []patchOperation{{Op: "remove", Path: "emails", Value: []any{map[string]any{"value": "bjensen@example.com"}}}},
+			assert: func(t *testing.T, res map[string]any) {
+				require.Equal(t, []any{map[string]any{"value": "babs@jensen.org", "type": "home"}}, res["emails"])
+			},
+		},
+	}
+
+	for name, tc := range tt {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			res := testUserResource()
+			require.NoError(t, applyPatch(res, tc.ops))
+			tc.assert(t, res)
+		})
+	}
+}
+
+func TestApplyPatch_Errors(t *testing.T) {
+	tt := map[string]struct {
+		op  patchOperation
+		err error
+	}{
+		"unknown op": {
+			op:  patchOperation{Op: "move", Path: "nickName"},
+			err: errInvalidOp,
+		},
+		"replace without path or object": {
+			op:  patchOperation{Op: "replace", Value: "Babs"},
+			err: errInvalidOp,
+		},
+		"remove without path": {
+			op:  patchOperation{Op: "remove"},
+			err: errNoTarget,
+		},
+		"invalid path": {
+			op:  patchOperation{Op: "replace", Path: `emails[type eq].value`, Value: "x"},
+			err: errInvalidPath,
+		},
+		"no match for complex filter": {
+			op:  patchOperation{Op: "replace", Path: `emails[type eq "other" or primary eq false].value`, Value: "x"},
+			err: errNoTarget,
+		},
+	}
+
+	for name, tc := range tt {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			err := applyPatch(testUserResource(), []patchOperation{tc.op})
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+// Schema URIs of RFC 7643 and RFC 7644.
+const (
+	schemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	schemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	schemaSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+	schemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+const (
+	resourceTypeUser  = "User"
+	resourceTypeGroup = "Group"
+	emailTypeWork     = "work"
+)
+
+type meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created"`
+	LastModified string `json:"lastModified"`
+	Location     string `json:"location"`
+}
+
+type name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type multiValued struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type address struct {
+	Country string `json:"country,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// user is the SCIM representation of a user. The userName is the email of the
+// user, which is also listed as the primary work email.
+type user struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	UserName    string        `json:"userName"`
+	Name        *name         `json:"name,omitempty"`
+	DisplayName string        `json:"displayName,omitempty"`
+	NickName    string        `json:"nickName,omitempty"`
+	Active      *bool         `json:"active,omitempty"`
+	Emails      []multiValued `json:"emails,omitempty"`
+	Addresses   []address     `json:"addresses,omitempty"`
+	Password    string        `json:"password,omitempty"`
+	Meta        *meta         `json:"meta,omitempty"`
+}
+
+type member struct {
+	Value string `json:"value"`
+	Ref   string `json:"$ref,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
+type group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []member `json:"members,omitempty"`
+	Meta        *meta    `json:"meta,omitempty"`
+}
+
+type listResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+func (s *Server) toUser(u models.User) user {
+	active := u.Status == models.UserStatusActive
+	res := user{
+		Schemas:  []string{schemaUser},
+		ID:       u.ID.String(),
+		UserName: u.Email,
+		NickName: u.Nickname,
+		Active:   &active,
+		Meta:     s.meta(resourceTypeUser, u.ID.String(), u.CreatedAt, u.UpdateAt),
+	}
+
+	if u.FirstName != "" || u.LastName != "" {
+		res.DisplayName = strings.TrimSpace(u.FirstName + " " + u.LastName)
+		res.Name = &name{
+			Formatted:  res.DisplayName,
+			GivenName:  u.FirstName,
+			FamilyName: u.LastName,
+		}
+	}
+
+	if u.Email != "" {
+		res.Emails = []multiValued{{Value: u.Email, Type: emailTypeWork, Primary: true}}
+	}
+
+	if u.Country != "" {
+		res.Addresses = []address{{Country: u.Country, Type: emailTypeWork, Primary: true}}
+	}
+
+	return res
+}
+
+// email returns the email of the user: the userName or, when missing, the
+// primary email.
+func (u user) email() string {
+	if u.UserName != "" {
+		return u.UserName
+	}
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+func (u user) names() (string, string) {
+	if u.Name == nil {
+		return "", ""
+	}
+	return u.Name.GivenName, u.Name.FamilyName
+}
+
+func (u user) country() string {
+	for _, a := range u.Addresses {
+		if a.Primary {
+			return a.Country
+		}
+	}
+	if len(u.Addresses) > 0 {
+		return u.Addresses[0].Country
+	}
+	return ""
+}
+
+func (s *Server) toGroup(g models.Group, members []models.GroupMember) group {
+	res := group{
+		Schemas:     []string{schemaGroup},
+		ID:          g.ID.String(),
+		DisplayName: g.Name,
+		Meta:        s.meta(resourceTypeGroup, g.ID.String(), g.CreatedAt, g.UpdateAt),
+	}
+
+	for _, m := range members {
+		res.Members = append(res.Members, member{
+			Value: m.UserID.String(),
+			Ref:   s.location(usersPath, m.UserID.String()),
+			Type:  resourceTypeUser,
+		})
+	}
+
+	return res
+}
+
+func (s *Server) meta(resourceType string, id string, created time.Time, updated *time.Time) *meta {
+	modified := created
+	if updated != nil {
+		modified = *updated
+	}
+
+	path := usersPath
+	if resourceType == resourceTypeGroup {
+		path = groupsPath
+	}
+
+	return &meta{
+		ResourceType: resourceType,
+		Created:      created.UTC().Format(time.RFC3339),
+		LastModified: modified.UTC().Format(time.RFC3339),
+		Location:     s.location(path, id),
+	}
+}
+
+func (s *Server) location(path string, id string) string {
+	return s.baseURL + path + "/" + id
+}
+
+// toMap returns the JSON representation of the resource, which filters and
+// patch operations are applied to.
+func toMap(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// fromMap decodes the JSON representation of a resource.
+func fromMap(m map[string]any, v any) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,462 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// internal
+	aip "github.com/TonyPath/user-mng-grpc-service/internal/filter"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+const (
+	testToken   = "umk_test"
+	testBaseURL = "https://idm.example.com/scim/v2"
+)
+
+func newTestServer(t *testing.T, users userService, groups groupService, scopes ...string) *httptest.Server {
+	authn := &AuthenticatorMock{
+		AuthenticateFunc: func(ctx context.Context, plaintext string) (models.Principal, error) {
+			if plaintext != testToken {
+				return models.Principal{}, models.ErrInvalidAPIKey
+			}
+			return models.Principal{UserID: uuid.New(), APIKeyID: uuid.New(), Scopes: scopes}, nil
+		},
+	}
+
+	s := NewServer(zap.NewNop().Sugar(), "", testBaseURL, users, groups, authn)
+	ts := httptest.NewServer(s.httpServer.Handler)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func doRequest(t *testing.T, ts *httptest.Server, method string, path string, body string) (*http.Response, map[string]any) {
+	req, err := http.NewRequest(method, ts.URL+basePath+path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var payload map[string]any
+	if resp.StatusCode != http.StatusNoContent {
+		require.Equal(t, contentType, resp.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	}
+
+	return resp, payload
+}
+
+func testUser(email string) models.User {
+	return models.User{
+		ID:        uuid.New(),
+		Status:    models.UserStatusActive,
+		Email:     email,
+		FirstName: "Barbara",
+		LastName:  "Jensen",
+		Country:   "GR",
+		CreatedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	ts := newTestServer(t, &UserServiceMock{}, &GroupServiceMock{}, models.ScopeUsersRead)
+
+	t.Log("missing token")
+	{
+		resp, err := ts.Client().Get(ts.URL + basePath + usersPath)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		require.Equal(t, `Bearer realm="scim"`, resp.Header.Get("WWW-Authenticate"))
+	}
+
+	t.Log("invalid token")
+	{
+		req, err := http.NewRequest(http.MethodGet, ts.URL+basePath+usersPath, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer umk_other")
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	t.Log("missing scope")
+	{
+		resp, payload := doRequest(t, ts, http.MethodPost, usersPath, `{"userName":"a@mail.com"}`)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+		require.Equal(t, []any{schemaError}, payload["schemas"])
+		require.Equal(t, "403", payload["status"])
+
+		resp, _ = doRequest(t, ts, http.MethodGet, groupsPath, "")
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	}
+
+	t.Log("discovery does not require a token")
+	{
+		resp, err := ts.Client().Get(ts.URL + basePath + serviceProviderConfigPath)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestUsers(t *testing.T) {
+	stored := map[uuid.UUID]models.User{}
+	users := &UserServiceMock{
+		CreateUserFunc: func(ctx context.Context, nu models.NewUser) (uuid.UUID, error) {
+			u := testUser(nu.Email)
+			u.FirstName, u.LastName, u.Nickname, u.Country = nu.FirstName, nu.LastName, nu.Nickname, nu.Country
+			stored[u.ID] = u
+			return u.ID, nil
+		},
+		GetUserFunc: func(ctx context.Context, userID uuid.UUID) (models.User, error) {
+			u, ok := stored[userID]
+			if !ok {
+				return models.User{}, models.ErrUserNotFound
+			}
+			return u, nil
+		},
+		UpdateUserFunc: func(ctx context.Context, userID uuid.UUID, uu models.UpdateUser) error {
+			u := stored[userID]
+			if uu.Nickname != "" {
+				u.Nickname = uu.Nickname
+			}
+			stored[userID] = u
+			return nil
+		},
+		DeactivateUserFunc: func(ctx context.Context, userID uuid.UUID, reason string) error {
+			u := stored[userID]
+			u.Status = models.UserStatusDeactivated
+			stored[userID] = u
+			return nil
+		},
+		DeleteUserFunc: func(ctx context.Context, userID uuid.UUID) error {
+			return nil
+		},
+	}
+	ts := newTestServer(t, users, &GroupServiceMock{}, models.ScopeUsersRead, models.ScopeUsersWrite)
+
+	resp, payload := doRequest(t, ts, http.MethodPost, usersPath, `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "bjensen@example.com",
+		"name": {"givenName": "Barbara", "familyName": "Jensen"},
+		"emails": [{"value": "bjensen@example.com", "type": "work", "primary": true}],
+		"active": true
+	}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.True(t, users.CreateUserCalls()[0].Nu.Passwordless)
+	require.Empty(t, users.DeactivateUserCalls())
+
+	id := payload["id"].(string)
+	require.Equal(t, testBaseURL+usersPath+"/"+id, resp.Header.Get("Location"))
+	require.Equal(t, "bjensen@example.com", payload["userName"])
+	require.Equal(t, "Barbara Jensen", payload["displayName"])
+	require.Equal(t, true, payload["active"])
+	require.NotContains(t, payload, "password")
+
+	t.Log("patch")
+	{
+		resp, payload := doRequest(t, ts, http.MethodPatch, usersPath+"/"+id, `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "Replace", "path": "active", "value": "False"},
+				{"op": "add", "path": "nickName", "value": "Babs"}
+			]
+		}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, false, payload["active"])
+		require.Equal(t, "Babs", payload["nickName"])
+		require.Equal(t, "Babs", users.UpdateUserCalls()[0].Uu.Nickname)
+		require.Equal(t, statusReason, users.DeactivateUserCalls()[0].Reason)
+
+		resp, payload = doRequest(t, ts, http.MethodPatch, usersPath+"/"+id, `{
+			"Operations": [{"op": "remove", "path": "nickName"}]
+		}`)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, scimTypeMutability, payload["scimType"])
+	}
+
+	t.Log("errors")
+	{
+		resp, _ := doRequest(t, ts, http.MethodGet, usersPath+"/"+uuid.NewString(), "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, _ = doRequest(t, ts, http.MethodGet, usersPath+"/not-a-uuid", "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, payload := doRequest(t, ts, http.MethodPost, usersPath, `{"userName":`)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, scimTypeInvalidSyntax, payload["scimType"])
+
+		users.CreateUserFunc = func(ctx context.Context, nu models.NewUser) (uuid.UUID, error) {
+			return uuid.Nil, models.ErrEmailTaken
+		}
+		resp, payload = doRequest(t, ts, http.MethodPost, usersPath, `{"userName":"bjensen@example.com"}`)
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+		require.Equal(t, scimTypeUniqueness, payload["scimType"])
+	}
+
+	resp, _ = doRequest(t, ts, http.MethodDelete, usersPath+"/"+id, "")
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestListUsers(t *testing.T) {
+	var all []models.User
+	for _, email := range []string{"a@mail.com", "b@mail.com", "c@mail.com", "d@work.com"} {
+		all = append(all, testUser(email))
+	}
+
+	// matching are the users the database returns for the filter of the case.
+	var matching []models.User
+	users := &UserServiceMock{
+		GetUsersFunc: func(ctx context.Context, opts models.GetUsersOptions) ([]models.User, error) {
+			start := (opts.PageNumber - 1) * opts.PageSize
+			if start >= uint64(len(matching)) {
+				return nil, nil
+			}
+			end := start + opts.PageSize
+			if end > uint64(len(matching)) {
+				end = uint64(len(matching))
+			}
+			return matching[start:end], nil
+		},
+		CountUsersFunc: func(ctx context.Context, opts models.GetUsersOptions) (models.UserCount, error) {
+			return models.UserCount{Total: uint64(len(matching))}, nil
+		},
+	}
+	ts := newTestServer(t, users, &GroupServiceMock{}, models.ScopeUsersRead)
+
+	eq := func(field string, kind aip.ValueKind, text string) aip.Restriction {
+		return aip.Restriction{Field: field, Op: aip.OpEq, Values: []aip.Value{{Kind: kind, Text: text}}}
+	}
+
+	tt := map[string]struct {
+		query    string
+		matching []models.User
+		expr     aip.Expr
+		emails   []string
+	}{
+		"all": {
+			query:    "",
+			matching: all,
+			emails:   []string{"a@mail.com", "b@mail.com", "c@mail.com", "d@work.com"},
+		},
+		"userName eq": {
+			query:    `?filter=userName+eq+"c@mail.com"`,
+			matching: all[2:3],
+			expr:     eq("email", aip.String, "c@mail.com"),
+			emails:   []string{"c@mail.com"},
+		},
+		"value path and active": {
+			query:    `?filter=emails[value+eq+"c@mail.com"]+and+active+eq+true`,
+			matching: all[2:3],
+			expr: aip.And{Exprs: []aip.Expr{
+				eq("email", aip.String, "c@mail.com"),
+				eq("status", aip.Text, "active"),
+			}},
+			emails: []string{"c@mail.com"},
+		},
+		"work emails": {
+			query:    `?filter=emails[type+eq+"work"]`,
+			matching: all,
+			expr:     everyUser,
+			emails:   []string{"a@mail.com", "b@mail.com", "c@mail.com", "d@work.com"},
+		},
+		"work email value": {
+			query:    `?filter=emails[type+eq+"work"+and+value+eq+"d@work.com"]`,
+			matching: all[3:],
+			expr: aip.And{Exprs: []aip.Expr{
+				everyUser,
+				eq("email", aip.String, "d@work.com"),
+			}},
+			emails: []string{"d@work.com"},
+		},
+		"home emails": {
+			query:    `?filter=emails.type+eq+"home"`,
+			matching: nil,
+			expr:     aip.Not{Expr: everyUser},
+			emails:   nil,
+		},
+		"not active or nickName prefix": {
+			query:    `?filter=active+eq+false+or+not+(nickName+sw+"to")`,
+			matching: all[1:2],
+			expr: aip.Or{Exprs: []aip.Expr{
+				aip.Restriction{Field: "status", Op: aip.OpNe, Values: []aip.Value{{Kind: aip.Text, Text: "active"}}},
+				aip.Not{Expr: aip.Restriction{Field: "nickname", Op: aip.OpHas, Values: []aip.Value{{Kind: aip.String, Text: "to*"}}}},
+			}},
+			emails: []string{"b@mail.com"},
+		},
+		"created after": {
+			query:    `?filter=meta.created+gt+"2024-01-01T00:00:00Z"`,
+			matching: all,
+			expr:     aip.Restriction{Field: "created_at", Op: aip.OpGt, Values: []aip.Value{{Kind: aip.String, Text: "2024-01-01T00:00:00Z"}}},
+			emails:   []string{"a@mail.com", "b@mail.com", "c@mail.com", "d@work.com"},
+		},
+		"window": {
+			query:    "?startIndex=2&count=2",
+			matching: all,
+			emails:   []string{"b@mail.com", "c@mail.com"},
+		},
+		"window past the end": {
+			query:    "?startIndex=4&count=3",
+			matching: all,
+			emails:   []string{"d@work.com"},
+		},
+	}
+
+	for name, tc := range tt {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			matching = tc.matching
+			calls := len(users.GetUsersCalls())
+
+			resp, payload := doRequest(t, ts, http.MethodGet, usersPath+tc.query, "")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, []any{schemaListResponse}, payload["schemas"])
+			require.Equal(t, float64(len(tc.matching)), payload["totalResults"])
+			require.Equal(t, float64(len(tc.emails)), payload["itemsPerPage"])
+
+			var emails []string
+			for _, res := range payload["Resources"].([]any) {
+				emails = append(emails, res.(map[string]any)["userName"].(string))
+			}
+			require.Equal(t, tc.emails, emails)
+
+			// Only the pages overlapping the window are fetched.
+			fetched := users.GetUsersCalls()[calls:]
+			require.NotEmpty(t, fetched)
+			require.LessOrEqual(t, len(fetched), 2)
+			for _, call := range fetched {
+				require.Equal(t, tc.expr, call.Opts.Filter.Expression)
+			}
+		})
+	}
+
+	t.Log("filters that cannot be evaluated by the database")
+	{
+		calls := len(users.GetUsersCalls())
+		for _, f := range []string{
+			`userName+like+"a"`,
+			`userName+co+"a"`,
+			`emails[type+sw+"w"]`,
+			`name.familyName+eq+"Path"`,
+			`addresses.country+sw+"G"`,
+			`active+gt+true`,
+		} {
+			resp, payload := doRequest(t, ts, http.MethodGet, usersPath+"?filter="+f, "")
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode, f)
+			require.Equal(t, scimTypeInvalidFilter, payload["scimType"], f)
+		}
+		require.Len(t, users.GetUsersCalls(), calls)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	groupID := uuid.New()
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+	members := []models.GroupMember{{GroupID: groupID, UserID: alice}, {GroupID: groupID, UserID: bob}}
+	groups := &GroupServiceMock{
+		GetGroupFunc: func(ctx context.Context, id uuid.UUID) (models.Group, error) {
+			if id != groupID {
+				return models.Group{}, models.ErrGroupNotFound
+			}
+			return models.Group{ID: groupID, Name: "admins", CreatedAt: time.Now()}, nil
+		},
+		GetMembersFunc: func(ctx context.Context, id uuid.UUID, opts models.GetGroupMembersOptions) ([]models.GroupMember, error) {
+			return members, nil
+		},
+		RenameGroupFunc: func(ctx context.Context, id uuid.UUID, name string) error {
+			return nil
+		},
+		AddMemberFunc: func(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+			return nil
+		},
+		RemoveMemberFunc: func(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+			return nil
+		},
+		GetGroupsFunc: func(ctx context.Context, opts models.GetGroupsOptions) ([]models.Group, error) {
+			return []models.Group{{ID: groupID, Name: "admins", CreatedAt: time.Now()}}, nil
+		},
+	}
+	ts := newTestServer(t, &UserServiceMock{}, groups, models.ScopeGroupsRead, models.ScopeGroupsWrite)
+
+	resp, payload := doRequest(t, ts, http.MethodGet, groupsPath+"/"+groupID.String(), "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "admins", payload["displayName"])
+	require.Len(t, payload["members"], 2)
+
+	t.Log("patch members and name")
+	{
+		resp, _ := doRequest(t, ts, http.MethodPatch, groupsPath+"/"+groupID.String(), `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "replace", "value": {"displayName": "operators"}},
+				{"op": "add", "path": "members", "value": [{"value": "`+carol.String()+`"}, {"value": "`+alice.String()+`"}]},
+				{"op": "remove", "path": "members[value eq \"`+bob.String()+`\"]"}
+			]
+		}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "operators", groups.RenameGroupCalls()[0].Name)
+		require.Len(t, groups.AddMemberCalls(), 1)
+		require.Equal(t, carol, groups.AddMemberCalls()[0].UserID)
+		require.Len(t, groups.RemoveMemberCalls(), 1)
+		require.Equal(t, bob, groups.RemoveMemberCalls()[0].UserID)
+
+		resp, payload := doRequest(t, ts, http.MethodPatch, groupsPath+"/"+groupID.String(), `{
+			"Operations": [{"op": "add", "path": "members", "value": [{"value": "carol"}]}]
+		}`)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, scimTypeInvalidValue, payload["scimType"])
+	}
+
+	t.Log("list without members")
+	{
+		calls := len(groups.GetMembersCalls())
+		resp, payload := doRequest(t, ts, http.MethodGet, groupsPath+`?filter=displayName+eq+"admins"&excludedAttributes=members`, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, float64(1), payload["totalResults"])
+		require.Equal(t, "admins", groups.GetGroupsCalls()[0].Opts.Name)
+		require.Len(t, groups.GetMembersCalls(), calls)
+	}
+
+	resp, _ = doRequest(t, ts, http.MethodGet, groupsPath+"/"+uuid.NewString(), "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestDiscovery(t *testing.T) {
+	ts := newTestServer(t, &UserServiceMock{}, &GroupServiceMock{})
+
+	resp, payload := doRequest(t, ts, http.MethodGet, serviceProviderConfigPath, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, true, payload["patch"].(map[string]any)["supported"])
+
+	resp, payload = doRequest(t, ts, http.MethodGet, schemasPath, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, float64(2), payload["totalResults"])
+
+	resp, payload = doRequest(t, ts, http.MethodGet, schemasPath+"/"+schemaGroup, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, testBaseURL+schemasPath+"/"+schemaGroup, payload["meta"].(map[string]any)["location"])
+
+	resp, payload = doRequest(t, ts, http.MethodGet, resourceTypesPath+"/User", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, usersPath, payload["endpoint"])
+}
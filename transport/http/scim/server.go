@@ -0,0 +1,334 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	// 3rd party
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/auth"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+const (
+	httpReadTimeout     = 30 * time.Second
+	httpWriteTimeout    = 60 * time.Second
+	shutdownGracePeriod = 5 * time.Second
+)
+
+// Paths of the endpoints, relative to the base URL.
+const (
+	basePath                  = "/scim/v2"
+	usersPath                 = "/Users"
+	groupsPath                = "/Groups"
+	serviceProviderConfigPath = "/ServiceProviderConfig"
+	schemasPath               = "/Schemas"
+	resourceTypesPath         = "/ResourceTypes"
+)
+
+const (
+	// defaultCount is the page size of list responses when count is not given.
+	defaultCount = 100
+	// maxResults is the largest page size of list responses.
+	maxResults = 200
+	// scanPageSize is the page size used to fetch resources from the services
+	// while evaluating filters.
+	scanPageSize = 500
+	// maxBodySize is the largest request body accepted.
+	maxBodySize = 1 << 20
+)
+
+// statusReason is recorded as the reason of status changes made by identity
+// providers.
+const statusReason = "scim"
+
+//go:generate moq -out user_service_mock_test.go . userService
+type userService interface {
+	CreateUser(ctx context.Context, nu models.NewUser) (uuid.UUID, error)
+	UpdateUser(ctx context.Context, userID uuid.UUID, uu models.UpdateUser) error
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	ReactivateUser(ctx context.Context, userID uuid.UUID, reason string) error
+	DeactivateUser(ctx context.Context, userID uuid.UUID, reason string) error
+	GetUser(ctx context.Context, userID uuid.UUID) (models.User, error)
+	GetUsers(ctx context.Context, opts models.GetUsersOptions) ([]models.User, error)
+	CountUsers(ctx context.Context, opts models.GetUsersOptions) (models.UserCount, error)
+}
+
+//go:generate moq -out group_service_mock_test.go . groupService
+type groupService interface {
+	CreateGroup(ctx context.Context, name string) (uuid.UUID, error)
+	RenameGroup(ctx context.Context, groupID uuid.UUID, name string) error
+	DeleteGroup(ctx context.Context, groupID uuid.UUID) error
+	GetGroup(ctx context.Context, groupID uuid.UUID) (models.Group, error)
+	GetGroups(ctx context.Context, opts models.GetGroupsOptions) ([]models.Group, error)
+	AddMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error
+	RemoveMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error
+	GetMembers(ctx context.Context, groupID uuid.UUID, opts models.GetGroupMembersOptions) ([]models.GroupMember, error)
+}
+
+//go:generate moq -out authenticator_mock_test.go . authenticator
+type authenticator interface {
+	Authenticate(ctx context.Context, plaintext string) (models.Principal, error)
+}
+
+// Server is the SCIM 2.0 (RFC 7643, RFC 7644) HTTP server used by identity
+// providers to provision users and groups.
+type Server struct {
+	httpServer http.Server
+	baseURL    string
+	users      userService
+	groups     groupService
+	authn      authenticator
+	logger     *zap.SugaredLogger
+}
+
+// NewServer returns a server listening on addr. The baseURL is the public URL
+// of the /scim/v2 endpoints, used in the location of the resources.
+func NewServer(logger *zap.SugaredLogger, addr string, baseURL string, users userService, groups groupService, authn authenticator) *Server {
+	s := &Server{
+		httpServer: http.Server{
+			Addr:         addr,
+			ReadTimeout:  httpReadTimeout,
+			WriteTimeout: httpWriteTimeout,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		users:   users,
+		groups:  groups,
+		authn:   authn,
+		logger:  logger,
+	}
+
+	s.addRoutes()
+
+	return s
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Infow("startup", "status", "http scim server started", "base_url", s.baseURL)
+		errCh <- s.httpServer.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return s.shutdown()
+	case err := <-errCh:
+		return err
+	}
+}
+
+func (s *Server) addRoutes() {
+	mux := chi.NewRouter()
+
+	mux.Route(basePath, func(r chi.Router) {
+		r.Get(serviceProviderConfigPath, s.serviceProviderConfig)
+		r.Get(schemasPath, s.schemas)
+		r.Get(schemasPath+"/{id}", s.schema)
+		r.Get(resourceTypesPath, s.resourceTypes)
+		r.Get(resourceTypesPath+"/{id}", s.resourceType)
+
+		r.Group(func(r chi.Router) {
+			r.Use(s.authenticate, requireScope(models.ScopeUsersRead, models.ScopeUsersWrite))
+			r.Get(usersPath, s.listUsers)
+			r.Post(usersPath, s.createUser)
+			r.Get(usersPath+"/{id}", s.getUser)
+			r.Put(usersPath+"/{id}", s.replaceUser)
+			r.Patch(usersPath+"/{id}", s.patchUser)
+			r.Delete(usersPath+"/{id}", s.deleteUser)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(s.authenticate, requireScope(models.ScopeGroupsRead, models.ScopeGroupsWrite))
+			r.Get(groupsPath, s.listGroups)
+			r.Post(groupsPath, s.createGroup)
+			r.Get(groupsPath+"/{id}", s.getGroup)
+			r.Put(groupsPath+"/{id}", s.replaceGroup)
+			r.Patch(groupsPath+"/{id}", s.patchGroup)
+			r.Delete(groupsPath+"/{id}", s.deleteGroup)
+		})
+	})
+
+	s.httpServer.Handler = mux
+}
+
+func (s *Server) shutdown() error {
+	tctx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+	defer cancel()
+
+	if err := s.httpServer.Shutdown(tctx); err != nil {
+		closeErr := s.httpServer.Close()
+		if closeErr != nil {
+			return fmt.Errorf("cannot stop server gracefully: %w", err)
+		}
+	}
+	s.logger.Infow("shutdown", "status", "gracefully stopped http scim server")
+
+	return nil
+}
+
+// authenticate requires an API key, sent as a bearer token. Each identity
+// provider is given the API key of its own service account.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+			respondError(w, http.StatusUnauthorized, "", "authentication required")
+			return
+		}
+
+		principal, err := s.authn.Authenticate(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidAPIKey) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="scim", error="invalid_token"`)
+				respondError(w, http.StatusUnauthorized, "", "invalid token")
+				return
+			}
+			s.handleError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// requireScope requires the read scope for GET requests and the write scope
+// for any other.
+func requireScope(read string, write string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := write
+			if r.Method == http.MethodGet {
+				scope = read
+			}
+
+			p, ok := auth.PrincipalFromContext(r.Context())
+			if !ok || !p.HasScope(scope) {
+				respondError(w, http.StatusForbidden, "", "the token lacks the "+scope+" scope")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// window is the part of the matching resources returned by a list request.
+type window struct {
+	startIndex int
+	count      int
+}
+
+func parseWindow(q url.Values) (window, error) {
+	win := window{startIndex: 1, count: defaultCount}
+
+	if v := q.Get("startIndex"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return window{}, fmt.Errorf("%w: startIndex must be an integer", errInvalidValue)
+		}
+		if n > 1 {
+			win.startIndex = n
+		}
+	}
+
+	if v := q.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return window{}, fmt.Errorf("%w: count must be an integer", errInvalidValue)
+		}
+		win.count = n
+	}
+
+	if win.count < 0 {
+		win.count = 0
+	}
+	if win.count > maxResults {
+		win.count = maxResults
+	}
+
+	return win, nil
+}
+
+// list pages through the resources returned by fetch, counting those that
+// match the filter and collecting the ones within the window.
+func list(f filter, win window, fetch func(pageNumber uint64) ([]any, error)) (listResponse, error) {
+	resp := listResponse{
+		Schemas:    []string{schemaListResponse},
+		StartIndex: win.startIndex,
+		Resources:  []any{},
+	}
+
+	for pageNumber := uint64(1); ; pageNumber++ {
+		resources, err := fetch(pageNumber)
+		if err != nil {
+			return listResponse{}, err
+		}
+
+		for _, res := range resources {
+			if f != nil {
+				m, err := toMap(res)
+				if err != nil {
+					return listResponse{}, err
+				}
+				if !f.match(m) {
+					continue
+				}
+			}
+
+			resp.TotalResults++
+			if resp.TotalResults >= win.startIndex && len(resp.Resources) < win.count {
+				resp.Resources = append(resp.Resources, res)
+			}
+		}
+
+		if len(resources) < scanPageSize {
+			break
+		}
+	}
+
+	resp.ItemsPerPage = len(resp.Resources)
+
+	return resp, nil
+}
+
+// excluded reports whether the attribute is listed in the excludedAttributes
+// parameter.
+func excluded(q url.Values, attr string) bool {
+	for _, name := range strings.Split(q.Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(name), attr) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceID parses the id of the path; ids that are not uuids cannot
+// identify a resource.
+func resourceID(r *http.Request, notFound error) (uuid.UUID, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, notFound
+	}
+	return id, nil
+}
+
+// decode reads the JSON body of the request into v.
+func decode(w http.ResponseWriter, r *http.Request, v any) error {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidSyntax, err)
+	}
+	return nil
+}
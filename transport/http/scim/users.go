@@ -0,0 +1,477 @@
+package scim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	// internal
+	aip "github.com/TonyPath/user-mng-grpc-service/internal/filter"
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+)
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	f, err := parseFilter(q.Get("filter"))
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	win, err := parseWindow(q)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	opts := models.GetUsersOptions{}
+	if f != nil {
+		if opts.Filter.Expression, err = userExpression(f, ""); err != nil {
+			s.handleError(w, err)
+			return
+		}
+	}
+
+	count, err := s.users.CountUsers(r.Context(), opts)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	users, err := s.windowUsers(r.Context(), opts, win)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	resp := listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: int(count.Total),
+		StartIndex:   win.startIndex,
+		ItemsPerPage: len(users),
+		Resources:    make([]any, 0, len(users)),
+	}
+	for _, u := range users {
+		resp.Resources = append(resp.Resources, s.toUser(u))
+	}
+
+	respond(w, resp, http.StatusOK)
+}
+
+// windowUsers fetches the users within the window, reading at most the two
+// pages of its size that overlap it.
+func (s *Server) windowUsers(ctx context.Context, opts models.GetUsersOptions, win window) ([]models.User, error) {
+	if win.count == 0 {
+		return nil, nil
+	}
+
+	offset := uint64(win.startIndex - 1)
+	opts.PageSize = uint64(win.count)
+	opts.PageNumber = offset/opts.PageSize + 1
+
+	users, err := s.users.GetUsers(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := offset % opts.PageSize
+	if skip > 0 && uint64(len(users)) == opts.PageSize {
+		opts.PageNumber++
+		next, err := s.users.GetUsers(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, next...)
+	}
+
+	if skip >= uint64(len(users)) {
+		return nil, nil
+	}
+	users = users[skip:]
+	if len(users) > win.count {
+		users = users[:win.count]
+	}
+	return users, nil
+}
+
+// userFilterFields are the user attributes filters may compare, by lower-cased
+// path, along with the field of the user filter expressions they compare.
+var userFilterFields = map[string]string{
+	"id":                "id",
+	"username":          "email",
+	"emails":            "email",
+	"emails.value":      "email",
+	"nickname":          "nickname",
+	"addresses.country": "country",
+	"active":            "status",
+	"meta.created":      "created_at",
+}
+
+// everyUser matches every user, whatever its type.
+var everyUser aip.Expr = aip.Restriction{Field: "type", Op: aip.OpIn, Values: []aip.Value{
+	{Kind: aip.String, Text: string(models.UserTypeHuman)},
+	{Kind: aip.String, Text: string(models.UserTypeServiceAccount)},
+}}
+
+// emailTypeRestriction translates a filter on the type of the emails. Users
+// have one email listed, their primary one, of type work, so the filter
+// matches either every user or none.
+func emailTypeRestriction(f attrFilter) (aip.Expr, error) {
+	if f.op == opPresent {
+		return everyUser, nil
+	}
+
+	value, ok := f.value.(string)
+	if !ok || (f.op != opEqual && f.op != opNotEqual) {
+		return nil, fmt.Errorf("%w: emails.type does not support %s", errInvalidFilter, f.op)
+	}
+
+	if strings.EqualFold(value, emailTypeWork) == (f.op == opEqual) {
+		return everyUser, nil
+	}
+	return aip.Not{Expr: everyUser}, nil
+}
+
+// userExpression translates the filter into a user filter expression, so that
+// it is evaluated by the database. Filters that cannot be translated, such as
+// substrings of encrypted attributes, are rejected rather than evaluated on
+// every user. The prefix is the attribute of an enclosing value path.
+func userExpression(f filter, prefix string) (aip.Expr, error) {
+	switch f := f.(type) {
+	case logicalFilter:
+		left, err := userExpression(f.left, prefix)
+		if err != nil {
+			return nil, err
+		}
+		right, err := userExpression(f.right, prefix)
+		if err != nil {
+			return nil, err
+		}
+		if f.and {
+			return aip.And{Exprs: []aip.Expr{left, right}}, nil
+		}
+		return aip.Or{Exprs: []aip.Expr{left, right}}, nil
+	case notFilter:
+		e, err := userExpression(f.filter, prefix)
+		if err != nil {
+			return nil, err
+		}
+		return aip.Not{Expr: e}, nil
+	case valuePathFilter:
+		return userExpression(f.filter, f.attr)
+	case attrFilter:
+		return userRestriction(f, prefix)
+	default:
+		return nil, fmt.Errorf("%w: unsupported filter", errInvalidFilter)
+	}
+}
+
+func userRestriction(f attrFilter, prefix string) (aip.Expr, error) {
+	path := strings.Join(f.path, ".")
+	if prefix != "" {
+		path = prefix + "." + path
+	}
+
+	if path == "emails.type" {
+		return emailTypeRestriction(f)
+	}
+
+	field, ok := userFilterFields[path]
+	if !ok {
+		return nil, fmt.Errorf("%w: users cannot be filtered on %s", errInvalidFilter, path)
+	}
+	unsupported := fmt.Errorf("%w: %s does not support %s", errInvalidFilter, path, f.op)
+
+	restriction := func(op aip.Op, v aip.Value) aip.Expr {
+		return aip.Restriction{Field: field, Op: op, Values: []aip.Value{v}}
+	}
+
+	value := f.value
+	// Some identity providers send booleans as strings.
+	if s, ok := value.(string); ok && field == "status" {
+		if active, err := strconv.ParseBool(s); err == nil {
+			value = active
+		}
+	}
+
+	switch value := value.(type) {
+	case nil:
+		// Only the nickname may be unassigned; pr and eq null are the only
+		// filters that do not compare a value.
+		if field != "nickname" {
+			return nil, unsupported
+		}
+		switch f.op {
+		case opPresent:
+			return restriction(aip.OpHas, aip.Value{Kind: aip.Any, Text: "*"}), nil
+		case opEqual:
+			return restriction(aip.OpEq, aip.Value{Kind: aip.Text, Text: "null"}), nil
+		case opNotEqual:
+			return restriction(aip.OpNe, aip.Value{Kind: aip.Text, Text: "null"}), nil
+		}
+	case bool:
+		// active is true for active users only.
+		if field != "status" || (f.op != opEqual && f.op != opNotEqual) {
+			return nil, unsupported
+		}
+		op := aip.OpEq
+		if value != (f.op == opEqual) {
+			op = aip.OpNe
+		}
+		return restriction(op, aip.Value{Kind: aip.Text, Text: string(models.UserStatusActive)}), nil
+	case string:
+		if field == "status" {
+			return nil, unsupported
+		}
+		v := aip.Value{Kind: aip.String, Text: value}
+		switch f.op {
+		case opEqual:
+			return restriction(aip.OpEq, v), nil
+		case opNotEqual:
+			return restriction(aip.OpNe, v), nil
+		case opStartsWith:
+			if field == "nickname" {
+				return restriction(aip.OpHas, aip.Value{Kind: aip.String, Text: value + "*"}), nil
+			}
+		case opGreater, opGreaterOrEqual, opLess, opLessOrEqual:
+			if field == "created_at" {
+				return restriction(userOrderingOps[f.op], v), nil
+			}
+		}
+	}
+
+	return nil, unsupported
+}
+
+// userOrderingOps are the filter expression operators of the ordering
+// operators of SCIM filters.
+var userOrderingOps = map[string]aip.Op{
+	opGreater:        aip.OpGt,
+	opGreaterOrEqual: aip.OpGe,
+	opLess:           aip.OpLt,
+	opLessOrEqual:    aip.OpLe,
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := resourceID(r, models.ErrUserNotFound)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	u, err := s.users.GetUser(r.Context(), userID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	respond(w, s.toUser(u), http.StatusOK)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var req user
+	if err := decode(w, r, &req); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	email := req.email()
+	if email == "" {
+		s.handleError(w, fmt.Errorf("%w: userName is required", errInvalidValue))
+		return
+	}
+
+	firstName, lastName := req.names()
+
+	// Users provisioned without a password sign in through the identity
+	// provider.
+	userID, err := s.users.CreateUser(r.Context(), models.NewUser{
+		Email:        email,
+		FirstName:    firstName,
+		LastName:     lastName,
+		Nickname:     req.NickName,
+		Country:      req.country(),
+		Password:     req.Password,
+		Passwordless: req.Password == "",
+	})
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if req.Active != nil && !*req.Active {
+		if err := s.users.DeactivateUser(r.Context(), userID, statusReason); err != nil {
+			s.handleError(w, err)
+			return
+		}
+	}
+
+	u, err := s.users.GetUser(r.Context(), userID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", s.location(usersPath, userID.String()))
+	respond(w, s.toUser(u), http.StatusCreated)
+}
+
+func (s *Server) replaceUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := resourceID(r, models.ErrUserNotFound)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	var req user
+	if err := decode(w, r, &req); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	current, err := s.users.GetUser(r.Context(), userID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	s.updateUser(r.Context(), w, current, req)
+}
+
+func (s *Server) patchUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := resourceID(r, models.ErrUserNotFound)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	var req patchRequest
+	if err := decode(w, r, &req); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	current, err := s.users.GetUser(r.Context(), userID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	res, err := toMap(s.toUser(current))
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := applyPatch(res, req.Operations); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	// Some identity providers send booleans as strings.
+	if v, ok := lookup(res, "active").(string); ok {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			s.handleError(w, fmt.Errorf("%w: active must be a boolean", errInvalidValue))
+			return
+		}
+		setKey(res, "active", active)
+	}
+
+	var patched user
+	if err := fromMap(res, &patched); err != nil {
+		s.handleError(w, fmt.Errorf("%w: %v", errInvalidValue, err))
+		return
+	}
+
+	s.updateUser(r.Context(), w, current, patched)
+}
+
+// updateUser makes the current user match the desired representation and
+// responds with the result. Attributes cannot be cleared, so removing a value
+// is rejected.
+func (s *Server) updateUser(ctx context.Context, w http.ResponseWriter, current models.User, desired user) {
+	var (
+		uu      models.UpdateUser
+		changed bool
+		err     error
+	)
+
+	change := func(name string, from string, to string, field *string) {
+		if err != nil || from == to {
+			return
+		}
+		if to == "" {
+			err = fmt.Errorf("%w: %s cannot be removed", errMutability, name)
+			return
+		}
+		*field = to
+		changed = true
+	}
+
+	firstName, lastName := desired.names()
+	change("userName", current.Email, desired.email(), &uu.Email)
+	change("name.givenName", current.FirstName, firstName, &uu.FirstName)
+	change("name.familyName", current.LastName, lastName, &uu.LastName)
+	change("nickName", current.Nickname, desired.NickName, &uu.Nickname)
+	change("addresses.country", current.Country, desired.country(), &uu.Country)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if desired.Password != "" {
+		uu.Password = desired.Password
+		changed = true
+	}
+
+	if changed {
+		if err := s.users.UpdateUser(ctx, current.ID, uu); err != nil {
+			s.handleError(w, err)
+			return
+		}
+	}
+
+	if err := s.setActive(ctx, current, desired.Active); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	u, err := s.users.GetUser(ctx, current.ID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	respond(w, s.toUser(u), http.StatusOK)
+}
+
+// setActive reactivates or deactivates the user when active changes.
+func (s *Server) setActive(ctx context.Context, current models.User, active *bool) error {
+	if active == nil || *active == (current.Status == models.UserStatusActive) {
+		return nil
+	}
+
+	if *active {
+		return s.users.ReactivateUser(ctx, current.ID, statusReason)
+	}
+	return s.users.DeactivateUser(ctx, current.ID, statusReason)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := resourceID(r, models.ErrUserNotFound)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := s.users.DeleteUser(r.Context(), userID); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
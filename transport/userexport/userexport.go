@@ -0,0 +1,346 @@
+// Package userexport encodes streams of users for bulk exports, in CSV, JSON
+// Lines or length-delimited protobuf, restricted to a selection of columns.
+// It is shared by the gRPC and HTTP transports, so that both produce the same
+// files.
+package userexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	// 3rd party
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+var (
+	ErrInvalidFormat = errors.New("invalid export format")
+	ErrInvalidColumn = errors.New("invalid export column")
+)
+
+// Format is the encoding of an export.
+type Format string
+
+const (
+	// FormatCSV writes a header row followed by a row per user.
+	FormatCSV Format = "csv"
+	// FormatJSONL writes a JSON object per user, one per line.
+	FormatJSONL Format = "jsonl"
+	// FormatProtobuf writes a UserInfo message per user, each preceded by its
+	// length as a varint.
+	FormatProtobuf Format = "protobuf"
+)
+
+// ContentType returns the media type of exports in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSONL:
+		return "application/jsonl"
+	default:
+		return "application/x-protobuf"
+	}
+}
+
+// Extension returns the file name extension of exports in the format.
+func (f Format) Extension() string {
+	switch f {
+	case FormatCSV:
+		return "csv"
+	case FormatJSONL:
+		return "jsonl"
+	default:
+		return "binpb"
+	}
+}
+
+// Filename returns the name of an export in the format taken at the time.
+func Filename(f Format, t time.Time) string {
+	return "users-" + t.UTC().Format("20060102T150405Z") + "." + f.Extension()
+}
+
+// ParseFormat returns the format of the given name, CSV for a blank one.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case "":
+		return FormatCSV, nil
+	case FormatCSV, FormatJSONL, FormatProtobuf:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidFormat, name)
+	}
+}
+
+// Columns are the columns that can be exported, in their default order.
+// Password hashes are never exported.
+var Columns = []string{
+	"id",
+	"type",
+	"status",
+	"email",
+	"first_name",
+	"last_name",
+	"nickname",
+	"country",
+	"attributes",
+	"created_at",
+	"updated_at",
+	"deleted_at",
+}
+
+// ParseColumns checks the names of the columns to export, all of them for an
+// empty list.
+func ParseColumns(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return Columns, nil
+	}
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !isColumn(name) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidColumn, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %q repeated", ErrInvalidColumn, name)
+		}
+		seen[name] = true
+	}
+
+	return names, nil
+}
+
+func isColumn(name string) bool {
+	for _, c := range Columns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Encoder writes users to an export.
+type Encoder interface {
+	Encode(u models.User) error
+	// Flush writes any buffered data; call it after the last user.
+	Flush() error
+}
+
+// NewEncoder returns an encoder writing the columns of users to w in the
+// format. The columns must have been checked with ParseColumns.
+func NewEncoder(w io.Writer, format Format, columns []string) (Encoder, error) {
+	switch format {
+	case FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w), columns: columns}, nil
+	case FormatJSONL:
+		return &jsonlEncoder{enc: json.NewEncoder(w), columns: columns}, nil
+	case FormatProtobuf:
+		return &protoEncoder{w: w, columns: columns}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFormat, format)
+	}
+}
+
+type csvEncoder struct {
+	w           *csv.Writer
+	columns     []string
+	wroteHeader bool
+}
+
+func (e *csvEncoder) Encode(u models.User) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(e.columns); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	record := make([]string, len(e.columns))
+	for i, c := range e.columns {
+		switch v := value(u, c).(type) {
+		case string:
+			record[i] = v
+		case *time.Time:
+			if v != nil {
+				record[i] = formatTime(*v)
+			}
+		case map[string]any:
+			if len(v) > 0 {
+				b, err := json.Marshal(v)
+				if err != nil {
+					return fmt.Errorf("encoding attributes of user %s: %w", u.ID, err)
+				}
+				record[i] = string(b)
+			}
+		}
+	}
+
+	return e.w.Write(record)
+}
+
+// Flush writes the header row of empty exports too.
+func (e *csvEncoder) Flush() error {
+	if !e.wroteHeader {
+		if err := e.w.Write(e.columns); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlEncoder struct {
+	enc     *json.Encoder
+	columns []string
+}
+
+func (e *jsonlEncoder) Encode(u models.User) error {
+	obj := make(map[string]any, len(e.columns))
+	for _, c := range e.columns {
+		switch v := value(u, c).(type) {
+		case *time.Time:
+			if v != nil {
+				obj[c] = formatTime(*v)
+			} else {
+				obj[c] = nil
+			}
+		default:
+			obj[c] = v
+		}
+	}
+
+	return e.enc.Encode(obj)
+}
+
+func (e *jsonlEncoder) Flush() error {
+	return nil
+}
+
+type protoEncoder struct {
+	w       io.Writer
+	columns []string
+	buf     []byte
+}
+
+func (e *protoEncoder) Encode(u models.User) error {
+	info, err := userInfo(u, e.columns)
+	if err != nil {
+		return err
+	}
+
+	msg, err := proto.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	e.buf = protowire.AppendVarint(e.buf[:0], uint64(len(msg)))
+	e.buf = append(e.buf, msg...)
+	_, err = e.w.Write(e.buf)
+	return err
+}
+
+func (e *protoEncoder) Flush() error {
+	return nil
+}
+
+// value returns the value of the column of the user: a string, a *time.Time
+// or, for attributes, a map.
+func value(u models.User, column string) any {
+	switch column {
+	case "id":
+		return u.ID.String()
+	case "type":
+		return string(u.Type)
+	case "status":
+		return string(u.Status)
+	case "email":
+		return u.Email
+	case "first_name":
+		return u.FirstName
+	case "last_name":
+		return u.LastName
+	case "nickname":
+		return u.Nickname
+	case "country":
+		return u.Country
+	case "attributes":
+		if u.Attributes == nil {
+			return map[string]any{}
+		}
+		return u.Attributes
+	case "created_at":
+		return &u.CreatedAt
+	case "updated_at":
+		return u.UpdateAt
+	case "deleted_at":
+		return u.DeletedAt
+	default:
+		return nil
+	}
+}
+
+// userInfo returns the UserInfo message of the columns of the user.
+func userInfo(u models.User, columns []string) (*pb.UserInfo, error) {
+	info := &pb.UserInfo{}
+	for _, c := range columns {
+		switch c {
+		case "id":
+			info.Id = u.ID.String()
+		case "type":
+			info.Type = pb.UserType(pb.UserType_value["USER_TYPE_"+strings.ToUpper(string(u.Type))])
+		case "status":
+			info.Status = pb.UserStatus(pb.UserStatus_value["USER_STATUS_"+strings.ToUpper(string(u.Status))])
+		case "email":
+			info.Email = u.Email
+		case "first_name":
+			info.FirstName = u.FirstName
+		case "last_name":
+			info.LastName = u.LastName
+		case "nickname":
+			info.Nickname = u.Nickname
+		case "country":
+			info.Country = u.Country
+		case "attributes":
+			if len(u.Attributes) == 0 {
+				continue
+			}
+			info.Attributes = make(map[string]*structpb.Value, len(u.Attributes))
+			for name, a := range u.Attributes {
+				v, err := structpb.NewValue(a)
+				if err != nil {
+					return nil, fmt.Errorf("encoding attributes of user %s: %w", u.ID, err)
+				}
+				info.Attributes[name] = v
+			}
+		case "created_at":
+			info.CreatedAt = timestamppb.New(u.CreatedAt)
+		case "updated_at":
+			if u.UpdateAt != nil {
+				info.UpdateAt = timestamppb.New(*u.UpdateAt)
+			}
+		case "deleted_at":
+			if u.DeletedAt != nil {
+				info.DeletedAt = timestamppb.New(*u.DeletedAt)
+			}
+		}
+	}
+
+	return info, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
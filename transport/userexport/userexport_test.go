@@ -0,0 +1,147 @@
+package userexport
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	// 3rd party
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	// internal
+	"github.com/TonyPath/user-mng-grpc-service/internal/models"
+	pb "github.com/TonyPath/user-mng-grpc-service/proto/services/user"
+)
+
+var testUsers = []models.User{
+	{
+		ID:         uuid.MustParse("b3ce8fed-d5e8-4583-8783-b95969b5bc0c"),
+		Type:       models.UserTypeHuman,
+		Status:     models.UserStatusActive,
+		Email:      "antonis+1@mail.com",
+		FirstName:  "tony+1",
+		LastName:   "papath, jr",
+		Country:    "GR",
+		Attributes: map[string]any{"team": "data"},
+		CreatedAt:  time.Date(2023, 3, 12, 10, 25, 41, 0, time.UTC),
+	},
+	{
+		ID:        uuid.MustParse("0e1b7a6c-3f5d-4a8e-9b2c-6d4f1e8a7c3b"),
+		Type:      models.UserTypeServiceAccount,
+		Status:    models.UserStatusSuspended,
+		Email:     "bot@mail.com",
+		Nickname:  "Bot",
+		CreatedAt: time.Date(2023, 3, 13, 8, 0, 0, 0, time.UTC),
+	},
+}
+
+func encode(t *testing.T, format Format, columns []string, users []models.User) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, format, columns)
+	require.NoError(t, err)
+	for _, u := range users {
+		require.NoError(t, enc.Encode(u))
+	}
+	require.NoError(t, enc.Flush())
+
+	return buf.Bytes()
+}
+
+func TestEncoder_CSV(t *testing.T) {
+	got := encode(t, FormatCSV, []string{"email", "last_name", "attributes", "created_at", "deleted_at"}, testUsers)
+	require.Equal(t, "email,last_name,attributes,created_at,deleted_at\n"+
+		"antonis+1@mail.com,\"papath, jr\",\"{\"\"team\"\":\"\"data\"\"}\",2023-03-12T10:25:41Z,\n"+
+		"bot@mail.com,,,2023-03-13T08:00:00Z,\n", string(got))
+
+	t.Log("empty export")
+	{
+		got := encode(t, FormatCSV, []string{"id", "email"}, nil)
+		require.Equal(t, "id,email\n", string(got))
+	}
+}
+
+func TestEncoder_JSONL(t *testing.T) {
+	got := encode(t, FormatJSONL, []string{"id", "status", "nickname", "updated_at"}, testUsers)
+	require.Equal(t, `{"id":"b3ce8fed-d5e8-4583-8783-b95969b5bc0c","nickname":"","status":"active","updated_at":null}`+"\n"+
+		`{"id":"0e1b7a6c-3f5d-4a8e-9b2c-6d4f1e8a7c3b","nickname":"Bot","status":"suspended","updated_at":null}`+"\n", string(got))
+}
+
+func TestEncoder_Protobuf(t *testing.T) {
+	got := encode(t, FormatProtobuf, Columns, testUsers)
+
+	var infos []*pb.UserInfo
+	for len(got) > 0 {
+		size, n := protowire.ConsumeVarint(got)
+		require.Greater(t, n, 0)
+		got = got[n:]
+
+		info := &pb.UserInfo{}
+		require.NoError(t, proto.Unmarshal(got[:size], info))
+		infos = append(infos, info)
+		got = got[size:]
+	}
+
+	require.Len(t, infos, 2)
+	require.Equal(t, "antonis+1@mail.com", infos[0].GetEmail())
+	require.Equal(t, pb.UserType_USER_TYPE_HUMAN, infos[0].GetType())
+	require.Equal(t, pb.UserStatus_USER_STATUS_ACTIVE, infos[0].GetStatus())
+	require.Equal(t, "data", infos[0].GetAttributes()["team"].GetStringValue())
+	require.Equal(t, testUsers[0].CreatedAt, infos[0].GetCreatedAt().AsTime())
+	require.Equal(t, pb.UserType_USER_TYPE_SERVICE_ACCOUNT, infos[1].GetType())
+	require.Equal(t, pb.UserStatus_USER_STATUS_SUSPENDED, infos[1].GetStatus())
+	require.Nil(t, infos[1].GetDeletedAt())
+	require.Empty(t, infos[1].GetPassword())
+
+	t.Log("selected columns only")
+	{
+		got := encode(t, FormatProtobuf, []string{"id"}, testUsers[:1])
+		size, n := protowire.ConsumeVarint(got)
+
+		info := &pb.UserInfo{}
+		require.NoError(t, proto.Unmarshal(got[n:n+int(size)], info))
+		require.True(t, proto.Equal(&pb.UserInfo{Id: testUsers[0].ID.String()}, info))
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns(nil)
+	require.NoError(t, err)
+	require.Equal(t, Columns, columns)
+
+	columns, err = ParseColumns([]string{"email", "id"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"email", "id"}, columns)
+
+	_, err = ParseColumns([]string{"password"})
+	require.ErrorIs(t, err, ErrInvalidColumn)
+
+	_, err = ParseColumns([]string{"id", "id"})
+	require.ErrorIs(t, err, ErrInvalidColumn)
+}
+
+func TestParseFormat(t *testing.T) {
+	tt := map[string]struct {
+		name   string
+		format Format
+		err    error
+	}{
+		"default":    {name: "", format: FormatCSV},
+		"jsonl":      {name: "jsonl", format: FormatJSONL},
+		"upper case": {name: "PROTOBUF", format: FormatProtobuf},
+		"unknown":    {name: "xml", err: ErrInvalidFormat},
+	}
+
+	for name, tc := range tt {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			format, err := ParseFormat(tc.name)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.format, format)
+		})
+	}
+}